@@ -7,18 +7,30 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"unified-workflow/internal/admission"
+	"unified-workflow/internal/api"
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/callback"
+	"unified-workflow/internal/completion"
 	"unified-workflow/internal/config"
+	"unified-workflow/internal/dataprotection"
 	"unified-workflow/internal/di"
 	"unified-workflow/internal/executor"
+	"unified-workflow/internal/grpcapi"
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/metrics"
 	"unified-workflow/internal/primitive"
 	"unified-workflow/internal/queue"
 	"unified-workflow/internal/registry"
 	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -30,10 +42,16 @@ func main() {
 	fmt.Println("")
 
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, configPath, err := config.LoadConfigWithSource()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := logging.Init(cfg.Logging); err != nil {
+		log.Printf("Warning: Failed to initialize logging: %v", err)
+	}
 
 	// Initialize DI container
 	container, err := initializeContainer(cfg)
@@ -60,6 +78,28 @@ func main() {
 		log.Fatalf("Failed to resolve queue service: %v", err)
 	}
 
+	// Register metrics collectors
+	registerMetrics(cfg, queueService)
+
+	// Authenticate API callers; nil when auth is disabled
+	authenticator, err := auth.Init(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication: %v", err)
+	}
+
+	// Limit the runs each tenant may start
+	quotas, err := tenant.NewQuotas(cfg.Tenancy)
+	if err != nil {
+		log.Fatalf("Failed to initialize tenant quotas: %v", err)
+	}
+
+	// Refuse runs beyond the client, workflow, queue depth and in-flight limits
+	admissionControl := admission.NewController(cfg.Admission, cfg.Executor.MaxConcurrentWorkflows, queueService)
+
+	// Apply config changes to running components
+	reloadManager := setupConfigReload(cfg, configPath, container, executorService, authenticator, quotas, admissionControl)
+	defer reloadManager.Stop()
+
 	// Start executor
 	ctx := context.Background()
 	if err := executorService.Start(ctx); err != nil {
@@ -67,34 +107,44 @@ func main() {
 	}
 	defer executorService.Stop(ctx)
 
-	// Initialize Gin router
-	router := gin.Default()
-
-	// Add middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
-
-	// API routes
-	api := router.Group("/api/v1")
-	{
-		// Workflow execution
-		api.POST("/execute", executeWorkflow(executorService, registryService))
-		api.POST("/execute/async", asyncExecuteWorkflow(executorService, registryService, queueService))
+	// Route the outcome of queued runs back into the state store and notify waiters
+	hub := completion.NewHub(cfg.Executor.MaxResultWaiters)
+	executorService.SetCompletionHub(hub)
+	executorService.SetQuotas(quotas)
+	executorService.SetAdmission(admissionControl)
+	stopResultRouting, err := startResultRouting(ctx, container, queueService, executorService, hub)
+	if err != nil {
+		log.Printf("Warning: Async execution results will not be recorded: %v", err)
+	} else {
+		defer stopResultRouting()
+	}
 
-		// Execution management
-		api.GET("/executions", listExecutions(executorService))
-		api.GET("/executions/:runId", getExecutionStatus(executorService))
-		api.GET("/executions/:runId/details", getExecutionDetails(executorService))
-		api.GET("/executions/:runId/data", getExecutionData(executorService))
-		api.GET("/executions/:runId/metrics", getExecutionMetrics(executorService))
-		api.POST("/executions/:runId/cancel", cancelExecution(executorService))
-		api.POST("/executions/:runId/pause", pauseExecution(executorService))
-		api.POST("/executions/:runId/resume", resumeExecution(executorService))
-		api.POST("/executions/:runId/retry", retryExecution(executorService))
+	// Persist runs sleeping on timer steps and resume them once their timers fire
+	stopTimers, err := startTimers(ctx, container, queueService, executorService)
+	if err != nil {
+		log.Printf("Warning: Timer steps will sleep in place: %v", err)
+	} else {
+		defer stopTimers()
+	}
 
-		// Step execution details
-		api.GET("/executions/:runId/steps/:stepIndex", getStepExecution(executorService))
-		api.GET("/executions/:runId/steps/:stepIndex/child-steps/:childStepIndex", getChildStepExecution(executorService))
+	// Deliver results to callback URLs registered on async executions
+	dispatcher, err := callback.StartDispatcher(ctx, cfg.Callbacks, hub, executorService.LoadResult)
+	if err != nil {
+		log.Printf("Warning: Callbacks will not be delivered: %v", err)
+	} else if dispatcher != nil {
+		defer dispatcher.Stop()
+	}
+
+	// Initialize Gin router with the execution API
+	router, err := api.NewRouter(api.Dependencies{
+		Registry:      registryService,
+		Executor:      executorService,
+		Callbacks:     dispatcher,
+		ReloadManager: reloadManager,
+		Auth:          authenticator,
+	}, api.GroupExecution, api.GroupExecutions, api.GroupAdmin)
+	if err != nil {
+		log.Fatalf("Failed to create router: %v", err)
 	}
 
 	// Health check with DI container health
@@ -122,22 +172,33 @@ func main() {
 				},
 			},
 			"metrics": gin.H{
-				"worker_count":             cfg.Executor.WorkerCount,
-				"max_concurrent_workflows": cfg.Executor.MaxConcurrentWorkflows,
+				"worker_count":             reloadManager.Current().Executor.WorkerCount,
+				"max_concurrent_workflows": reloadManager.Current().Executor.MaxConcurrentWorkflows,
 			},
+			"circuit_breakers": di.DefaultCircuitBreakerManager.GetStatus(),
 		})
 	})
 
 	// DI container health endpoint
 	router.GET("/health/di", func(c *gin.Context) {
 		health := container.HealthCheck()
-		c.JSON(http.StatusOK, gin.H{
-			"container": "high-performance-di",
-			"health":    health,
-			"timestamp": time.Now().Unix(),
-		})
+		response := gin.H{
+			"container":        "high-performance-di",
+			"health":           health,
+			"circuit_breakers": di.DefaultCircuitBreakerManager.GetStatus(),
+			"timestamp":        time.Now().Unix(),
+		}
+		if provider, ok := container.(di.MetricsProvider); ok {
+			response["metrics"] = provider.GetMetrics()
+		}
+		c.JSON(http.StatusOK, response)
 	})
 
+	// Prometheus metrics endpoint
+	if cfg.Metrics.Enabled {
+		router.GET(cfg.Metrics.Endpoint(), gin.WrapH(metrics.Default.Handler()))
+	}
+
 	// Start server
 	port := getEnv("EXECUTOR_PORT", "8081")
 	srv := &http.Server{
@@ -153,6 +214,16 @@ func main() {
 		}
 	}()
 
+	// Serve the execution API over gRPC on the executor and registry resolved from the container
+	var grpcServer *grpc.Server
+	grpcPort := getEnv("EXECUTOR_GRPC_PORT", strconv.Itoa(cfg.Server.GRPCPort))
+	if grpcPort != "" && grpcPort != "0" {
+		grpcServer, err = grpcapi.Listen(fmt.Sprintf(":%s", grpcPort), grpcapi.NewServer(executorService, registryService, dispatcher), grpcapi.AuthOptions(authenticator)...)
+		if err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -164,6 +235,9 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
@@ -190,6 +264,12 @@ func initializeContainer(cfg *config.Config) (di.Container, error) {
 		log.Printf("Initialized global primitives successfully")
 	}
 
+	// Wrap primitives with circuit breaker, timeout, retry and bulkhead policies
+	primitiveResilience := di.WrapPrimitiveServices(primitive.Default, cfg.Resilience, di.DefaultCircuitBreakerManager)
+	if err := container.RegisterInstance((*di.PrimitiveResilience)(nil), primitiveResilience); err != nil {
+		return nil, fmt.Errorf("failed to register primitive resilience: %w", err)
+	}
+
 	// Register primitive services with DI
 	if err := di.RegisterPrimitiveServices(container, primitiveConfig); err != nil {
 		return nil, fmt.Errorf("failed to register primitive services: %w", err)
@@ -229,15 +309,23 @@ func registerCoreServices(container di.Container, cfg *config.Config) error {
 		// Get registry service URL from config
 		registryURL := cfg.Services.Registry.URL
 		log.Printf("Creating HTTP registry client for endpoint: %s", registryURL)
-		return registry.NewHTTPRegistry(registryURL)
+		return registry.NewHTTPRegistry(registryURL, cfg.Services.Registry.AuthToken)
 	}, di.Singleton)
 	if err != nil {
 		return err
 	}
 
-	// Register state management
+	// Register state management; classified workflow data is encrypted at rest
+	encryptor, err := dataprotection.Init(cfg.DataProtection)
+	if err != nil {
+		return fmt.Errorf("failed to initialize data protection: %w", err)
+	}
 	err = container.RegisterFactory((*state.StateManagement)(nil), func(c di.Container) (interface{}, error) {
-		return state.NewInMemoryState(), nil
+		store, err := state.NewStateManagement(cfg.State)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize state store: %w", err)
+		}
+		return state.NewEncryptedState(store, encryptor), nil
 	}, di.Singleton)
 	if err != nil {
 		return err
@@ -260,6 +348,41 @@ func registerCoreServices(container di.Container, cfg *config.Config) error {
 	return nil
 }
 
+// setupConfigReload registers the live-reloadable components and watches the config file
+func setupConfigReload(cfg *config.Config, configPath string, container di.Container, executorService *executor.WorkflowExecutor, authenticator *auth.Authenticator, quotas *tenant.Quotas, admissionControl *admission.Controller) *config.ReloadManager {
+	reloadManager := config.NewReloadManager(cfg, configPath)
+
+	owners := []config.SectionOwner{logging.ConfigOwner{}, dataprotection.ConfigOwner{}}
+	if instance, err := container.Resolve((*di.PrimitiveResilience)(nil)); err == nil {
+		owners = append(owners, instance.(*di.PrimitiveResilience))
+	}
+	owners = append(owners, executor.NewConfigOwner(executorService), tenant.NewConfigOwner(quotas), admission.NewConfigOwner(admissionControl))
+	if authenticator != nil {
+		owners = append(owners, auth.NewConfigOwner(authenticator))
+	}
+
+	for _, owner := range owners {
+		if err := reloadManager.Register(owner); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if configPath != "" {
+		if _, err := reloadManager.WatchFile(configPath); err != nil {
+			log.Printf("Warning: Config hot reload disabled: %v", err)
+		}
+	}
+
+	return reloadManager
+}
+
+// registerMetrics configures the metrics registry and its scrape-time collectors
+func registerMetrics(cfg *config.Config, q queue.Queue) {
+	metrics.Default.SetMaxSeries(cfg.Metrics.MaxSeriesPerMetric)
+	queue.RegisterMetrics(metrics.Default, q)
+	di.DefaultCircuitBreakerManager.RegisterMetrics(metrics.Default)
+}
+
 // createQueueService creates the appropriate queue service based on config
 func createQueueService(cfg *config.Config) queue.Queue {
 	if cfg.Queue.Type == "nats" {
@@ -282,8 +405,26 @@ func createQueueService(cfg *config.Config) queue.Queue {
 	return queue.NewInMemoryQueue()
 }
 
+// startResultRouting records the outcome of runs processed by workers in the executor's state store
+func startResultRouting(ctx context.Context, container di.Container, q queue.Queue, executorService *executor.WorkflowExecutor, hub *completion.Hub) (func(), error) {
+	instance, err := container.Resolve((*state.StateManagement)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve state management: %w", err)
+	}
+	return executor.StartResultRouting(ctx, q, instance.(state.StateManagement), executorService, hub)
+}
+
+// startTimers makes the timer steps of the runs executed here durable, kept in the executor's state store
+func startTimers(ctx context.Context, container di.Container, q queue.Queue, executorService *executor.WorkflowExecutor) (func(), error) {
+	instance, err := container.Resolve((*state.StateManagement)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve state management: %w", err)
+	}
+	return executor.StartTimers(ctx, q, instance.(state.StateManagement), executorService)
+}
+
 // resolveExecutorService resolves the executor service from container
-func resolveExecutorService(container di.Container) (executor.Executor, error) {
+func resolveExecutorService(container di.Container) (*executor.WorkflowExecutor, error) {
 	// Resolve executor factory
 	factoryInstance, err := container.Resolve((*executor.DIFactory)(nil))
 	if err != nil {
@@ -332,461 +473,6 @@ func resolveQueueService(container di.Container, cfg *config.Config) (queue.Queu
 	return instance.(queue.Queue), nil
 }
 
-// Handler functions
-
-func executeWorkflow(exec executor.Executor, reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		var request struct {
-			WorkflowID string                 `json:"workflow_id" binding:"required"`
-			InputData  map[string]interface{} `json:"input_data,omitempty"`
-			TimeoutMs  int64                  `json:"timeout_ms,omitempty"`
-			Priority   int                    `json:"priority,omitempty"`
-		}
-
-		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request body",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		log.Printf("[Executor] Getting workflow with ID: %s", request.WorkflowID)
-		// Get workflow
-		workflow, err := reg.GetWorkflow(ctx, request.WorkflowID)
-		if err != nil {
-			log.Printf("[Executor] Failed to get workflow: %v", err)
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Workflow not found",
-				"details": err.Error(),
-			})
-			return
-		}
-		log.Printf("[Executor] Successfully retrieved workflow: %s", workflow.GetName())
-
-		// Submit workflow for execution
-		runID, err := exec.SubmitWorkflowByID(ctx, workflow.GetID())
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to execute workflow",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Get execution status
-		status, err := exec.GetExecutionStatus(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to get execution status",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"run_id":      runID,
-			"workflow_id": workflow.GetID(),
-			"status":      status.Status,
-			"progress":    status.Progress,
-			"start_time":  status.StartTime,
-			"end_time":    status.EndTime,
-			"message":     "Workflow execution initiated",
-		})
-	}
-}
-
-func asyncExecuteWorkflow(exec executor.Executor, reg registry.Registry, q queue.Queue) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		var request struct {
-			WorkflowID        string                 `json:"workflow_id" binding:"required"`
-			InputData         map[string]interface{} `json:"input_data,omitempty"`
-			CallbackURL       string                 `json:"callback_url,omitempty"`
-			TimeoutMs         int64                  `json:"timeout_ms,omitempty"`
-			WaitForCompletion bool                   `json:"wait_for_completion,omitempty"`
-		}
-
-		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request body",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Get workflow
-		workflow, err := reg.GetWorkflow(ctx, request.WorkflowID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Workflow not found",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Submit workflow for execution
-		runID, err := exec.SubmitWorkflow(ctx, workflow)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to submit workflow",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusAccepted, gin.H{
-			"run_id":                  runID,
-			"status":                  "queued",
-			"message":                 "Workflow execution queued",
-			"status_url":              fmt.Sprintf("/api/v1/executions/%s", runID),
-			"result_url":              fmt.Sprintf("/api/v1/executions/%s/data", runID),
-			"poll_after_ms":           1000,
-			"estimated_completion_ms": 5000,
-			"expires_at":              time.Now().Add(1 * time.Hour).Format(time.RFC3339),
-		})
-	}
-}
-
-func listExecutions(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		// Parse query parameters
-		workflowID := c.Query("workflow_id")
-		status := c.Query("status")
-		limitStr := c.Query("limit")
-		offsetStr := c.Query("offset")
-
-		limit := 50
-		if limitStr != "" {
-			if val, err := parseInt(limitStr); err == nil && val > 0 {
-				limit = val
-			}
-		}
-
-		offset := 0
-		if offsetStr != "" {
-			if val, err := parseInt(offsetStr); err == nil && val >= 0 {
-				offset = val
-			}
-		}
-
-		// Build filters
-		filters := executor.ExecutionFilters{
-			WorkflowID: workflowID,
-			Status:     status,
-			Limit:      limit,
-			Offset:     offset,
-		}
-
-		// Get executions
-		executions, err := exec.ListExecutions(ctx, filters)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to list executions",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Convert to response format
-		response := make([]gin.H, 0, len(executions))
-		for _, exec := range executions {
-			response = append(response, gin.H{
-				"run_id":                   exec.RunID,
-				"workflow_id":              exec.WorkflowDefinitionID,
-				"status":                   exec.Status,
-				"current_step_index":       exec.CurrentStepIndex,
-				"current_child_step_index": exec.CurrentChildStepIndex,
-				"start_time":               exec.StartTime,
-				"end_time":                 exec.EndTime,
-				"error_message":            exec.ErrorMessage,
-				"last_attempted_step":      exec.LastAttemptedStep,
-				"is_terminal":              exec.IsTerminal,
-				"is_running":               exec.IsRunning,
-				"is_pending":               exec.IsPending,
-				"created_at":               exec.CreatedAt,
-				"updated_at":               exec.UpdatedAt,
-			})
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"executions": response,
-			"count":      len(response),
-			"limit":      limit,
-			"offset":     offset,
-		})
-	}
-}
-
-func getExecutionStatus(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		status, err := exec.GetExecutionStatus(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Execution not found",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"run_id":                   status.RunID,
-			"workflow_id":              status.WorkflowID,
-			"status":                   status.Status,
-			"current_step":             status.CurrentStep,
-			"current_step_index":       status.CurrentStepIndex,
-			"current_child_step_index": status.CurrentChildStepIndex,
-			"progress":                 status.Progress,
-			"start_time":               status.StartTime,
-			"end_time":                 status.EndTime,
-			"error_message":            status.ErrorMessage,
-			"last_attempted_step":      status.LastAttemptedStep,
-			"is_terminal":              status.IsTerminal,
-			"metadata":                 status.Metadata,
-		})
-	}
-}
-
-func getExecutionDetails(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		// TODO: Implement detailed execution information
-		// For now, return basic status
-		status, err := exec.GetExecutionStatus(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Execution not found",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"run_id":                   status.RunID,
-			"workflow_id":              status.WorkflowID,
-			"status":                   status.Status,
-			"current_step":             status.CurrentStep,
-			"current_step_index":       status.CurrentStepIndex,
-			"current_child_step_index": status.CurrentChildStepIndex,
-			"progress":                 status.Progress,
-			"start_time":               status.StartTime,
-			"end_time":                 status.EndTime,
-			"error_message":            status.ErrorMessage,
-			"last_attempted_step":      status.LastAttemptedStep,
-			"is_terminal":              status.IsTerminal,
-			"message":                  "Detailed execution information (implementation pending)",
-		})
-	}
-}
-
-func getExecutionData(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		data, err := exec.GetExecutionData(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Failed to get execution data",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"run_id": runID,
-			"data":   data,
-		})
-	}
-}
-
-func getExecutionMetrics(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		metrics, err := exec.GetMetrics(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Failed to get execution metrics",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"run_id":                metrics.RunID,
-			"workflow_id":           metrics.WorkflowID,
-			"total_steps":           metrics.TotalSteps,
-			"completed_steps":       metrics.CompletedSteps,
-			"failed_steps":          metrics.FailedSteps,
-			"total_child_steps":     metrics.TotalChildSteps,
-			"completed_child_steps": metrics.CompletedChildSteps,
-			"failed_child_steps":    metrics.FailedChildSteps,
-			"total_duration_millis": metrics.TotalDurationMillis,
-			"average_step_duration": metrics.AverageStepDuration,
-			"success_rate":          metrics.SuccessRate,
-		})
-	}
-}
-
-func cancelExecution(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		err := exec.CancelExecution(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to cancel execution",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Execution cancelled successfully",
-			"run_id":  runID,
-		})
-	}
-}
-
-func pauseExecution(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		err := exec.PauseExecution(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to pause execution",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Execution paused successfully",
-			"run_id":  runID,
-		})
-	}
-}
-
-func resumeExecution(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		err := exec.ResumeExecution(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to resume execution",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Execution resumed successfully",
-			"run_id":  runID,
-		})
-	}
-}
-
-func retryExecution(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		runID := c.Param("runId")
-
-		err := exec.RetryExecution(ctx, runID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to retry execution",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Execution retry initiated successfully",
-			"run_id":  runID,
-		})
-	}
-}
-
-func getStepExecution(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		runID := c.Param("runId")
-		stepIndexStr := c.Param("stepIndex")
-
-		stepIndex, err := parseInt(stepIndexStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid step index",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// TODO: Implement step execution details
-		c.JSON(http.StatusOK, gin.H{
-			"run_id":     runID,
-			"step_index": stepIndex,
-			"message":    "Step execution details (implementation pending)",
-		})
-	}
-}
-
-func getChildStepExecution(exec executor.Executor) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		runID := c.Param("runId")
-		stepIndexStr := c.Param("stepIndex")
-		childStepIndexStr := c.Param("childStepIndex")
-
-		stepIndex, err := parseInt(stepIndexStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid step index",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		childStepIndex, err := parseInt(childStepIndexStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid child step index",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// TODO: Implement child step execution details
-		c.JSON(http.StatusOK, gin.H{
-			"run_id":           runID,
-			"step_index":       stepIndex,
-			"child_step_index": childStepIndex,
-			"message":          "Child step execution details (implementation pending)",
-		})
-	}
-}
-
-func parseInt(s string) (int, error) {
-	var result int
-	_, err := fmt.Sscanf(s, "%d", &result)
-	return result, err
-}
-
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -10,10 +10,13 @@ import (
 	"syscall"
 	"time"
 
-	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/api"
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/metrics"
 	"unified-workflow/internal/registry"
 	"unified-workflow/workflows"
-	"unified-workflow/workflows/steps"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,30 +27,34 @@ func main() {
 	fmt.Println("Purpose: Workflow definition storage and management")
 	fmt.Println("")
 
+	// Load configuration for the logging and auth settings
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := logging.Init(cfg.Logging); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
 	// Initialize registry
 	reg := registry.NewInMemoryRegistry()
 
 	// Load example workflows on startup
 	loadExampleWorkflows(reg)
 
-	// Initialize Gin router
-	router := gin.Default()
-
-	// Add middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	// Authenticate API callers; nil when auth is disabled
+	authenticator, err := auth.Init(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication: %v", err)
+	}
 
-	// API routes
-	api := router.Group("/api/v1")
-	{
-		// Workflow management
-		api.GET("/workflows", listWorkflows(reg))
-		api.POST("/workflows", createWorkflow(reg))
-		api.GET("/workflows/:id", getWorkflow(reg))
-		api.PUT("/workflows/:id", updateWorkflow(reg))
-		api.DELETE("/workflows/:id", deleteWorkflow(reg))
-		api.GET("/workflows/:id/exists", containsWorkflow(reg))
-		api.GET("/workflows/count", getWorkflowCount(reg))
+	// Initialize Gin router with the workflow definition API
+	router, err := api.NewRouter(api.Dependencies{Registry: reg, Auth: authenticator}, api.GroupWorkflows)
+	if err != nil {
+		log.Fatalf("Failed to create router: %v", err)
 	}
 
 	// Health check
@@ -60,6 +67,11 @@ func main() {
 		})
 	})
 
+	// Prometheus metrics endpoint
+	if cfg.Metrics.Enabled {
+		router.GET(cfg.Metrics.Endpoint(), gin.WrapH(metrics.Default.Handler()))
+	}
+
 	// Start server
 	port := getEnv("REGISTRY_PORT", "8080")
 	srv := &http.Server{
@@ -93,273 +105,6 @@ func main() {
 	log.Println("Registry Service exited gracefully")
 }
 
-// Handler functions
-
-func listWorkflows(reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		// Get query parameters
-		nameFilter := c.Query("name")
-		descriptionFilter := c.Query("description")
-		limit := c.Query("limit")
-		offset := c.Query("offset")
-
-		// Get all workflow IDs
-		workflowIDs, err := reg.GetAllWorkflowIDs(ctx)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to list workflows",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Get each workflow
-		workflows := make([]gin.H, 0, len(workflowIDs))
-		for _, workflowID := range workflowIDs {
-			workflow, err := reg.GetWorkflow(ctx, workflowID)
-			if err != nil {
-				// Skip workflows that can't be retrieved
-				continue
-			}
-
-			// Apply filters
-			if nameFilter != "" && workflow.GetName() != nameFilter {
-				continue
-			}
-			if descriptionFilter != "" && workflow.GetDescription() != descriptionFilter {
-				continue
-			}
-
-			workflows = append(workflows, gin.H{
-				"id":          workflow.GetID(),
-				"name":        workflow.GetName(),
-				"description": workflow.GetDescription(),
-				"step_count":  workflow.GetStepCount(),
-				"created_at":  time.Now().Format(time.RFC3339),
-				"updated_at":  time.Now().Format(time.RFC3339),
-			})
-		}
-
-		// Apply pagination
-		totalCount := len(workflows)
-		filteredCount := totalCount
-
-		// TODO: Implement proper pagination
-		_ = limit
-		_ = offset
-
-		c.JSON(http.StatusOK, gin.H{
-			"workflows":      workflows,
-			"total_count":    totalCount,
-			"filtered_count": filteredCount,
-		})
-	}
-}
-
-func getWorkflow(reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		workflowID := c.Param("id")
-
-		workflow, err := reg.GetWorkflow(ctx, workflowID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Workflow not found",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Get steps
-		workflowSteps := workflow.GetSteps()
-		stepDetails := make([]gin.H, 0, len(workflowSteps))
-		for _, step := range workflowSteps {
-			stepDetails = append(stepDetails, gin.H{
-				"name":             step.GetName(),
-				"child_step_count": step.GetChildStepCount(),
-				"is_parallel":      step.IsParallel(),
-			})
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"id":          workflow.GetID(),
-			"name":        workflow.GetName(),
-			"description": workflow.GetDescription(),
-			"step_count":  workflow.GetStepCount(),
-			"steps":       stepDetails,
-			"created_at":  time.Now().Format(time.RFC3339),
-			"updated_at":  time.Now().Format(time.RFC3339),
-		})
-	}
-}
-
-func createWorkflow(reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		var request struct {
-			Name        string                   `json:"name" binding:"required"`
-			Description string                   `json:"description"`
-			Steps       []map[string]interface{} `json:"steps"`
-		}
-
-		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request body",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Create workflow
-		workflow := model.NewBaseWorkflow(request.Name, request.Description)
-
-		// Add steps if provided
-		for _, stepData := range request.Steps {
-			if stepType, ok := stepData["type"].(string); ok {
-				stepName, _ := stepData["name"].(string)
-				if stepName == "" {
-					stepName = stepType + "-step"
-				}
-
-				// Create appropriate step based on type
-				var step model.Step
-				switch stepType {
-				case "sequential":
-					step = model.NewSequentialStep(stepName)
-				case "echo":
-					// Create echo step with child steps
-					step = steps.NewEchoStep(stepName, "Echo step created via API")
-				default:
-					// Default to sequential step
-					step = model.NewSequentialStep(stepName)
-				}
-
-				// Add step to workflow
-				workflow.AddStep(step)
-			}
-		}
-
-		// Register workflow
-		err := reg.RegisterWorkflow(ctx, workflow)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to create workflow",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusCreated, gin.H{
-			"id":          workflow.GetID(),
-			"name":        workflow.GetName(),
-			"description": workflow.GetDescription(),
-			"message":     "Workflow created successfully",
-			"created_at":  time.Now().Format(time.RFC3339),
-		})
-	}
-}
-
-func updateWorkflow(reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		workflowID := c.Param("id")
-
-		var request struct {
-			Name        *string `json:"name,omitempty"`
-			Description *string `json:"description,omitempty"`
-		}
-
-		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request body",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// Get existing workflow
-		workflow, err := reg.GetWorkflow(ctx, workflowID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Workflow not found",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		// TODO: Implement update logic
-		// For now, just return the workflow
-		_ = request
-
-		c.JSON(http.StatusOK, gin.H{
-			"id":          workflow.GetID(),
-			"name":        workflow.GetName(),
-			"description": workflow.GetDescription(),
-			"message":     "Workflow update endpoint (implementation pending)",
-			"updated_at":  time.Now().Format(time.RFC3339),
-		})
-	}
-}
-
-func deleteWorkflow(reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		workflowID := c.Param("id")
-
-		err := reg.RemoveWorkflow(ctx, workflowID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error":   "Failed to delete workflow",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"message":    "Workflow deleted successfully",
-			"deleted_id": workflowID,
-			"deleted_at": time.Now().Format(time.RFC3339),
-		})
-	}
-}
-
-func containsWorkflow(reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		workflowID := c.Param("id")
-
-		_, err := reg.GetWorkflow(ctx, workflowID)
-		exists := err == nil
-
-		c.JSON(http.StatusOK, gin.H{
-			"exists": exists,
-			"id":     workflowID,
-		})
-	}
-}
-
-func getWorkflowCount(reg registry.Registry) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		workflowIDs, err := reg.GetAllWorkflowIDs(ctx)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to get workflow count",
-				"details": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"count": len(workflowIDs),
-		})
-	}
-}
-
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
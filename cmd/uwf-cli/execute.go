@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
@@ -131,7 +132,7 @@ func runExecuteSyncCmd(cmd *cobra.Command, args []string) error {
 		lastResponse = pollData
 
 		if status, ok := pollData["status"].(string); ok {
-			if status == "completed" || status == "failed" || status == "cancelled" {
+			if status == "completed" || status == "failed" || status == "cancelled" || status == "timed_out" {
 				return printOutput(lastResponse, output)
 			}
 		}
@@ -257,7 +258,7 @@ func runExecuteAsyncCmd(cmd *cobra.Command, args []string) error {
 		lastResponse = pollData
 
 		if status, ok := pollData["status"].(string); ok {
-			if status == "completed" || status == "failed" || status == "cancelled" {
+			if status == "completed" || status == "failed" || status == "cancelled" || status == "timed_out" {
 				return printOutput(lastResponse, output)
 			}
 		}
@@ -277,10 +278,22 @@ func newExecuteBulkCmd() *cobra.Command {
 	cmd.Flags().BoolP("parallel", "p", false, "Execute in parallel")
 	cmd.Flags().Int("concurrency", 5, "Maximum concurrent executions")
 	cmd.Flags().BoolP("async", "a", true, "Execute asynchronously")
+	cmd.Flags().Int("max-retries", 3, "Retries of an execution refused with 429, waiting for its Retry-After")
 
 	return cmd
 }
 
+// defaultRetryAfter is the wait before retrying a 429 response without a Retry-After header
+const defaultRetryAfter = 1 * time.Second
+
+// retryAfter returns the wait requested by the Retry-After header of a 429 response
+func retryAfter(resp *http.Response) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultRetryAfter
+}
+
 type bulkEntry struct {
 	WorkflowID string                 `json:"workflow_id"`
 	InputData  map[string]interface{} `json:"input_data"`
@@ -299,6 +312,7 @@ func runExecuteBulkCmd(cmd *cobra.Command, args []string) error {
 	concurrency, _ := cmd.Flags().GetInt("concurrency")
 	output, _ := cmd.Flags().GetString("output")
 	endpoint, _ := cmd.Flags().GetString("endpoint")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
 
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -323,6 +337,12 @@ func runExecuteBulkCmd(cmd *cobra.Command, args []string) error {
 
 		url := fmt.Sprintf("%s/api/v1/workflows/%s/execute", endpoint, entry.WorkflowID)
 		resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+		// Back off while the API sheds load instead of failing the entry
+		for attempt := 0; err == nil && resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries; attempt++ {
+			resp.Body.Close()
+			time.Sleep(retryAfter(resp))
+			resp, err = httpClient.Post(url, "application/json", bytes.NewReader(body))
+		}
 		if err != nil {
 			return bulkResult{WorkflowID: entry.WorkflowID, Status: "failed", Error: err.Error()}
 		}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	neturl "net/url"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -24,6 +26,7 @@ func newExecutionsCmd() *cobra.Command {
 	cmd.AddCommand(newExecutionsDataCmd())
 	cmd.AddCommand(newExecutionsMetricsCmd())
 	cmd.AddCommand(newExecutionsCancelCmd())
+	cmd.AddCommand(newExecutionsSignalCmd())
 	cmd.AddCommand(newExecutionsPauseCmd())
 	cmd.AddCommand(newExecutionsResumeCmd())
 	cmd.AddCommand(newExecutionsRetryCmd())
@@ -291,6 +294,73 @@ func runExecutionsCancelCmd(cmd *cobra.Command, args []string) error {
 	return printOutput(response, output)
 }
 
+func newExecutionsSignalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signal [run-id] [name]",
+		Short: "Send a signal to an execution waiting for it",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runExecutionsSignalCmd,
+	}
+
+	cmd.Flags().StringP("payload", "p", "", "Signal payload as JSON string")
+	cmd.Flags().StringP("payload-file", "f", "", "Signal payload from JSON file")
+
+	return cmd
+}
+
+func runExecutionsSignalCmd(cmd *cobra.Command, args []string) error {
+	runID := args[0]
+	name := args[1]
+	payload, _ := cmd.Flags().GetString("payload")
+	payloadFile, _ := cmd.Flags().GetString("payload-file")
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	output, _ := cmd.Flags().GetString("output")
+
+	var payloadData map[string]interface{}
+	if payloadFile != "" {
+		data, err := os.ReadFile(payloadFile)
+		if err != nil {
+			return fmt.Errorf("failed to read payload file: %v", err)
+		}
+		if err := json.Unmarshal(data, &payloadData); err != nil {
+			return fmt.Errorf("failed to parse payload JSON: %v", err)
+		}
+	} else if payload != "" {
+		if err := json.Unmarshal([]byte(payload), &payloadData); err != nil {
+			return fmt.Errorf("failed to parse payload JSON: %v", err)
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"payload": payloadData,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/executions/%s/signals/%s", endpoint, runID, neturl.PathEscape(name))
+
+	tr := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	httpClient := &http.Client{Transport: tr}
+
+	resp, err := httpClient.Post(url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to signal execution: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return printOutput(response, output)
+}
+
 func newExecutionsPauseCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pause [run-id]",
@@ -476,7 +546,7 @@ func runExecutionsWatchCmd(cmd *cobra.Command, args []string) error {
 		}
 
 		if st, ok := response["status"].(string); ok {
-			if st == "completed" || st == "failed" || st == "cancelled" {
+			if st == "completed" || st == "failed" || st == "cancelled" || st == "timed_out" {
 				return nil
 			}
 		}
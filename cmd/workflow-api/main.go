@@ -10,39 +10,94 @@ import (
 	"syscall"
 	"time"
 
-	"unified-workflow/internal/api/handlers"
+	"unified-workflow/internal/admission"
+	"unified-workflow/internal/api"
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/callback"
+	"unified-workflow/internal/completion"
 	"unified-workflow/internal/config"
+	"unified-workflow/internal/dataprotection"
+	"unified-workflow/internal/di"
 	"unified-workflow/internal/executor"
+	"unified-workflow/internal/grpcapi"
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/metrics"
 	"unified-workflow/internal/queue"
 	"unified-workflow/internal/registry"
 	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, configPath, err := config.LoadConfigWithSource()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := logging.Init(cfg.Logging); err != nil {
+		log.Printf("Warning: Failed to initialize logging: %v", err)
+	}
+
+	// Authenticate API callers; nil when auth is disabled
+	authenticator, err := auth.Init(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication: %v", err)
+	}
+
+	// Limit the runs each tenant may start
+	quotas, err := tenant.NewQuotas(cfg.Tenancy)
+	if err != nil {
+		log.Fatalf("Failed to initialize tenant quotas: %v", err)
+	}
+
+	// Apply config changes to running components
+	reloadManager := config.NewReloadManager(cfg, configPath)
+	owners := []config.SectionOwner{logging.ConfigOwner{}, dataprotection.ConfigOwner{}, tenant.NewConfigOwner(quotas)}
+	if authenticator != nil {
+		owners = append(owners, auth.NewConfigOwner(authenticator))
+	}
+	for _, owner := range owners {
+		if err := reloadManager.Register(owner); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if configPath != "" {
+		if _, err := reloadManager.WatchFile(configPath); err != nil {
+			log.Printf("Warning: Config hot reload disabled: %v", err)
+		}
+	}
+	defer reloadManager.Stop()
 
 	// Initialize components
 	// Use HTTP registry to connect to registry service
 	var reg registry.Registry
-	httpReg, err := registry.NewHTTPRegistry("http://registry-service:8080")
+	httpReg, err := registry.NewHTTPRegistry("http://registry-service:8080", cfg.Services.Registry.AuthToken)
 	if err != nil {
 		log.Printf("Failed to create HTTP registry, falling back to in-memory: %v", err)
 		reg = registry.NewInMemoryRegistry()
 	} else {
 		reg = httpReg
 	}
-	stateMgmt := state.NewInMemoryState()
+	encryptor, err := dataprotection.Init(cfg.DataProtection)
+	if err != nil {
+		log.Fatalf("Failed to initialize data protection: %v", err)
+	}
+	store, err := state.NewStateManagement(cfg.State)
+	if err != nil {
+		log.Fatalf("Failed to initialize state store: %v", err)
+	}
+	stateMgmt := state.NewEncryptedState(store, encryptor)
 
-	// Initialize queue based on configuration
+	// Initialize queue based on configuration; workers consume the same stream
 	var q queue.Queue
 	if cfg.Queue.Type == "nats" {
-		natsConfig := queue.NATSConfig{
+		natsConfig := queue.EnhancedNATSConfig{
 			URLs:           cfg.Queue.NATS.URLs,
 			StreamName:     cfg.Queue.NATS.StreamName,
 			SubjectPrefix:  cfg.Queue.NATS.SubjectPrefix,
@@ -51,7 +106,7 @@ func main() {
 			ReconnectWait:  cfg.Queue.NATS.ReconnectWait,
 			ConnectTimeout: cfg.Queue.NATS.ConnectTimeout,
 		}
-		q, err = queue.NewNATSQueue(natsConfig)
+		q, err = queue.NewEnhancedNATSQueue(natsConfig)
 		if err != nil {
 			log.Printf("Failed to create NATS queue, falling back to in-memory: %v", err)
 			q = queue.NewInMemoryQueue()
@@ -60,8 +115,22 @@ func main() {
 		q = queue.NewInMemoryQueue()
 	}
 
-	// Initialize executor
-	exec := executor.NewSimpleExecutor(reg, q, stateMgmt)
+	// Register metrics collectors
+	metrics.Default.SetMaxSeries(cfg.Metrics.MaxSeriesPerMetric)
+	queue.RegisterMetrics(metrics.Default, q)
+	di.DefaultCircuitBreakerManager.RegisterMetrics(metrics.Default)
+
+	// Refuse runs beyond the client, workflow, queue depth and in-flight limits
+	admissionControl := admission.NewController(cfg.Admission, cfg.Executor.MaxConcurrentWorkflows, q)
+
+	// Initialize executor with the configured limits and run/step timeouts
+	exec := executor.NewWorkflowExecutor(reg, stateMgmt, executor.ConfigFromSettings(cfg.Executor))
+	exec.SetQueue(q)
+	for _, owner := range []config.SectionOwner{admission.NewConfigOwner(admissionControl), executor.NewConfigOwner(exec)} {
+		if err := reloadManager.Register(owner); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
 
 	// Start executor
 	ctx := context.Background()
@@ -70,37 +139,44 @@ func main() {
 	}
 	defer exec.Stop(ctx)
 
-	// Initialize Gin router
-	router := gin.Default()
-
-	// Add middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
-
-	// Initialize handlers
-	handler := handlers.NewWorkflowHandler(exec, reg, stateMgmt)
-
-	// API routes
-	api := router.Group("/api/v1")
-	{
-		// Workflow management
-		api.GET("/workflows", handler.ListWorkflows)
-		api.POST("/workflows", handler.CreateWorkflow)
-		api.GET("/workflows/:id", handler.GetWorkflow)
-		api.DELETE("/workflows/:id", handler.DeleteWorkflow)
-
-		// Workflow execution
-		api.POST("/workflows/:id/execute", handler.ExecuteWorkflow)
-		api.POST("/workflows/:id/async-execute", handler.AsyncExecuteWorkflow) // NEW: Async execution
-		api.GET("/executions", handler.ListExecutions)
-		api.GET("/executions/:runId", handler.GetExecutionStatus)
-		api.GET("/executions/:runId/result", handler.GetExecutionResult) // NEW: Get async result
-		api.POST("/executions/:runId/cancel", handler.CancelExecution)
-		api.POST("/executions/:runId/pause", handler.PauseExecution)
-		api.POST("/executions/:runId/resume", handler.ResumeExecution)
-		api.POST("/executions/:runId/retry", handler.RetryExecution)
-		api.GET("/executions/:runId/data", handler.GetExecutionData)
-		api.GET("/executions/:runId/metrics", handler.GetExecutionMetrics)
+	// Route the outcome of queued runs back into the state store and notify waiters
+	hub := completion.NewHub(cfg.Executor.MaxResultWaiters)
+	exec.SetCompletionHub(hub)
+	exec.SetQuotas(quotas)
+	exec.SetAdmission(admissionControl)
+	stopResultRouting, err := executor.StartResultRouting(ctx, q, stateMgmt, exec, hub)
+	if err != nil {
+		log.Printf("Warning: Async execution results will not be recorded: %v", err)
+	} else {
+		defer stopResultRouting()
+	}
+
+	// Persist runs sleeping on timer steps and resume them once their timers fire
+	stopTimers, err := executor.StartTimers(ctx, q, stateMgmt, exec)
+	if err != nil {
+		log.Printf("Warning: Timer steps will sleep in place: %v", err)
+	} else {
+		defer stopTimers()
+	}
+
+	// Deliver results to callback URLs registered on async executions
+	dispatcher, err := callback.StartDispatcher(ctx, cfg.Callbacks, hub, exec.LoadResult)
+	if err != nil {
+		log.Printf("Warning: Callbacks will not be delivered: %v", err)
+	} else if dispatcher != nil {
+		defer dispatcher.Stop()
+	}
+
+	// Initialize Gin router with the full API
+	router, err := api.NewRouter(api.Dependencies{
+		Registry:      reg,
+		Executor:      exec,
+		Callbacks:     dispatcher,
+		ReloadManager: reloadManager,
+		Auth:          authenticator,
+	}, api.GroupWorkflows, api.GroupExecution, api.GroupExecutions, api.GroupAdmin)
+	if err != nil {
+		log.Fatalf("Failed to create router: %v", err)
 	}
 
 	// Health check
@@ -111,6 +187,11 @@ func main() {
 		})
 	})
 
+	// Prometheus metrics endpoint
+	if cfg.Metrics.Enabled {
+		router.GET(cfg.Metrics.Endpoint(), gin.WrapH(metrics.Default.Handler()))
+	}
+
 	// Start server
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
@@ -126,6 +207,15 @@ func main() {
 
 	log.Printf("Server started on port %d", cfg.Server.Port)
 
+	// Serve the execution API over gRPC on the same executor and registry
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort > 0 {
+		grpcServer, err = grpcapi.Listen(fmt.Sprintf(":%d", cfg.Server.GRPCPort), grpcapi.NewServer(exec, reg, dispatcher), grpcapi.AuthOptions(authenticator)...)
+		if err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -137,6 +227,9 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
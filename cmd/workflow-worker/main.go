@@ -5,14 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"unified-workflow/internal/config"
+	"unified-workflow/internal/dataprotection"
 	"unified-workflow/internal/di"
 	"unified-workflow/internal/executor"
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/metrics"
 	"unified-workflow/internal/primitive"
 	"unified-workflow/internal/queue"
 	"unified-workflow/internal/registry"
@@ -23,10 +27,16 @@ func main() {
 	log.Println("Starting workflow worker with DI/IoC framework...")
 
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, configPath, err := config.LoadConfigWithSource()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := logging.Init(cfg.Logging); err != nil {
+		log.Printf("Warning: Failed to initialize logging: %v", err)
+	}
 
 	// Initialize DI container
 	container, err := initializeContainer(cfg)
@@ -57,8 +67,30 @@ func main() {
 
 	log.Println("Workflow worker started with DI-enabled executor")
 
-	// Process messages
-	processMessages(ctx, queueService, executorService)
+	// Apply config changes to running components
+	reloadManager := setupConfigReload(cfg, configPath, container, executorService)
+	defer reloadManager.Stop()
+
+	// Expose metrics for scraping
+	if cfg.Metrics.Enabled {
+		startMetricsServer(cfg, queueService, reloadManager)
+	}
+
+	// Persist runs sleeping on timer steps and resume them once their timers fire
+	stopTimers, err := startTimers(ctx, container, queueService, executorService)
+	if err != nil {
+		log.Printf("Warning: Timer steps will sleep in place: %v", err)
+	} else {
+		defer stopTimers()
+	}
+
+	// Process execution requests until shutdown
+	workerCtx, stopWorker := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		executor.NewWorker(queueService, executorService).Run(workerCtx)
+	}()
 
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
@@ -66,6 +98,84 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down worker...")
+	stopWorker()
+	<-done
+}
+
+// startTimers makes the timer steps of the runs executed here durable, kept in the executor's state store
+func startTimers(ctx context.Context, container di.Container, q queue.Queue, executorService *executor.WorkflowExecutor) (func(), error) {
+	instance, err := container.Resolve((*state.StateManagement)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve state management: %w", err)
+	}
+	return executor.StartTimers(ctx, q, instance.(state.StateManagement), executorService)
+}
+
+// setupConfigReload registers the live-reloadable components and watches the config file
+func setupConfigReload(cfg *config.Config, configPath string, container di.Container, executorService *executor.WorkflowExecutor) *config.ReloadManager {
+	reloadManager := config.NewReloadManager(cfg, configPath)
+
+	owners := []config.SectionOwner{logging.ConfigOwner{}, dataprotection.ConfigOwner{}}
+	if instance, err := container.Resolve((*di.PrimitiveResilience)(nil)); err == nil {
+		owners = append(owners, instance.(*di.PrimitiveResilience))
+	}
+	owners = append(owners, executor.NewConfigOwner(executorService))
+
+	for _, owner := range owners {
+		if err := reloadManager.Register(owner); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if configPath != "" {
+		if _, err := reloadManager.WatchFile(configPath); err != nil {
+			log.Printf("Warning: Config hot reload disabled: %v", err)
+		}
+	}
+
+	return reloadManager
+}
+
+// startMetricsServer serves the Prometheus metrics, health and admin endpoints on WORKER_METRICS_PORT
+func startMetricsServer(cfg *config.Config, q queue.Queue, reloadManager *config.ReloadManager) {
+	metrics.Default.SetMaxSeries(cfg.Metrics.MaxSeriesPerMetric)
+	queue.RegisterMetrics(metrics.Default, q)
+	di.DefaultCircuitBreakerManager.RegisterMetrics(metrics.Default)
+
+	path := cfg.Metrics.Endpoint()
+	// 9090 is the default server.grpc_port, so a worker next to an API server must not take it
+	port := os.Getenv("WORKER_METRICS_PORT")
+	if port == "" {
+		port = "9091"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, metrics.Default.Handler())
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":           "healthy",
+			"service":          "workflow-worker",
+			"timestamp":        time.Now().Unix(),
+			"circuit_breakers": di.DefaultCircuitBreakerManager.GetStatus(),
+		})
+	})
+	mux.HandleFunc("/admin/config", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"config":      reloadManager.Current().Redacted(),
+			"source":      reloadManager.Source(),
+			"owners":      reloadManager.Owners(),
+			"last_reload": reloadManager.LastReload(),
+		})
+	})
+
+	go func() {
+		log.Printf("Worker metrics listening on :%s%s", port, path)
+		if err := http.ListenAndServe(":"+port, mux); err != nil && err != http.ErrServerClosed {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
 }
 
 // initializeContainer creates and configures the DI container
@@ -87,6 +197,12 @@ func initializeContainer(cfg *config.Config) (di.Container, error) {
 		log.Printf("Initialized global primitives successfully")
 	}
 
+	// Wrap primitives with circuit breaker, timeout, retry and bulkhead policies
+	primitiveResilience := di.WrapPrimitiveServices(primitive.Default, cfg.Resilience, di.DefaultCircuitBreakerManager)
+	if err := container.RegisterInstance((*di.PrimitiveResilience)(nil), primitiveResilience); err != nil {
+		return nil, fmt.Errorf("failed to register primitive resilience: %w", err)
+	}
+
 	// Register primitive services with DI
 	if err := di.RegisterPrimitiveServices(container, primitiveConfig); err != nil {
 		return nil, fmt.Errorf("failed to register primitive services: %w", err)
@@ -121,17 +237,27 @@ func initializeContainer(cfg *config.Config) (di.Container, error) {
 
 // registerCoreServices registers core application services
 func registerCoreServices(container di.Container, cfg *config.Config) error {
-	// Register registry service
+	// Register registry service - workflows are resolved from the registry service
 	err := container.RegisterFactory((*registry.Registry)(nil), func(c di.Container) (interface{}, error) {
-		return registry.NewInMemoryRegistry(), nil
+		registryURL := cfg.Services.Registry.URL
+		log.Printf("Creating HTTP registry client for endpoint: %s", registryURL)
+		return registry.NewHTTPRegistry(registryURL, cfg.Services.Registry.AuthToken)
 	}, di.Singleton)
 	if err != nil {
 		return err
 	}
 
-	// Register state management
+	// Register state management; classified workflow data is encrypted at rest
+	encryptor, err := dataprotection.Init(cfg.DataProtection)
+	if err != nil {
+		return fmt.Errorf("failed to initialize data protection: %w", err)
+	}
 	err = container.RegisterFactory((*state.StateManagement)(nil), func(c di.Container) (interface{}, error) {
-		return state.NewInMemoryState(), nil
+		store, err := state.NewStateManagement(cfg.State)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize state store: %w", err)
+		}
+		return state.NewEncryptedState(store, encryptor), nil
 	}, di.Singleton)
 	if err != nil {
 		return err
@@ -187,7 +313,7 @@ func resolveQueueService(container di.Container, cfg *config.Config) (queue.Queu
 }
 
 // resolveExecutorService resolves the executor service from container
-func resolveExecutorService(container di.Container) (executor.Executor, error) {
+func resolveExecutorService(container di.Container) (*executor.WorkflowExecutor, error) {
 	// Resolve executor factory
 	factoryInstance, err := container.Resolve((*executor.DIFactory)(nil))
 	if err != nil {
@@ -212,153 +338,3 @@ func resolveExecutorService(container di.Container) (executor.Executor, error) {
 
 	return exec, nil
 }
-
-// processMessages continuously processes messages from the queue
-func processMessages(ctx context.Context, q queue.Queue, exec executor.Executor) {
-	// Try to cast to EnhancedNATSQueue for enhanced features
-	enhancedQueue, isEnhanced := q.(*queue.EnhancedNATSQueue)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			if isEnhanced {
-				// Use enhanced dequeue for better message handling
-				processEnhancedMessage(ctx, enhancedQueue, exec)
-			} else {
-				// Use standard dequeue
-				processStandardMessage(ctx, q, exec)
-			}
-		}
-	}
-}
-
-// processEnhancedMessage processes a message using enhanced queue features
-func processEnhancedMessage(ctx context.Context, q *queue.EnhancedNATSQueue, exec executor.Executor) {
-	// Dequeue message with enhanced info
-	enhancedMsg, err := q.DequeueEnhanced(ctx)
-	if err != nil {
-		log.Printf("Failed to dequeue message: %v", err)
-		time.Sleep(1 * time.Second)
-		return
-	}
-
-	if enhancedMsg == nil {
-		// No messages available, wait before trying again
-		time.Sleep(100 * time.Millisecond)
-		return
-	}
-
-	// Process the message
-	if err := processWorkflowExecution(ctx, enhancedMsg.Message, exec, q, enhancedMsg); err != nil {
-		log.Printf("Failed to process workflow execution %s: %v", enhancedMsg.RunID, err)
-
-		// Reject message for retry
-		if err := q.RejectEnhanced(ctx, enhancedMsg, 5*time.Second); err != nil {
-			log.Printf("Failed to reject message %s: %v", enhancedMsg.RunID, err)
-		}
-	} else {
-		// Acknowledge successful processing
-		if err := q.AcknowledgeEnhanced(ctx, enhancedMsg); err != nil {
-			log.Printf("Failed to acknowledge message %s: %v", enhancedMsg.RunID, err)
-		}
-	}
-}
-
-// processStandardMessage processes a message using standard queue interface
-func processStandardMessage(ctx context.Context, q queue.Queue, exec executor.Executor) {
-	// Dequeue message
-	msg, err := q.Dequeue(ctx)
-	if err != nil {
-		log.Printf("Failed to dequeue message: %v", err)
-		time.Sleep(1 * time.Second)
-		return
-	}
-
-	if msg == nil {
-		// No messages available, wait before trying again
-		time.Sleep(100 * time.Millisecond)
-		return
-	}
-
-	// Process the message
-	if err := processWorkflowExecution(ctx, msg, exec, q, nil); err != nil {
-		log.Printf("Failed to process workflow execution %s: %v", msg.RunID, err)
-
-		// Reject message for retry
-		if err := q.Reject(ctx, msg.ID, 5*time.Second); err != nil {
-			log.Printf("Failed to reject message %s: %v", msg.RunID, err)
-		}
-	} else {
-		// Acknowledge successful processing
-		if err := q.Acknowledge(ctx, msg.ID); err != nil {
-			log.Printf("Failed to acknowledge message %s: %v", msg.RunID, err)
-		}
-	}
-}
-
-// processWorkflowExecution processes a workflow execution request
-func processWorkflowExecution(ctx context.Context, msg *queue.Message, exec executor.Executor, q queue.Queue, enhancedMsg *queue.EnhancedMessage) error {
-	// Unmarshal execution request
-	var execReq queue.ExecutionRequest
-	if err := json.Unmarshal(msg.Data, &execReq); err != nil {
-		return fmt.Errorf("failed to unmarshal execution request: %w", err)
-	}
-
-	log.Printf("Processing workflow execution %s for workflow %s", execReq.RunID, execReq.WorkflowID)
-
-	// Try to cast to WorkflowExecutor to use real execution
-	if workflowExecutor, ok := exec.(*executor.WorkflowExecutor); ok {
-		// Use real workflow execution with child-step tracking
-		result, err := workflowExecutor.ExecuteWorkflow(ctx, execReq.WorkflowID, execReq.InputData)
-		if err != nil {
-			// Publish error if using enhanced queue
-			if enhancedQueue, ok := q.(*queue.EnhancedNATSQueue); ok && enhancedMsg != nil {
-				errorResult := queue.ExecutionResult{
-					RunID:       execReq.RunID,
-					WorkflowID:  execReq.WorkflowID,
-					Status:      "failed",
-					Error:       err.Error(),
-					CompletedAt: time.Now(),
-				}
-				errorData, _ := json.Marshal(errorResult)
-				if err := enhancedQueue.PublishError(ctx, execReq.RunID, errorData); err != nil {
-					log.Printf("Failed to publish error for %s: %v", execReq.RunID, err)
-				}
-			}
-			return fmt.Errorf("workflow execution failed: %w", err)
-		}
-
-		// Publish result if using enhanced queue
-		if enhancedQueue, ok := q.(*queue.EnhancedNATSQueue); ok && enhancedMsg != nil {
-			execResult := queue.ExecutionResult{
-				RunID:       result.RunID,
-				WorkflowID:  result.WorkflowID,
-				Status:      result.Status,
-				OutputData:  result.Result,
-				CompletedAt: result.EndTime,
-			}
-			resultData, _ := json.Marshal(execResult)
-			if err := enhancedQueue.PublishResult(ctx, execReq.RunID, resultData); err != nil {
-				log.Printf("Failed to publish result for %s: %v", execReq.RunID, err)
-			}
-		}
-
-		log.Printf("Successfully processed workflow execution %s with real executor, status: %s", result.RunID, result.Status)
-		return nil
-	}
-
-	// Fall back to simulation if not a WorkflowExecutor
-	log.Printf("Using simulated execution (executor is not WorkflowExecutor)")
-	return simulateWorkflowExecution(ctx, execReq)
-}
-
-// simulateWorkflowExecution simulates workflow execution (fallback)
-func simulateWorkflowExecution(ctx context.Context, execReq queue.ExecutionRequest) error {
-	// Simulate processing time
-	time.Sleep(100 * time.Millisecond)
-
-	log.Printf("Simulated workflow execution %s completed", execReq.RunID)
-	return nil
-}
@@ -1,6 +1,9 @@
+//go:build ignore
+
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -11,20 +14,24 @@ import (
 )
 
 func main() {
+	// Every antifraud call takes a context; cancelling it or its deadline expiring aborts the request
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
 	// Example 1: Using global primitive
 	fmt.Println("=== Example 1: Using Global Primitive ===")
-	exampleGlobalPrimitive()
+	exampleGlobalPrimitive(ctx)
 
 	// Example 2: Full transaction validation flow
 	fmt.Println("\n=== Example 2: Full Transaction Validation Flow ===")
-	exampleFullTransactionFlow()
+	exampleFullTransactionFlow(ctx)
 
 	// Example 3: Error handling
 	fmt.Println("\n=== Example 3: Error Handling ===")
-	exampleErrorHandling()
+	exampleErrorHandling(ctx)
 }
 
-func exampleGlobalPrimitive() {
+func exampleGlobalPrimitive(ctx context.Context) {
 	// Initialize primitive with antifraud configuration
 	config := &primitive.Config{
 		AntifraudAPIKey:                  os.Getenv("ANTIFRAUD_API_KEY"),
@@ -55,7 +62,7 @@ func exampleGlobalPrimitive() {
 
 	// Store transaction
 	fmt.Println("Storing transaction...")
-	err = primitive.Default.Antifraud.StoreTransaction(transaction)
+	err = primitive.Default.Antifraud.StoreTransaction(ctx, transaction)
 	if err != nil {
 		fmt.Printf("Failed to store transaction: %v\n", err)
 		return
@@ -64,7 +71,7 @@ func exampleGlobalPrimitive() {
 
 	// Validate with AML service
 	fmt.Println("\nValidating transaction with AML service...")
-	amlResult, err := primitive.Default.Antifraud.ValidateTransactionByAML(transaction)
+	amlResult, err := primitive.Default.Antifraud.ValidateTransactionByAML(ctx, transaction)
 	if err != nil {
 		fmt.Printf("AML validation failed: %v\n", err)
 		return
@@ -72,7 +79,7 @@ func exampleGlobalPrimitive() {
 	fmt.Printf("AML Result: %v\n", amlResult)
 }
 
-func exampleFullTransactionFlow() {
+func exampleFullTransactionFlow(ctx context.Context) {
 	// For this example, we'll use environment variables
 	// In production, you would load these from config
 	apiKey := os.Getenv("ANTIFRAUD_API_KEY")
@@ -108,7 +115,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 1: Store transaction
 	fmt.Println("\n1. Storing transaction...")
-	err = primitive.Default.Antifraud.StoreTransaction(transaction)
+	err = primitive.Default.Antifraud.StoreTransaction(ctx, transaction)
 	if err != nil {
 		fmt.Printf("❌ Failed to store transaction: %v\n", err)
 		return
@@ -117,7 +124,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 2: Validate with AML
 	fmt.Println("\n2. Validating with AML service...")
-	amlResult, err := primitive.Default.Antifraud.ValidateTransactionByAML(transaction)
+	amlResult, err := primitive.Default.Antifraud.ValidateTransactionByAML(ctx, transaction)
 	if err != nil {
 		fmt.Printf("❌ AML validation failed: %v\n", err)
 		return
@@ -126,7 +133,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 3: Store AML resolution
 	fmt.Println("\n3. Storing AML resolution...")
-	err = primitive.Default.Antifraud.StoreServiceResolution(amlResult)
+	err = primitive.Default.Antifraud.StoreServiceResolution(ctx, amlResult)
 	if err != nil {
 		fmt.Printf("❌ Failed to store AML resolution: %v\n", err)
 		return
@@ -135,7 +142,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 4: Add to transaction check
 	fmt.Println("\n4. Adding AML check to transaction...")
-	err = primitive.Default.Antifraud.AddTransactionServiceCheck(amlResult)
+	err = primitive.Default.Antifraud.AddTransactionServiceCheck(ctx, amlResult)
 	if err != nil {
 		fmt.Printf("❌ Failed to add AML check: %v\n", err)
 		return
@@ -144,7 +151,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 5: Validate with FC
 	fmt.Println("\n5. Validating with FC service...")
-	fcResult, err := primitive.Default.Antifraud.ValidateTransactionByFC(transaction)
+	fcResult, err := primitive.Default.Antifraud.ValidateTransactionByFC(ctx, transaction)
 	if err != nil {
 		fmt.Printf("❌ FC validation failed: %v\n", err)
 		return
@@ -153,7 +160,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 6: Store FC resolution
 	fmt.Println("\n6. Storing FC resolution...")
-	err = primitive.Default.Antifraud.StoreServiceResolution(fcResult)
+	err = primitive.Default.Antifraud.StoreServiceResolution(ctx, fcResult)
 	if err != nil {
 		fmt.Printf("❌ Failed to store FC resolution: %v\n", err)
 		return
@@ -162,7 +169,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 7: Add FC check
 	fmt.Println("\n7. Adding FC check to transaction...")
-	err = primitive.Default.Antifraud.AddTransactionServiceCheck(fcResult)
+	err = primitive.Default.Antifraud.AddTransactionServiceCheck(ctx, fcResult)
 	if err != nil {
 		fmt.Printf("❌ Failed to add FC check: %v\n", err)
 		return
@@ -171,7 +178,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 8: Finalize transaction
 	fmt.Println("\n8. Finalizing transaction...")
-	finalResult, err := primitive.Default.Antifraud.FinalizeTransaction(transaction)
+	finalResult, err := primitive.Default.Antifraud.FinalizeTransaction(ctx, transaction)
 	if err != nil {
 		fmt.Printf("❌ Failed to finalize transaction: %v\n", err)
 		return
@@ -180,7 +187,7 @@ func exampleFullTransactionFlow() {
 
 	// Step 9: Store final resolution
 	fmt.Println("\n9. Storing final resolution...")
-	err = primitive.Default.Antifraud.StoreFinalResolution(finalResult)
+	err = primitive.Default.Antifraud.StoreFinalResolution(ctx, finalResult)
 	if err != nil {
 		fmt.Printf("❌ Failed to store final resolution: %v\n", err)
 		return
@@ -190,7 +197,7 @@ func exampleFullTransactionFlow() {
 	fmt.Println("\n🎉 Transaction validation completed successfully!")
 }
 
-func exampleErrorHandling() {
+func exampleErrorHandling(ctx context.Context) {
 	// Initialize with disabled antifraud service
 	config := &primitive.Config{
 		AntifraudEnabled: false,
@@ -206,7 +213,7 @@ func exampleErrorHandling() {
 	transaction := createSampleTransaction()
 
 	fmt.Println("Attempting to use disabled antifraud service...")
-	err = primitive.Default.Antifraud.StoreTransaction(transaction)
+	err = primitive.Default.Antifraud.StoreTransaction(ctx, transaction)
 	if err != nil {
 		fmt.Printf("Expected error (service disabled): %v\n", err)
 	} else {
@@ -215,7 +222,7 @@ func exampleErrorHandling() {
 
 	// Test health check
 	fmt.Println("\nChecking service health...")
-	healthy, err := primitive.Default.Antifraud.HealthCheck()
+	healthy, err := primitive.Default.Antifraud.HealthCheck(ctx)
 	if err != nil {
 		fmt.Printf("Health check error: %v\n", err)
 	} else {
@@ -0,0 +1,352 @@
+// Package admission protects execution ingress from bursts
+// Runs are refused when their client or workflow exceeds its request rate, when too many execution
+// requests wait in the queue, or when the node already has its maximum of runs in flight
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/metrics"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/tenant"
+)
+
+// ErrRejected is returned when admission control refuses a run
+var ErrRejected = errors.New("execution request rejected")
+
+// Reasons reported by a RejectedError
+const (
+	ReasonClientRate   = "client_rate"
+	ReasonWorkflowRate = "workflow_rate"
+	ReasonQueueDepth   = "queue_depth"
+	ReasonSaturated    = "saturated"
+)
+
+// queueDepthTimeout bounds a queue depth sample taken on the request path
+const queueDepthTimeout = 2 * time.Second
+
+// maxIdleBuckets is the number of rate buckets kept before full, idle buckets are dropped
+const maxIdleBuckets = 10000
+
+// RejectedError describes why a run was refused; it matches ErrRejected
+type RejectedError struct {
+	Reason string
+	// Key is the client or workflow whose rate was exceeded
+	Key string
+	// RetryAfter estimates when a run would be admitted
+	RetryAfter time.Duration
+}
+
+func (e *RejectedError) Error() string {
+	switch e.Reason {
+	case ReasonClientRate:
+		return fmt.Sprintf("%s: client %s exceeds its request rate", ErrRejected, e.Key)
+	case ReasonWorkflowRate:
+		return fmt.Sprintf("%s: workflow %s exceeds its request rate", ErrRejected, e.Key)
+	case ReasonQueueDepth:
+		return fmt.Sprintf("%s: execution queue is full", ErrRejected)
+	default:
+		return fmt.Sprintf("%s: executor has its maximum of runs in flight", ErrRejected)
+	}
+}
+
+// Is makes errors.Is(err, ErrRejected) hold
+func (e *RejectedError) Is(target error) bool {
+	return target == ErrRejected
+}
+
+// Controller admits workflow runs on a node
+// A run holds its in-flight slot until Release, or until the in-flight TTL reclaims it when its
+// completion is never reported. Limits are enforced per process
+type Controller struct {
+	mu          sync.Mutex
+	cfg         config.AdmissionConfig
+	maxInFlight int
+	clients     map[string]*bucket
+	workflows   map[workflowKey]*bucket
+	inFlight    map[string]time.Time
+	now         func() time.Time
+
+	queue    queue.Queue
+	depth    int
+	sampled  time.Time
+	sampling bool
+}
+
+// workflowKey names the rate bucket of a workflow of a tenant
+type workflowKey struct {
+	tenant     string
+	workflowID string
+}
+
+// NewController creates admission control for the runs of a node
+// maxInFlight caps the runs admitted and not yet finished; q, when set, is sampled for its depth
+func NewController(cfg config.AdmissionConfig, maxInFlight int, q queue.Queue) *Controller {
+	c := &Controller{
+		clients:   make(map[string]*bucket),
+		workflows: make(map[workflowKey]*bucket),
+		inFlight:  make(map[string]time.Time),
+		now:       time.Now,
+		queue:     q,
+	}
+	c.Apply(cfg, maxInFlight)
+	return c
+}
+
+// Apply replaces the limits; runs already admitted keep their slots
+func (c *Controller) Apply(cfg config.AdmissionConfig, maxInFlight int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg = cfg
+	c.maxInFlight = maxInFlight
+}
+
+// Admit reserves an in-flight slot for runID and takes a token from the rate buckets of its client and workflow
+// The client is the principal of ctx, or its tenant for anonymous callers. It returns a *RejectedError
+// when a limit is reached. A nil Controller admits every run
+func (c *Controller) Admit(ctx context.Context, workflowID, runID string) error {
+	if c == nil {
+		return nil
+	}
+	depth := c.queueDepth(ctx)
+	tenantID := tenant.FromContext(ctx)
+	client := tenantID
+	if principal := auth.PrincipalFromContext(ctx); principal != nil && principal.ID != "" {
+		client = principal.ID
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+
+	if c.maxInFlight > 0 && len(c.inFlight) >= c.maxInFlight {
+		c.reclaim(now)
+		if len(c.inFlight) >= c.maxInFlight {
+			return c.reject(&RejectedError{Reason: ReasonSaturated, RetryAfter: c.cfg.RetryAfter})
+		}
+	}
+	if c.cfg.MaxQueueDepth > 0 && depth >= c.cfg.MaxQueueDepth {
+		return c.reject(&RejectedError{Reason: ReasonQueueDepth, RetryAfter: c.cfg.RetryAfter})
+	}
+
+	clientRate := c.clientRate(client)
+	clientBucket := c.clientBucket(client, clientRate, now)
+	if wait := clientBucket.wait(clientRate, now); wait > 0 {
+		return c.reject(&RejectedError{Reason: ReasonClientRate, Key: client, RetryAfter: wait})
+	}
+	workflowRate := c.workflowRate(workflowID)
+	workflowBucket := c.workflowBucket(workflowKey{tenant: tenantID, workflowID: workflowID}, workflowRate, now)
+	if wait := workflowBucket.wait(workflowRate, now); wait > 0 {
+		return c.reject(&RejectedError{Reason: ReasonWorkflowRate, Key: workflowID, RetryAfter: wait})
+	}
+	clientBucket.take(clientRate)
+	workflowBucket.take(workflowRate)
+
+	c.inFlight[runID] = now
+	metrics.AdmissionRunsInFlight.Set(float64(len(c.inFlight)))
+	return nil
+}
+
+// Release frees the in-flight slot of a run; unknown runs are ignored
+func (c *Controller) Release(runID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.inFlight[runID]; ok {
+		delete(c.inFlight, runID)
+		metrics.AdmissionRunsInFlight.Set(float64(len(c.inFlight)))
+	}
+}
+
+// InFlight returns the number of admitted runs that have not been released
+func (c *Controller) InFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.inFlight)
+}
+
+// reject counts a refused run; callers hold c.mu
+func (c *Controller) reject(err *RejectedError) error {
+	metrics.AdmissionRejections.Inc(err.Reason)
+	return err
+}
+
+// reclaim frees the slots held longer than the in-flight TTL; callers hold c.mu
+func (c *Controller) reclaim(now time.Time) {
+	if c.cfg.InFlightTTL <= 0 {
+		return
+	}
+	for runID, admitted := range c.inFlight {
+		if now.Sub(admitted) > c.cfg.InFlightTTL {
+			delete(c.inFlight, runID)
+		}
+	}
+	metrics.AdmissionRunsInFlight.Set(float64(len(c.inFlight)))
+}
+
+// clientRate returns the rate limit of a client; callers hold c.mu
+func (c *Controller) clientRate(client string) config.RateLimitConfig {
+	if rate, ok := c.cfg.ClientRates[client]; ok {
+		return rate
+	}
+	return c.cfg.ClientRate
+}
+
+// workflowRate returns the rate limit of a workflow; callers hold c.mu
+func (c *Controller) workflowRate(workflowID string) config.RateLimitConfig {
+	if rate, ok := c.cfg.WorkflowRates[workflowID]; ok {
+		return rate
+	}
+	return c.cfg.WorkflowRate
+}
+
+// clientBucket returns the refilled rate bucket of a client; callers hold c.mu
+func (c *Controller) clientBucket(client string, rate config.RateLimitConfig, now time.Time) *bucket {
+	b := c.clients[client]
+	if b == nil {
+		if len(c.clients) >= maxIdleBuckets {
+			pruneBuckets(c.clients, c.clientRate, now)
+		}
+		b = newBucket(rate, now)
+		c.clients[client] = b
+	}
+	b.refill(rate, now)
+	return b
+}
+
+// workflowBucket returns the refilled rate bucket of a workflow; callers hold c.mu
+func (c *Controller) workflowBucket(key workflowKey, rate config.RateLimitConfig, now time.Time) *bucket {
+	b := c.workflows[key]
+	if b == nil {
+		if len(c.workflows) >= maxIdleBuckets {
+			pruneBuckets(c.workflows, func(key workflowKey) config.RateLimitConfig { return c.workflowRate(key.workflowID) }, now)
+		}
+		b = newBucket(rate, now)
+		c.workflows[key] = b
+	}
+	b.refill(rate, now)
+	return b
+}
+
+// queueDepth returns the last sample of the queue depth, refreshing it when it is older than the sample interval
+// Only one caller samples at a time; the others use the previous sample, and a failed sample keeps it
+func (c *Controller) queueDepth(ctx context.Context) int {
+	c.mu.Lock()
+	if c.queue == nil || c.cfg.MaxQueueDepth <= 0 || c.sampling || c.now().Sub(c.sampled) < c.cfg.QueueDepthInterval {
+		depth := c.depth
+		c.mu.Unlock()
+		return depth
+	}
+	c.sampling = true
+	c.mu.Unlock()
+
+	sampleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), queueDepthTimeout)
+	size, err := c.queue.Size(sampleCtx)
+	cancel()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sampling = false
+	c.sampled = c.now()
+	if err != nil {
+		slog.Warn("Failed to sample the execution queue depth", "error", err)
+		return c.depth
+	}
+	c.depth = size
+	return size
+}
+
+// bucket is a token bucket refilled at the requests per second of its rate limit
+type bucket struct {
+	tokens   float64
+	refilled time.Time
+}
+
+// newBucket creates a full bucket
+func newBucket(rate config.RateLimitConfig, now time.Time) *bucket {
+	return &bucket{tokens: float64(burst(rate)), refilled: now}
+}
+
+// refill adds the tokens accrued since the last refill
+func (b *bucket) refill(rate config.RateLimitConfig, now time.Time) {
+	if rate.RequestsPerSecond <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(burst(rate)), b.tokens+now.Sub(b.refilled).Seconds()*rate.RequestsPerSecond)
+	b.refilled = now
+}
+
+// wait returns how long until the bucket holds a token, zero when it holds one or the rate is off
+func (b *bucket) wait(rate config.RateLimitConfig, now time.Time) time.Duration {
+	if rate.RequestsPerSecond <= 0 || b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate.RequestsPerSecond * float64(time.Second))
+}
+
+// take removes a token when the rate is on
+func (b *bucket) take(rate config.RateLimitConfig) {
+	if rate.RequestsPerSecond > 0 {
+		b.tokens--
+	}
+}
+
+// burst returns the bucket size of a rate limit, at least one request
+func burst(rate config.RateLimitConfig) int {
+	if rate.Burst > 0 {
+		return rate.Burst
+	}
+	return int(math.Max(1, math.Ceil(rate.RequestsPerSecond)))
+}
+
+// pruneBuckets drops the buckets that would be full by now, as a new bucket is equivalent
+func pruneBuckets[K comparable](buckets map[K]*bucket, rateOf func(K) config.RateLimitConfig, now time.Time) {
+	for key, b := range buckets {
+		rate := rateOf(key)
+		b.refill(rate, now)
+		if rate.RequestsPerSecond <= 0 || b.tokens >= float64(burst(rate)) {
+			delete(buckets, key)
+		}
+	}
+}
+
+// ConfigOwner applies the admission section and the executor concurrency limit to a running controller
+type ConfigOwner struct {
+	controller *Controller
+}
+
+// NewConfigOwner creates the section owner of a controller
+func NewConfigOwner(controller *Controller) *ConfigOwner {
+	return &ConfigOwner{controller: controller}
+}
+
+// Name implements config.SectionOwner
+func (o *ConfigOwner) Name() string {
+	return "admission"
+}
+
+// Sections implements config.SectionOwner
+func (o *ConfigOwner) Sections() []string {
+	return []string{"admission", "executor"}
+}
+
+// ValidateConfig implements config.SectionOwner
+func (o *ConfigOwner) ValidateConfig(cfg *config.Config) error {
+	return nil
+}
+
+// ApplyConfig implements config.SectionOwner
+func (o *ConfigOwner) ApplyConfig(oldConfig, newConfig *config.Config) error {
+	o.controller.Apply(newConfig.Admission, newConfig.Executor.MaxConcurrentWorkflows)
+	return nil
+}
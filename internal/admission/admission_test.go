@@ -0,0 +1,152 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/tenant"
+)
+
+func newTestController(cfg config.AdmissionConfig, maxInFlight int, q queue.Queue) (*Controller, *time.Time) {
+	c := NewController(cfg, maxInFlight, q)
+	now := time.Unix(1700000000, 0)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func asClient(id string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{ID: id})
+}
+
+func wantRejected(t *testing.T, err error, reason string) *RejectedError {
+	t.Helper()
+	var rejected *RejectedError
+	if !errors.Is(err, ErrRejected) || !errors.As(err, &rejected) || rejected.Reason != reason {
+		t.Fatalf("Admit() error = %v, want %s rejection", err, reason)
+	}
+	return rejected
+}
+
+func TestClientRateIsPerClient(t *testing.T) {
+	c, now := newTestController(config.AdmissionConfig{
+		ClientRate:  config.RateLimitConfig{RequestsPerSecond: 1},
+		ClientRates: map[string]config.RateLimitConfig{"bulk-loader": {RequestsPerSecond: 10, Burst: 3}},
+	}, 0, nil)
+
+	if err := c.Admit(asClient("checkout"), "payment", "run-1"); err != nil {
+		t.Fatal(err)
+	}
+	rejected := wantRejected(t, c.Admit(asClient("checkout"), "payment", "run-2"), ReasonClientRate)
+	if rejected.Key != "checkout" || rejected.RetryAfter != time.Second {
+		t.Errorf("rejection = %+v, want checkout retrying after 1s", rejected)
+	}
+	for i, runID := range []string{"run-3", "run-4", "run-5"} {
+		if err := c.Admit(asClient("bulk-loader"), "payment", runID); err != nil {
+			t.Fatalf("bulk-loader Admit(%d) error = %v", i, err)
+		}
+	}
+	wantRejected(t, c.Admit(asClient("bulk-loader"), "payment", "run-6"), ReasonClientRate)
+
+	*now = now.Add(time.Second)
+	if err := c.Admit(asClient("checkout"), "payment", "run-2"); err != nil {
+		t.Errorf("Admit() after refill error = %v", err)
+	}
+}
+
+func TestAnonymousClientsShareTenantRate(t *testing.T) {
+	c, _ := newTestController(config.AdmissionConfig{ClientRate: config.RateLimitConfig{RequestsPerSecond: 1}}, 0, nil)
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	if err := c.Admit(acme, "payment", "run-1"); err != nil {
+		t.Fatal(err)
+	}
+	wantRejected(t, c.Admit(acme, "refund", "run-2"), ReasonClientRate)
+	if err := c.Admit(context.Background(), "payment", "run-3"); err != nil {
+		t.Errorf("default tenant Admit() error = %v", err)
+	}
+}
+
+func TestWorkflowRateDoesNotSpendClientTokens(t *testing.T) {
+	c, _ := newTestController(config.AdmissionConfig{
+		ClientRate:   config.RateLimitConfig{RequestsPerSecond: 2},
+		WorkflowRate: config.RateLimitConfig{RequestsPerSecond: 1},
+	}, 0, nil)
+
+	if err := c.Admit(asClient("checkout"), "payment", "run-1"); err != nil {
+		t.Fatal(err)
+	}
+	wantRejected(t, c.Admit(asClient("checkout"), "payment", "run-2"), ReasonWorkflowRate)
+	if err := c.Admit(asClient("checkout"), "refund", "run-3"); err != nil {
+		t.Errorf("Admit() of another workflow error = %v, want the refused run to keep the client token", err)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	c, now := newTestController(config.AdmissionConfig{RetryAfter: 2 * time.Second, InFlightTTL: time.Minute}, 2, nil)
+	ctx := context.Background()
+
+	for _, runID := range []string{"run-1", "run-2"} {
+		if err := c.Admit(ctx, "payment", runID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rejected := wantRejected(t, c.Admit(ctx, "payment", "run-3"), ReasonSaturated)
+	if rejected.RetryAfter != 2*time.Second {
+		t.Errorf("RetryAfter = %v, want 2s", rejected.RetryAfter)
+	}
+
+	c.Release("run-1")
+	if err := c.Admit(ctx, "payment", "run-3"); err != nil {
+		t.Fatalf("Admit() after Release error = %v", err)
+	}
+	wantRejected(t, c.Admit(ctx, "payment", "run-4"), ReasonSaturated)
+
+	*now = now.Add(2 * time.Minute)
+	if err := c.Admit(ctx, "payment", "run-4"); err != nil {
+		t.Errorf("Admit() after in-flight TTL error = %v", err)
+	}
+	if got := c.InFlight(); got != 1 {
+		t.Errorf("InFlight() = %d, want 1", got)
+	}
+}
+
+func TestMaxQueueDepth(t *testing.T) {
+	ctx := context.Background()
+	q := queue.NewInMemoryQueue()
+	c, now := newTestController(config.AdmissionConfig{MaxQueueDepth: 2, QueueDepthInterval: time.Second}, 0, q)
+
+	for _, runID := range []string{"queued-1", "queued-2"} {
+		if err := q.Enqueue(ctx, runID, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantRejected(t, c.Admit(ctx, "payment", "run-1"), ReasonQueueDepth)
+
+	if _, err := q.Dequeue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantRejected(t, c.Admit(ctx, "payment", "run-1"), ReasonQueueDepth)
+	*now = now.Add(time.Second)
+	if err := c.Admit(ctx, "payment", "run-1"); err != nil {
+		t.Errorf("Admit() after a new depth sample error = %v", err)
+	}
+}
+
+func TestConfigOwnerAppliesExecutorLimit(t *testing.T) {
+	c, _ := newTestController(config.AdmissionConfig{}, 0, nil)
+	cfg := config.DefaultConfig()
+	cfg.Executor.MaxConcurrentWorkflows = 1
+	if err := NewConfigOwner(c).ApplyConfig(nil, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Admit(context.Background(), "payment", "run-1"); err != nil {
+		t.Fatal(err)
+	}
+	wantRejected(t, c.Admit(context.Background(), "payment", "run-2"), ReasonSaturated)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"unified-workflow/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles administrative API requests
+type AdminHandler struct {
+	reloadManager *config.ReloadManager
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(reloadManager *config.ReloadManager) *AdminHandler {
+	return &AdminHandler{
+		reloadManager: reloadManager,
+	}
+}
+
+// GetConfig returns the effective configuration (secrets redacted), its source and the last reload outcome
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, ConfigResponse{
+		Config:     h.reloadManager.Current().Redacted(),
+		Source:     h.reloadManager.Source(),
+		Owners:     h.reloadManager.Owners(),
+		LastReload: h.reloadManager.LastReload(),
+	})
+}
@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/registry"
+	"unified-workflow/workflows/steps"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefinitionHandler handles workflow definition API requests
+type DefinitionHandler struct {
+	registry registry.Registry
+}
+
+// NewDefinitionHandler creates a new workflow definition handler
+func NewDefinitionHandler(registry registry.Registry) *DefinitionHandler {
+	return &DefinitionHandler{
+		registry: registry,
+	}
+}
+
+// CreateWorkflowRequest is the body of POST /workflows
+type CreateWorkflowRequest struct {
+	Name        string               `json:"name" binding:"required,max=200"`
+	Description string               `json:"description" binding:"max=2000"`
+	Steps       []CreateWorkflowStep `json:"steps" binding:"omitempty,dive"`
+	// TimeoutMs bounds each run of the workflow; zero applies the execution timeout of the executor
+	TimeoutMs int64 `json:"timeout_ms,omitempty" binding:"min=0"`
+}
+
+// CreateWorkflowStep describes a step of a workflow created through the API
+type CreateWorkflowStep struct {
+	Type string `json:"type" binding:"required,oneof=sequential echo branch subworkflow foreach wait timer"`
+	Name string `json:"name"`
+	// When is a condition over the workflow data; the step is skipped when it does not hold
+	When string `json:"when,omitempty" binding:"max=4096"`
+	// Branches are the cases of a branch step; the first whose condition holds runs
+	Branches []CreateWorkflowBranch `json:"branches,omitempty" binding:"omitempty,dive"`
+	// Default holds the steps a branch step runs when no case holds
+	Default []CreateWorkflowStep `json:"default,omitempty" binding:"omitempty,dive"`
+	// DependsOn names the top-level steps that must finish first; the workflow then runs as a graph
+	DependsOn []string `json:"depends_on,omitempty" binding:"omitempty,max=100,dive,required,max=200"`
+	// Workflow is the ID of the registered workflow a subworkflow step runs, or a foreach step runs per item
+	Workflow string `json:"workflow,omitempty" binding:"max=200"`
+	// Version pins the version of the workflow a subworkflow or foreach step runs; zero runs the latest
+	Version int `json:"version,omitempty" binding:"min=0"`
+	// Input maps keys of the child run input to expressions over the data of the parent
+	Input map[string]string `json:"input,omitempty" binding:"omitempty,max=100,dive,max=4096"`
+	// Output maps keys of the parent data to expressions over the result of the child run
+	Output map[string]string `json:"output,omitempty" binding:"omitempty,max=100,dive,max=4096"`
+	// Items is the workflow data key of the list a foreach step iterates
+	Items string `json:"items,omitempty" binding:"max=200"`
+	// ItemKey is the data key each item is bound to; "item" when empty
+	ItemKey string `json:"item_key,omitempty" binding:"max=200"`
+	// Into is the data key the ordered results of a foreach step are stored under
+	Into string `json:"into,omitempty" binding:"max=200"`
+	// Concurrency is the number of items a foreach step runs at once
+	Concurrency int `json:"concurrency,omitempty" binding:"min=0,max=100"`
+	// OnError is the error policy of a foreach step; fail_fast when empty
+	OnError string `json:"on_error,omitempty" binding:"omitempty,oneof=fail_fast collect threshold"`
+	// MaxFailures is the number of failed items the threshold policy tolerates
+	MaxFailures int `json:"max_failures,omitempty" binding:"min=0"`
+	// Signal is the name of the signal a wait step waits for
+	Signal string `json:"signal,omitempty" binding:"max=200"`
+	// TimeoutMs bounds the wait of a wait step, zero waiting until the signal arrives, or each execution
+	// of a sequential or echo step, zero applying the step timeout of the executor
+	TimeoutMs int64 `json:"timeout_ms,omitempty" binding:"min=0"`
+	// OnTimeout holds the steps a wait step runs when its timeout expires
+	OnTimeout []CreateWorkflowStep `json:"on_timeout,omitempty" binding:"omitempty,dive"`
+	// DelayMs is how long a timer step pauses the run
+	DelayMs int64 `json:"delay_ms,omitempty" binding:"min=0"`
+}
+
+// CreateWorkflowBranch is a case of a branch step
+type CreateWorkflowBranch struct {
+	Name  string               `json:"name" binding:"required,max=200"`
+	When  string               `json:"when" binding:"required,max=4096"`
+	Steps []CreateWorkflowStep `json:"steps" binding:"omitempty,dive"`
+}
+
+// maxBranchDepth bounds the nesting of branch steps in a workflow created through the API
+const maxBranchDepth = 8
+
+// UpdateWorkflowRequest is the body of PUT /workflows/:id
+type UpdateWorkflowRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=2000"`
+}
+
+// ListWorkflows lists registered workflows, optionally filtered by name and description
+func (h *DefinitionHandler) ListWorkflows(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit, ok := intQuery(c, "limit", 0, 0, 1000)
+	if !ok {
+		return
+	}
+	offset, ok := intQuery(c, "offset", 0, 0, 0)
+	if !ok {
+		return
+	}
+	nameFilter := c.Query("name")
+	descriptionFilter := c.Query("description")
+
+	// Get all workflow IDs
+	workflowIDs, err := h.registry.GetAllWorkflowIDs(ctx)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "Failed to list workflows", err)
+		return
+	}
+
+	// Get each workflow
+	workflows := make([]WorkflowSummary, 0, len(workflowIDs))
+	for _, workflowID := range workflowIDs {
+		workflow, err := h.registry.GetWorkflow(ctx, workflowID)
+		if err != nil {
+			// Skip workflows that can't be retrieved
+			continue
+		}
+
+		// Apply filters
+		if nameFilter != "" && workflow.GetName() != nameFilter {
+			continue
+		}
+		if descriptionFilter != "" && workflow.GetDescription() != descriptionFilter {
+			continue
+		}
+
+		workflows = append(workflows, WorkflowSummary{
+			ID:          workflow.GetID(),
+			Name:        workflow.GetName(),
+			Description: workflow.GetDescription(),
+			StepCount:   workflow.GetStepCount(),
+		})
+	}
+
+	// Apply pagination
+	filteredCount := len(workflows)
+	if offset > len(workflows) {
+		offset = len(workflows)
+	}
+	workflows = workflows[offset:]
+	if limit > 0 && limit < len(workflows) {
+		workflows = workflows[:limit]
+	}
+
+	c.JSON(http.StatusOK, WorkflowListResponse{
+		Workflows:     workflows,
+		Count:         len(workflows),
+		TotalCount:    len(workflowIDs),
+		FilteredCount: filteredCount,
+	})
+}
+
+// GetWorkflow gets a specific workflow by ID
+func (h *DefinitionHandler) GetWorkflow(c *gin.Context) {
+	ctx := c.Request.Context()
+	workflowID := c.Param("id")
+
+	workflow, err := h.registry.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, CodeWorkflowNotFound, "Workflow not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WorkflowResponse{
+		ID:          workflow.GetID(),
+		Name:        workflow.GetName(),
+		Description: workflow.GetDescription(),
+		StepCount:   workflow.GetStepCount(),
+		TimeoutMs:   workflowTimeout(workflow).Milliseconds(),
+		Steps:       stepSummaries(workflow.GetSteps()),
+	})
+}
+
+// workflowTimeout returns the run timeout a workflow declares, zero when the executor's applies
+func workflowTimeout(workflow model.Workflow) time.Duration {
+	if timed, ok := workflow.(model.TimedWorkflow); ok {
+		return timed.GetTimeout()
+	}
+	return 0
+}
+
+// stepSummaries describes steps with their conditions and branches
+func stepSummaries(workflowSteps []model.Step) []StepSummary {
+	summaries := make([]StepSummary, 0, len(workflowSteps))
+	for _, step := range workflowSteps {
+		summary := StepSummary{
+			Name:           step.GetName(),
+			ChildStepCount: step.GetChildStepCount(),
+			IsParallel:     step.IsParallel(),
+		}
+		if conditional, ok := step.(model.ConditionalStep); ok && conditional.GetCondition() != nil {
+			summary.When = conditional.GetCondition().String()
+		}
+		if dependent, ok := step.(model.DependentStep); ok {
+			summary.DependsOn = dependent.GetDependsOn()
+		}
+		if subWorkflow, ok := step.(*model.SubWorkflowStep); ok {
+			summary.Workflow = subWorkflow.WorkflowID
+			summary.Version = subWorkflow.Version
+		}
+		if forEach, ok := step.(*model.ForEachStep); ok {
+			summary.Items = forEach.Items
+			summary.Concurrency = max(forEach.Concurrency, 1)
+			if forEach.SubWorkflow != nil {
+				summary.Workflow = forEach.SubWorkflow.WorkflowID
+				summary.Version = forEach.SubWorkflow.Version
+			}
+		}
+		if timed, ok := step.(model.TimedStep); ok {
+			summary.TimeoutMs = timed.GetTimeout().Milliseconds()
+		}
+		if wait, ok := step.(*model.WaitForSignalStep); ok {
+			summary.Signal = wait.Signal
+			summary.TimeoutMs = wait.Timeout.Milliseconds()
+		}
+		if timer, ok := step.(*model.TimerStep); ok {
+			summary.DelayMs = timer.Duration.Milliseconds()
+		}
+		if branchStep, ok := step.(model.BranchingStep); ok {
+			for _, branch := range branchStep.AllBranches() {
+				branchSummary := BranchSummary{Name: branch.Name, Steps: stepSummaries(branch.Steps)}
+				if branch.Condition != nil {
+					branchSummary.When = branch.Condition.String()
+				}
+				summary.Branches = append(summary.Branches, branchSummary)
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// CreateWorkflow creates a new workflow
+func (h *DefinitionHandler) CreateWorkflow(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request CreateWorkflowRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	// Create workflow using the common model
+	workflowSteps, err := buildSteps(request.Steps, 0)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid workflow steps", err)
+		return
+	}
+	workflow := model.NewBaseWorkflow(request.Name, request.Description)
+	workflow.AddSteps(workflowSteps)
+	workflow.SetTimeout(time.Duration(request.TimeoutMs) * time.Millisecond)
+	if err := model.ValidateWorkflow(workflow); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid workflow steps", err)
+		return
+	}
+
+	// Register workflow
+	if err := h.registry.RegisterWorkflow(ctx, workflow); err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "Failed to create workflow", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, WorkflowChangedResponse{
+		ID:          workflow.GetID(),
+		Name:        workflow.GetName(),
+		Description: workflow.GetDescription(),
+		Message:     "Workflow created successfully",
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	})
+}
+
+// buildSteps creates the steps of a workflow definition, compiling their conditions
+func buildSteps(requests []CreateWorkflowStep, depth int) ([]model.Step, error) {
+	if depth > maxBranchDepth {
+		return nil, fmt.Errorf("branch steps are nested deeper than %d levels", maxBranchDepth)
+	}
+
+	built := make([]model.Step, 0, len(requests))
+	for _, stepRequest := range requests {
+		stepName := stepRequest.Name
+		if stepName == "" {
+			stepName = stepRequest.Type + "-step"
+		}
+		if stepRequest.Type != "branch" && (len(stepRequest.Branches) > 0 || len(stepRequest.Default) > 0) {
+			return nil, fmt.Errorf("step %s: only branch steps have branches", stepName)
+		}
+		if stepRequest.Type != "subworkflow" && stepRequest.Type != "foreach" && (stepRequest.Workflow != "" ||
+			stepRequest.Version != 0 || len(stepRequest.Input) > 0 || len(stepRequest.Output) > 0) {
+			return nil, fmt.Errorf("step %s: only subworkflow and foreach steps have a workflow, a version, an input or an output", stepName)
+		}
+		if stepRequest.Type != "foreach" && (stepRequest.Items != "" || stepRequest.ItemKey != "" || stepRequest.Into != "" ||
+			stepRequest.Concurrency != 0 || stepRequest.OnError != "" || stepRequest.MaxFailures != 0) {
+			return nil, fmt.Errorf("step %s: only foreach steps have items, an item key, a target, a concurrency or an error policy", stepName)
+		}
+		if stepRequest.Type != "wait" && (stepRequest.Signal != "" || len(stepRequest.OnTimeout) > 0) {
+			return nil, fmt.Errorf("step %s: only wait steps have a signal or timeout steps", stepName)
+		}
+		if stepRequest.Type != "wait" && stepRequest.Type != "sequential" && stepRequest.Type != "echo" && stepRequest.TimeoutMs != 0 {
+			return nil, fmt.Errorf("step %s: only wait, sequential and echo steps have a timeout", stepName)
+		}
+		if stepRequest.Type != "timer" && stepRequest.DelayMs != 0 {
+			return nil, fmt.Errorf("step %s: only timer steps have a delay", stepName)
+		}
+
+		var step model.ConditionalStep
+		switch stepRequest.Type {
+		case "echo":
+			step = steps.NewEchoStep(stepName, "Echo step created via API")
+		case "branch":
+			branchStep, err := buildBranchStep(stepName, stepRequest, depth)
+			if err != nil {
+				return nil, err
+			}
+			step = branchStep
+		case "subworkflow":
+			subWorkflowStep, err := buildSubWorkflowStep(stepName, stepRequest)
+			if err != nil {
+				return nil, err
+			}
+			step = subWorkflowStep
+		case "foreach":
+			forEachStep, err := buildForEachStep(stepName, stepRequest)
+			if err != nil {
+				return nil, err
+			}
+			step = forEachStep
+		case "wait":
+			waitStep, err := buildWaitStep(stepName, stepRequest, depth)
+			if err != nil {
+				return nil, err
+			}
+			step = waitStep
+		case "timer":
+			timerStep := model.NewTimerStep(stepName, time.Duration(stepRequest.DelayMs)*time.Millisecond)
+			if err := timerStep.Validate(); err != nil {
+				return nil, err
+			}
+			step = timerStep
+		default:
+			step = model.NewSequentialStep(stepName)
+		}
+
+		if stepRequest.When != "" {
+			condition, err := model.ParseCondition(stepRequest.When)
+			if err != nil {
+				return nil, fmt.Errorf("step %s: when: %w", stepName, err)
+			}
+			step.SetCondition(condition)
+		}
+		if timed, ok := step.(model.TimedStep); ok && stepRequest.Type != "wait" && stepRequest.TimeoutMs > 0 {
+			timed.SetTimeout(time.Duration(stepRequest.TimeoutMs) * time.Millisecond)
+		}
+		if len(stepRequest.DependsOn) > 0 {
+			dependent, ok := step.(model.DependentStep)
+			if !ok {
+				return nil, fmt.Errorf("step %s: %s steps cannot declare dependencies", stepName, stepRequest.Type)
+			}
+			dependent.SetDependsOn(stepRequest.DependsOn...)
+		}
+		built = append(built, step)
+	}
+	return built, nil
+}
+
+// buildBranchStep creates a branch step from its cases and default steps
+func buildBranchStep(name string, request CreateWorkflowStep, depth int) (*model.BranchStep, error) {
+	if len(request.Branches) == 0 {
+		return nil, fmt.Errorf("step %s: a branch step needs at least one branch", name)
+	}
+	cases := make([]model.Branch, 0, len(request.Branches))
+	for _, branchRequest := range request.Branches {
+		condition, err := model.ParseCondition(branchRequest.When)
+		if err != nil {
+			return nil, fmt.Errorf("step %s: branch %s: when: %w", name, branchRequest.Name, err)
+		}
+		branchSteps, err := buildSteps(branchRequest.Steps, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, model.Branch{Name: branchRequest.Name, Condition: condition, Steps: branchSteps})
+	}
+	defaultSteps, err := buildSteps(request.Default, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewSwitchStep(name, cases, defaultSteps), nil
+}
+
+// buildSubWorkflowStep creates a sub-workflow step, compiling its input and output bindings
+// The workflow it runs is resolved when the step runs, so it may be registered later
+func buildSubWorkflowStep(name string, request CreateWorkflowStep) (*model.SubWorkflowStep, error) {
+	if request.Workflow == "" {
+		return nil, fmt.Errorf("step %s: a subworkflow step needs a workflow", name)
+	}
+	step := model.NewSubWorkflowStep(name, request.Workflow).PinVersion(request.Version)
+	var err error
+	if step.Input, err = model.ParseBindings(request.Input); err != nil {
+		return nil, fmt.Errorf("step %s: input: %w", name, err)
+	}
+	if step.Output, err = model.ParseBindings(request.Output); err != nil {
+		return nil, fmt.Errorf("step %s: output: %w", name, err)
+	}
+	return step, nil
+}
+
+// buildForEachStep creates a foreach step running a sub-workflow for each item of a list
+func buildForEachStep(name string, request CreateWorkflowStep) (*model.ForEachStep, error) {
+	if request.Items == "" {
+		return nil, fmt.Errorf("step %s: a foreach step needs items", name)
+	}
+	if request.Workflow == "" {
+		return nil, fmt.Errorf("step %s: a foreach step needs a workflow to run for each item", name)
+	}
+	subWorkflowStep, err := buildSubWorkflowStep(name, request)
+	if err != nil {
+		return nil, err
+	}
+	step := model.NewForEachSubWorkflow(name, request.Items, subWorkflowStep).
+		WithConcurrency(request.Concurrency).
+		OnItemError(model.ErrorPolicy(request.OnError), request.MaxFailures).
+		StoreInto(request.Into)
+	step.ItemKey = request.ItemKey
+	if err := step.Validate(); err != nil {
+		return nil, err
+	}
+	return step, nil
+}
+
+// buildWaitStep creates a wait step with the steps it runs when its timeout expires
+func buildWaitStep(name string, request CreateWorkflowStep, depth int) (*model.WaitForSignalStep, error) {
+	onTimeout, err := buildSteps(request.OnTimeout, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	step := model.NewWaitForSignalStep(name, request.Signal).
+		WithTimeout(time.Duration(request.TimeoutMs)*time.Millisecond, onTimeout...)
+	if err := step.Validate(); err != nil {
+		return nil, err
+	}
+	return step, nil
+}
+
+// UpdateWorkflow validates an update of a workflow definition
+func (h *DefinitionHandler) UpdateWorkflow(c *gin.Context) {
+	ctx := c.Request.Context()
+	workflowID := c.Param("id")
+
+	var request UpdateWorkflowRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	// Get existing workflow
+	workflow, err := h.registry.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, CodeWorkflowNotFound, "Workflow not found", err)
+		return
+	}
+
+	// TODO: Implement update logic
+	c.JSON(http.StatusOK, WorkflowChangedResponse{
+		ID:          workflow.GetID(),
+		Name:        workflow.GetName(),
+		Description: workflow.GetDescription(),
+		Message:     "Workflow update endpoint (implementation pending)",
+		UpdatedAt:   time.Now().Format(time.RFC3339),
+	})
+}
+
+// DeleteWorkflow deletes a workflow
+func (h *DefinitionHandler) DeleteWorkflow(c *gin.Context) {
+	ctx := c.Request.Context()
+	workflowID := c.Param("id")
+
+	if err := h.registry.RemoveWorkflow(ctx, workflowID); err != nil {
+		respondError(c, http.StatusNotFound, CodeWorkflowNotFound, "Failed to delete workflow", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WorkflowDeletedResponse{
+		Message:   "Workflow deleted successfully",
+		DeletedID: workflowID,
+		DeletedAt: time.Now().Format(time.RFC3339),
+	})
+}
+
+// WorkflowExists reports whether a workflow is registered
+func (h *DefinitionHandler) WorkflowExists(c *gin.Context) {
+	ctx := c.Request.Context()
+	workflowID := c.Param("id")
+
+	_, err := h.registry.GetWorkflow(ctx, workflowID)
+	c.JSON(http.StatusOK, WorkflowExistsResponse{
+		Exists: err == nil,
+		ID:     workflowID,
+	})
+}
+
+// CountWorkflows returns the number of registered workflows
+func (h *DefinitionHandler) CountWorkflows(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	workflowIDs, err := h.registry.GetAllWorkflowIDs(ctx)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "Failed to get workflow count", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CountResponse{
+		Count: len(workflowIDs),
+	})
+}
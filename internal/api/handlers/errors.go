@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"unified-workflow/internal/admission"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Error codes returned in the "code" field of every error response
+const (
+	CodeInvalidRequest      = "INVALID_REQUEST"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeInvalidCallback     = "INVALID_CALLBACK"
+	CodeNotFound            = "NOT_FOUND"
+	CodeWorkflowNotFound    = "WORKFLOW_NOT_FOUND"
+	CodeExecutionNotFound   = "EXECUTION_NOT_FOUND"
+	CodeExecutionFailed     = "EXECUTION_FAILED"
+	CodeExecutionFinished   = "EXECUTION_FINISHED"
+	CodeExecutionNotWaiting = "EXECUTION_NOT_WAITING"
+	CodeQuotaExceeded       = "QUOTA_EXCEEDED"
+	CodeRateLimited         = "RATE_LIMITED"
+	CodeInternal            = "INTERNAL_ERROR"
+)
+
+// ErrorResponse is the error envelope shared by all API endpoints
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+	Details string `json:"details,omitempty"`
+	RunID   string `json:"run_id,omitempty"`
+}
+
+// respondError writes the error envelope; err may be nil
+func respondError(c *gin.Context, status int, code, message string, err error) {
+	response := ErrorResponse{Error: message, Code: code}
+	if err != nil {
+		response.Details = err.Error()
+	}
+	c.AbortWithStatusJSON(status, response)
+}
+
+// respondRunError writes the error envelope for a failure concerning an existing run
+func respondRunError(c *gin.Context, status int, code, message string, err error, runID string) {
+	response := ErrorResponse{Error: message, Code: code, RunID: runID}
+	if err != nil {
+		response.Details = err.Error()
+	}
+	c.AbortWithStatusJSON(status, response)
+}
+
+// respondExecutionError maps an error loading a run to 404 when the run does not exist
+func respondExecutionError(c *gin.Context, message string, err error) {
+	if errors.Is(err, state.ErrStateNotFound) {
+		respondError(c, http.StatusNotFound, CodeExecutionNotFound, "Execution not found", err)
+		return
+	}
+	respondError(c, http.StatusInternalServerError, CodeInternal, message, err)
+}
+
+// respondSubmitError maps an error starting a run to 429 when admission control or the tenant quota refused it
+func respondSubmitError(c *gin.Context, message string, err error, runID string) {
+	var rejected *admission.RejectedError
+	if errors.As(err, &rejected) {
+		respondTooManyRequests(c, CodeRateLimited, "Too many execution requests", rejected.RetryAfter, err)
+		return
+	}
+	var quotaErr *tenant.QuotaError
+	if errors.As(err, &quotaErr) {
+		respondTooManyRequests(c, CodeQuotaExceeded, "Tenant quota exceeded", quotaErr.RetryAfter, err)
+		return
+	}
+	respondRunError(c, http.StatusInternalServerError, CodeExecutionFailed, message, err, runID)
+}
+
+// respondTooManyRequests writes a 429 error envelope with Retry-After in whole seconds when retryAfter is known
+func respondTooManyRequests(c *gin.Context, code, message string, retryAfter time.Duration, err error) {
+	if retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	respondError(c, http.StatusTooManyRequests, code, message, err)
+}
+
+// bindJSON decodes and validates the request body, writing the error envelope on failure
+// An empty body is accepted for requests whose fields are all optional
+func bindJSON(c *gin.Context, request interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		if err := binding.Validator.ValidateStruct(request); err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body", err)
+			return false
+		}
+		return true
+	}
+	if err := c.ShouldBindJSON(request); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body", err)
+		return false
+	}
+	return true
+}
+
+// intParam parses a non-negative integer path parameter, writing the error envelope on failure
+func intParam(c *gin.Context, name string) (int, bool) {
+	value, err := strconv.Atoi(c.Param(name))
+	if err != nil || value < 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid "+name, errors.New(name+" must be a non-negative integer"))
+		return 0, false
+	}
+	return value, true
+}
+
+// intQuery parses an integer query parameter of at least min and at most max (unbounded if zero),
+// falling back to def when absent
+func intQuery(c *gin.Context, name string, def, min, max int) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || (max > 0 && value > max) {
+		reason := fmt.Errorf("%s must be an integer of at least %d", name, min)
+		if max > 0 {
+			reason = fmt.Errorf("%s must be an integer between %d and %d", name, min, max)
+		}
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid query parameter "+name, reason)
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"time"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/executor"
+)
+
+// WorkflowSummary describes a workflow in a listing
+type WorkflowSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	StepCount   int    `json:"step_count"`
+}
+
+// WorkflowListResponse is the body of GET /workflows
+type WorkflowListResponse struct {
+	Workflows     []WorkflowSummary `json:"workflows"`
+	Count         int               `json:"count"`
+	TotalCount    int               `json:"total_count"`
+	FilteredCount int               `json:"filtered_count"`
+}
+
+// StepSummary describes a step of a workflow definition
+type StepSummary struct {
+	Name           string `json:"name"`
+	ChildStepCount int    `json:"child_step_count"`
+	IsParallel     bool   `json:"is_parallel"`
+	// When is the condition guarding the step
+	When string `json:"when,omitempty"`
+	// Branches are the branches of a branch step, the default last
+	Branches []BranchSummary `json:"branches,omitempty"`
+	// DependsOn names the steps the step waits for in a graph workflow
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Workflow and Version identify the workflow a sub-workflow or foreach step runs
+	Workflow string `json:"workflow,omitempty"`
+	Version  int    `json:"version,omitempty"`
+	// Items and Concurrency are the list a foreach step iterates and the number of items it runs at once
+	Items       string `json:"items,omitempty"`
+	Concurrency int    `json:"concurrency,omitempty"`
+	// Signal is the signal a wait step waits for
+	Signal string `json:"signal,omitempty"`
+	// TimeoutMs is how long a wait step waits, or how long a step may execute when it has its own timeout
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+	// DelayMs is how long a timer step pauses its run
+	DelayMs int64 `json:"delay_ms,omitempty"`
+}
+
+// BranchSummary describes a branch of a branch step, or the timeout branch of a wait step
+type BranchSummary struct {
+	Name  string        `json:"name"`
+	When  string        `json:"when,omitempty"`
+	Steps []StepSummary `json:"steps"`
+}
+
+// WorkflowResponse is the body of GET /workflows/:id
+type WorkflowResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	StepCount   int    `json:"step_count"`
+	// TimeoutMs is the run timeout of the workflow, omitted when the execution timeout of the executor applies
+	TimeoutMs int64         `json:"timeout_ms,omitempty"`
+	Steps     []StepSummary `json:"steps"`
+}
+
+// WorkflowChangedResponse is the body of POST /workflows and PUT /workflows/:id
+type WorkflowChangedResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Message     string `json:"message"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// WorkflowDeletedResponse is the body of DELETE /workflows/:id
+type WorkflowDeletedResponse struct {
+	Message   string `json:"message"`
+	DeletedID string `json:"deleted_id"`
+	DeletedAt string `json:"deleted_at"`
+}
+
+// WorkflowExistsResponse is the body of GET /workflows/:id/exists
+type WorkflowExistsResponse struct {
+	Exists bool   `json:"exists"`
+	ID     string `json:"id"`
+}
+
+// CountResponse is the body of GET /workflows/count
+type CountResponse struct {
+	Count int `json:"count"`
+}
+
+// SyncExecutionResponse is the body of a run that finished within timeout_ms
+type SyncExecutionResponse struct {
+	RunID      string                         `json:"run_id"`
+	WorkflowID string                         `json:"workflow_id"`
+	Status     string                         `json:"status"`
+	Mode       string                         `json:"mode"`
+	Result     map[string]interface{}         `json:"result"`
+	Steps      []executor.StepExecutionResult `json:"steps"`
+	Error      string                         `json:"error,omitempty"`
+	StartTime  time.Time                      `json:"start_time"`
+	EndTime    time.Time                      `json:"end_time"`
+	DurationMs int64                          `json:"duration_ms"`
+
+	// CompensationStatus and Compensations report the undoing of the completed steps of a failed or cancelled run
+	CompensationStatus string                        `json:"compensation_status,omitempty"`
+	Compensations      []executor.CompensationResult `json:"compensations,omitempty"`
+}
+
+// ExecutionAcceptedResponse is the body of a run that continues asynchronously
+type ExecutionAcceptedResponse struct {
+	RunID                 string `json:"run_id"`
+	WorkflowID            string `json:"workflow_id,omitempty"`
+	Status                string `json:"status"`
+	Mode                  string `json:"mode,omitempty"`
+	Message               string `json:"message"`
+	StatusURL             string `json:"status_url"`
+	ResultURL             string `json:"result_url"`
+	PollAfterMs           int    `json:"poll_after_ms"`
+	EstimatedCompletionMs int    `json:"estimated_completion_ms,omitempty"`
+	ExpiresAt             string `json:"expires_at,omitempty"`
+}
+
+// ExecutionPendingResponse is the body of GET /executions/:runId/result for an unfinished run
+type ExecutionPendingResponse struct {
+	RunID                 string  `json:"run_id"`
+	Status                string  `json:"status"`
+	PollAfterMs           int     `json:"poll_after_ms"`
+	EstimatedCompletionMs int     `json:"estimated_completion_ms"`
+	Progress              float64 `json:"progress"`
+}
+
+// ExecutionResultResponse is the body of a finished run
+type ExecutionResultResponse struct {
+	RunID  string          `json:"run_id"`
+	Status string          `json:"status"`
+	Result ExecutionResult `json:"result"`
+}
+
+// ExecutionResult is the outcome of a finished run
+type ExecutionResult struct {
+	RunID               string                 `json:"run_id"`
+	WorkflowID          string                 `json:"workflow_id"`
+	Status              string                 `json:"status"`
+	Result              map[string]interface{} `json:"result"`
+	ErrorMessage        string                 `json:"error_message"`
+	CompletedAt         string                 `json:"completed_at"`
+	ExecutionTimeMillis int64                  `json:"execution_time_millis"`
+	StepCount           int                    `json:"step_count"`
+}
+
+// ExecutionDetails is the status of a run with the status of the sub-workflow runs it started
+type ExecutionDetails struct {
+	executor.ExecutionStatus
+	ChildRuns []executor.ExecutionStatus `json:"child_runs,omitempty"`
+}
+
+// ExecutionSummary describes a run in a listing
+type ExecutionSummary struct {
+	RunID                 string     `json:"run_id"`
+	WorkflowID            string     `json:"workflow_id"`
+	Status                string     `json:"status"`
+	CurrentStepIndex      int        `json:"current_step_index"`
+	CurrentChildStepIndex int        `json:"current_child_step_index"`
+	StartTime             *time.Time `json:"start_time"`
+	EndTime               *time.Time `json:"end_time"`
+	ErrorMessage          string     `json:"error_message"`
+	LastAttemptedStep     string     `json:"last_attempted_step"`
+	IsTerminal            bool       `json:"is_terminal"`
+	IsRunning             bool       `json:"is_running"`
+	IsPending             bool       `json:"is_pending"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// ExecutionListResponse is the body of GET /executions
+type ExecutionListResponse struct {
+	Executions []ExecutionSummary `json:"executions"`
+	Count      int                `json:"count"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+}
+
+// ExecutionControlResponse is the body of the cancel, pause, resume and retry endpoints
+type ExecutionControlResponse struct {
+	Message string `json:"message"`
+	RunID   string `json:"run_id"`
+}
+
+// ExecutionDataResponse is the body of GET /executions/:runId/data
+type ExecutionDataResponse struct {
+	RunID string                 `json:"run_id"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+// ExecutionMetricsResponse is the body of GET /executions/:runId/metrics
+type ExecutionMetricsResponse struct {
+	RunID               string                 `json:"run_id"`
+	WorkflowID          string                 `json:"workflow_id"`
+	WorkflowMetrics     map[string]interface{} `json:"workflow_metrics"`
+	StepMetrics         map[string]interface{} `json:"step_metrics"`
+	ChildStepMetrics    map[string]interface{} `json:"child_step_metrics"`
+	TotalSteps          int                    `json:"total_steps"`
+	CompletedSteps      int                    `json:"completed_steps"`
+	FailedSteps         int                    `json:"failed_steps"`
+	TotalChildSteps     int                    `json:"total_child_steps"`
+	CompletedChildSteps int                    `json:"completed_child_steps"`
+	FailedChildSteps    int                    `json:"failed_child_steps"`
+	TotalDurationMillis int64                  `json:"total_duration_millis"`
+	AverageStepDuration int64                  `json:"average_step_duration"`
+	SuccessRate         float64                `json:"success_rate"`
+}
+
+// StepExecutionResponse is the body of the step and child step endpoints
+type StepExecutionResponse struct {
+	RunID          string `json:"run_id"`
+	StepIndex      int    `json:"step_index"`
+	ChildStepIndex *int   `json:"child_step_index,omitempty"`
+	Message        string `json:"message"`
+}
+
+// ConfigResponse is the body of GET /admin/config
+type ConfigResponse struct {
+	Config     *config.Config       `json:"config"`
+	Source     config.ConfigSource  `json:"source"`
+	Owners     map[string][]string  `json:"owners"`
+	LastReload *config.ReloadResult `json:"last_reload"`
+}
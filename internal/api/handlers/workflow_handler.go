@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
-	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/callback"
+	"unified-workflow/internal/dataprotection"
 	"unified-workflow/internal/executor"
 	"unified-workflow/internal/registry"
 	"unified-workflow/internal/state"
@@ -13,337 +16,218 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// WorkflowHandler handles workflow API requests
+// WorkflowHandler handles workflow execution API requests
 type WorkflowHandler struct {
-	executor        executor.Executor
-	registry        registry.Registry
-	stateManagement state.StateManagement
+	executor  *executor.WorkflowExecutor
+	registry  registry.Registry
+	callbacks *callback.Dispatcher
 }
 
 // NewWorkflowHandler creates a new workflow handler
+// callbacks may be nil, in which case requests with a callback_url are rejected
 func NewWorkflowHandler(
-	executor executor.Executor,
+	executor *executor.WorkflowExecutor,
 	registry registry.Registry,
-	stateManagement state.StateManagement,
+	callbacks *callback.Dispatcher,
 ) *WorkflowHandler {
 	return &WorkflowHandler{
-		executor:        executor,
-		registry:        registry,
-		stateManagement: stateManagement,
+		executor:  executor,
+		registry:  registry,
+		callbacks: callbacks,
 	}
 }
 
-// ListWorkflows lists all registered workflows
-func (h *WorkflowHandler) ListWorkflows(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	// Get all workflow IDs
-	workflowIDs, err := h.registry.GetAllWorkflowIDs(ctx)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to list workflows",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	// Get each workflow
-	response := make([]gin.H, 0, len(workflowIDs))
-	for _, workflowID := range workflowIDs {
-		workflow, err := h.registry.GetWorkflow(ctx, workflowID)
-		if err != nil {
-			// Skip workflows that can't be retrieved
-			continue
-		}
-
-		response = append(response, gin.H{
-			"id":          workflow.GetID(),
-			"name":        workflow.GetName(),
-			"description": workflow.GetDescription(),
-			"step_count":  workflow.GetStepCount(),
-		})
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"workflows": response,
-		"count":     len(response),
-	})
+// ExecuteWorkflowRequest is the body of POST /workflows/:id/execute
+type ExecuteWorkflowRequest struct {
+	WorkflowID string                 `json:"workflow_id,omitempty"` // Only read by the deprecated /execute route
+	InputData  map[string]interface{} `json:"input_data,omitempty"`
+	TimeoutMs  int64                  `json:"timeout_ms,omitempty" binding:"min=0"`
+	Priority   int                    `json:"priority,omitempty" binding:"min=0,max=10"`
 }
 
-// GetWorkflow gets a specific workflow by ID
-func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
-	ctx := c.Request.Context()
-	workflowID := c.Param("id")
-
-	workflow, err := h.registry.GetWorkflow(ctx, workflowID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Workflow not found",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	// Get steps
-	steps := workflow.GetSteps()
-	stepDetails := make([]gin.H, 0, len(steps))
-	for _, step := range steps {
-		stepDetails = append(stepDetails, gin.H{
-			"name":             step.GetName(),
-			"child_step_count": step.GetChildStepCount(),
-			"is_parallel":      step.IsParallel(),
-		})
-	}
+// SignalExecutionRequest is the body of POST /executions/:runId/signals/:name
+type SignalExecutionRequest struct {
+	// Payload is merged into the data of the run when a wait step takes the signal
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":          workflow.GetID(),
-		"name":        workflow.GetName(),
-		"description": workflow.GetDescription(),
-		"step_count":  workflow.GetStepCount(),
-		"steps":       stepDetails,
-	})
+// AsyncExecuteWorkflowRequest is the body of POST /workflows/:id/async-execute
+type AsyncExecuteWorkflowRequest struct {
+	WorkflowID        string                 `json:"workflow_id,omitempty"` // Only read by the deprecated /execute/async route
+	InputData         map[string]interface{} `json:"input_data,omitempty"`
+	CallbackURL       string                 `json:"callback_url,omitempty" binding:"omitempty,url"`
+	TimeoutMs         int64                  `json:"timeout_ms,omitempty" binding:"min=0"`
+	WaitForCompletion bool                   `json:"wait_for_completion,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// CreateWorkflow creates a new workflow
-func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
+// ExecuteWorkflow runs a workflow, waiting at most timeout_ms for the outcome
+// Runs that take longer continue asynchronously and are answered with 202
+func (h *WorkflowHandler) ExecuteWorkflow(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	var request struct {
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+	var request ExecuteWorkflowRequest
+	if !bindJSON(c, &request) {
 		return
 	}
-
-	// Create workflow using the common model
-	workflow := model.NewBaseWorkflow(request.Name, request.Description)
-
-	// Register workflow
-	err := h.registry.RegisterWorkflow(ctx, workflow)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create workflow",
-			"details": err.Error(),
-		})
+	workflowID, ok := workflowIDFor(c, request.WorkflowID)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":          workflow.GetID(),
-		"name":        workflow.GetName(),
-		"description": workflow.GetDescription(),
-		"message":     "Workflow created successfully",
-	})
-}
-
-// DeleteWorkflow deletes a workflow
-func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
-	ctx := c.Request.Context()
-	workflowID := c.Param("id")
-
-	err := h.registry.RemoveWorkflow(ctx, workflowID)
+	// Get workflow
+	workflow, err := h.registry.GetWorkflow(ctx, workflowID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Failed to delete workflow",
-			"details": err.Error(),
-		})
+		respondError(c, http.StatusNotFound, CodeWorkflowNotFound, "Workflow not found", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Workflow deleted successfully",
-	})
-}
-
-// ExecuteWorkflow executes a workflow
-func (h *WorkflowHandler) ExecuteWorkflow(c *gin.Context) {
-	ctx := c.Request.Context()
-	workflowID := c.Param("id")
-
-	// Get workflow
-	workflow, err := h.registry.GetWorkflow(ctx, workflowID)
+	// Run the workflow, waiting at most timeout_ms for the outcome
+	timeout := time.Duration(request.TimeoutMs) * time.Millisecond
+	runID, result, err := h.executor.ExecuteWorkflowWithTimeout(ctx, workflow.GetID(), request.InputData, timeout)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Workflow not found",
-			"details": err.Error(),
-		})
+		respondSubmitError(c, "Failed to execute workflow", err, runID)
 		return
 	}
 
-	// Submit workflow for execution
-	runID, err := h.executor.SubmitWorkflow(ctx, workflow)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to execute workflow",
-			"details": err.Error(),
+	// Still running: hand back the run ID so the caller can poll for the result
+	if result == nil {
+		status := "running"
+		if current, err := h.executor.GetExecutionStatus(ctx, runID); err == nil {
+			status = current.Status
+		}
+		c.JSON(http.StatusAccepted, ExecutionAcceptedResponse{
+			RunID:       runID,
+			WorkflowID:  workflow.GetID(),
+			Status:      status,
+			Mode:        "async",
+			Message:     "Workflow execution exceeded timeout_ms and continues asynchronously",
+			StatusURL:   executionURL(runID),
+			ResultURL:   executionURL(runID) + "/result",
+			PollAfterMs: 1000,
 		})
 		return
 	}
 
-	c.JSON(http.StatusAccepted, gin.H{
-		"run_id":     runID,
-		"message":    "Workflow execution started",
-		"status_url": "/api/v1/executions/" + runID,
+	c.JSON(http.StatusOK, SyncExecutionResponse{
+		RunID:      result.RunID,
+		WorkflowID: result.WorkflowID,
+		Status:     result.Status,
+		Mode:       "sync",
+		Result:     dataprotection.MaskForCaller(ctx, result.Result),
+		Steps:      result.Steps,
+		Error:      result.Error,
+		StartTime:  result.StartTime,
+		EndTime:    result.EndTime,
+		DurationMs: result.EndTime.Sub(result.StartTime).Milliseconds(),
+
+		CompensationStatus: result.CompensationStatus,
+		Compensations:      result.Compensations,
 	})
 }
 
-// AsyncExecuteWorkflow executes a workflow asynchronously with immediate 202 response
+// AsyncExecuteWorkflow queues a workflow run and answers 202 immediately
+// unless wait_for_completion is set and the run finishes within timeout_ms
 func (h *WorkflowHandler) AsyncExecuteWorkflow(c *gin.Context) {
 	ctx := c.Request.Context()
-	workflowID := c.Param("id")
 
-	// Parse request body
-	var request struct {
-		InputData         map[string]interface{} `json:"input_data"`
-		CallbackURL       string                 `json:"callback_url"`
-		TimeoutMs         int                    `json:"timeout_ms"`
-		WaitForCompletion bool                   `json:"wait_for_completion"`
-		Metadata          map[string]interface{} `json:"metadata"`
+	var request AsyncExecuteWorkflowRequest
+	if !bindJSON(c, &request) {
+		return
 	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+	workflowID, ok := workflowIDFor(c, request.WorkflowID)
+	if !ok {
 		return
 	}
 
+	// Reject callbacks that could never be delivered before queuing the run
+	clientID := c.GetHeader(callback.ClientIDHeader)
+	if request.CallbackURL != "" {
+		if err := h.validateCallback(request.CallbackURL, clientID); err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidCallback, "Invalid callback_url", err)
+			return
+		}
+	}
+
 	// Get workflow
 	workflow, err := h.registry.GetWorkflow(ctx, workflowID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Workflow not found",
-			"details": err.Error(),
-		})
+		respondError(c, http.StatusNotFound, CodeWorkflowNotFound, "Workflow not found", err)
 		return
 	}
 
-	// For now, use the same executor but we'll enhance this later
-	// to publish directly to NATS with response routing
-	runID, err := h.executor.SubmitWorkflow(ctx, workflow)
+	// Persist the pending run and queue it for a worker
+	runID, err := h.executor.SubmitWorkflowWithInput(ctx, workflow, request.InputData)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to execute workflow",
-			"details": err.Error(),
-		})
+		respondSubmitError(c, "Failed to submit workflow", err, "")
 		return
 	}
 
-	// Return 202 Accepted with polling information
-	c.JSON(http.StatusAccepted, gin.H{
-		"run_id":                  runID,
-		"status":                  "queued",
-		"message":                 "Workflow execution queued",
-		"status_url":              "/api/v1/executions/" + runID,
-		"result_url":              "/api/v1/executions/" + runID + "/result",
-		"poll_after_ms":           1000,
-		"estimated_completion_ms": 5000,
-		"expires_at":              time.Now().Add(1 * time.Hour).Format(time.RFC3339),
+	if request.CallbackURL != "" {
+		if err := h.callbacks.Register(ctx, runID, request.CallbackURL, clientID); err != nil {
+			respondRunError(c, http.StatusInternalServerError, CodeInternal, "Failed to register callback", err, runID)
+			return
+		}
+	}
+
+	// Block until the run finishes or timeout_ms elapses
+	if request.WaitForCompletion {
+		timeout := time.Duration(request.TimeoutMs) * time.Millisecond
+		if status, ok := h.waitForResult(c, runID, timeout); ok {
+			h.respondWithResult(c, runID, status)
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, ExecutionAcceptedResponse{
+		RunID:                 runID,
+		Status:                "queued",
+		Message:               "Workflow execution queued",
+		StatusURL:             executionURL(runID),
+		ResultURL:             executionURL(runID) + "/result",
+		PollAfterMs:           1000,
+		EstimatedCompletionMs: 5000,
+		ExpiresAt:             time.Now().Add(1 * time.Hour).Format(time.RFC3339),
 	})
 }
 
 // GetExecutionResult gets the result of an async workflow execution
+// With long_poll=true the request is parked for up to wait_ms until the run finishes
 func (h *WorkflowHandler) GetExecutionResult(c *gin.Context) {
 	ctx := c.Request.Context()
 	runID := c.Param("runId")
 
-	// Parse query parameters
-	waitMsStr := c.Query("wait_ms")
-	longPollStr := c.Query("long_poll")
-
-	waitMs := 0
-	if waitMsStr != "" {
-		if val, err := strconv.Atoi(waitMsStr); err == nil && val >= 0 {
-			waitMs = val
-		}
-	}
-
-	longPoll := false
-	if longPollStr == "true" {
-		longPoll = true
+	waitMs, ok := intQuery(c, "wait_ms", 0, 0, 0)
+	if !ok {
+		return
 	}
+	longPoll := c.Query("long_poll") == "true"
 
-	// For now, check execution status
-	// TODO: Implement proper result storage and retrieval from Redis
 	status, err := h.executor.GetExecutionStatus(ctx, runID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Execution not found",
-			"details": err.Error(),
-		})
+		respondExecutionError(c, "Failed to get execution status", err)
 		return
 	}
 
-	// Check if execution is complete
-	if status.Status == "completed" || status.Status == "failed" || status.Status == "cancelled" {
-		// Get execution data for result
-		data, err := h.executor.GetExecutionData(ctx, runID)
-		if err != nil {
-			data = make(map[string]interface{})
+	// If long polling is enabled, wait for the completion notification
+	if !status.IsTerminal && longPoll && waitMs > 0 {
+		if finished, ok := h.waitForResult(c, runID, time.Duration(waitMs)*time.Millisecond); ok {
+			status = finished
 		}
-
-		// Return completed result
-		c.JSON(http.StatusOK, gin.H{
-			"run_id": runID,
-			"status": status.Status,
-			"result": gin.H{
-				"run_id":                runID,
-				"workflow_id":           status.WorkflowID,
-				"status":                status.Status,
-				"result":                data,
-				"completed_at":          time.Now().Format(time.RFC3339),
-				"execution_time_millis": 0, // TODO: Calculate actual execution time
-				"step_count":            0, // TODO: Get actual step count
-			},
-		})
-		return
 	}
 
-	// Execution still in progress
-	// If long polling is enabled and waitMs > 0, wait for completion
-	if longPoll && waitMs > 0 {
-		// Simple implementation: poll with timeout
-		timeout := time.Duration(waitMs) * time.Millisecond
-		startTime := time.Now()
-
-		for time.Since(startTime) < timeout {
-			status, err := h.executor.GetExecutionStatus(ctx, runID)
-			if err == nil && (status.Status == "completed" || status.Status == "failed" || status.Status == "cancelled") {
-				// Get result and return
-				data, _ := h.executor.GetExecutionData(ctx, runID)
-				c.JSON(http.StatusOK, gin.H{
-					"run_id": runID,
-					"status": status.Status,
-					"result": gin.H{
-						"run_id":                runID,
-						"workflow_id":           status.WorkflowID,
-						"status":                status.Status,
-						"result":                data,
-						"completed_at":          time.Now().Format(time.RFC3339),
-						"execution_time_millis": 0,
-						"step_count":            0,
-					},
-				})
-				return
-			}
-			time.Sleep(100 * time.Millisecond)
-		}
+	if status.IsTerminal {
+		h.respondWithResult(c, runID, status)
+		return
 	}
 
 	// Return "not ready" response
-	c.JSON(http.StatusAccepted, gin.H{
-		"run_id":                  runID,
-		"status":                  status.Status,
-		"poll_after_ms":           1000,
-		"estimated_completion_ms": 5000,
-		"progress":                status.Progress,
+	c.JSON(http.StatusAccepted, ExecutionPendingResponse{
+		RunID:                 runID,
+		Status:                status.Status,
+		PollAfterMs:           1000,
+		EstimatedCompletionMs: 5000,
+		Progress:              status.Progress,
 	})
 }
 
@@ -351,30 +235,19 @@ func (h *WorkflowHandler) GetExecutionResult(c *gin.Context) {
 func (h *WorkflowHandler) ListExecutions(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// Parse query parameters
-	workflowID := c.Query("workflow_id")
-	status := c.Query("status")
-	limitStr := c.Query("limit")
-	offsetStr := c.Query("offset")
-
-	limit := 50
-	if limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = val
-		}
+	limit, ok := intQuery(c, "limit", 50, 1, 1000)
+	if !ok {
+		return
 	}
-
-	offset := 0
-	if offsetStr != "" {
-		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
-			offset = val
-		}
+	offset, ok := intQuery(c, "offset", 0, 0, 0)
+	if !ok {
+		return
 	}
 
 	// Build filters
 	filters := executor.ExecutionFilters{
-		WorkflowID: workflowID,
-		Status:     status,
+		WorkflowID: c.Query("workflow_id"),
+		Status:     c.Query("status"),
 		Limit:      limit,
 		Offset:     offset,
 	}
@@ -382,39 +255,36 @@ func (h *WorkflowHandler) ListExecutions(c *gin.Context) {
 	// Get executions
 	executions, err := h.executor.ListExecutions(ctx, filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to list executions",
-			"details": err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, CodeInternal, "Failed to list executions", err)
 		return
 	}
 
 	// Convert to response format
-	response := make([]gin.H, 0, len(executions))
+	response := make([]ExecutionSummary, 0, len(executions))
 	for _, exec := range executions {
-		response = append(response, gin.H{
-			"run_id":                   exec.RunID,
-			"workflow_id":              exec.WorkflowDefinitionID,
-			"status":                   exec.Status,
-			"current_step_index":       exec.CurrentStepIndex,
-			"current_child_step_index": exec.CurrentChildStepIndex,
-			"start_time":               exec.StartTime,
-			"end_time":                 exec.EndTime,
-			"error_message":            exec.ErrorMessage,
-			"last_attempted_step":      exec.LastAttemptedStep,
-			"is_terminal":              exec.IsTerminal,
-			"is_running":               exec.IsRunning,
-			"is_pending":               exec.IsPending,
-			"created_at":               exec.CreatedAt,
-			"updated_at":               exec.UpdatedAt,
+		response = append(response, ExecutionSummary{
+			RunID:                 exec.RunID,
+			WorkflowID:            exec.WorkflowDefinitionID,
+			Status:                exec.Status,
+			CurrentStepIndex:      exec.CurrentStepIndex,
+			CurrentChildStepIndex: exec.CurrentChildStepIndex,
+			StartTime:             exec.StartTime,
+			EndTime:               exec.EndTime,
+			ErrorMessage:          exec.ErrorMessage,
+			LastAttemptedStep:     exec.LastAttemptedStep,
+			IsTerminal:            exec.IsTerminal,
+			IsRunning:             exec.IsRunning,
+			IsPending:             exec.IsPending,
+			CreatedAt:             exec.CreatedAt,
+			UpdatedAt:             exec.UpdatedAt,
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"executions": response,
-		"count":      len(response),
-		"limit":      limit,
-		"offset":     offset,
+	c.JSON(http.StatusOK, ExecutionListResponse{
+		Executions: response,
+		Count:      len(response),
+		Limit:      limit,
+		Offset:     offset,
 	})
 }
 
@@ -425,103 +295,107 @@ func (h *WorkflowHandler) GetExecutionStatus(c *gin.Context) {
 
 	status, err := h.executor.GetExecutionStatus(ctx, runID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Execution not found",
-			"details": err.Error(),
-		})
+		respondExecutionError(c, "Failed to get execution status", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"run_id":                   status.RunID,
-		"workflow_id":              status.WorkflowID,
-		"status":                   status.Status,
-		"current_step":             status.CurrentStep,
-		"current_step_index":       status.CurrentStepIndex,
-		"current_child_step_index": status.CurrentChildStepIndex,
-		"progress":                 status.Progress,
-		"start_time":               status.StartTime,
-		"end_time":                 status.EndTime,
-		"error_message":            status.ErrorMessage,
-		"last_attempted_step":      status.LastAttemptedStep,
-		"is_terminal":              status.IsTerminal,
-		"metadata":                 status.Metadata,
-	})
+	c.JSON(http.StatusOK, status)
 }
 
-// CancelExecution cancels a running workflow execution
-func (h *WorkflowHandler) CancelExecution(c *gin.Context) {
+// GetExecutionDetails gets detailed information about a workflow execution
+func (h *WorkflowHandler) GetExecutionDetails(c *gin.Context) {
 	ctx := c.Request.Context()
 	runID := c.Param("runId")
 
-	err := h.executor.CancelExecution(ctx, runID)
+	status, err := h.executor.GetExecutionStatus(ctx, runID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to cancel execution",
-			"details": err.Error(),
-		})
+		respondExecutionError(c, "Failed to get execution status", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Execution cancelled successfully",
-	})
+	// TODO: Include step and child step details
+	details := ExecutionDetails{ExecutionStatus: *status}
+	for _, childRunID := range status.ChildRunIDs {
+		child, err := h.executor.GetExecutionStatus(ctx, childRunID)
+		if err != nil {
+			// The state of a child run may have expired before that of its parent
+			continue
+		}
+		details.ChildRuns = append(details.ChildRuns, *child)
+	}
+	c.JSON(http.StatusOK, details)
+}
+
+// CancelExecution cancels a running workflow execution
+func (h *WorkflowHandler) CancelExecution(c *gin.Context) {
+	h.control(c, h.executor.CancelExecution, "cancel", "Execution cancelled successfully")
 }
 
 // PauseExecution pauses a running workflow execution
 func (h *WorkflowHandler) PauseExecution(c *gin.Context) {
-	ctx := c.Request.Context()
-	runID := c.Param("runId")
-
-	err := h.executor.PauseExecution(ctx, runID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to pause execution",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Execution paused successfully",
-	})
+	h.control(c, h.executor.PauseExecution, "pause", "Execution paused successfully")
 }
 
 // ResumeExecution resumes a paused workflow execution
 func (h *WorkflowHandler) ResumeExecution(c *gin.Context) {
+	h.control(c, h.executor.ResumeExecution, "resume", "Execution resumed successfully")
+}
+
+// RetryExecution retries a failed workflow execution
+func (h *WorkflowHandler) RetryExecution(c *gin.Context) {
+	h.control(c, h.executor.RetryExecution, "retry", "Execution retry initiated successfully")
+}
+
+// SignalExecution sends a named signal to a run, resuming a wait step for it
+func (h *WorkflowHandler) SignalExecution(c *gin.Context) {
 	ctx := c.Request.Context()
 	runID := c.Param("runId")
+	name := c.Param("name")
 
-	err := h.executor.ResumeExecution(ctx, runID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to resume execution",
-			"details": err.Error(),
-		})
+	var request SignalExecutionRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	if err := h.executor.SignalExecution(ctx, runID, name, request.Payload); err != nil {
+		if errors.Is(err, executor.ErrRunFinished) {
+			respondRunError(c, http.StatusConflict, CodeExecutionFinished, "Execution already finished", err, runID)
+			return
+		}
+		if errors.Is(err, executor.ErrRunNotWaiting) {
+			respondRunError(c, http.StatusConflict, CodeExecutionNotWaiting, "Execution not waiting for the signal", err, runID)
+			return
+		}
+		respondExecutionError(c, "Failed to signal execution", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Execution resumed successfully",
+	c.JSON(http.StatusOK, ExecutionControlResponse{
+		Message: fmt.Sprintf("Signal %s sent", name),
+		RunID:   runID,
 	})
 }
 
-// RetryExecution retries a failed workflow execution
-func (h *WorkflowHandler) RetryExecution(c *gin.Context) {
+// control applies a state change to a run
+func (h *WorkflowHandler) control(c *gin.Context, action func(ctx context.Context, runID string) error, verb, message string) {
 	ctx := c.Request.Context()
 	runID := c.Param("runId")
 
-	err := h.executor.RetryExecution(ctx, runID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to retry execution",
-			"details": err.Error(),
-		})
+	if err := action(ctx, runID); err != nil {
+		switch {
+		case errors.Is(err, state.ErrStateNotFound):
+			respondRunError(c, http.StatusNotFound, CodeExecutionNotFound, "Execution not found", err, runID)
+		case errors.Is(err, executor.ErrRunFinished):
+			respondRunError(c, http.StatusConflict, CodeExecutionFinished, "Execution already finished", err, runID)
+		default:
+			respondRunError(c, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to %s execution", verb), err, runID)
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Execution retry initiated successfully",
+	c.JSON(http.StatusOK, ExecutionControlResponse{
+		Message: message,
+		RunID:   runID,
 	})
 }
 
@@ -532,16 +406,13 @@ func (h *WorkflowHandler) GetExecutionData(c *gin.Context) {
 
 	data, err := h.executor.GetExecutionData(ctx, runID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Failed to get execution data",
-			"details": err.Error(),
-		})
+		respondExecutionError(c, "Failed to get execution data", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"run_id": runID,
-		"data":   data,
+	c.JSON(http.StatusOK, ExecutionDataResponse{
+		RunID: runID,
+		Data:  dataprotection.MaskForCaller(ctx, data),
 	})
 }
 
@@ -552,27 +423,154 @@ func (h *WorkflowHandler) GetExecutionMetrics(c *gin.Context) {
 
 	metrics, err := h.executor.GetMetrics(ctx, runID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Failed to get execution metrics",
-			"details": err.Error(),
-		})
+		respondExecutionError(c, "Failed to get execution metrics", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"run_id":                metrics.RunID,
-		"workflow_id":           metrics.WorkflowID,
-		"workflow_metrics":      metrics.WorkflowMetrics,
-		"step_metrics":          metrics.StepMetrics,
-		"child_step_metrics":    metrics.ChildStepMetrics,
-		"total_steps":           metrics.TotalSteps,
-		"completed_steps":       metrics.CompletedSteps,
-		"failed_steps":          metrics.FailedSteps,
-		"total_child_steps":     metrics.TotalChildSteps,
-		"completed_child_steps": metrics.CompletedChildSteps,
-		"failed_child_steps":    metrics.FailedChildSteps,
-		"total_duration_millis": metrics.TotalDurationMillis,
-		"average_step_duration": metrics.AverageStepDuration,
-		"success_rate":          metrics.SuccessRate,
+	c.JSON(http.StatusOK, ExecutionMetricsResponse{
+		RunID:               metrics.RunID,
+		WorkflowID:          metrics.WorkflowID,
+		WorkflowMetrics:     metrics.WorkflowMetrics,
+		StepMetrics:         metrics.StepMetrics,
+		ChildStepMetrics:    metrics.ChildStepMetrics,
+		TotalSteps:          metrics.TotalSteps,
+		CompletedSteps:      metrics.CompletedSteps,
+		FailedSteps:         metrics.FailedSteps,
+		TotalChildSteps:     metrics.TotalChildSteps,
+		CompletedChildSteps: metrics.CompletedChildSteps,
+		FailedChildSteps:    metrics.FailedChildSteps,
+		TotalDurationMillis: metrics.TotalDurationMillis,
+		AverageStepDuration: metrics.AverageStepDuration,
+		SuccessRate:         metrics.SuccessRate,
 	})
 }
+
+// GetStepExecution gets the execution details of a step
+func (h *WorkflowHandler) GetStepExecution(c *gin.Context) {
+	stepIndex, ok := intParam(c, "stepIndex")
+	if !ok {
+		return
+	}
+
+	// TODO: Implement step execution details
+	c.JSON(http.StatusOK, StepExecutionResponse{
+		RunID:     c.Param("runId"),
+		StepIndex: stepIndex,
+		Message:   "Step execution details (implementation pending)",
+	})
+}
+
+// GetChildStepExecution gets the execution details of a child step
+func (h *WorkflowHandler) GetChildStepExecution(c *gin.Context) {
+	stepIndex, ok := intParam(c, "stepIndex")
+	if !ok {
+		return
+	}
+	childStepIndex, ok := intParam(c, "childStepIndex")
+	if !ok {
+		return
+	}
+
+	// TODO: Implement child step execution details
+	c.JSON(http.StatusOK, StepExecutionResponse{
+		RunID:          c.Param("runId"),
+		StepIndex:      stepIndex,
+		ChildStepIndex: &childStepIndex,
+		Message:        "Child step execution details (implementation pending)",
+	})
+}
+
+// waitForResult waits up to timeout for a run to finish and returns its final status
+func (h *WorkflowHandler) waitForResult(c *gin.Context, runID string, timeout time.Duration) (*executor.ExecutionStatus, bool) {
+	ctx := c.Request.Context()
+	result, err := h.executor.WaitForResult(ctx, runID, timeout)
+	if err != nil || result == nil {
+		return nil, false
+	}
+	status, err := h.executor.GetExecutionStatus(ctx, runID)
+	if err != nil || !status.IsTerminal {
+		return nil, false
+	}
+	return status, true
+}
+
+// respondWithResult writes the result of a finished execution
+func (h *WorkflowHandler) respondWithResult(c *gin.Context, runID string, status *executor.ExecutionStatus) {
+	ctx := c.Request.Context()
+	data, err := h.executor.GetExecutionData(ctx, runID)
+	if err != nil {
+		data = make(map[string]interface{})
+	}
+
+	c.JSON(http.StatusOK, ExecutionResultResponse{
+		RunID:  runID,
+		Status: status.Status,
+		Result: ExecutionResult{
+			RunID:               runID,
+			WorkflowID:          status.WorkflowID,
+			Status:              status.Status,
+			Result:              dataprotection.MaskForCaller(ctx, data),
+			ErrorMessage:        status.ErrorMessage,
+			CompletedAt:         completedAt(status),
+			ExecutionTimeMillis: executionTimeMillis(status),
+			StepCount:           h.stepCount(c, status.WorkflowID),
+		},
+	})
+}
+
+// stepCount returns the number of steps of a workflow, or 0 if it is no longer registered
+func (h *WorkflowHandler) stepCount(c *gin.Context, workflowID string) int {
+	workflow, err := h.registry.GetWorkflow(c.Request.Context(), workflowID)
+	if err != nil {
+		return 0
+	}
+	return workflow.GetStepCount()
+}
+
+// validateCallback checks that a callback can be delivered; callbacks is nil when they are disabled
+func (h *WorkflowHandler) validateCallback(callbackURL, clientID string) error {
+	if h.callbacks == nil {
+		return fmt.Errorf("callbacks are disabled")
+	}
+	return h.callbacks.Validate(callbackURL, clientID)
+}
+
+// workflowIDFor returns the workflow to run: the :id path parameter, or workflow_id from the
+// body on the deprecated routes that have no path parameter
+func workflowIDFor(c *gin.Context, bodyID string) (string, bool) {
+	pathID := c.Param("id")
+	switch {
+	case pathID != "" && bodyID != "" && bodyID != pathID:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body",
+			errors.New("workflow_id does not match the workflow in the path"))
+		return "", false
+	case pathID != "":
+		return pathID, true
+	case bodyID != "":
+		return bodyID, true
+	default:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body", errors.New("workflow_id is required"))
+		return "", false
+	}
+}
+
+// executionURL returns the status URL of a run
+func executionURL(runID string) string {
+	return "/api/v1/executions/" + runID
+}
+
+// completedAt formats the end time of a finished execution
+func completedAt(status *executor.ExecutionStatus) string {
+	if status.EndTime == nil {
+		return time.Now().Format(time.RFC3339)
+	}
+	return status.EndTime.Format(time.RFC3339)
+}
+
+// executionTimeMillis returns how long a finished execution ran
+func executionTimeMillis(status *executor.ExecutionStatus) int64 {
+	if status.StartTime == nil || status.EndTime == nil {
+		return 0
+	}
+	return status.EndTime.Sub(*status.StartTime).Milliseconds()
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"unified-workflow/internal/api/handlers"
+	"unified-workflow/internal/api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps lower-case HTTP methods to the operations of a path
+type PathItem map[string]*Operation
+
+// Operation describes an endpoint
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+// Parameter describes a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas and security schemes referenced by the operations
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how a caller authenticates
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement maps a security scheme to the permissions it must grant
+type SecurityRequirement map[string][]string
+
+// securitySchemes are the credentials accepted when authentication is enabled
+var securitySchemes = map[string]*SecurityScheme{
+	"apiKey": {Type: "apiKey", In: "header", Name: middleware.APIKeyHeader,
+		Description: "API key; it is also accepted as a bearer token"},
+	"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+		Description: "JWT signed with the shared HMAC secret or a key of the configured JWKS"},
+}
+
+// Schema is an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+}
+
+// jsonContent is the media type of every request and response body of the API
+const jsonContent = "application/json"
+
+// Spec builds the OpenAPI document of the routes registered on a router
+// Documented routes carry their parameters, bodies and responses; other routes such as
+// health checks and metrics are listed with a generic response
+func Spec(router *gin.Engine) *Document {
+	doc := &Document{
+		OpenAPI:    "3.0.3",
+		Info:       Info{Title: "Unified Workflow API", Version: strings.TrimPrefix(BasePath, "/api/")},
+		Paths:      make(map[string]PathItem),
+		Components: Components{Schemas: make(map[string]*Schema), SecuritySchemes: securitySchemes},
+	}
+	schemas := newSchemaRegistry(doc.Components.Schemas)
+
+	for _, info := range router.Routes() {
+		path := openAPIPath(info.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+
+		r, documented := findRoute(info.Method, info.Path)
+		if !documented {
+			item[strings.ToLower(info.Method)] = operationalOperation(info.Path)
+			continue
+		}
+		item[strings.ToLower(info.Method)] = r.operation(schemas)
+	}
+	return doc
+}
+
+// operation describes a documented route
+func (r route) operation(schemas *schemaRegistry) *Operation {
+	op := &Operation{
+		OperationID: r.operationID,
+		Summary:     r.summary,
+		Tags:        []string{string(r.group)},
+		Deprecated:  r.successor != "",
+		Parameters:  r.parameters(),
+		Responses:   make(map[string]Response, len(r.responses)),
+	}
+
+	if r.request != nil {
+		schema := schemas.request(r.request)
+		op.RequestBody = &RequestBody{
+			Required: len(schemas.resolve(schema).Required) > 0,
+			Content:  map[string]MediaType{jsonContent: {Schema: schema}},
+		}
+	}
+
+	// Either credential must grant the permission of the route when authentication is enabled
+	if r.permission != "" {
+		op.Security = []SecurityRequirement{{"apiKey": {r.permission}}, {"bearer": {r.permission}}}
+		for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+			op.Responses[strconv.Itoa(status)] = Response{
+				Description: http.StatusText(status),
+				Content:     map[string]MediaType{jsonContent: {Schema: schemas.response(handlers.ErrorResponse{})}},
+			}
+		}
+	}
+
+	for status, body := range r.responses {
+		op.Responses[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{jsonContent: {Schema: schemas.response(body)}},
+		}
+	}
+	return op
+}
+
+// parameters returns the declared parameters of a route, adding the path parameters it does not declare
+// and the tenant header every route accepts
+func (r route) parameters() []Parameter {
+	params := append([]Parameter(nil), r.params...)
+	declared := make(map[string]bool, len(params))
+	for _, p := range params {
+		declared[p.In+":"+p.Name] = true
+	}
+
+	var implicit []Parameter
+	for _, segment := range strings.Split(r.path, "/") {
+		if !strings.HasPrefix(segment, ":") || declared["path:"+segment[1:]] {
+			continue
+		}
+		implicit = append(implicit, Parameter{Name: segment[1:], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	params = append(append(implicit, params...), tenantParam)
+
+	sort.SliceStable(params, func(i, j int) bool { return params[i].In == "path" && params[j].In != "path" })
+	return params
+}
+
+// operationalSummaries describe the routes the binaries add next to the API
+var operationalSummaries = map[string]string{
+	"/health":    "Service health",
+	"/health/di": "Dependency injection container health",
+	"/metrics":   "Prometheus metrics",
+	SpecPath:     "OpenAPI document of this service",
+}
+
+// operationalOperation describes a route that is not part of the documented API
+func operationalOperation(path string) *Operation {
+	summary, ok := operationalSummaries[path]
+	if !ok {
+		summary = "Operational endpoint"
+	}
+	return &Operation{
+		Summary:   summary,
+		Tags:      []string{"operations"},
+		Responses: map[string]Response{strconv.Itoa(http.StatusOK): {Description: http.StatusText(http.StatusOK)}},
+	}
+}
+
+// specHandler serves the OpenAPI document of the router
+// The document is built on the first request, once every route has been registered
+func specHandler(router *gin.Engine) gin.HandlerFunc {
+	var (
+		once sync.Once
+		doc  *Document
+	)
+	return func(c *gin.Context) {
+		once.Do(func() { doc = Spec(router) })
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
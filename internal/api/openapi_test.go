@@ -0,0 +1,268 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/executor"
+)
+
+// newContractServer mounts every route group, so the document lists the whole API
+func newContractServer(t *testing.T) (*asyncTestServer, *Document) {
+	t.Helper()
+	server := newAsyncTestServer(t)
+
+	exec := executor.NewWorkflowExecutor(server.registry, server.stateMgmt, executor.DefaultConfig())
+	exec.SetQueue(server.queue)
+	exec.SetCompletionHub(server.hub)
+	router, err := NewRouter(Dependencies{
+		Registry:      server.registry,
+		Executor:      exec,
+		ReloadManager: config.NewReloadManager(config.DefaultConfig(), ""),
+	}, GroupWorkflows, GroupExecution, GroupExecutions, GroupAdmin)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	server.router = router
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SpecPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET %s status = %d", SpecPath, recorder.Code)
+	}
+	var doc Document
+	if err := json.Unmarshal(recorder.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("GET %s returned an invalid document: %v", SpecPath, err)
+	}
+	return server, &doc
+}
+
+func TestSpecListsEveryRegisteredRoute(t *testing.T) {
+	server, doc := newContractServer(t)
+
+	registered := make(map[string]bool)
+	for _, info := range server.router.Routes() {
+		key := info.Method + " " + openAPIPath(info.Path)
+		registered[key] = true
+
+		op := doc.Paths[openAPIPath(info.Path)][strings.ToLower(info.Method)]
+		if op == nil {
+			t.Errorf("%s is served but missing from the document", key)
+			continue
+		}
+		if info.Path != SpecPath && op.OperationID == "" {
+			t.Errorf("%s is served but not described by the route table", key)
+		}
+	}
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			if key := strings.ToUpper(method) + " " + path; !registered[key] {
+				t.Errorf("%s is documented but not served", key)
+			}
+		}
+	}
+	if len(registered) != len(routes)+1 {
+		t.Errorf("router serves %d routes, want the %d of the route table and %s", len(registered), len(routes), SpecPath)
+	}
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	server, doc := newContractServer(t)
+	server.startWorker(t)
+	workflow := server.registerWorkflow(t)
+	id := workflow.GetID()
+
+	covered := make(map[string]bool)
+	call := func(method, template string, params map[string]string, query string, body interface{}) map[string]interface{} {
+		t.Helper()
+		path := template
+		for name, value := range params {
+			path = strings.Replace(path, "{"+name+"}", value, 1)
+		}
+		if query != "" {
+			path += "?" + query
+		}
+
+		var encoded []byte
+		if body != nil {
+			encoded, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(encoded))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, req)
+
+		op := doc.Paths[template][strings.ToLower(method)]
+		if op == nil {
+			t.Fatalf("%s %s is not documented", method, template)
+		}
+		covered[op.OperationID] = true
+
+		response, ok := op.Responses[strconv.Itoa(recorder.Code)]
+		if !ok {
+			t.Errorf("%s %s returned undocumented status %d: %s", method, path, recorder.Code, recorder.Body.String())
+			return nil
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("%s %s returned invalid JSON: %v", method, path, err)
+		}
+		for _, problem := range validate(doc, response.Content[jsonContent].Schema, decoded, "body") {
+			t.Errorf("%s %s (%d): %s", method, path, recorder.Code, problem)
+		}
+		object, _ := decoded.(map[string]interface{})
+		return object
+	}
+
+	v1 := func(path string) string { return BasePath + path }
+	workflowParam := map[string]string{"id": id}
+
+	call(http.MethodGet, v1("/workflows"), nil, "limit=10", nil)
+	created := call(http.MethodPost, v1("/workflows"), nil, "", map[string]interface{}{
+		"name": "contract", "steps": []map[string]string{{"type": "echo"}},
+	})
+	call(http.MethodGet, v1("/workflows/count"), nil, "", nil)
+	call(http.MethodGet, v1("/workflows/{id}"), workflowParam, "", nil)
+	call(http.MethodGet, v1("/workflows/{id}"), map[string]string{"id": "missing"}, "", nil)
+	call(http.MethodPut, v1("/workflows/{id}"), workflowParam, "", map[string]interface{}{"name": "renamed"})
+	call(http.MethodGet, v1("/workflows/{id}/exists"), workflowParam, "", nil)
+
+	call(http.MethodPost, v1("/workflows/{id}/execute"), workflowParam, "", map[string]interface{}{"timeout_ms": 5000})
+	call(http.MethodPost, v1("/execute"), nil, "", map[string]interface{}{"workflow_id": id, "timeout_ms": 5000})
+	call(http.MethodPost, v1("/execute/async"), nil, "", map[string]interface{}{"workflow_id": id})
+	accepted := call(http.MethodPost, v1("/workflows/{id}/async-execute"), workflowParam, "", map[string]interface{}{
+		"input_data": map[string]interface{}{"amount": "100"},
+	})
+	runID, _ := accepted["run_id"].(string)
+	if runID == "" {
+		t.Fatalf("async-execute returned no run_id: %v", accepted)
+	}
+	run := map[string]string{"runId": runID, "stepIndex": "0", "childStepIndex": "0"}
+
+	call(http.MethodGet, v1("/executions/{runId}/result"), run, "long_poll=true&wait_ms=5000", nil)
+	call(http.MethodGet, v1("/executions"), nil, "limit=5", nil)
+	call(http.MethodGet, v1("/executions/{runId}"), run, "", nil)
+	call(http.MethodGet, v1("/executions/{runId}/details"), run, "", nil)
+	call(http.MethodGet, v1("/executions/{runId}/data"), run, "", nil)
+	call(http.MethodGet, v1("/executions/{runId}/metrics"), run, "", nil)
+	for _, action := range []string{"cancel", "pause", "resume", "retry"} {
+		call(http.MethodPost, v1("/executions/{runId}/"+action), run, "", nil)
+	}
+	signal := map[string]string{"runId": runID, "name": "decision"}
+	call(http.MethodPost, v1("/executions/{runId}/signals/{name}"), signal, "", map[string]interface{}{
+		"payload": map[string]interface{}{"decision": "approve"},
+	})
+	call(http.MethodPost, v1("/executions/{runId}/signals/{name}"), map[string]string{"runId": "missing", "name": "decision"}, "", nil)
+	call(http.MethodGet, v1("/executions/{runId}/steps/{stepIndex}"), run, "", nil)
+	call(http.MethodGet, v1("/executions/{runId}/steps/{stepIndex}/child-steps/{childStepIndex}"), run, "", nil)
+	call(http.MethodGet, "/admin/config", nil, "", nil)
+
+	createdID, _ := created["id"].(string)
+	call(http.MethodDelete, v1("/workflows/{id}"), map[string]string{"id": createdID}, "", nil)
+
+	var missing []string
+	for _, r := range routes {
+		if !covered[r.operationID] {
+			missing = append(missing, r.operationID)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("operations not exercised by the contract test: %v", missing)
+	}
+}
+
+// validate checks a decoded JSON value against a schema of the document; objects may not carry
+// properties their schema does not declare
+func validate(doc *Document, schema *Schema, value interface{}, at string) []string {
+	if schema == nil {
+		return []string{at + ": no schema"}
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		component, ok := doc.Components.Schemas[name]
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown component %s", at, name)}
+		}
+		return validate(doc, component, value, at)
+	}
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && len(schema.AllOf) == 0) {
+			return nil
+		}
+		return []string{at + ": null is not allowed"}
+	}
+
+	var problems []string
+	for _, part := range schema.AllOf {
+		problems = append(problems, validate(doc, part, value, at)...)
+	}
+
+	mismatch := func() []string {
+		return append(problems, fmt.Sprintf("%s: %T does not match type %s", at, value, schema.Type))
+	}
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch()
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: required property %s is missing", at, name))
+			}
+		}
+		for name, property := range object {
+			if propertySchema, ok := schema.Properties[name]; ok {
+				problems = append(problems, validate(doc, propertySchema, property, at+"."+name)...)
+			} else if schema.AdditionalProperties != nil {
+				problems = append(problems, validate(doc, schema.AdditionalProperties, property, at+"."+name)...)
+			} else if schema.Properties != nil {
+				problems = append(problems, fmt.Sprintf("%s: property %s is not documented", at, name))
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		for i, item := range items {
+			problems = append(problems, validate(doc, schema.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return mismatch()
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a date-time", at, s))
+			}
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return mismatch()
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return mismatch()
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return mismatch()
+		}
+	}
+	return problems
+}
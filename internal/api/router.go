@@ -0,0 +1,164 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"unified-workflow/internal/api/handlers"
+	"unified-workflow/internal/api/middleware"
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/callback"
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/executor"
+	"unified-workflow/internal/registry"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BasePath is the prefix of the current API version
+const BasePath = "/api/v1"
+
+// SpecPath serves the OpenAPI document of the routes a binary mounts
+const SpecPath = "/openapi.json"
+
+// RouteGroup is a set of routes a binary can mount
+type RouteGroup string
+
+const (
+	// GroupWorkflows serves workflow definitions: list, get, create, update, delete
+	GroupWorkflows RouteGroup = "workflows"
+
+	// GroupExecution starts workflow runs, synchronously or asynchronously
+	GroupExecution RouteGroup = "execution"
+
+	// GroupExecutions inspects and controls workflow runs
+	GroupExecutions RouteGroup = "executions"
+
+	// GroupAdmin serves the administrative endpoints outside the versioned API
+	GroupAdmin RouteGroup = "admin"
+)
+
+// Dependencies are the services the route groups are backed by; each group only needs its own
+type Dependencies struct {
+	Registry      registry.Registry          // workflows, execution
+	Executor      *executor.WorkflowExecutor // execution, executions
+	Callbacks     *callback.Dispatcher       // execution; nil disables callback_url
+	ReloadManager *config.ReloadManager      // admin
+	Auth          *auth.Authenticator        // all groups; nil serves every route without authentication
+}
+
+// NewRouter creates a gin engine serving the given route groups and their OpenAPI document
+// Routes added to the engine afterwards, such as health checks, are listed in the document too
+func NewRouter(deps Dependencies, groups ...RouteGroup) (*gin.Engine, error) {
+	router := gin.New()
+	router.Use(gin.Logger())
+	router.Use(gin.Recovery())
+	router.NoRoute(func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, handlers.ErrorResponse{
+			Error: "Route not found",
+			Code:  handlers.CodeNotFound,
+		})
+	})
+
+	if err := Mount(router, deps, groups...); err != nil {
+		return nil, err
+	}
+	router.GET(SpecPath, specHandler(router))
+	return router, nil
+}
+
+// Mount registers the given route groups on the router
+func Mount(router *gin.Engine, deps Dependencies, groups ...RouteGroup) error {
+	h := &handlerSet{deps: deps}
+
+	for _, group := range groups {
+		if err := deps.check(group); err != nil {
+			return err
+		}
+
+		mounted := false
+		for _, r := range routes {
+			if r.group != group {
+				continue
+			}
+			chain := []gin.HandlerFunc{}
+			if deps.Auth != nil {
+				chain = append(chain, middleware.RequirePermission(deps.Auth, r.permission, r.audited))
+			} else {
+				chain = append(chain, middleware.ResolveTenant())
+			}
+			if r.successor != "" {
+				chain = append(chain, deprecated(r.successor))
+			}
+			router.Handle(r.method, r.fullPath(), append(chain, r.handler(h))...)
+			mounted = true
+		}
+		if !mounted {
+			return fmt.Errorf("unknown route group %q", group)
+		}
+	}
+	return nil
+}
+
+// handlerSet creates the handlers of the mounted groups on first use
+type handlerSet struct {
+	deps        Dependencies
+	definitions *handlers.DefinitionHandler
+	workflows   *handlers.WorkflowHandler
+	admin       *handlers.AdminHandler
+}
+
+func (h *handlerSet) definition() *handlers.DefinitionHandler {
+	if h.definitions == nil {
+		h.definitions = handlers.NewDefinitionHandler(h.deps.Registry)
+	}
+	return h.definitions
+}
+
+func (h *handlerSet) workflow() *handlers.WorkflowHandler {
+	if h.workflows == nil {
+		h.workflows = handlers.NewWorkflowHandler(h.deps.Executor, h.deps.Registry, h.deps.Callbacks)
+	}
+	return h.workflows
+}
+
+func (h *handlerSet) administration() *handlers.AdminHandler {
+	if h.admin == nil {
+		h.admin = handlers.NewAdminHandler(h.deps.ReloadManager)
+	}
+	return h.admin
+}
+
+// check reports a dependency missing for a route group
+func (d Dependencies) check(group RouteGroup) error {
+	missing := ""
+	switch group {
+	case GroupWorkflows:
+		if d.Registry == nil {
+			missing = "registry"
+		}
+	case GroupExecution, GroupExecutions:
+		if d.Registry == nil {
+			missing = "registry"
+		} else if d.Executor == nil {
+			missing = "executor"
+		}
+	case GroupAdmin:
+		if d.ReloadManager == nil {
+			missing = "reload manager"
+		}
+	}
+	if missing != "" {
+		return fmt.Errorf("route group %q requires a %s", group, missing)
+	}
+	return nil
+}
+
+// deprecated marks the responses of a route kept for compatibility and points to its successor
+func deprecated(successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", BasePath, successor))
+		c.Next()
+	}
+}
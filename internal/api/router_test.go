@@ -0,0 +1,950 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/admission"
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/executor"
+	"unified-workflow/internal/primitive"
+	primitiveModel "unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/registry"
+	"unified-workflow/internal/serviceclients/antifraud/antifraudtest"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+	"unified-workflow/workflows"
+
+	af "github.com/baraic-io/antifraud-go"
+	"github.com/gin-gonic/gin"
+)
+
+// asyncTestServer wires the workflow API to an in-memory queue and state store
+type asyncTestServer struct {
+	router    *gin.Engine
+	registry  *registry.InMemoryRegistry
+	queue     queue.Queue
+	stateMgmt state.StateManagement
+	hub       *completion.Hub
+}
+
+func newAsyncTestServer(t *testing.T) *asyncTestServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	if err := primitive.Init(&primitive.Config{EchoEnabled: true}); err != nil {
+		t.Fatalf("primitive.Init() error = %v", err)
+	}
+
+	reg := registry.NewInMemoryRegistry()
+	q := queue.NewInMemoryQueue()
+	stateMgmt := state.NewInMemoryState()
+	hub := completion.NewHub(0)
+
+	exec := executor.NewWorkflowExecutor(reg, stateMgmt, executor.DefaultConfig())
+	exec.SetQueue(q)
+	exec.SetCompletionHub(hub)
+	router, err := NewRouter(Dependencies{Registry: reg, Executor: exec}, GroupWorkflows, GroupExecution, GroupExecutions)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	return &asyncTestServer{router: router, registry: reg, queue: q, stateMgmt: stateMgmt, hub: hub}
+}
+
+// startWorker starts the embedded worker that drains the in-memory queue
+func (s *asyncTestServer) startWorker(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	local := executor.NewWorkflowExecutor(s.registry, s.stateMgmt, executor.DefaultConfig())
+	local.SetCompletionHub(s.hub)
+	stop, err := executor.StartResultRouting(ctx, s.queue, s.stateMgmt, local, s.hub)
+	if err != nil {
+		t.Fatalf("StartResultRouting() error = %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		stop()
+	})
+}
+
+func (s *asyncTestServer) registerWorkflow(t *testing.T) model.Workflow {
+	t.Helper()
+	step := model.NewBaseStep("validate", false)
+	step.AddChildStep(model.NewChildStep("check-limits", nil, nil, nil))
+	workflow := model.NewBaseWorkflow("payment", "async test workflow")
+	workflow.AddStep(step)
+	if err := s.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return workflow
+}
+
+func (s *asyncTestServer) do(t *testing.T, method, path string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s %s returned invalid JSON %q: %v", method, path, recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+func TestAsyncExecuteRunsOnWorkerAndReturnsResult(t *testing.T) {
+	server := newAsyncTestServer(t)
+	server.startWorker(t)
+	workflow := server.registerWorkflow(t)
+
+	code, accepted := server.do(t, http.MethodPost, "/api/v1/workflows/"+workflow.GetID()+"/async-execute", map[string]interface{}{
+		"input_data": map[string]interface{}{
+			"amount":     "100000",
+			"client_pan": "4111111111111111",
+		},
+	})
+	if code != http.StatusAccepted {
+		t.Fatalf("async-execute status = %d, body = %v", code, accepted)
+	}
+	runID, _ := accepted["run_id"].(string)
+	if runID == "" {
+		t.Fatalf("async-execute returned no run_id: %v", accepted)
+	}
+
+	code, response := server.do(t, http.MethodGet, "/api/v1/executions/"+runID+"/result?long_poll=true&wait_ms=5000", nil)
+	if code != http.StatusOK {
+		t.Fatalf("result status = %d, body = %v", code, response)
+	}
+	if response["status"] != "completed" {
+		t.Fatalf("result status = %v, want completed: %v", response["status"], response)
+	}
+
+	result := response["result"].(map[string]interface{})
+	if result["step_count"] != float64(1) {
+		t.Errorf("result step_count = %v, want 1", result["step_count"])
+	}
+	if _, ok := result["execution_time_millis"].(float64); !ok {
+		t.Errorf("result has no execution_time_millis: %v", result)
+	}
+	if result["workflow_id"] != workflow.GetID() {
+		t.Errorf("result workflow_id = %v, want %s", result["workflow_id"], workflow.GetID())
+	}
+	data := result["result"].(map[string]interface{})
+	if data["amount"] != "100000" {
+		t.Errorf("result data lost input amount: %v", data)
+	}
+	if data["client_pan"] != "************1111" {
+		t.Errorf("result client_pan = %v, want masked value", data["client_pan"])
+	}
+
+	code, status := server.do(t, http.MethodGet, "/api/v1/executions/"+runID, nil)
+	if code != http.StatusOK || status["status"] != "completed" {
+		t.Errorf("execution status = %d %v, want completed", code, status["status"])
+	}
+}
+
+func TestAsyncExecuteRecordsWorkerFailure(t *testing.T) {
+	server := newAsyncTestServer(t)
+	workflow := server.registerWorkflow(t)
+
+	code, accepted := server.do(t, http.MethodPost, "/api/v1/workflows/"+workflow.GetID()+"/async-execute", map[string]interface{}{})
+	if code != http.StatusAccepted {
+		t.Fatalf("async-execute status = %d, body = %v", code, accepted)
+	}
+	runID := accepted["run_id"].(string)
+	code, pending := server.do(t, http.MethodGet, "/api/v1/executions/"+runID+"/result", nil)
+	if code != http.StatusAccepted || pending["status"] != "pending" {
+		t.Fatalf("result before processing = %d %v, want pending", code, pending)
+	}
+
+	// The worker can no longer resolve the workflow
+	if err := server.registry.RemoveWorkflow(context.Background(), workflow.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	server.startWorker(t)
+
+	code, response := server.do(t, http.MethodGet, "/api/v1/executions/"+runID+"/result?long_poll=true&wait_ms=5000", nil)
+	if code != http.StatusOK || response["status"] != "failed" {
+		t.Fatalf("result = %d %v, want failed", code, response)
+	}
+	result := response["result"].(map[string]interface{})
+	if result["error_message"] == "" || result["error_message"] == nil {
+		t.Errorf("failed result has no error_message: %v", result)
+	}
+}
+
+func TestTypedStepsRunOnWorker(t *testing.T) {
+	server := newAsyncTestServer(t)
+	server.startWorker(t)
+
+	type card struct {
+		ClientPAN string `json:"client_pan"`
+		Amount    int    `json:"amount"`
+	}
+	step := typed.NewStep("authorize", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		c, _ := typed.Result[card](data, "read-card")
+		if c.Amount > 1000 {
+			return fmt.Errorf("amount %d over limit", c.Amount)
+		}
+		data.Put("authorized", true)
+		return nil
+	})
+	typed.AddChildSteps(step, typed.NewChildStep[card, card](
+		"read-card",
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (card, error) {
+			pan, _ := data.Get("pan").(string)
+			amount, _ := data.Get("amount").(float64)
+			return card{ClientPAN: pan, Amount: int(amount)}, nil
+		},
+		nil, nil,
+	))
+	workflow := model.NewBaseWorkflow("typed", "typed step workflow")
+	workflow.AddStep(step)
+	if err := server.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(amount int) map[string]interface{} {
+		code, accepted := server.do(t, http.MethodPost, "/api/v1/workflows/"+workflow.GetID()+"/async-execute", map[string]interface{}{
+			"input_data": map[string]interface{}{"pan": "4111111111111111", "amount": amount},
+		})
+		if code != http.StatusAccepted {
+			t.Fatalf("async-execute status = %d, body = %v", code, accepted)
+		}
+		code, response := server.do(t, http.MethodGet, "/api/v1/executions/"+accepted["run_id"].(string)+"/result?long_poll=true&wait_ms=5000", nil)
+		if code != http.StatusOK {
+			t.Fatalf("result status = %d, body = %v", code, response)
+		}
+		return response
+	}
+
+	response := run(500)
+	if response["status"] != "completed" {
+		t.Fatalf("result status = %v, want completed: %v", response["status"], response)
+	}
+	data := response["result"].(map[string]interface{})["result"].(map[string]interface{})
+	if data["authorized"] != true {
+		t.Errorf("step logic did not run: %v", data)
+	}
+	readCard, ok := data[model.ResultKey("read-card")].(map[string]interface{})
+	if !ok || readCard["client_pan"] != "************1111" {
+		t.Errorf("read-card result = %v, want its client_pan masked", data[model.ResultKey("read-card")])
+	}
+
+	response = run(5000)
+	if response["status"] != "failed" {
+		t.Fatalf("result status = %v, want failed: %v", response["status"], response)
+	}
+	if message, _ := response["result"].(map[string]interface{})["error_message"].(string); !strings.Contains(message, "over limit") {
+		t.Errorf("error_message = %q, want the step logic error", message)
+	}
+}
+
+func TestDeclarativeConditionsSkipAndBranch(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	code, created := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{
+		"name": "risk-routing",
+		"steps": []map[string]interface{}{
+			{"type": "sequential", "name": "ml-validation", "when": `aml.resolution != "FAIL"`},
+			{"type": "branch", "name": "route", "branches": []map[string]interface{}{
+				{"name": "manual-review", "when": "risk_score > 70", "steps": []map[string]interface{}{{"type": "sequential", "name": "review"}}},
+			}, "default": []map[string]interface{}{{"type": "sequential", "name": "approve"}}},
+		},
+	})
+	if code != http.StatusCreated {
+		t.Fatalf("create status = %d, body = %v", code, created)
+	}
+	workflowID := created["id"].(string)
+
+	code, response := server.do(t, http.MethodPost, "/api/v1/workflows/"+workflowID+"/execute", map[string]interface{}{
+		"input_data": map[string]interface{}{"aml": map[string]interface{}{"resolution": "FAIL"}, "risk_score": 85},
+	})
+	if code != http.StatusOK || response["status"] != "completed" {
+		t.Fatalf("execute = %d %v, want completed", code, response)
+	}
+
+	want := map[string][2]string{
+		"ml-validation": {"skipped", ""},
+		"route":         {"completed", ""},
+		"review":        {"completed", "route.manual-review"},
+		"approve":       {"skipped", "route.default"},
+	}
+	stepResults := response["steps"].([]interface{})
+	if len(stepResults) != len(want) {
+		t.Fatalf("step results = %v, want %d", stepResults, len(want))
+	}
+	for _, raw := range stepResults {
+		stepResult := raw.(map[string]interface{})
+		name := stepResult["name"].(string)
+		branch, _ := stepResult["branch"].(string)
+		if got := [2]string{stepResult["status"].(string), branch}; got != want[name] {
+			t.Errorf("step %s = %v, want %v", name, got, want[name])
+		}
+		if stepResult["status"] == "skipped" && stepResult["skip_reason"] == nil {
+			t.Errorf("skipped step %s has no skip_reason", name)
+		}
+	}
+	if stepResults[1].(map[string]interface{})["selected_branch"] != "manual-review" {
+		t.Errorf("route selected_branch = %v, want manual-review", stepResults[1])
+	}
+
+	code, invalid := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{
+		"name":  "broken",
+		"steps": []map[string]interface{}{{"type": "sequential", "when": "risk_score >"}},
+	})
+	if code != http.StatusBadRequest || invalid["code"] != "INVALID_REQUEST" {
+		t.Errorf("invalid condition = %d %v, want 400 INVALID_REQUEST", code, invalid)
+	}
+}
+
+func TestCreateWorkflowRejectsInvalidGraph(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	for name, steps := range map[string][]map[string]interface{}{
+		"cycle": {
+			{"type": "sequential", "name": "a", "depends_on": []string{"b"}},
+			{"type": "sequential", "name": "b", "depends_on": []string{"a"}},
+		},
+		"unknown dependency": {
+			{"type": "sequential", "name": "a", "depends_on": []string{"missing"}},
+		},
+		"nested dependency": {
+			{"type": "sequential", "name": "a"},
+			{"type": "branch", "name": "route", "branches": []map[string]interface{}{
+				{"name": "high", "when": "risk_score > 70", "steps": []map[string]interface{}{{"type": "sequential", "name": "review", "depends_on": []string{"a"}}}},
+			}},
+		},
+	} {
+		code, invalid := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{"name": name, "steps": steps})
+		if code != http.StatusBadRequest || invalid["code"] != "INVALID_REQUEST" {
+			t.Errorf("%s = %d %v, want 400 INVALID_REQUEST", name, code, invalid)
+		}
+	}
+}
+
+func TestSubWorkflowRunsAsLinkedRun(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	// Version 1 of the limits workflow approves up to 1000, version 2 up to 500
+	register := func(limit float64) model.Workflow {
+		step := typed.NewStep("check-limit", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+			amount, _ := data.GetFloat("amount")
+			data.Put("approved", amount <= limit)
+			return nil
+		})
+		workflow := model.NewBaseWorkflow("limits", "limits workflow")
+		workflow.ID = "limits"
+		workflow.AddStep(step)
+		if err := server.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+			t.Fatal(err)
+		}
+		return workflow
+	}
+	register(1000)
+	register(500)
+
+	execute := func(version int) (map[string]interface{}, string) {
+		code, created := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{
+			"name": "payment",
+			"steps": []map[string]interface{}{{
+				"type": "subworkflow", "name": "limits", "workflow": "limits", "version": version,
+				"input":  map[string]string{"amount": "payment.amount"},
+				"output": map[string]string{"limit_approved": "approved"},
+			}},
+		})
+		if code != http.StatusCreated {
+			t.Fatalf("create status = %d, body = %v", code, created)
+		}
+		code, response := server.do(t, http.MethodPost, "/api/v1/workflows/"+created["id"].(string)+"/execute", map[string]interface{}{
+			"input_data": map[string]interface{}{"payment": map[string]interface{}{"amount": 800}},
+		})
+		if code != http.StatusOK || response["status"] != "completed" {
+			t.Fatalf("execute = %d %v, want completed", code, response)
+		}
+		childRunID, _ := response["steps"].([]interface{})[0].(map[string]interface{})["child_run_id"].(string)
+		if childRunID == "" {
+			t.Fatalf("sub-workflow step has no child_run_id: %v", response["steps"])
+		}
+		return response, childRunID
+	}
+
+	for version, want := range map[int]bool{1: true, 2: false, 0: false} {
+		response, _ := execute(version)
+		if got := response["result"].(map[string]interface{})["limit_approved"]; got != want {
+			t.Errorf("version %d: limit_approved = %v, want %v", version, got, want)
+		}
+	}
+
+	response, childRunID := execute(1)
+	parentRunID := response["run_id"].(string)
+	code, details := server.do(t, http.MethodGet, "/api/v1/executions/"+parentRunID+"/details", nil)
+	if code != http.StatusOK {
+		t.Fatalf("details status = %d, body = %v", code, details)
+	}
+	if childRunIDs, _ := details["child_run_ids"].([]interface{}); len(childRunIDs) != 1 || childRunIDs[0] != childRunID {
+		t.Errorf("child_run_ids = %v, want [%s]", details["child_run_ids"], childRunID)
+	}
+	childRuns, _ := details["child_runs"].([]interface{})
+	if len(childRuns) != 1 {
+		t.Fatalf("child_runs = %v, want one run", details["child_runs"])
+	}
+	if child := childRuns[0].(map[string]interface{}); child["run_id"] != childRunID || child["parent_run_id"] != parentRunID || child["status"] != "completed" {
+		t.Errorf("child run = %v, want completed run %s of parent %s", child, childRunID, parentRunID)
+	}
+}
+
+func TestCancellingRunCancelsSubWorkflow(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	started := make(chan primitiveModel.WorkflowContext, 1)
+	blocking := model.NewBaseWorkflow("blocking", "waits until cancelled")
+	blocking.AddStep(typed.NewStep("wait", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		started <- workflowContext
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("not cancelled")
+		}
+	}))
+	parent := model.NewBaseWorkflow("parent", "runs the blocking workflow")
+	parent.AddStep(model.NewSubWorkflowStep("child", blocking.GetID()))
+	parent.AddStep(model.NewSequentialStep("after"))
+	for _, workflow := range []model.Workflow{blocking, parent} {
+		if err := server.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := make(chan map[string]interface{}, 1)
+	go func() {
+		_, response := server.do(t, http.MethodPost, "/api/v1/workflows/"+parent.GetID()+"/execute", map[string]interface{}{})
+		done <- response
+	}()
+	childContext := <-started
+	if code, response := server.do(t, http.MethodPost, "/api/v1/executions/"+childContext.GetParentRunID()+"/cancel", nil); code != http.StatusOK {
+		t.Fatalf("cancel = %d %v", code, response)
+	}
+
+	response := <-done
+	if response["status"] != "cancelled" {
+		t.Errorf("parent status = %v, want cancelled: %v", response["status"], response)
+	}
+	if steps := response["steps"].([]interface{}); len(steps) != 1 || steps[0].(map[string]interface{})["status"] != "cancelled" {
+		t.Errorf("steps = %v, want only the sub-workflow step, cancelled", steps)
+	}
+	code, status := server.do(t, http.MethodGet, "/api/v1/executions/"+childContext.GetRunID(), nil)
+	if code != http.StatusOK || status["status"] != "cancelled" {
+		t.Errorf("child run = %d %v, want cancelled", code, status)
+	}
+
+	code, response = server.do(t, http.MethodPost, "/api/v1/executions/"+childContext.GetParentRunID()+"/cancel", nil)
+	if code != http.StatusConflict || response["code"] != "EXECUTION_FINISHED" {
+		t.Errorf("cancel of a finished run = %d %v, want 409 EXECUTION_FINISHED", code, response)
+	}
+	if code, response = server.do(t, http.MethodPost, "/api/v1/executions/run-unknown/cancel", nil); code != http.StatusNotFound || response["code"] != "EXECUTION_NOT_FOUND" {
+		t.Errorf("cancel of an unknown run = %d %v, want 404 EXECUTION_NOT_FOUND", code, response)
+	}
+}
+
+func TestDeclarativeForEachRunsSubWorkflowPerItem(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	limits := model.NewBaseWorkflow("limits", "approves amounts up to 500")
+	limits.ID = "limits"
+	limits.AddStep(typed.NewStep("check-limit", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		amount, _ := data.GetFloat("amount")
+		data.Put("approved", amount <= 500)
+		return nil
+	}))
+	if err := server.registry.RegisterWorkflow(context.Background(), limits); err != nil {
+		t.Fatal(err)
+	}
+
+	code, created := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{
+		"name": "batch",
+		"steps": []map[string]interface{}{{
+			"type": "foreach", "name": "check-payments", "items": "payments", "item_key": "payment",
+			"concurrency": 3, "into": "decisions", "workflow": "limits",
+			"input":  map[string]string{"amount": "payment.amount"},
+			"output": map[string]string{"approved": "approved"},
+		}},
+	})
+	if code != http.StatusCreated {
+		t.Fatalf("create status = %d, body = %v", code, created)
+	}
+	code, response := server.do(t, http.MethodPost, "/api/v1/workflows/"+created["id"].(string)+"/execute", map[string]interface{}{
+		"input_data": map[string]interface{}{"payments": []map[string]interface{}{{"amount": 100}, {"amount": 900}, {"amount": 500}}},
+	})
+	if code != http.StatusOK || response["status"] != "completed" {
+		t.Fatalf("execute = %d %v, want completed", code, response)
+	}
+	if got := fmt.Sprint(response["result"].(map[string]interface{})["decisions"]); got != "[map[approved:true] map[approved:false] map[approved:true]]" {
+		t.Errorf("decisions = %s, want one decision per payment in order", got)
+	}
+	items := response["steps"].([]interface{})[0].(map[string]interface{})["items"].([]interface{})
+	for _, item := range items {
+		if item.(map[string]interface{})["child_run_id"] == "" {
+			t.Errorf("item %v has no child run", item)
+		}
+	}
+	_, details := server.do(t, http.MethodGet, "/api/v1/executions/"+response["run_id"].(string)+"/details", nil)
+	if childRuns, _ := details["child_runs"].([]interface{}); len(childRuns) != 3 {
+		t.Errorf("child_runs = %v, want a run per payment", details["child_runs"])
+	}
+
+	for name, step := range map[string]map[string]interface{}{
+		"no items":        {"type": "foreach", "name": "each", "workflow": "limits"},
+		"no workflow":     {"type": "foreach", "name": "each", "items": "payments"},
+		"unknown policy":  {"type": "foreach", "name": "each", "items": "payments", "workflow": "limits", "on_error": "ignore"},
+		"items elsewhere": {"type": "sequential", "name": "each", "items": "payments"},
+	} {
+		code, body := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{"name": "batch", "steps": []interface{}{step}})
+		if code != http.StatusBadRequest {
+			t.Errorf("%s: create status = %d, want 400: %v", name, code, body)
+		}
+	}
+}
+
+func TestSignalEndpoint(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	runIDs := make(chan string, 1)
+	workflow := model.NewBaseWorkflow("review", "waits for the decision of an analyst")
+	workflow.AddStep(typed.NewStep("flag", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		runIDs <- workflowContext.GetRunID()
+		return nil
+	}))
+	workflow.AddStep(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(5 * time.Second))
+	if err := server.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan map[string]interface{}, 1)
+	go func() {
+		_, response := server.do(t, http.MethodPost, "/api/v1/workflows/"+workflow.GetID()+"/execute", map[string]interface{}{})
+		done <- response
+	}()
+	runID := <-runIDs
+	server.awaitStatus(t, runID, "waiting")
+
+	code, response := server.do(t, http.MethodPost, "/api/v1/executions/"+runID+"/signals/escalation", nil)
+	if code != http.StatusConflict || response["code"] != "EXECUTION_NOT_WAITING" {
+		t.Errorf("signal not waited for = %d %v, want 409 EXECUTION_NOT_WAITING", code, response)
+	}
+
+	code, response = server.do(t, http.MethodPost, "/api/v1/executions/"+runID+"/signals/decision", map[string]interface{}{
+		"payload": map[string]interface{}{"decision": "approve"},
+	})
+	if code != http.StatusOK || response["run_id"] != runID {
+		t.Fatalf("signal = %d %v", code, response)
+	}
+	if response = <-done; response["status"] != "completed" || response["result"].(map[string]interface{})["decision"] != "approve" {
+		t.Fatalf("execute = %v, want completed with the payload merged", response)
+	}
+
+	code, response = server.do(t, http.MethodPost, "/api/v1/executions/"+runID+"/signals/decision", nil)
+	if code != http.StatusConflict || response["code"] != "EXECUTION_FINISHED" {
+		t.Errorf("signal to a finished run = %d %v, want 409 EXECUTION_FINISHED", code, response)
+	}
+	if code, response = server.do(t, http.MethodPost, "/api/v1/executions/run-unknown/signals/decision", nil); code != http.StatusNotFound {
+		t.Errorf("signal to an unknown run = %d %v, want 404", code, response)
+	}
+}
+
+// awaitStatus polls the status of a run until it reaches want
+func (s *asyncTestServer) awaitStatus(t *testing.T, runID, want string) map[string]interface{} {
+	t.Helper()
+	var status map[string]interface{}
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if _, status = s.do(t, http.MethodGet, "/api/v1/executions/"+runID, nil); status["status"] == want {
+			return status
+		}
+	}
+	t.Fatalf("status = %v, want %s", status, want)
+	return nil
+}
+
+func TestDefinitionTimeouts(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	code, created := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{
+		"name":       "screening",
+		"timeout_ms": 60000,
+		"steps": []map[string]interface{}{
+			{"type": "sequential", "name": "aml-check", "timeout_ms": 5000},
+			{"type": "wait", "name": "analyst-review", "signal": "decision", "timeout_ms": 86400000},
+		},
+	})
+	if code != http.StatusCreated {
+		t.Fatalf("create = %d %v", code, created)
+	}
+	_, workflow := server.do(t, http.MethodGet, "/api/v1/workflows/"+created["id"].(string), nil)
+	steps, _ := workflow["steps"].([]interface{})
+	if workflow["timeout_ms"] != float64(60000) || len(steps) != 2 ||
+		steps[0].(map[string]interface{})["timeout_ms"] != float64(5000) || steps[1].(map[string]interface{})["timeout_ms"] != float64(86400000) {
+		t.Errorf("workflow = %v, want the run, step and wait timeouts", workflow)
+	}
+
+	code, invalid := server.do(t, http.MethodPost, "/api/v1/workflows", map[string]interface{}{
+		"name":  "cool-off",
+		"steps": []map[string]interface{}{{"type": "timer", "name": "cool-off", "delay_ms": 1000, "timeout_ms": 5000}},
+	})
+	if code != http.StatusBadRequest || invalid["code"] != "INVALID_REQUEST" {
+		t.Errorf("timer with a timeout = %d %v, want 400 INVALID_REQUEST", code, invalid)
+	}
+}
+
+func TestAntifraudWorkflowAgainstFakeService(t *testing.T) {
+	server := newAsyncTestServer(t)
+	fake := antifraudtest.NewServer("test-api-key")
+	defer fake.Close()
+	fake.SetDelay(30 * time.Millisecond)
+	primitive.ResetForTesting()
+	t.Cleanup(primitive.ResetForTesting)
+	err := primitive.Init(&primitive.Config{
+		EchoEnabled:      true,
+		AntifraudEnabled: true,
+		AntifraudAPIKey:  fake.APIKey,
+		AntifraudAPIHost: fake.URL,
+		AntifraudTimeout: 5,
+	})
+	if err != nil {
+		t.Fatalf("primitive.Init() error = %v", err)
+	}
+
+	execute := func(timeout time.Duration) map[string]interface{} {
+		workflow := workflows.CreateAntifraudTransactionWorkflow(fake.URL)
+		workflow.(model.TimedWorkflow).SetTimeout(timeout)
+		if err := server.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+			t.Fatal(err)
+		}
+		_, response := server.do(t, http.MethodPost, "/api/v1/workflows/"+workflow.GetID()+"/execute", map[string]interface{}{
+			"input_data": map[string]interface{}{"transaction": map[string]interface{}{
+				"id": "txn-1", "type": "deposit", "amount": "100000", "currency": "KZT", "client_id": "client-1",
+			}},
+		})
+		return response
+	}
+
+	response := execute(0)
+	if response["status"] != "completed" {
+		t.Fatalf("status = %v, want completed: %v", response["status"], response)
+	}
+	for _, path := range []string{"/api/amlsvc/validate", "/api/fcsvc/validate", "/api/mlsvc/validate", "/api/fzrsvc/transaction/finalize"} {
+		if calls := fake.Calls(path); calls != 1 {
+			t.Errorf("%s called %d times, want once", path, calls)
+		}
+	}
+	if calls := fake.Calls("/api/fzrsvc/transaction/add-service-check"); calls != 3 {
+		t.Errorf("service checks added = %d, want 3", calls)
+	}
+	if got := fake.MaxInFlight(); got != 3 {
+		t.Errorf("requests in flight at once = %d, want the AML, FC and ML validations awaited concurrently", got)
+	}
+
+	fake.SetResolution("FC", af.ServiceResolution{Fraud: true})
+	response = execute(0)
+	if response["status"] != "failed" || !strings.Contains(fmt.Sprint(response["steps"]), "FC validation failed: potential fraud detected") {
+		t.Errorf("status = %v %v, want failed by the FC resolution", response["status"], response["steps"])
+	}
+	if calls := fake.Calls("/api/fzrsvc/transaction/finalize"); calls != 1 {
+		t.Errorf("finalize called %d times, want a rejected transaction not finalized", calls)
+	}
+
+	fake.SetDelay(time.Minute)
+	started := time.Now()
+	response = execute(100 * time.Millisecond)
+	if response["status"] != "timed_out" {
+		t.Errorf("status = %v %v, want timed_out by the run timeout", response["status"], response["error"])
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("run returned after %s, want the antifraud call cancelled with the run", elapsed)
+	}
+}
+
+func TestExecutionResultUnknownRun(t *testing.T) {
+	server := newAsyncTestServer(t)
+
+	code, response := server.do(t, http.MethodGet, "/api/v1/executions/run-unknown/result", nil)
+	if code != http.StatusNotFound {
+		t.Errorf("result for unknown run status = %d, want %d", code, http.StatusNotFound)
+	}
+	if response["code"] != "EXECUTION_NOT_FOUND" {
+		t.Errorf("result for unknown run code = %v, want EXECUTION_NOT_FOUND", response["code"])
+	}
+}
+
+func TestRequestValidationReturnsErrorEnvelope(t *testing.T) {
+	server := newAsyncTestServer(t)
+	workflow := server.registerWorkflow(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		status int
+		code   string
+	}{
+		{"negative timeout", http.MethodPost, "/api/v1/workflows/" + workflow.GetID() + "/execute",
+			map[string]interface{}{"timeout_ms": -1}, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"workflow id mismatch", http.MethodPost, "/api/v1/workflows/" + workflow.GetID() + "/async-execute",
+			map[string]interface{}{"workflow_id": "other"}, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"missing workflow name", http.MethodPost, "/api/v1/workflows",
+			map[string]interface{}{"description": "no name"}, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"unknown step type", http.MethodPost, "/api/v1/workflows",
+			map[string]interface{}{"name": "w", "steps": []map[string]string{{"type": "shell"}}}, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"invalid limit", http.MethodGet, "/api/v1/workflows?limit=abc", nil, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"unknown workflow", http.MethodGet, "/api/v1/workflows/missing", nil, http.StatusNotFound, "WORKFLOW_NOT_FOUND"},
+		{"unknown route", http.MethodGet, "/api/v2/workflows", nil, http.StatusNotFound, "NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := server.do(t, tt.method, tt.path, tt.body)
+			if code != tt.status || response["code"] != tt.code {
+				t.Errorf("%s %s = %d %v, want %d %s", tt.method, tt.path, code, response["code"], tt.status, tt.code)
+			}
+			if message, _ := response["error"].(string); message == "" {
+				t.Errorf("%s %s returned no error message: %v", tt.method, tt.path, response)
+			}
+		})
+	}
+}
+
+func TestMountsOnlyRequestedGroups(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := registry.NewInMemoryRegistry()
+
+	router, err := NewRouter(Dependencies{Registry: reg}, GroupWorkflows)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	server := &asyncTestServer{router: router}
+	if code, _ := server.do(t, http.MethodGet, "/api/v1/workflows", nil); code != http.StatusOK {
+		t.Errorf("workflows status = %d, want %d", code, http.StatusOK)
+	}
+	if code, _ := server.do(t, http.MethodGet, "/api/v1/executions", nil); code != http.StatusNotFound {
+		t.Errorf("executions status = %d on a registry-only router, want %d", code, http.StatusNotFound)
+	}
+
+	if _, err := NewRouter(Dependencies{Registry: reg}, GroupExecution); err == nil {
+		t.Error("NewRouter() without an executor error = nil, want error")
+	}
+}
+
+func TestAuthEnforcesRoutePermissions(t *testing.T) {
+	cfg := config.DefaultConfig().Auth
+	cfg.Enabled = true
+	cfg.APIKeys = []config.APIKeyConfig{
+		{ID: "viewer", Key: "viewer-key", Roles: []string{"viewer"}},
+		{ID: "operator", Key: "operator-key", Roles: []string{"operator"}, Permissions: []string{auth.PermissionExecutionsReadSensitive}},
+	}
+	authenticator, err := auth.NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newAsyncTestServer(t)
+	router, err := NewRouter(Dependencies{Registry: server.registry, Executor: newTestExecutor(server), Auth: authenticator},
+		GroupWorkflows, GroupExecution, GroupExecutions)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	server.router = router
+	workflow := server.registerWorkflow(t)
+
+	request := func(method, path, key string, body interface{}) (int, map[string]interface{}) {
+		encoded, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewReader(encoded))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, req)
+		var response map[string]interface{}
+		json.Unmarshal(recorder.Body.Bytes(), &response)
+		return recorder.Code, response
+	}
+
+	if code, response := request(http.MethodGet, "/api/v1/workflows", "", nil); code != http.StatusUnauthorized || response["code"] != "UNAUTHORIZED" {
+		t.Errorf("anonymous list = %d %v, want 401 UNAUTHORIZED", code, response["code"])
+	}
+	if code, _ := request(http.MethodGet, "/api/v1/workflows", "viewer-key", nil); code != http.StatusOK {
+		t.Errorf("viewer list = %d, want 200", code)
+	}
+	if code, response := request(http.MethodDelete, "/api/v1/workflows/"+workflow.GetID(), "operator-key", nil); code != http.StatusForbidden || response["code"] != "FORBIDDEN" {
+		t.Errorf("operator delete = %d %v, want 403 FORBIDDEN", code, response["code"])
+	}
+	if code, _ := request(http.MethodGet, SpecPath, "", nil); code != http.StatusOK {
+		t.Errorf("anonymous %s = %d, want 200", SpecPath, code)
+	}
+
+	// Classified fields are only returned in clear text to holders of executions:read-sensitive
+	input := map[string]interface{}{"input_data": map[string]interface{}{"client_pan": "4111111111111111"}, "timeout_ms": 5000}
+	if code, _ := request(http.MethodPost, "/api/v1/workflows/"+workflow.GetID()+"/execute", "viewer-key", input); code != http.StatusForbidden {
+		t.Errorf("viewer execute = %d, want 403", code)
+	}
+	code, response := request(http.MethodPost, "/api/v1/workflows/"+workflow.GetID()+"/execute", "operator-key", input)
+	if code != http.StatusOK {
+		t.Fatalf("operator execute = %d %v, want 200", code, response)
+	}
+	runID, _ := response["run_id"].(string)
+	for key, want := range map[string]string{"viewer-key": "************1111", "operator-key": "4111111111111111"} {
+		code, data := request(http.MethodGet, "/api/v1/executions/"+runID+"/data", key, nil)
+		if code != http.StatusOK {
+			t.Fatalf("%s data = %d %v, want 200", key, code, data)
+		}
+		if pan := data["data"].(map[string]interface{})["client_pan"]; pan != want {
+			t.Errorf("%s client_pan = %v, want %s", key, pan, want)
+		}
+	}
+}
+
+func TestTenantsAreIsolated(t *testing.T) {
+	cfg := config.DefaultConfig().Auth
+	cfg.Enabled = true
+	cfg.APIKeys = []config.APIKeyConfig{
+		{ID: "acme", Key: "acme-key", Roles: []string{"operator"}, Tenant: "acme"},
+		{ID: "globex", Key: "globex-key", Roles: []string{"operator"}, Tenant: "globex"},
+		{ID: "platform", Key: "platform-key", Roles: []string{"operator"}, Tenant: auth.AnyTenant},
+	}
+	authenticator, err := auth.NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotas, err := tenant.NewQuotas(config.TenancyConfig{
+		Quotas: map[string]config.TenantQuotaConfig{"acme": {MaxConcurrentRuns: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newAsyncTestServer(t)
+	exec := newTestExecutor(server)
+	exec.SetQuotas(quotas)
+	router, err := NewRouter(Dependencies{Registry: server.registry, Executor: exec, Auth: authenticator},
+		GroupWorkflows, GroupExecution, GroupExecutions)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	server.router = router
+
+	workflow := model.NewBaseWorkflow("payment", "acme workflow")
+	workflow.AddStep(model.NewBaseStep("validate", false))
+	if err := server.registry.RegisterWorkflow(tenant.WithTenant(context.Background(), "acme"), workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	request := func(method, path, key, tenantID string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte("{}")))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		if tenantID != "" {
+			req.Header.Set(tenant.Header, tenantID)
+		}
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, req)
+		var response map[string]interface{}
+		json.Unmarshal(recorder.Body.Bytes(), &response)
+		return recorder, response
+	}
+
+	workflowPath := "/api/v1/workflows/" + workflow.GetID()
+	if rec, _ := request(http.MethodGet, workflowPath, "acme-key", ""); rec.Code != http.StatusOK {
+		t.Errorf("acme get = %d, want 200", rec.Code)
+	}
+	if rec, _ := request(http.MethodGet, workflowPath, "globex-key", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("globex get = %d, want 404", rec.Code)
+	}
+	if rec, _ := request(http.MethodGet, workflowPath, "globex-key", "acme"); rec.Code != http.StatusForbidden {
+		t.Errorf("globex get as acme = %d, want 403", rec.Code)
+	}
+	if rec, _ := request(http.MethodGet, workflowPath, "platform-key", "acme"); rec.Code != http.StatusOK {
+		t.Errorf("platform get as acme = %d, want 200", rec.Code)
+	}
+	if rec, _ := request(http.MethodGet, workflowPath, "platform-key", "Not A Tenant"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid tenant header = %d, want 400", rec.Code)
+	}
+
+	// No worker drains the queue, so the first run keeps the only concurrency slot of acme
+	rec, accepted := request(http.MethodPost, workflowPath+"/async-execute", "acme-key", "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("acme async-execute = %d %v, want 202", rec.Code, accepted)
+	}
+	runID, _ := accepted["run_id"].(string)
+	rec, rejected := request(http.MethodPost, workflowPath+"/async-execute", "acme-key", "")
+	if rec.Code != http.StatusTooManyRequests || rejected["code"] != "QUOTA_EXCEEDED" {
+		t.Errorf("second acme async-execute = %d %v, want 429 QUOTA_EXCEEDED", rec.Code, rejected["code"])
+	}
+	if rec, _ := request(http.MethodGet, "/api/v1/executions/"+runID, "acme-key", ""); rec.Code != http.StatusOK {
+		t.Errorf("acme execution status = %d, want 200", rec.Code)
+	}
+	if rec, _ := request(http.MethodGet, "/api/v1/executions/"+runID, "globex-key", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("globex execution status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdmissionRejectsWithRetryAfter(t *testing.T) {
+	server := newAsyncTestServer(t)
+	exec := newTestExecutor(server)
+	exec.SetAdmission(admission.NewController(config.AdmissionConfig{
+		WorkflowRate: config.RateLimitConfig{RequestsPerSecond: 0.5},
+	}, 0, nil))
+	router, err := NewRouter(Dependencies{Registry: server.registry, Executor: exec}, GroupExecution)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	server.router = router
+	workflow := server.registerWorkflow(t)
+	path := "/api/v1/workflows/" + workflow.GetID() + "/async-execute"
+
+	if code, response := server.do(t, http.MethodPost, path, nil); code != http.StatusAccepted {
+		t.Fatalf("first async-execute = %d %v, want 202", code, response)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, req)
+	var response map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	if recorder.Code != http.StatusTooManyRequests || response["code"] != "RATE_LIMITED" {
+		t.Fatalf("second async-execute = %d %v, want 429 RATE_LIMITED", recorder.Code, response)
+	}
+	if got := recorder.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+}
+
+// newTestExecutor creates an executor on the state store and completion hub of the test server
+func newTestExecutor(server *asyncTestServer) *executor.WorkflowExecutor {
+	exec := executor.NewWorkflowExecutor(server.registry, server.stateMgmt, executor.DefaultConfig())
+	exec.SetQueue(server.queue)
+	exec.SetCompletionHub(server.hub)
+	return exec
+}
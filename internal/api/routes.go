@@ -0,0 +1,335 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"unified-workflow/internal/api/handlers"
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/callback"
+	"unified-workflow/internal/executor"
+	"unified-workflow/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// route describes an endpoint: where it is mounted, the handler serving it and its contract
+type route struct {
+	group       RouteGroup
+	method      string
+	path        string // gin syntax, relative to BasePath unless root is set
+	root        bool
+	operationID string
+	summary     string
+	handler     func(h *handlerSet) gin.HandlerFunc
+	params      []Parameter
+	request     interface{}
+	responses   map[int]interface{}
+	successor   string // set on deprecated routes, relative to BasePath
+	permission  string // required of the caller when authentication is enabled
+	audited     bool   // allowed requests are audited too, not only denied ones
+}
+
+// fullPath returns the gin path the route is mounted on
+func (r route) fullPath() string {
+	if r.root {
+		return r.path
+	}
+	return BasePath + r.path
+}
+
+// Query and header parameters shared by several routes
+var (
+	offsetParam = Parameter{Name: "offset", In: "query", Description: "Number of items to skip",
+		Schema: &Schema{Type: "integer", Minimum: float64Ptr(0)}}
+	clientIDParam = Parameter{Name: callback.ClientIDHeader, In: "header",
+		Description: "Client whose callback secret signs the callback_url deliveries", Schema: &Schema{Type: "string"}}
+	tenantParam = Parameter{Name: tenant.Header, In: "header",
+		Description: "Tenant the request acts for; defaults to the tenant of the credential", Schema: &Schema{Type: "string"}}
+	stepIndexParam = Parameter{Name: "stepIndex", In: "path", Required: true,
+		Schema: &Schema{Type: "integer", Minimum: float64Ptr(0)}}
+	childStepIndexParam = Parameter{Name: "childStepIndex", In: "path", Required: true,
+		Schema: &Schema{Type: "integer", Minimum: float64Ptr(0)}}
+)
+
+// limitParam is the page size query parameter
+func limitParam(min, max int) Parameter {
+	return Parameter{Name: "limit", In: "query", Description: "Maximum number of items to return",
+		Schema: &Schema{Type: "integer", Minimum: float64Ptr(float64(min)), Maximum: float64Ptr(float64(max))}}
+}
+
+// withErrors adds the error envelope to the responses of a route for the given statuses
+func withErrors(responses map[int]interface{}, statuses ...int) map[int]interface{} {
+	for _, status := range statuses {
+		responses[status] = handlers.ErrorResponse{}
+	}
+	return responses
+}
+
+// routes lists every endpoint of the API; Mount and the OpenAPI document are both built from it
+var routes = []route{
+	// Workflow definitions
+	{
+		group: GroupWorkflows, method: http.MethodGet, path: "/workflows",
+		operationID: "listWorkflows", summary: "List registered workflows",
+		permission: auth.PermissionWorkflowsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.definition().ListWorkflows },
+		params: []Parameter{
+			{Name: "name", In: "query", Description: "Only workflows with this exact name", Schema: &Schema{Type: "string"}},
+			{Name: "description", In: "query", Description: "Only workflows with this exact description", Schema: &Schema{Type: "string"}},
+			limitParam(0, 1000), offsetParam,
+		},
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.WorkflowListResponse{}},
+			http.StatusBadRequest, http.StatusInternalServerError),
+	},
+	{
+		group: GroupWorkflows, method: http.MethodPost, path: "/workflows",
+		operationID: "createWorkflow", summary: "Register a workflow",
+		permission: auth.PermissionWorkflowsWrite, audited: true,
+		handler: func(h *handlerSet) gin.HandlerFunc { return h.definition().CreateWorkflow },
+		request: handlers.CreateWorkflowRequest{},
+		responses: withErrors(map[int]interface{}{http.StatusCreated: handlers.WorkflowChangedResponse{}},
+			http.StatusBadRequest, http.StatusInternalServerError),
+	},
+	{
+		group: GroupWorkflows, method: http.MethodGet, path: "/workflows/count",
+		operationID: "countWorkflows", summary: "Count registered workflows",
+		permission: auth.PermissionWorkflowsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.definition().CountWorkflows },
+		responses:  withErrors(map[int]interface{}{http.StatusOK: handlers.CountResponse{}}, http.StatusInternalServerError),
+	},
+	{
+		group: GroupWorkflows, method: http.MethodGet, path: "/workflows/:id",
+		operationID: "getWorkflow", summary: "Get a workflow and its steps",
+		permission: auth.PermissionWorkflowsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.definition().GetWorkflow },
+		responses:  withErrors(map[int]interface{}{http.StatusOK: handlers.WorkflowResponse{}}, http.StatusNotFound),
+	},
+	{
+		group: GroupWorkflows, method: http.MethodPut, path: "/workflows/:id",
+		operationID: "updateWorkflow", summary: "Update a workflow",
+		permission: auth.PermissionWorkflowsWrite, audited: true,
+		handler: func(h *handlerSet) gin.HandlerFunc { return h.definition().UpdateWorkflow },
+		request: handlers.UpdateWorkflowRequest{},
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.WorkflowChangedResponse{}},
+			http.StatusBadRequest, http.StatusNotFound),
+	},
+	{
+		group: GroupWorkflows, method: http.MethodDelete, path: "/workflows/:id",
+		operationID: "deleteWorkflow", summary: "Remove a workflow",
+		permission: auth.PermissionWorkflowsDelete, audited: true,
+		handler:   func(h *handlerSet) gin.HandlerFunc { return h.definition().DeleteWorkflow },
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.WorkflowDeletedResponse{}}, http.StatusNotFound),
+	},
+	{
+		group: GroupWorkflows, method: http.MethodGet, path: "/workflows/:id/exists",
+		operationID: "workflowExists", summary: "Check whether a workflow is registered",
+		permission: auth.PermissionWorkflowsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.definition().WorkflowExists },
+		responses:  map[int]interface{}{http.StatusOK: handlers.WorkflowExistsResponse{}},
+	},
+
+	// Starting runs
+	{
+		group: GroupExecution, method: http.MethodPost, path: "/workflows/:id/execute",
+		operationID: "executeWorkflow", summary: "Run a workflow, waiting at most timeout_ms for the outcome",
+		permission: auth.PermissionExecutionsCreate,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().ExecuteWorkflow },
+		request:    handlers.ExecuteWorkflowRequest{},
+		responses: withErrors(map[int]interface{}{
+			http.StatusOK:       handlers.SyncExecutionResponse{},
+			http.StatusAccepted: handlers.ExecutionAcceptedResponse{},
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecution, method: http.MethodPost, path: "/workflows/:id/async-execute",
+		operationID: "asyncExecuteWorkflow", summary: "Queue a workflow run",
+		permission: auth.PermissionExecutionsCreate,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().AsyncExecuteWorkflow },
+		params:     []Parameter{clientIDParam},
+		request:    handlers.AsyncExecuteWorkflowRequest{},
+		responses: withErrors(map[int]interface{}{
+			http.StatusOK:       handlers.ExecutionResultResponse{},
+			http.StatusAccepted: handlers.ExecutionAcceptedResponse{},
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecution, method: http.MethodPost, path: "/execute",
+		operationID: "executeWorkflowByBody", summary: "Run the workflow named by workflow_id",
+		permission: auth.PermissionExecutionsCreate,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().ExecuteWorkflow },
+		successor:  "/workflows/{id}/execute",
+		request:    handlers.ExecuteWorkflowRequest{},
+		responses: withErrors(map[int]interface{}{
+			http.StatusOK:       handlers.SyncExecutionResponse{},
+			http.StatusAccepted: handlers.ExecutionAcceptedResponse{},
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecution, method: http.MethodPost, path: "/execute/async",
+		operationID: "asyncExecuteWorkflowByBody", summary: "Queue a run of the workflow named by workflow_id",
+		permission: auth.PermissionExecutionsCreate,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().AsyncExecuteWorkflow },
+		successor:  "/workflows/{id}/async-execute",
+		params:     []Parameter{clientIDParam},
+		request:    handlers.AsyncExecuteWorkflowRequest{},
+		responses: withErrors(map[int]interface{}{
+			http.StatusOK:       handlers.ExecutionResultResponse{},
+			http.StatusAccepted: handlers.ExecutionAcceptedResponse{},
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError),
+	},
+
+	// Inspecting and controlling runs
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions",
+		operationID: "listExecutions", summary: "List workflow runs",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().ListExecutions },
+		params: []Parameter{
+			{Name: "workflow_id", In: "query", Description: "Only runs of this workflow", Schema: &Schema{Type: "string"}},
+			{Name: "status", In: "query", Description: "Only runs in this status", Schema: &Schema{Type: "string"}},
+			limitParam(1, 1000), offsetParam,
+		},
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.ExecutionListResponse{}},
+			http.StatusBadRequest, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions/:runId",
+		operationID: "getExecutionStatus", summary: "Get the status of a run",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().GetExecutionStatus },
+		responses: withErrors(map[int]interface{}{http.StatusOK: executor.ExecutionStatus{}},
+			http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions/:runId/details",
+		operationID: "getExecutionDetails", summary: "Get the detailed status of a run",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().GetExecutionDetails },
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.ExecutionDetails{}},
+			http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions/:runId/data",
+		operationID: "getExecutionData", summary: "Get the data of a run",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().GetExecutionData },
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.ExecutionDataResponse{}},
+			http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions/:runId/result",
+		operationID: "getExecutionResult", summary: "Get the result of a run, optionally waiting for it to finish",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().GetExecutionResult },
+		params: []Parameter{
+			{Name: "long_poll", In: "query", Description: "Wait for the run to finish", Schema: &Schema{Type: "boolean"}},
+			{Name: "wait_ms", In: "query", Description: "How long to wait when long_poll is set",
+				Schema: &Schema{Type: "integer", Minimum: float64Ptr(0)}},
+		},
+		responses: withErrors(map[int]interface{}{
+			http.StatusOK:       handlers.ExecutionResultResponse{},
+			http.StatusAccepted: handlers.ExecutionPendingResponse{},
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions/:runId/metrics",
+		operationID: "getExecutionMetrics", summary: "Get the metrics of a run",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().GetExecutionMetrics },
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.ExecutionMetricsResponse{}},
+			http.StatusNotFound, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodPost, path: "/executions/:runId/cancel",
+		operationID: "cancelExecution", summary: "Cancel a run",
+		permission: auth.PermissionExecutionsCancel, audited: true,
+		handler: func(h *handlerSet) gin.HandlerFunc { return h.workflow().CancelExecution },
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.ExecutionControlResponse{}},
+			http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodPost, path: "/executions/:runId/signals/:name",
+		operationID: "signalExecution", summary: "Send a signal to a run, resuming the step waiting for it",
+		permission: auth.PermissionExecutionsControl, audited: true,
+		handler: func(h *handlerSet) gin.HandlerFunc { return h.workflow().SignalExecution },
+		request: handlers.SignalExecutionRequest{},
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.ExecutionControlResponse{}},
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+	},
+	{
+		group: GroupExecutions, method: http.MethodPost, path: "/executions/:runId/pause",
+		operationID: "pauseExecution", summary: "Pause a run",
+		permission: auth.PermissionExecutionsControl, audited: true,
+		handler:   func(h *handlerSet) gin.HandlerFunc { return h.workflow().PauseExecution },
+		responses: controlResponses(),
+	},
+	{
+		group: GroupExecutions, method: http.MethodPost, path: "/executions/:runId/resume",
+		operationID: "resumeExecution", summary: "Resume a paused run",
+		permission: auth.PermissionExecutionsControl, audited: true,
+		handler:   func(h *handlerSet) gin.HandlerFunc { return h.workflow().ResumeExecution },
+		responses: controlResponses(),
+	},
+	{
+		group: GroupExecutions, method: http.MethodPost, path: "/executions/:runId/retry",
+		operationID: "retryExecution", summary: "Retry a failed run",
+		permission: auth.PermissionExecutionsControl, audited: true,
+		handler:   func(h *handlerSet) gin.HandlerFunc { return h.workflow().RetryExecution },
+		responses: controlResponses(),
+	},
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions/:runId/steps/:stepIndex",
+		operationID: "getStepExecution", summary: "Get the execution of a step",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().GetStepExecution },
+		params:     []Parameter{stepIndexParam},
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.StepExecutionResponse{}},
+			http.StatusBadRequest),
+	},
+	{
+		group: GroupExecutions, method: http.MethodGet, path: "/executions/:runId/steps/:stepIndex/child-steps/:childStepIndex",
+		operationID: "getChildStepExecution", summary: "Get the execution of a child step",
+		permission: auth.PermissionExecutionsRead,
+		handler:    func(h *handlerSet) gin.HandlerFunc { return h.workflow().GetChildStepExecution },
+		params:     []Parameter{stepIndexParam, childStepIndexParam},
+		responses: withErrors(map[int]interface{}{http.StatusOK: handlers.StepExecutionResponse{}},
+			http.StatusBadRequest),
+	},
+
+	// Administration
+	{
+		group: GroupAdmin, method: http.MethodGet, path: "/admin/config", root: true,
+		operationID: "getConfig", summary: "Get the effective configuration, secrets redacted",
+		permission: auth.PermissionAdminConfig, audited: true,
+		handler:   func(h *handlerSet) gin.HandlerFunc { return h.administration().GetConfig },
+		responses: map[int]interface{}{http.StatusOK: handlers.ConfigResponse{}},
+	},
+}
+
+// controlResponses are the responses of the endpoints changing the state of a run
+func controlResponses() map[int]interface{} {
+	return withErrors(map[int]interface{}{http.StatusOK: handlers.ExecutionControlResponse{}},
+		http.StatusInternalServerError)
+}
+
+// findRoute returns the documented route served at a gin method and path
+func findRoute(method, fullPath string) (route, bool) {
+	for _, r := range routes {
+		if r.method == method && r.fullPath() == fullPath {
+			return r, true
+		}
+	}
+	return route{}, false
+}
+
+// openAPIPath converts a gin path to OpenAPI syntax: /executions/:runId becomes /executions/{runId}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry derives schemas from Go types the way encoding/json serializes them
+// Named structs become components referenced by $ref; request structs also carry their binding rules
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[schemaKey]string
+}
+
+// schemaKey separates the request and response schemas of a type, whose required fields differ
+type schemaKey struct {
+	t       reflect.Type
+	request bool
+}
+
+func newSchemaRegistry(schemas map[string]*Schema) *schemaRegistry {
+	return &schemaRegistry{schemas: schemas, names: make(map[schemaKey]string)}
+}
+
+// request returns the schema of a request body; fields are required when their binding says so
+func (r *schemaRegistry) request(body interface{}) *Schema {
+	return r.schemaOf(reflect.TypeOf(body), true)
+}
+
+// response returns the schema of a response body; fields are required unless omitted when empty
+func (r *schemaRegistry) response(body interface{}) *Schema {
+	return r.schemaOf(reflect.TypeOf(body), false)
+}
+
+// resolve follows a $ref to its component
+func (r *schemaRegistry) resolve(schema *Schema) *Schema {
+	if schema.Ref == "" {
+		return schema
+	}
+	return r.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+}
+
+func (r *schemaRegistry) schemaOf(t reflect.Type, request bool) *Schema {
+	switch {
+	case t == nil || t.Kind() == reflect.Interface:
+		return &Schema{}
+	case t.Kind() == reflect.Ptr:
+		schema := r.schemaOf(t.Elem(), request)
+		if schema.Ref != "" {
+			// $ref siblings are ignored in OpenAPI 3.0, so a nullable reference is wrapped
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Uint, reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem(), request), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem(), request), Nullable: true}
+	case reflect.Struct:
+		return r.structSchema(t, request)
+	}
+	return &Schema{}
+}
+
+// structSchema returns a reference to the component of a named struct, or the inline schema of an anonymous one
+func (r *schemaRegistry) structSchema(t reflect.Type, request bool) *Schema {
+	if t.Name() == "" {
+		return r.objectSchema(t, request)
+	}
+
+	key := schemaKey{t: t, request: request}
+	if name, ok := r.names[key]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	name := r.componentName(t)
+	r.names[key] = name
+	r.schemas[name] = &Schema{} // placeholder for recursive types
+	*r.schemas[name] = *r.objectSchema(t, request)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName names a component after its type, qualifying it with the package on a clash
+func (r *schemaRegistry) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := r.schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	for i := 2; ; i++ {
+		if _, taken := r.schemas[name]; !taken {
+			return name
+		}
+		name = strings.TrimRight(name, "0123456789") + strconv.Itoa(i)
+	}
+}
+
+// objectSchema lists the serialized fields of a struct, promoting those of embedded structs
+func (r *schemaRegistry) objectSchema(t reflect.Type, request bool) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(schema, t, request)
+	return schema
+}
+
+func (r *schemaRegistry) addFields(schema *Schema, t reflect.Type, request bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(schema, embedded, request)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schemaOf(field.Type, request)
+		rules := bindingRules(field)
+		if request {
+			applyBinding(property, field.Type, rules)
+			if rules["required"] {
+				schema.Required = append(schema.Required, name)
+			}
+		} else if !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// jsonField returns the serialized name of a field, empty when it keeps its Go name
+func jsonField(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false, true
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+// bindingRules returns the validator rules of a field that apply to the field itself, not its elements
+func bindingRules(field reflect.StructField) map[string]bool {
+	rules := make(map[string]bool)
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "dive" {
+			break
+		}
+		if rule != "" {
+			rules[rule] = true
+		}
+	}
+	return rules
+}
+
+// applyBinding translates validator rules into schema constraints
+func applyBinding(schema *Schema, t reflect.Type, rules map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			switch t.Kind() {
+			case reflect.String:
+				length := int(n)
+				if key == "min" {
+					schema.MinLength = &length
+				} else {
+					schema.MaxLength = &length
+				}
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+				reflect.Float32, reflect.Float64:
+				if key == "min" {
+					schema.Minimum = &n
+				} else {
+					schema.Maximum = &n
+				}
+			}
+		case "oneof":
+			schema.Enum = strings.Fields(value)
+		case "url":
+			schema.Format = "uri"
+		}
+	}
+}
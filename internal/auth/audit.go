@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Audit outcomes
+const (
+	OutcomeAllowed = "allowed"
+	OutcomeDenied  = "denied"
+)
+
+// AuditEvent records an access decision
+type AuditEvent struct {
+	Time        time.Time
+	PrincipalID string // empty when the caller could not be authenticated
+	Method      string
+	Permission  string
+	Resource    string
+	Outcome     string
+	Reason      string
+	RemoteAddr  string
+	Tenant      string
+}
+
+// AuditSink receives denied requests and the allowed requests of audited actions
+type AuditSink interface {
+	Record(ctx context.Context, event AuditEvent)
+}
+
+// LogAuditSink writes audit events as structured log records
+type LogAuditSink struct{}
+
+// Record implements AuditSink
+func (LogAuditSink) Record(ctx context.Context, event AuditEvent) {
+	level := slog.LevelInfo
+	if event.Outcome == OutcomeDenied {
+		level = slog.LevelWarn
+	}
+	slog.Default().LogAttrs(ctx, level, "Audit",
+		slog.Bool("audit", true),
+		slog.String("principal", event.PrincipalID),
+		slog.String("auth_method", event.Method),
+		slog.String("permission", event.Permission),
+		slog.String("resource", event.Resource),
+		slog.String("outcome", event.Outcome),
+		slog.String("reason", event.Reason),
+		slog.String("remote_addr", event.RemoteAddr),
+		slog.String("tenant", event.Tenant),
+	)
+}
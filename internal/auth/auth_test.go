@@ -0,0 +1,356 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/dataprotection"
+)
+
+// recordingSink collects audit events
+type recordingSink struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (s *recordingSink) Record(ctx context.Context, event AuditEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func testAuthConfig() config.AuthConfig {
+	cfg := config.DefaultConfig().Auth
+	cfg.Enabled = true
+	cfg.JWT.HMACSecret = "jwt-secret"
+	cfg.JWT.Issuer = "https://idp.example.com"
+	cfg.JWT.Audience = "unified-workflow"
+	cfg.APIKeys = []config.APIKeyConfig{
+		{ID: "checkout", Key: "checkout-key", Roles: []string{"operator"}},
+		{ID: "support", KeySHA256: sha256Hex("support-key"), Roles: []string{"viewer"}, Permissions: []string{PermissionExecutionsReadSensitive}},
+	}
+	return cfg
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func validClaims(extra map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": "alice",
+		"iss": "https://idp.example.com",
+		"aud": []string{"unified-workflow"},
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func hmacToken(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAPIKeysMapToRolePermissions(t *testing.T) {
+	authenticator, err := NewAuthenticator(testAuthConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	principal, err := authenticator.Authenticate(ctx, ParseCredentials("checkout-key", ""))
+	if err != nil {
+		t.Fatalf("Authenticate(X-API-Key) error = %v", err)
+	}
+	if principal.ID != "checkout" || principal.Method != MethodAPIKey {
+		t.Errorf("principal = %+v, want checkout via api_key", principal)
+	}
+	if !principal.Can(PermissionExecutionsCancel) || principal.Can(PermissionWorkflowsDelete) {
+		t.Errorf("operator permissions = %v", principal.Permissions)
+	}
+
+	// Keys stored as digests are accepted as bearer tokens too
+	principal, err = authenticator.Authenticate(ctx, ParseCredentials("", "Bearer support-key"))
+	if err != nil {
+		t.Fatalf("Authenticate(bearer key) error = %v", err)
+	}
+	if principal.ID != "support" || !principal.Can(PermissionExecutionsReadSensitive) {
+		t.Errorf("principal = %+v, want support with read-sensitive", principal)
+	}
+
+	if _, err := authenticator.Authenticate(ctx, ParseCredentials("wrong-key", "")); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate(unknown key) error = %v, want ErrUnauthenticated", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, Credentials{}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate(no credentials) error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestWildcardsNeverGrantSensitiveData(t *testing.T) {
+	admin := &Principal{Permissions: []string{"*"}}
+	if !admin.Can(PermissionWorkflowsDelete) || !admin.Can(PermissionAdminConfig) {
+		t.Error("* does not grant every permission")
+	}
+	if admin.Can(PermissionExecutionsReadSensitive) {
+		t.Error("* grants executions:read-sensitive")
+	}
+	executions := &Principal{Permissions: []string{"executions:*"}}
+	if !executions.Can(PermissionExecutionsCancel) || executions.Can(PermissionWorkflowsRead) {
+		t.Error("executions:* does not match the executions resource only")
+	}
+
+	ctx := WithPrincipal(context.Background(), admin)
+	if dataprotection.CanUnmask(ctx) {
+		t.Error("admin context unmasks classified data")
+	}
+	ctx = WithPrincipal(context.Background(), &Principal{Permissions: []string{PermissionExecutionsReadSensitive}})
+	if !dataprotection.CanUnmask(ctx) || PrincipalFromContext(ctx) == nil {
+		t.Error("read-sensitive context does not unmask classified data")
+	}
+}
+
+func TestHMACTokens(t *testing.T) {
+	authenticator, err := NewAuthenticator(testAuthConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	token := hmacToken(t, "jwt-secret", validClaims(map[string]interface{}{"roles": []string{"viewer"}, "scope": "executions:cancel"}))
+	principal, err := authenticator.Authenticate(ctx, ParseCredentials("", "Bearer "+token))
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.ID != "alice" || principal.Method != MethodJWT {
+		t.Errorf("principal = %+v, want alice via jwt", principal)
+	}
+	if !principal.Can(PermissionExecutionsRead) || !principal.Can(PermissionExecutionsCancel) || principal.Can(PermissionExecutionsCreate) {
+		t.Errorf("permissions from roles and scope = %v", principal.Permissions)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong secret", hmacToken(t, "other-secret", validClaims(nil))},
+		{"expired", hmacToken(t, "jwt-secret", validClaims(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}))},
+		{"not yet valid", hmacToken(t, "jwt-secret", validClaims(map[string]interface{}{"nbf": time.Now().Add(time.Hour).Unix()}))},
+		{"wrong issuer", hmacToken(t, "jwt-secret", validClaims(map[string]interface{}{"iss": "https://evil.example.com"}))},
+		{"wrong audience", hmacToken(t, "jwt-secret", validClaims(map[string]interface{}{"aud": "other"}))},
+		{"no expiry", hmacToken(t, "jwt-secret", map[string]interface{}{"sub": "alice", "iss": "https://idp.example.com", "aud": "unified-workflow"})},
+		{"alg none", encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims(nil)) + "."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := authenticator.Authenticate(ctx, Credentials{BearerToken: tt.token}); !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("Authenticate() error = %v, want ErrUnauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestJWKSTokens(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	padded := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.FillBytes(make([]byte, 32))) }
+
+	var fetches int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encode(rsaKey.N), "e": encode(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": padded(ecKey.X), "y": padded(ecKey.Y)},
+		}})
+	}))
+	defer server.Close()
+
+	cfg := testAuthConfig()
+	cfg.JWT.HMACSecret = ""
+	cfg.JWT.JWKSURL = server.URL
+	authenticator, err := NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(alg, kid string, claims map[string]interface{}) string {
+		signed := encodeSegment(t, map[string]string{"alg": alg, "kid": kid}) + "." + encodeSegment(t, claims)
+		digest := sha256.Sum256([]byte(signed))
+		var signature []byte
+		if alg == "RS256" {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+		} else {
+			var r, s *big.Int
+			r, s, err = ecdsa.Sign(rand.Reader, ecKey, digest[:])
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	ctx := context.Background()
+	for _, alg := range []string{"RS256", "ES256"} {
+		kid := map[string]string{"RS256": "rsa-1", "ES256": "ec-1"}[alg]
+		token := sign(alg, kid, validClaims(map[string]interface{}{"permissions": []string{"workflows:read"}}))
+		principal, err := authenticator.Authenticate(ctx, Credentials{BearerToken: token})
+		if err != nil {
+			t.Fatalf("Authenticate(%s) error = %v", alg, err)
+		}
+		if !principal.Can(PermissionWorkflowsRead) {
+			t.Errorf("%s principal permissions = %v", alg, principal.Permissions)
+		}
+	}
+
+	// HMAC tokens are refused without a shared secret, unknown keys without hammering the provider
+	if _, err := authenticator.Authenticate(ctx, Credentials{BearerToken: hmacToken(t, "", validClaims(nil))}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate(HS256 without secret) error = %v, want ErrUnauthenticated", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, Credentials{BearerToken: sign("RS256", "rotated", validClaims(nil))}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate(unknown kid) error = %v, want ErrUnauthenticated", err)
+	}
+	if fetches != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", fetches)
+	}
+}
+
+func TestAuthorizeAuditsDeniedAndAuditedActions(t *testing.T) {
+	authenticator, err := NewAuthenticator(testAuthConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &recordingSink{}
+	authenticator.SetAuditSink(sink)
+	ctx := context.Background()
+	viewer := ParseCredentials("support-key", "")
+
+	if _, err := authenticator.Authorize(ctx, viewer, Access{Permission: PermissionExecutionsRead, Resource: "GET /executions"}); err != nil {
+		t.Fatalf("Authorize(read) error = %v", err)
+	}
+	if _, err := authenticator.Authorize(ctx, viewer, Access{Permission: PermissionExecutionsCancel, Resource: "POST /cancel", Audited: true}); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("Authorize(cancel) error = %v, want ErrPermissionDenied", err)
+	}
+	if _, err := authenticator.Authorize(ctx, ParseCredentials("checkout-key", ""), Access{Permission: PermissionExecutionsCancel, Resource: "POST /cancel", Audited: true}); err != nil {
+		t.Fatalf("Authorize(operator cancel) error = %v", err)
+	}
+	if _, err := authenticator.Authorize(ctx, Credentials{}, Access{Permission: PermissionExecutionsRead, Resource: "GET /executions"}); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Authorize(anonymous) error = %v, want ErrUnauthenticated", err)
+	}
+
+	want := []struct{ principal, outcome string }{
+		{"support", OutcomeDenied},
+		{"checkout", OutcomeAllowed},
+		{"", OutcomeDenied},
+	}
+	if len(sink.events) != len(want) {
+		t.Fatalf("audit events = %+v, want %d", sink.events, len(want))
+	}
+	for i, w := range want {
+		if sink.events[i].PrincipalID != w.principal || sink.events[i].Outcome != w.outcome {
+			t.Errorf("audit event %d = %+v, want %s %s", i, sink.events[i], w.principal, w.outcome)
+		}
+	}
+}
+
+func TestConfigOwnerRotatesKeys(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Auth = testAuthConfig()
+	authenticator, err := NewAuthenticator(cfg.Auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := NewConfigOwner(authenticator)
+
+	rotated := *cfg
+	rotated.Auth.APIKeys = []config.APIKeyConfig{{ID: "checkout", Key: "checkout-key-2", Roles: []string{"operator"}}}
+	if err := owner.ValidateConfig(&rotated); err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if err := owner.ApplyConfig(cfg, &rotated); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := authenticator.Authenticate(ctx, ParseCredentials("checkout-key", "")); err == nil {
+		t.Error("rotated-out key still authenticates")
+	}
+	if _, err := authenticator.Authenticate(ctx, ParseCredentials("checkout-key-2", "")); err != nil {
+		t.Errorf("rotated-in key error = %v", err)
+	}
+
+	invalid := rotated
+	invalid.Auth.APIKeys = []config.APIKeyConfig{{ID: "bad", KeySHA256: "not-hex"}}
+	if err := owner.ValidateConfig(&invalid); err == nil {
+		t.Error("ValidateConfig() accepted a malformed key digest")
+	}
+}
+
+func TestReloadKeepsCachedKeySet(t *testing.T) {
+	cfg := testAuthConfig()
+	cfg.JWT.JWKSURL = "https://idp.example/jwks"
+	first, err := newAuthState(cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An unset refresh interval is the default one, so reloading the same config keeps the fetched keys
+	second, err := newAuthState(cfg, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.jwks != first.jwks {
+		t.Error("reload with an unchanged JWKS config dropped the cached key set")
+	}
+
+	cfg.JWT.JWKSRefresh = time.Minute
+	third, err := newAuthState(cfg, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.jwks == second.jwks || third.jwks.refresh != time.Minute {
+		t.Errorf("reload with a new refresh interval kept the key set refreshed every %s", third.jwks.refresh)
+	}
+}
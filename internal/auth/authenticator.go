@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/tenant"
+)
+
+var (
+	// ErrUnauthenticated is returned when a request carries no valid credential
+	ErrUnauthenticated = errors.New("authentication required")
+
+	// ErrPermissionDenied is returned when the principal lacks the permission of a request
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Credentials are the credentials presented with a request
+type Credentials struct {
+	APIKey      string
+	BearerToken string
+}
+
+// ParseCredentials extracts the credentials from the X-API-Key and Authorization header values
+// A bearer token that is not a JWT is treated as an API key, so clients only setting a token work with both
+func ParseCredentials(apiKey, authorization string) Credentials {
+	creds := Credentials{APIKey: strings.TrimSpace(apiKey)}
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return creds
+	}
+	token = strings.TrimSpace(token)
+	if strings.Count(token, ".") == 2 {
+		creds.BearerToken = token
+	} else if creds.APIKey == "" {
+		creds.APIKey = token
+	}
+	return creds
+}
+
+// Access describes the action a request performs
+type Access struct {
+	Permission string
+	Resource   string
+	RemoteAddr string
+
+	// Tenant is the tenant named by the request, empty when it names none
+	Tenant string
+
+	// Audited records the request even when it is allowed
+	Audited bool
+}
+
+// Authenticator maps credentials to principals and checks their permissions
+type Authenticator struct {
+	state atomic.Pointer[authState]
+	audit AuditSink
+}
+
+// authState is the configuration an Authenticator verifies against; it is swapped as a whole on reload
+type authState struct {
+	apiKeys     map[[sha256.Size]byte]config.APIKeyConfig
+	jwt         *jwtVerifier
+	jwks        *jwksCache
+	jwksURL     string
+	roles       map[string][]string
+	rolesClaim  string
+	tenantClaim string
+}
+
+// NewAuthenticator creates an authenticator for the auth configuration; audit events are logged
+func NewAuthenticator(cfg config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{audit: LogAuditSink{}}
+	if err := a.Apply(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetAuditSink replaces the destination of audit events
+func (a *Authenticator) SetAuditSink(sink AuditSink) {
+	a.audit = sink
+}
+
+// Apply replaces the keys, token settings and roles; requests in flight finish with the previous ones
+func (a *Authenticator) Apply(cfg config.AuthConfig) error {
+	state, err := newAuthState(cfg, a.state.Load())
+	if err != nil {
+		return err
+	}
+	a.state.Store(state)
+	return nil
+}
+
+// newAuthState builds the verification state, reusing the cached key set of previous if the JWKS URL is unchanged
+func newAuthState(cfg config.AuthConfig, previous *authState) (*authState, error) {
+	state := &authState{
+		apiKeys:     make(map[[sha256.Size]byte]config.APIKeyConfig, len(cfg.APIKeys)),
+		roles:       cfg.Roles,
+		rolesClaim:  cfg.JWT.RolesClaim,
+		tenantClaim: cfg.JWT.TenantClaim,
+		jwksURL:     cfg.JWT.JWKSURL,
+	}
+	if state.rolesClaim == "" {
+		state.rolesClaim = "roles"
+	}
+	if state.tenantClaim == "" {
+		state.tenantClaim = "tenant"
+	}
+
+	for i, key := range cfg.APIKeys {
+		digest := sha256.Sum256([]byte(key.Key))
+		if key.KeySHA256 != "" {
+			decoded, err := hex.DecodeString(key.KeySHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("auth.api_keys[%d].key_sha256 must be a hex SHA-256 digest", i)
+			}
+			copy(digest[:], decoded)
+		}
+		if key.Tenant != "" && key.Tenant != AnyTenant {
+			if err := tenant.Validate(key.Tenant); err != nil {
+				return nil, fmt.Errorf("auth.api_keys[%d].tenant: %w", i, err)
+			}
+		}
+		state.apiKeys[digest] = key
+	}
+
+	if cfg.JWT.HMACSecret != "" || cfg.JWT.JWKSURL != "" {
+		state.jwt = &jwtVerifier{
+			hmacSecret: []byte(cfg.JWT.HMACSecret),
+			issuer:     cfg.JWT.Issuer,
+			audience:   cfg.JWT.Audience,
+			clockSkew:  cfg.JWT.ClockSkew,
+			now:        time.Now,
+		}
+		if cfg.JWT.JWKSURL != "" {
+			if previous != nil && previous.jwksURL == cfg.JWT.JWKSURL && previous.jwks.refresh == jwksRefresh(cfg.JWT.JWKSRefresh) {
+				state.jwks = previous.jwks
+			} else {
+				state.jwks = newJWKSCache(cfg.JWT.JWKSURL, cfg.JWT.JWKSRefresh)
+			}
+			state.jwt.keys = state.jwks
+		}
+	}
+	return state, nil
+}
+
+// Authenticate returns the principal of the credentials
+func (a *Authenticator) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	state := a.state.Load()
+
+	if creds.BearerToken != "" {
+		if state.jwt == nil {
+			return nil, fmt.Errorf("%w: bearer tokens are not accepted", ErrUnauthenticated)
+		}
+		claims, err := state.jwt.verify(ctx, creds.BearerToken)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+		}
+		subject, _ := claims["sub"].(string)
+		roles := stringsClaim(claims, state.rolesClaim)
+		permissions := state.expand(roles)
+		for _, claim := range []string{"permissions", "scope", "scp"} {
+			permissions = append(permissions, stringsClaim(claims, claim)...)
+		}
+		tenantID, _ := claims[state.tenantClaim].(string)
+		if tenantID != "" && tenantID != AnyTenant {
+			if err := tenant.Validate(tenantID); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+			}
+		}
+		return &Principal{ID: subject, Method: MethodJWT, Roles: roles, Permissions: permissions, Claims: claims, Tenant: tenantID}, nil
+	}
+
+	if creds.APIKey != "" {
+		key, ok := state.apiKeys[sha256.Sum256([]byte(creds.APIKey))]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown API key", ErrUnauthenticated)
+		}
+		permissions := append(state.expand(key.Roles), key.Permissions...)
+		return &Principal{ID: key.ID, Method: MethodAPIKey, Roles: key.Roles, Permissions: permissions, Tenant: key.Tenant}, nil
+	}
+
+	return nil, fmt.Errorf("%w: no credentials", ErrUnauthenticated)
+}
+
+// Authorize authenticates a request and checks that its principal holds the permission of the access
+// and may act for the tenant it names
+// Denied requests are always audited, allowed ones when the access is marked as audited
+func (a *Authenticator) Authorize(ctx context.Context, creds Credentials, access Access) (*Principal, error) {
+	event := AuditEvent{
+		Time:       time.Now(),
+		Permission: access.Permission,
+		Resource:   access.Resource,
+		RemoteAddr: access.RemoteAddr,
+		Tenant:     access.Tenant,
+	}
+
+	principal, err := a.Authenticate(ctx, creds)
+	if err != nil {
+		event.Outcome = OutcomeDenied
+		event.Reason = err.Error()
+		a.audit.Record(ctx, event)
+		return nil, err
+	}
+	event.PrincipalID = principal.ID
+	event.Method = principal.Method
+
+	if access.Permission != "" && !principal.Can(access.Permission) {
+		event.Outcome = OutcomeDenied
+		event.Reason = "missing permission " + access.Permission
+		a.audit.Record(ctx, event)
+		return principal, fmt.Errorf("%w: %s requires %s", ErrPermissionDenied, access.Resource, access.Permission)
+	}
+
+	tenantID, err := principal.TenantFor(access.Tenant)
+	if err != nil {
+		event.Outcome = OutcomeDenied
+		event.Reason = "tenant " + access.Tenant + " not allowed"
+		a.audit.Record(ctx, event)
+		return principal, err
+	}
+	event.Tenant = tenantID
+
+	if access.Audited {
+		event.Outcome = OutcomeAllowed
+		a.audit.Record(ctx, event)
+	}
+	return principal, nil
+}
+
+// expand returns the permissions granted by roles
+func (s *authState) expand(roles []string) []string {
+	var permissions []string
+	for _, role := range roles {
+		permissions = append(permissions, s.roles[role]...)
+	}
+	return permissions
+}
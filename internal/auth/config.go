@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"log/slog"
+
+	"unified-workflow/internal/config"
+)
+
+// Init returns the authenticator of the auth configuration, or nil when authentication is disabled
+func Init(cfg config.AuthConfig) (*Authenticator, error) {
+	if !cfg.Enabled {
+		// Logged at error level so that no log level configuration hides it
+		slog.Error("SECURITY WARNING: authentication is disabled, every API route accepts unauthenticated requests with full permissions; set auth.enabled (or AUTH_ENABLED) and configure api_keys or jwt before exposing this server",
+			"setting", "auth.enabled")
+		return nil, nil
+	}
+	return NewAuthenticator(cfg)
+}
+
+// ConfigOwner applies the keys, token settings and roles of the auth section at runtime
+// Enabling or disabling authentication only takes effect on restart
+type ConfigOwner struct {
+	authenticator *Authenticator
+}
+
+// NewConfigOwner creates the section owner of an authenticator
+func NewConfigOwner(authenticator *Authenticator) *ConfigOwner {
+	return &ConfigOwner{authenticator: authenticator}
+}
+
+// Name implements config.SectionOwner
+func (o *ConfigOwner) Name() string {
+	return "auth"
+}
+
+// Sections implements config.SectionOwner
+func (o *ConfigOwner) Sections() []string {
+	return []string{"auth"}
+}
+
+// ValidateConfig implements config.SectionOwner
+func (o *ConfigOwner) ValidateConfig(cfg *config.Config) error {
+	_, err := newAuthState(cfg.Auth, nil)
+	return err
+}
+
+// ApplyConfig implements config.SectionOwner
+func (o *ConfigOwner) ApplyConfig(oldConfig, newConfig *config.Config) error {
+	return o.authenticator.Apply(newConfig.Auth)
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// jwtHeader is the JOSE header of a token
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// keySource returns the public key with the given ID
+type keySource interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// jwtVerifier verifies the signature and registered claims of bearer tokens
+type jwtVerifier struct {
+	hmacSecret []byte
+	keys       keySource // nil disables RS* and ES* tokens
+	issuer     string
+	audience   string
+	clockSkew  time.Duration
+	now        func() time.Time
+}
+
+// verify checks a compact JWS and returns its claims
+func (v *jwtVerifier) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	if err := v.verifySignature(ctx, header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if err := v.verifyClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// verifySignature checks the signature with the key selected by the algorithm and key ID
+func (v *jwtVerifier) verifySignature(ctx context.Context, header jwtHeader, signed, signature []byte) error {
+	hashFunc, newHash, ok := algorithmHash(header.Alg)
+	if !ok {
+		return fmt.Errorf("unsupported token algorithm %q", header.Alg)
+	}
+
+	if strings.HasPrefix(header.Alg, "HS") {
+		if len(v.hmacSecret) == 0 {
+			return errors.New("HMAC tokens are not accepted")
+		}
+		mac := hmac.New(newHash, v.hmacSecret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+
+	if v.keys == nil {
+		return fmt.Errorf("%s tokens are not accepted", header.Alg)
+	}
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return err
+	}
+	digest := newHash()
+	digest.Write(signed)
+	sum := digest.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(header.Alg, "RS") {
+			return fmt.Errorf("key %q cannot verify %s tokens", header.Kid, header.Alg)
+		}
+		if err := rsa.VerifyPKCS1v15(key, hashFunc, sum, signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(header.Alg, "ES") || len(signature) != 2*size {
+			return fmt.Errorf("key %q cannot verify %s tokens", header.Kid, header.Alg)
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, sum, r, s) {
+			return errors.New("invalid token signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return nil
+}
+
+// verifyClaims checks expiry, not-before, issuer and audience
+func (v *jwtVerifier) verifyClaims(claims map[string]interface{}) error {
+	now := v.now()
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(exp, 0).Add(v.clockSkew)) {
+		return errors.New("token expired")
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(v.clockSkew).Before(time.Unix(nbf, 0)) {
+		return errors.New("token not yet valid")
+	}
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return errors.New("unexpected token issuer")
+	}
+	if v.audience != "" && !containsString(stringsClaim(claims, "aud"), v.audience) {
+		return errors.New("unexpected token audience")
+	}
+	return nil
+}
+
+// algorithmHash returns the hash of a supported JWS algorithm
+func algorithmHash(alg string) (crypto.Hash, func() hash.Hash, bool) {
+	switch alg {
+	case "HS256", "RS256", "ES256":
+		return crypto.SHA256, sha256.New, true
+	case "HS384", "RS384", "ES384":
+		return crypto.SHA384, sha512.New384, true
+	case "HS512", "RS512", "ES512":
+		return crypto.SHA512, sha512.New, true
+	}
+	return 0, nil, false
+}
+
+// decodeSegment decodes a base64url JSON segment of a token
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// numericClaim returns a NumericDate claim in Unix seconds
+func numericClaim(claims map[string]interface{}, name string) (int64, bool) {
+	value, ok := claims[name].(float64)
+	return int64(value), ok
+}
+
+// stringsClaim returns a claim holding a string, a space-separated string or an array of strings
+func stringsClaim(claims map[string]interface{}, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
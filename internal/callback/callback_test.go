@@ -0,0 +1,178 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/queue"
+)
+
+// receiver records the callbacks it accepts and fails the first failFirst requests
+type receiver struct {
+	t         *testing.T
+	secret    string
+	failFirst int
+
+	mu       sync.Mutex
+	requests int
+	results  []queue.ExecutionResult
+	received chan struct{}
+}
+
+func newReceiver(t *testing.T, secret string, failFirst int) (*receiver, *httptest.Server) {
+	r := &receiver{t: t, secret: secret, failFirst: failFirst, received: make(chan struct{}, 1)}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if err := Verify(r.secret, req.Header.Get(SignatureHeader), body, time.Minute); err != nil {
+		r.t.Errorf("callback signature rejected: %v", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests++
+	if r.requests <= r.failFirst {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var result queue.ExecutionResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		r.t.Errorf("callback body is not an execution result: %v", err)
+	}
+	r.results = append(r.results, result)
+	select {
+	case r.received <- struct{}{}:
+	default:
+	}
+}
+
+func (r *receiver) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-r.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+}
+
+// waitForEmptyStore waits until delivered callbacks have been removed from the store
+func waitForEmptyStore(t *testing.T, store Store) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		records, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("delivered callback still stored: %+v", records[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func testConfig() config.CallbacksConfig {
+	return config.CallbacksConfig{
+		Enabled:        true,
+		Secrets:        map[string]string{"client-a": "secret-a"},
+		Timeout:        time.Second,
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	}
+}
+
+func TestDispatcherDeliversSignedResultWithRetries(t *testing.T) {
+	recv, server := newReceiver(t, "secret-a", 2)
+	store := NewMemoryStore()
+	dispatcher := NewDispatcher(testConfig(), store, nil)
+	if err := dispatcher.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer dispatcher.Stop()
+
+	if err := dispatcher.Register(context.Background(), "run-1", server.URL, "client-a"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	dispatcher.Complete(queue.ExecutionResult{RunID: "run-1", WorkflowID: "payment", Status: "completed"})
+	recv.wait(t)
+
+	recv.mu.Lock()
+	if recv.requests != 3 {
+		t.Errorf("requests = %d, want 3 (two failures then success)", recv.requests)
+	}
+	if recv.results[0].RunID != "run-1" || recv.results[0].Status != "completed" {
+		t.Errorf("delivered result = %+v", recv.results[0])
+	}
+	recv.mu.Unlock()
+
+	waitForEmptyStore(t, store)
+}
+
+func TestDispatcherRejectsUndeliverableCallbacks(t *testing.T) {
+	dispatcher := NewDispatcher(testConfig(), NewMemoryStore(), nil)
+
+	if err := dispatcher.Validate("ftp://example.com/hook", "client-a"); err == nil {
+		t.Error("Validate() accepted a non-HTTP URL")
+	}
+	if err := dispatcher.Validate("https://example.com/hook", "client-b"); err == nil {
+		t.Error("Validate() accepted a client without a secret")
+	}
+	if err := dispatcher.Validate("https://example.com/hook", "client-a"); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestDispatcherResumesPersistedCallbacksAfterRestart(t *testing.T) {
+	recv, server := newReceiver(t, "secret-a", 0)
+	dir := t.TempDir()
+
+	// The first process registers the callback and stops before the run finishes
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := NewDispatcher(testConfig(), store, nil)
+	if err := first.Register(context.Background(), "run-2", server.URL, "client-a"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// The restarted process finds the run finished in the state store
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader := func(ctx context.Context, runID string) (*queue.ExecutionResult, error) {
+		return &queue.ExecutionResult{RunID: runID, Status: "failed", Error: "step failed"}, nil
+	}
+	second := NewDispatcher(testConfig(), reopened, loader)
+	if err := second.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer second.Stop()
+	recv.wait(t)
+	waitForEmptyStore(t, reopened)
+
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	if recv.results[0].RunID != "run-2" || recv.results[0].Error != "step failed" {
+		t.Errorf("delivered result = %+v", recv.results[0])
+	}
+}
@@ -0,0 +1,401 @@
+package callback
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/dataprotection"
+	"unified-workflow/internal/metrics"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/tenant"
+)
+
+// ErrNoSecret is returned when a callback is registered for a client without a signing secret
+var ErrNoSecret = errors.New("no callback signing secret configured for client")
+
+// pollInterval is how often the dispatcher looks for due deliveries when it is not woken up
+const pollInterval = time.Second
+
+// ResultLoader returns the result of a finished run, or nil while the run is still in progress
+type ResultLoader func(ctx context.Context, runID string) (*queue.ExecutionResult, error)
+
+// Dispatcher POSTs the final result of a run to its callback URL
+// Deliveries are signed with the client's secret, retried with exponential backoff and
+// persisted in a Store until they succeed or run out of attempts
+type Dispatcher struct {
+	cfg    config.CallbacksConfig
+	store  Store
+	loader ResultLoader
+	client *http.Client
+
+	mu       sync.Mutex
+	records  map[string]*Record
+	inflight map[string]bool
+	wake     chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+	workers  sync.WaitGroup
+}
+
+// NewStore creates the callback store selected by the configuration
+func NewStore(cfg config.CallbacksConfig) (Store, error) {
+	if cfg.StoreDir == "" {
+		return NewMemoryStore(), nil
+	}
+	return NewFileStore(cfg.StoreDir)
+}
+
+// NewDispatcher creates a dispatcher; loader resolves runs that finished before their callback was seen
+func NewDispatcher(cfg config.CallbacksConfig, store Store, loader ResultLoader) *Dispatcher {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = cfg.InitialBackoff
+	}
+	return &Dispatcher{
+		cfg:      cfg,
+		store:    store,
+		loader:   loader,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		records:  make(map[string]*Record),
+		inflight: make(map[string]bool),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// StartDispatcher creates a dispatcher fed by the completion hub and starts it
+// It returns nil when callbacks are disabled
+func StartDispatcher(ctx context.Context, cfg config.CallbacksConfig, hub *completion.Hub, loader ResultLoader) (*Dispatcher, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	store, err := NewStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dispatcher := NewDispatcher(cfg, store, loader)
+	hub.OnCompletion(dispatcher.Complete)
+	if err := dispatcher.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start callback dispatcher: %w", err)
+	}
+	return dispatcher, nil
+}
+
+// Start loads persisted callbacks and starts delivering them
+func (d *Dispatcher) Start(ctx context.Context) error {
+	records, err := d.store.List()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	for _, record := range records {
+		d.records[record.ID] = record
+	}
+	d.mu.Unlock()
+
+	// Runs may have finished while the dispatcher was down
+	for _, record := range records {
+		if record.Result == nil {
+			d.resolve(tenant.WithTenant(ctx, record.Tenant), record.RunID)
+		}
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.loop(loopCtx)
+
+	slog.Info("Callback dispatcher started", "pending", len(records))
+	return nil
+}
+
+// Stop stops delivering callbacks and waits for in-flight deliveries; pending ones stay in the store
+func (d *Dispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+		<-d.done
+		d.workers.Wait()
+	}
+}
+
+// ValidateURL checks that a callback URL can be delivered to
+func ValidateURL(callbackURL string) error {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback_url: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("callback_url must be an http or https URL")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("callback_url must include a host")
+	}
+	return nil
+}
+
+// Validate checks that a callback to callbackURL can be signed and delivered for the client
+func (d *Dispatcher) Validate(callbackURL, clientID string) error {
+	if err := ValidateURL(callbackURL); err != nil {
+		return err
+	}
+	if _, ok := d.secret(clientID); !ok {
+		return fmt.Errorf("%w %q", ErrNoSecret, clientID)
+	}
+	return nil
+}
+
+// Register records that the result of runID, a run of the tenant of ctx, must be delivered to callbackURL
+func (d *Dispatcher) Register(ctx context.Context, runID, callbackURL, clientID string) error {
+	if err := d.Validate(callbackURL, clientID); err != nil {
+		return err
+	}
+
+	record := &Record{
+		ID:        newRecordID(),
+		RunID:     runID,
+		Tenant:    tenant.FromContext(ctx),
+		URL:       callbackURL,
+		ClientID:  clientID,
+		CreatedAt: time.Now(),
+	}
+	if err := d.store.Save(record); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.records[record.ID] = record
+	d.mu.Unlock()
+
+	// The run may already have finished before the callback was registered
+	d.resolve(ctx, runID)
+	return nil
+}
+
+// Complete schedules delivery of a finished run's result to its registered callbacks
+func (d *Dispatcher) Complete(result queue.ExecutionResult) {
+	masked := result
+	if result.OutputData != nil {
+		masked.OutputData = dataprotection.CurrentPolicy().Mask(result.OutputData)
+	}
+
+	d.mu.Lock()
+	scheduled := false
+	for _, record := range d.records {
+		if record.RunID != result.RunID || tenant.Normalize(record.Tenant) != tenant.Normalize(result.Tenant) || record.Result != nil {
+			continue
+		}
+		resultCopy := masked
+		record.Result = &resultCopy
+		record.NextAttemptAt = time.Now()
+		if err := d.store.Save(record); err != nil {
+			slog.Warn("Failed to persist callback", "run_id", record.RunID, "callback_id", record.ID, "error", err)
+		}
+		scheduled = true
+	}
+	d.mu.Unlock()
+
+	if scheduled {
+		d.notify()
+	}
+}
+
+// notify wakes the delivery loop
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// resolve completes the callbacks of runID if the run has already finished
+func (d *Dispatcher) resolve(ctx context.Context, runID string) {
+	if d.loader == nil {
+		return
+	}
+	result, err := d.loader(ctx, runID)
+	if err != nil {
+		slog.Warn("Failed to load run result for callback", "run_id", runID, "error", err)
+		return
+	}
+	if result != nil {
+		d.Complete(*result)
+	}
+}
+
+// loop delivers due callbacks until the context is cancelled
+func (d *Dispatcher) loop(ctx context.Context) {
+	defer close(d.done)
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+
+	for {
+		wait := d.deliverDue(ctx)
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// deliverDue starts a delivery for every callback whose next attempt is due
+// and returns how long to wait before the next one is due
+func (d *Dispatcher) deliverDue(ctx context.Context) time.Duration {
+	now := time.Now()
+	wait := pollInterval
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, record := range d.records {
+		if record.Result == nil || record.Failed || d.inflight[id] {
+			continue
+		}
+		if until := record.NextAttemptAt.Sub(now); until > 0 {
+			if until < wait {
+				wait = until
+			}
+			continue
+		}
+		d.inflight[id] = true
+		d.workers.Add(1)
+		go d.deliver(ctx, *record)
+	}
+	return wait
+}
+
+// deliver makes one delivery attempt and records its outcome
+func (d *Dispatcher) deliver(ctx context.Context, record Record) {
+	defer d.workers.Done()
+	err := d.post(ctx, record)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inflight, record.ID)
+
+	// An attempt interrupted by Stop does not count; it is retried after a restart
+	current, ok := d.records[record.ID]
+	if !ok || (err != nil && ctx.Err() != nil) {
+		return
+	}
+	current.Attempts++
+
+	if err == nil {
+		delete(d.records, record.ID)
+		if err := d.store.Delete(record.ID); err != nil {
+			slog.Warn("Failed to remove delivered callback", "run_id", record.RunID, "callback_id", record.ID, "error", err)
+		}
+		metrics.CallbackDeliveries.Inc("delivered")
+		slog.Info("Delivered callback", "run_id", record.RunID, "attempts", current.Attempts)
+		return
+	}
+
+	current.LastError = err.Error()
+	if current.Attempts >= d.cfg.MaxAttempts {
+		current.Failed = true
+		metrics.CallbackDeliveries.Inc("failed")
+		slog.Warn("Giving up on callback", "run_id", record.RunID, "attempts", current.Attempts, "error", err)
+	} else {
+		current.NextAttemptAt = time.Now().Add(d.backoff(current.Attempts))
+		metrics.CallbackDeliveries.Inc("retry")
+		slog.Warn("Callback delivery failed, will retry", "run_id", record.RunID, "attempts", current.Attempts, "retry_at", current.NextAttemptAt, "error", err)
+	}
+	if err := d.store.Save(current); err != nil {
+		slog.Warn("Failed to persist callback", "run_id", record.RunID, "callback_id", record.ID, "error", err)
+	}
+	d.notify()
+}
+
+// post sends the signed result to the callback URL
+func (d *Dispatcher) post(ctx context.Context, record Record) error {
+	body, err := json.Marshal(record.Result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	secret, ok := d.secret(record.ClientID)
+	if !ok {
+		return fmt.Errorf("%w %q", ErrNoSecret, record.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, record.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), body))
+	req.Header.Set(DeliveryHeader, record.ID)
+	req.Header.Set(AttemptHeader, strconv.Itoa(record.Attempts+1))
+	req.Header.Set(RunIDHeader, record.RunID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// backoff returns the delay before the next attempt after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.InitialBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.MaxBackoff {
+		delay = d.cfg.MaxBackoff
+	}
+	return delay
+}
+
+// secret returns the signing secret of a client
+func (d *Dispatcher) secret(clientID string) (string, bool) {
+	if secret, ok := d.cfg.Secrets[clientID]; ok && secret != "" {
+		return secret, true
+	}
+	if d.cfg.DefaultSecret != "" {
+		return d.cfg.DefaultSecret, true
+	}
+	return "", false
+}
+
+// newRecordID generates a random callback ID
+func newRecordID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,75 @@
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on every callback request
+const (
+	SignatureHeader = "X-UWF-Signature"
+	DeliveryHeader  = "X-UWF-Delivery"
+	AttemptHeader   = "X-UWF-Attempt"
+	RunIDHeader     = "X-UWF-Run-ID"
+)
+
+// ClientIDHeader identifies the client registering a callback; it selects the signing secret
+const ClientIDHeader = "X-Client-ID"
+
+// Sign returns the signature header value for a callback body sent at the given time
+// The format is "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, computeMAC(secret, t, body))
+}
+
+// Verify checks a signature header against the body; tolerance bounds the age of the signature
+// (zero disables the age check). Receivers of callbacks can use it to authenticate deliveries
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	if t == "" || v1 == "" {
+		return fmt.Errorf("malformed signature header")
+	}
+
+	if tolerance > 0 {
+		seconds, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return fmt.Errorf("malformed signature timestamp: %w", err)
+		}
+		if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("signature timestamp outside tolerance")
+		}
+	}
+
+	expected := computeMAC(secret, t, body)
+	if !hmac.Equal([]byte(expected), []byte(v1)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// computeMAC signs "<timestamp>.<body>" with the secret
+func computeMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
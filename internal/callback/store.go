@@ -0,0 +1,146 @@
+package callback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/queue"
+)
+
+// Record is a callback registered for a run; it carries the result once the run has finished
+type Record struct {
+	ID            string                 `json:"id"`
+	RunID         string                 `json:"run_id"`
+	Tenant        string                 `json:"tenant,omitempty"`
+	URL           string                 `json:"url"`
+	ClientID      string                 `json:"client_id,omitempty"`
+	Result        *queue.ExecutionResult `json:"result,omitempty"`
+	Attempts      int                    `json:"attempts"`
+	NextAttemptAt time.Time              `json:"next_attempt_at,omitempty"`
+	LastError     string                 `json:"last_error,omitempty"`
+	Failed        bool                   `json:"failed,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// Store persists callback records until they are delivered
+type Store interface {
+	// Save creates or replaces a record
+	Save(record *Record) error
+
+	// Delete removes a delivered record
+	Delete(id string) error
+
+	// List returns all stored records
+	List() ([]*Record, error)
+}
+
+// MemoryStore keeps callback records in memory; they are lost on restart
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore creates an in-memory callback store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Save implements Store
+func (s *MemoryStore) Save(record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.ID] = *record
+	return nil
+}
+
+// Delete implements Store
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+	return nil
+}
+
+// List implements Store
+func (s *MemoryStore) List() ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]*Record, 0, len(s.records))
+	for _, record := range s.records {
+		copied := record
+		records = append(records, &copied)
+	}
+	return records, nil
+}
+
+// FileStore keeps one JSON file per callback record in a directory, so pending callbacks survive a restart
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a file store in dir, creating the directory if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create callback store %s: %w", dir, err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Save implements Store; the file is replaced atomically
+func (s *FileStore) Save(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode callback %s: %w", record.ID, err)
+	}
+	tmp := s.path(record.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write callback %s: %w", record.ID, err)
+	}
+	if err := os.Rename(tmp, s.path(record.ID)); err != nil {
+		return fmt.Errorf("failed to write callback %s: %w", record.ID, err)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *FileStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete callback %s: %w", id, err)
+	}
+	return nil
+}
+
+// List implements Store
+func (s *FileStore) List() ([]*Record, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read callback store %s: %w", s.dir, err)
+	}
+
+	records := make([]*Record, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read callback %s: %w", entry.Name(), err)
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode callback %s: %w", entry.Name(), err)
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
+
+// path returns the file of a record
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
@@ -0,0 +1,330 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type node interface {
+	eval(vars Vars) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(Vars) (interface{}, error) {
+	return n.value, nil
+}
+
+// pathNode reads a value from the data; segments are field names, quoted keys or indexes
+// Missing fields yield null
+type pathNode struct {
+	segments []interface{}
+}
+
+func (n *pathNode) eval(vars Vars) (interface{}, error) {
+	value, _ := n.lookup(vars)
+	return value, nil
+}
+
+// lookup resolves the path and reports whether every segment was found
+func (n *pathNode) lookup(vars Vars) (interface{}, bool) {
+	if vars == nil {
+		return nil, false
+	}
+	value := vars.Get(n.segments[0].(string))
+	if value == nil {
+		return nil, false
+	}
+	for _, segment := range n.segments[1:] {
+		value = plain(value)
+		switch s := segment.(type) {
+		case string:
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = m[s]; !ok {
+				return nil, false
+			}
+		case int:
+			list, ok := value.([]interface{})
+			if !ok || s >= len(list) {
+				return nil, false
+			}
+			value = list[s]
+		}
+	}
+	return value, true
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) eval(vars Vars) (interface{}, error) {
+	values := make([]interface{}, len(n.items))
+	for i, item := range n.items {
+		value, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(vars Vars) (interface{}, error) {
+	value, err := evalBool(n.operand, vars, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !value, nil
+}
+
+// logicalNode is && or ||; the right operand is only evaluated when it decides the result
+type logicalNode struct {
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) eval(vars Vars) (interface{}, error) {
+	op := "&&"
+	if n.or {
+		op = "||"
+	}
+	left, err := evalBool(n.left, vars, op)
+	if err != nil {
+		return nil, err
+	}
+	if left == n.or {
+		return left, nil
+	}
+	return evalBool(n.right, vars, op)
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(vars Vars) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+
+	if l, ok := number(left); ok {
+		if r, ok := number(right); ok {
+			return ordered(n.op, compareFloats(l, r)), nil
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return ordered(n.op, strings.Compare(l, r)), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot compare %s %s %s", typeName(left), n.op, typeName(right))
+}
+
+type inNode struct {
+	value, list node
+}
+
+func (n *inNode) eval(vars Vars) (interface{}, error) {
+	value, err := n.value.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	list, err := n.list.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch l := plain(list).(type) {
+	case []interface{}:
+		for _, item := range l {
+			if equal(value, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		key, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("in an object needs a string key, not %s", typeName(value))
+		}
+		_, found := l[key]
+		return found, nil
+	case string:
+		substring, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("in a string needs a string, not %s", typeName(value))
+		}
+		return strings.Contains(l, substring), nil
+	}
+	return nil, fmt.Errorf("in needs a list, an object or a string, not %s", typeName(list))
+}
+
+type callNode struct {
+	name     string
+	argument node
+}
+
+func (n *callNode) eval(vars Vars) (interface{}, error) {
+	if n.name == "exists" {
+		_, found := n.argument.(*pathNode).lookup(vars)
+		return found, nil
+	}
+
+	value, err := n.argument.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch v := plain(value).(type) {
+	case string:
+		return float64(len([]rune(v))), nil
+	case []interface{}:
+		return float64(len(v)), nil
+	case map[string]interface{}:
+		return float64(len(v)), nil
+	case nil:
+		return float64(0), nil
+	}
+	return nil, fmt.Errorf("len needs a string, a list or an object, not %s", typeName(value))
+}
+
+// evalBool evaluates an operand of a boolean operator
+func evalBool(n node, vars Vars, op string) (bool, error) {
+	value, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s needs booleans, not %s", op, typeName(value))
+	}
+	return b, nil
+}
+
+// equal compares values the way their JSON forms compare; numbers of any Go type are equal by value
+func equal(left, right interface{}) bool {
+	if l, ok := number(left); ok {
+		r, ok := number(right)
+		return ok && l == r
+	}
+	return reflect.DeepEqual(plain(left), plain(right))
+}
+
+func ordered(op string, cmp int) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func compareFloats(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+// number converts the numeric types workflow data holds to float64
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// plain converts typed values, such as structs stored by typed child steps, to their JSON form
+func plain(value interface{}) interface{} {
+	switch value.(type) {
+	case nil, bool, string, float64, map[string]interface{}, []interface{}:
+		return value
+	}
+	if _, ok := number(value); ok {
+		return value
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
+
+// typeName names the JSON type of a value for error messages
+func typeName(value interface{}) string {
+	if _, ok := number(value); ok {
+		return "number"
+	}
+	switch plain(value).(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,84 @@
+// Package expr is the expression language of declarative workflow conditions
+// Expressions read workflow data through dotted paths and combine comparisons with boolean operators:
+//
+//	aml.resolution != "FAIL" && (risk_score > 70 || channel in ["web", "mobile"])
+//
+// They have no side effects, no loops and no access to anything but the data they are given,
+// so definitions submitted over the API can be evaluated safely
+package expr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxLength is the longest expression source accepted
+const MaxLength = 4096
+
+// maxDepth bounds the nesting of an expression
+const maxDepth = 64
+
+// ErrSyntax is returned for an expression that does not parse
+var ErrSyntax = errors.New("invalid expression")
+
+// Vars resolves the top-level names of an expression; WorkflowData satisfies it
+type Vars interface {
+	Get(key string) interface{}
+}
+
+// Map adapts a map to Vars
+type Map map[string]interface{}
+
+// Get implements Vars
+func (m Map) Get(key string) interface{} {
+	return m[key]
+}
+
+// Expression is a compiled expression; it is safe for concurrent use
+type Expression struct {
+	source string
+	root   node
+}
+
+// Compile parses an expression
+func Compile(source string) (*Expression, error) {
+	if len(source) > MaxLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrSyntax, MaxLength)
+	}
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %s at offset %d", ErrSyntax, tok, tok.pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression against vars
+func (e *Expression) Eval(vars Vars) (interface{}, error) {
+	return e.root.eval(vars)
+}
+
+// EvalBool evaluates an expression that must yield a boolean
+func (e *Expression) EvalBool(vars Vars) (bool, error) {
+	value, err := e.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q yields %s, not a boolean", e.source, typeName(value))
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package expr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type resolution struct {
+	Resolution string `json:"resolution"`
+	Score      int    `json:"score"`
+}
+
+var testVars = Map{
+	"risk_score": 85,
+	"amount":     "100000",
+	"channel":    "web",
+	"aml":        map[string]interface{}{"resolution": "FAIL", "checks": []interface{}{"pep", "sanctions"}},
+	"ml":         resolution{Resolution: "PASS", Score: 12},
+	"flagged":    true,
+}
+
+func TestEvalBool(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{`risk_score > 70`, true},
+		{`risk_score >= 85 && risk_score < 85.5`, true},
+		{`aml.resolution != "FAIL"`, false},
+		{`aml.resolution == 'FAIL' || missing > 1`, true},
+		{`!flagged`, false},
+		{`channel in ["web", "mobile"]`, true},
+		{`"pep" in aml.checks`, true},
+		{`"resolution" in aml`, true},
+		{`aml.checks[1] == "sanctions"`, true},
+		{`aml["resolution"] == "FAIL"`, true},
+		{`ml.resolution == "PASS" && ml.score < 50`, true},
+		{`missing == null && !exists(missing) && exists(aml.resolution)`, true},
+		{`len(aml.checks) == 2 && len(channel) == 3`, true},
+		{`amount == "100000"`, true},
+		{`(risk_score > 90 || flagged) && !(channel == "branch")`, true},
+		{`false && missing > 1`, false},
+	}
+	for _, tt := range tests {
+		expression, err := Compile(tt.source)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.source, err)
+			continue
+		}
+		got, err := expression.EvalBool(testVars)
+		if err != nil {
+			t.Errorf("EvalBool(%q) error = %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvalBool(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestCompileRejectsInvalidExpressions(t *testing.T) {
+	for _, source := range []string{
+		``,
+		`risk_score >`,
+		`risk_score > 70 70`,
+		`(risk_score > 70`,
+		`aml.`,
+		`"unterminated`,
+		`risk_score = 70`,
+		`exists("literal")`,
+		`aml[-1] == 1`,
+		strings.Repeat("(", 100) + "true" + strings.Repeat(")", 100),
+		strings.Repeat("a", MaxLength+1),
+	} {
+		if _, err := Compile(source); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Compile(%q) error = %v, want ErrSyntax", source, err)
+		}
+	}
+}
+
+func TestEvalReportsTypeErrors(t *testing.T) {
+	for _, source := range []string{
+		`missing > 70`,
+		`channel > 70`,
+		`risk_score && flagged`,
+		`risk_score`,
+		`1 in risk_score`,
+	} {
+		expression, err := Compile(source)
+		if err != nil {
+			t.Fatalf("Compile(%q) error = %v", source, err)
+		}
+		if _, err := expression.EvalBool(testVars); err == nil {
+			t.Errorf("EvalBool(%q) error = nil, want a type error", source)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package expr
+
+import (
+	"fmt"
+)
+
+// parser is a recursive descent parser over the tokens of an expression
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | comparison
+//	comparison = operand [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" ) operand ]
+//	operand    = literal | path | call | list | "(" or ")"
+//	path       = ident { "." ident | "[" ( number | string ) "]" }
+//	call       = ( "exists" | "len" ) "(" or ")"
+//	list       = "[" [ or { "," or } ] "]"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token when it is the operator or keyword op
+func (p *parser) accept(op string) bool {
+	tok := p.peek()
+	if (tok.kind == tokenOperator || tok.kind == tokenIdent) && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return fmt.Errorf("%w: expected %q but found %s at offset %d", ErrSyntax, op, tok, tok.pos)
+	}
+	return nil
+}
+
+func (p *parser) checkDepth(depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("%w: nested deeper than %d levels", ErrSyntax, maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	if err := p.checkDepth(depth); err != nil {
+		return nil, err
+	}
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary(depth int) (node, error) {
+	if p.accept("!") {
+		if err := p.checkDepth(depth + 1); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison(depth)
+}
+
+func (p *parser) parseComparison(depth int) (node, error) {
+	left, err := p.parseOperand(depth)
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenOperator && isComparison(tok.text):
+		p.next()
+		right, err := p.parseOperand(depth)
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: tok.text, left: left, right: right}, nil
+	case tok.kind == tokenIdent && tok.text == "in":
+		p.next()
+		right, err := p.parseOperand(depth)
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{value: left, list: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseOperand(depth int) (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber, tokenString:
+		return &literalNode{value: tok.value}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		case "in":
+			return nil, fmt.Errorf("%w: unexpected %s at offset %d", ErrSyntax, tok, tok.pos)
+		case "exists", "len":
+			if p.peek().text == "(" && p.peek().kind == tokenOperator {
+				return p.parseCall(tok.text, depth)
+			}
+		}
+		return p.parsePath(tok)
+	case tokenOperator:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseOr(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		case "[":
+			return p.parseList(depth)
+		}
+	}
+	return nil, fmt.Errorf("%w: unexpected %s at offset %d", ErrSyntax, tok, tok.pos)
+}
+
+func (p *parser) parseCall(name string, depth int) (node, error) {
+	p.next() // (
+	argument, err := p.parseOr(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if name == "exists" {
+		if _, ok := argument.(*pathNode); !ok {
+			return nil, fmt.Errorf("%w: exists takes a path", ErrSyntax)
+		}
+	}
+	return &callNode{name: name, argument: argument}, nil
+}
+
+func (p *parser) parseList(depth int) (node, error) {
+	list := &listNode{}
+	if p.accept("]") {
+		return list, nil
+	}
+	for {
+		item, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		list.items = append(list.items, item)
+		if p.accept("]") {
+			return list, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parsePath(first token) (node, error) {
+	path := &pathNode{segments: []interface{}{first.text}}
+	for {
+		switch {
+		case p.accept("."):
+			tok := p.next()
+			if tok.kind != tokenIdent {
+				return nil, fmt.Errorf("%w: expected a field name but found %s at offset %d", ErrSyntax, tok, tok.pos)
+			}
+			path.segments = append(path.segments, tok.text)
+		case p.accept("["):
+			tok := p.next()
+			switch {
+			case tok.kind == tokenString:
+				path.segments = append(path.segments, tok.value)
+			case tok.kind == tokenNumber && tok.value.(float64) >= 0 && tok.value.(float64) == float64(int(tok.value.(float64))):
+				path.segments = append(path.segments, int(tok.value.(float64)))
+			default:
+				return nil, fmt.Errorf("%w: expected an index or a quoted key but found %s at offset %d", ErrSyntax, tok, tok.pos)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return path, nil
+		}
+	}
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// hookContextKey is the key of the context.Context in the context map of an untyped hook
+const hookContextKey = "context"
+
+// ContextMap returns the run of a workflow context as the map untyped hooks receive as their context
+func ContextMap(workflowContext primitiveModel.WorkflowContext) map[string]interface{} {
+	if workflowContext == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"run_id":      workflowContext.GetRunID(),
+		"workflow_id": workflowContext.GetWorkflowDefinitionID(),
+	}
+}
+
+// HookContext returns the context.Context an untyped hook receives in its context map, cancelled once the
+// step or run of the hook times out or is cancelled; it returns context.Background for any other context
+func HookContext(hookContext interface{}) context.Context {
+	if c, ok := hookContext.(map[string]interface{}); ok {
+		if ctx, ok := c[hookContextKey].(context.Context); ok {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// WorkflowContextOf returns an untyped step context as a WorkflowContext
+// A context map contributes its run_id and workflow_id; anything else yields an empty context
+func WorkflowContextOf(context interface{}) primitiveModel.WorkflowContext {
+	switch c := context.(type) {
+	case primitiveModel.WorkflowContext:
+		return c
+	case map[string]interface{}:
+		runID, _ := c["run_id"].(string)
+		workflowID, _ := c["workflow_id"].(string)
+		return primitiveModel.NewWorkflowContextForRun(runID, workflowID)
+	default:
+		return primitiveModel.NewWorkflowContextForRun("", "")
+	}
+}
+
+// WorkflowDataOf returns untyped step data as WorkflowData
+// A data map is wrapped rather than copied, so results stored through the WorkflowData land in the map
+func WorkflowDataOf(data interface{}) primitiveModel.WorkflowData {
+	switch d := data.(type) {
+	case primitiveModel.WorkflowData:
+		return d
+	case map[string]interface{}:
+		return primitiveModel.WrapWorkflowData(d)
+	default:
+		return primitiveModel.NewWorkflowData()
+	}
+}
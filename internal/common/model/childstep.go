@@ -1,5 +1,12 @@
 package model
 
+import (
+	"context"
+	"fmt"
+
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
 // ChildStep represents a granular execution unit with hooks
 // Similar to Java's ChildStep class in workflow-common
 type ChildStep struct {
@@ -7,8 +14,13 @@ type ChildStep struct {
 	requestHook  func(context interface{}, data interface{}) interface{}
 	responseHook func(context interface{}, data interface{}) interface{}
 	validateHook func(response interface{}) error
+	run          ChildStepFunc
+	compensation *Compensation
 }
 
+// ChildStepFunc runs a child step against the context and data of its run and returns the child step result
+type ChildStepFunc func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (interface{}, error)
+
 // NewChildStep creates a new ChildStep
 func NewChildStep(name string, requestHook, responseHook func(context interface{}, data interface{}) interface{}, validateHook func(response interface{}) error) *ChildStep {
 	return &ChildStep{
@@ -19,6 +31,14 @@ func NewChildStep(name string, requestHook, responseHook func(context interface{
 	}
 }
 
+// NewChildStepFunc creates a ChildStep that runs fn; typed child steps are adapted to it
+func NewChildStepFunc(name string, fn ChildStepFunc) *ChildStep {
+	return &ChildStep{
+		name: name,
+		run:  fn,
+	}
+}
+
 // GetName returns the name of the child step
 func (cs *ChildStep) GetName() string {
 	return cs.name
@@ -38,3 +58,76 @@ func (cs *ChildStep) GetResponseHook() func(context interface{}, data interface{
 func (cs *ChildStep) GetValidateHook() func(response interface{}) error {
 	return cs.validateHook
 }
+
+// SetCompensation registers the action undoing the child step when its run fails or is cancelled after it completed
+func (cs *ChildStep) SetCompensation(compensation *Compensation) *ChildStep {
+	cs.compensation = compensation
+	return cs
+}
+
+// GetCompensation returns the compensating action of the child step, nil when it has none
+func (cs *ChildStep) GetCompensation() *Compensation {
+	return cs.compensation
+}
+
+// HasLogic reports whether the child step has a function or hooks to run
+// Definitions loaded from a remote registry carry only the child step names
+func (cs *ChildStep) HasLogic() bool {
+	return cs.run != nil || cs.requestHook != nil || cs.responseHook != nil || cs.validateHook != nil
+}
+
+// Execute runs the child step and returns its result
+// Untyped hooks are adapted: they receive the run as a context map, carrying ctx for HookContext, and a copy of the data as a map,
+// a hook returning an error fails the child step, and the validate hook checks the response, or the
+// request when there is no response hook
+func (cs *ChildStep) Execute(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if cs.run != nil {
+		return cs.run(ctx, workflowContext, data)
+	}
+
+	hookContext := ContextMap(workflowContext)
+	hookContext[hookContextKey] = ctx
+	var request, response interface{}
+	if cs.requestHook != nil {
+		request = cs.requestHook(hookContext, data.ToMap())
+		if err, ok := request.(error); ok {
+			return nil, err
+		}
+	}
+	if cs.responseHook != nil {
+		response = cs.responseHook(hookContext, data.ToMap())
+		if err, ok := response.(error); ok {
+			return nil, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := response
+	if result == nil {
+		result = request
+	}
+	if result != nil && cs.validateHook != nil {
+		if err := cs.validateHook(result); err != nil {
+			return nil, fmt.Errorf("validation failed for child step %s: %w", cs.name, err)
+		}
+	}
+	return result, nil
+}
+
+// ResultKey returns the workflow data key the result of a child step is stored under
+func ResultKey(childStepName string) string {
+	return childStepName + "Result"
+}
+
+// StoreChildStepResult stores the result of a child step in the workflow data and marks it completed
+func StoreChildStepResult(data primitiveModel.WorkflowData, childStepName string, result interface{}) {
+	if result != nil {
+		data.Put(ResultKey(childStepName), result)
+	}
+	data.Put(childStepName+"Completed", true)
+}
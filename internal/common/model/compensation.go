@@ -0,0 +1,68 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// CompensationFunc undoes the side effects of a step or child step that completed
+// It receives the context of the run as it was when the step ran and the data of the run as it ended
+type CompensationFunc func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error
+
+// RetryPolicy bounds the attempts of a compensation; the delay between attempts doubles up to MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultCompensationRetry is the retry policy of compensations that do not set one
+var DefaultCompensationRetry = RetryPolicy{MaxAttempts: 3, Backoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// Delay returns the delay before the next attempt after the given number of failed attempts
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// Attempts returns the number of attempts the policy allows, at least one
+func (p RetryPolicy) Attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Compensation is the compensating action of a step or child step
+// When a run fails or is cancelled, the executor runs the compensations of its completed steps and
+// child steps in the reverse order they completed
+type Compensation struct {
+	Run   CompensationFunc
+	Retry RetryPolicy
+}
+
+// NewCompensation creates a compensation retried with DefaultCompensationRetry
+func NewCompensation(fn CompensationFunc) *Compensation {
+	return &Compensation{Run: fn, Retry: DefaultCompensationRetry}
+}
+
+// WithRetry sets the retry policy of the compensation
+func (c *Compensation) WithRetry(policy RetryPolicy) *Compensation {
+	c.Retry = policy
+	return c
+}
+
+// CompensableStep is a step that can register a compensating action
+type CompensableStep interface {
+	Step
+	GetCompensation() *Compensation
+	SetCompensation(compensation *Compensation) Step
+}
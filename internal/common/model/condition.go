@@ -0,0 +1,150 @@
+package model
+
+import (
+	"fmt"
+
+	"unified-workflow/internal/common/expr"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// Condition decides from the data of a run whether a step runs or a branch is taken
+type Condition interface {
+	Evaluate(data primitiveModel.WorkflowData) (bool, error)
+	String() string
+}
+
+// Predicate is a condition written in Go
+type Predicate func(data primitiveModel.WorkflowData) (bool, error)
+
+// Evaluate implements Condition
+func (p Predicate) Evaluate(data primitiveModel.WorkflowData) (bool, error) {
+	return p(data)
+}
+
+// String implements Condition
+func (p Predicate) String() string {
+	return "predicate"
+}
+
+// When creates a condition from a predicate that cannot fail
+func When(predicate func(data primitiveModel.WorkflowData) bool) Condition {
+	return Predicate(func(data primitiveModel.WorkflowData) (bool, error) {
+		return predicate(data), nil
+	})
+}
+
+// expressionCondition is a condition written in the expression language of declarative definitions
+type expressionCondition struct {
+	expression *expr.Expression
+}
+
+// ParseCondition compiles an expression over the workflow data, such as `aml.resolution != "FAIL"`
+func ParseCondition(source string) (Condition, error) {
+	expression, err := expr.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	return &expressionCondition{expression: expression}, nil
+}
+
+// MustParseCondition is ParseCondition for expressions known to be valid; it panics otherwise
+func MustParseCondition(source string) Condition {
+	condition, err := ParseCondition(source)
+	if err != nil {
+		panic(fmt.Sprintf("condition %q: %v", source, err))
+	}
+	return condition
+}
+
+// Evaluate implements Condition
+func (c *expressionCondition) Evaluate(data primitiveModel.WorkflowData) (bool, error) {
+	return c.expression.EvalBool(data)
+}
+
+// String implements Condition
+func (c *expressionCondition) String() string {
+	return c.expression.String()
+}
+
+// ConditionalStep is a step that only runs when its condition holds; otherwise it is skipped
+type ConditionalStep interface {
+	Step
+	GetCondition() Condition
+	SetCondition(condition Condition) Step
+}
+
+// Branch is an alternative of a BranchStep: its steps run when it is the first branch whose condition holds
+type Branch struct {
+	Name      string
+	Condition Condition
+	Steps     []Step
+}
+
+// BranchStep routes a run to one of its branches, in the manner of if/else and switch
+// The steps of the branches not taken are reported as skipped
+type BranchStep struct {
+	*BaseStep
+	Branches []Branch
+	// Default runs when no branch condition holds; it may be nil
+	Default *Branch
+}
+
+// NewIfStep creates a branch step running then when condition holds and otherwise when it does not
+func NewIfStep(name string, condition Condition, then, otherwise []Step) *BranchStep {
+	step := &BranchStep{
+		BaseStep: NewBaseStep(name, false),
+		Branches: []Branch{{Name: "then", Condition: condition, Steps: then}},
+	}
+	if len(otherwise) > 0 {
+		step.Default = &Branch{Name: "else", Steps: otherwise}
+	}
+	return step
+}
+
+// NewSwitchStep creates a branch step running the first case whose condition holds, else defaultSteps
+func NewSwitchStep(name string, cases []Branch, defaultSteps []Step) *BranchStep {
+	step := &BranchStep{
+		BaseStep: NewBaseStep(name, false),
+		Branches: cases,
+	}
+	if len(defaultSteps) > 0 {
+		step.Default = &Branch{Name: "default", Steps: defaultSteps}
+	}
+	return step
+}
+
+// Select returns the branch to take, or nil when no condition holds and there is no default
+func (s *BranchStep) Select(data primitiveModel.WorkflowData) (*Branch, error) {
+	for i := range s.Branches {
+		branch := &s.Branches[i]
+		if branch.Condition == nil {
+			return branch, nil
+		}
+		taken, err := branch.Condition.Evaluate(data)
+		if err != nil {
+			return nil, fmt.Errorf("condition of branch %s failed: %w", branch.Name, err)
+		}
+		if taken {
+			return branch, nil
+		}
+	}
+	return s.Default, nil
+}
+
+// AllBranches returns the branches followed by the default branch, if any
+func (s *BranchStep) AllBranches() []*Branch {
+	branches := make([]*Branch, 0, len(s.Branches)+1)
+	for i := range s.Branches {
+		branches = append(branches, &s.Branches[i])
+	}
+	if s.Default != nil {
+		branches = append(branches, s.Default)
+	}
+	return branches
+}
+
+// BranchingStep is a step with branches of nested steps, of which a run takes at most one
+type BranchingStep interface {
+	Step
+	AllBranches() []*Branch
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// ErrorPolicy decides how a ForEachStep handles items that fail
+type ErrorPolicy string
+
+const (
+	// FailFast fails the step on the first failed item, cancelling the items in flight and skipping the rest
+	FailFast ErrorPolicy = "fail_fast"
+	// CollectErrors runs every item; the step completes and the errors are stored next to the results
+	CollectErrors ErrorPolicy = "collect"
+	// FailureThreshold tolerates up to MaxFailures failed items and fails the step like FailFast beyond that
+	FailureThreshold ErrorPolicy = "threshold"
+)
+
+// DefaultItemKey is the workflow data key each item of a ForEachStep is bound to unless ItemKey is set
+const DefaultItemKey = "item"
+
+// ForEachStep runs a child step or a sub-workflow once per element of a list in the workflow data
+// Each item runs against a copy of the data of the run holding the element under ItemKey and its index
+// under ItemKey+"Index"; the result of each item is stored, in the order of the list, under Into
+type ForEachStep struct {
+	*BaseStep
+	// Items is the workflow data key of the list to iterate
+	Items string
+	// ItemKey is the data key an item is bound to; DefaultItemKey when empty
+	ItemKey string
+	// Into is the data key the ordered results are stored under; ResultKey of the step name when empty
+	Into string
+	// Concurrency is the number of items run at once; items run one at a time when it is below 2
+	Concurrency int
+	// ErrorPolicy is FailFast when empty
+	ErrorPolicy ErrorPolicy
+	// MaxFailures is the number of failed items FailureThreshold tolerates
+	MaxFailures int
+
+	// ChildStep runs for each item unless SubWorkflow is set
+	ChildStep *ChildStep
+	// SubWorkflow runs a linked run for each item; its input bindings see the item data
+	SubWorkflow *SubWorkflowStep
+}
+
+// NewForEachStep creates a step running childStep for each element of the list under items
+func NewForEachStep(name, items string, childStep *ChildStep) *ForEachStep {
+	return &ForEachStep{
+		BaseStep:  NewBaseStep(name, false),
+		Items:     items,
+		ChildStep: childStep,
+	}
+}
+
+// NewForEachSubWorkflow creates a step running a sub-workflow for each element of the list under items
+func NewForEachSubWorkflow(name, items string, subWorkflow *SubWorkflowStep) *ForEachStep {
+	return &ForEachStep{
+		BaseStep:    NewBaseStep(name, false),
+		Items:       items,
+		SubWorkflow: subWorkflow,
+	}
+}
+
+// WithConcurrency runs up to n items at once
+func (s *ForEachStep) WithConcurrency(n int) *ForEachStep {
+	s.Concurrency = n
+	return s
+}
+
+// OnItemError sets the error policy; maxFailures only applies to FailureThreshold
+func (s *ForEachStep) OnItemError(policy ErrorPolicy, maxFailures int) *ForEachStep {
+	s.ErrorPolicy = policy
+	s.MaxFailures = maxFailures
+	return s
+}
+
+// StoreInto stores the ordered results under key
+func (s *ForEachStep) StoreInto(key string) *ForEachStep {
+	s.Into = key
+	return s
+}
+
+// ResultsKey returns the data key the ordered results are stored under
+func (s *ForEachStep) ResultsKey() string {
+	if s.Into != "" {
+		return s.Into
+	}
+	return ResultKey(s.GetName())
+}
+
+// ErrorsKey returns the data key the errors of the failed items are stored under
+func (s *ForEachStep) ErrorsKey() string {
+	return s.ResultsKey() + "Errors"
+}
+
+// Workers returns the number of items to run at once for a list of n items
+func (s *ForEachStep) Workers(n int) int {
+	workers := s.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	return workers
+}
+
+// Tolerates reports whether the step still proceeds after failures failed items
+func (s *ForEachStep) Tolerates(failures int) bool {
+	switch s.ErrorPolicy {
+	case CollectErrors:
+		return true
+	case FailureThreshold:
+		return failures <= s.MaxFailures
+	default:
+		return failures == 0
+	}
+}
+
+// ItemsOf returns the list to iterate from the workflow data
+// A missing key is an empty list; a value that is not a list is an error
+func (s *ForEachStep) ItemsOf(data primitiveModel.WorkflowData) ([]interface{}, error) {
+	if items, ok := data.GetSlice(s.Items); ok {
+		return items, nil
+	}
+	value := data.Get(s.Items)
+	if value == nil {
+		return nil, nil
+	}
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil, fmt.Errorf("foreach %s: %s is a %T, not a list", s.GetName(), s.Items, value)
+	}
+	items := make([]interface{}, list.Len())
+	for i := range items {
+		items[i] = list.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// ItemData returns the data an item runs against: a copy of the data of the run with the item bound
+func (s *ForEachStep) ItemData(data primitiveModel.WorkflowData, index int, item interface{}) primitiveModel.WorkflowData {
+	key := s.ItemKey
+	if key == "" {
+		key = DefaultItemKey
+	}
+	itemData := primitiveModel.WrapWorkflowData(data.ToMap())
+	itemData.Put(key, item)
+	itemData.Put(key+"Index", index)
+	return itemData
+}
+
+// Validate checks that the step runs exactly one of a child step and a sub-workflow
+func (s *ForEachStep) Validate() error {
+	if (s.ChildStep == nil) == (s.SubWorkflow == nil) {
+		return fmt.Errorf("foreach %s must run exactly one of a child step and a sub-workflow", s.GetName())
+	}
+	switch s.ErrorPolicy {
+	case "", FailFast, CollectErrors, FailureThreshold:
+	default:
+		return fmt.Errorf("foreach %s has unknown error policy %q", s.GetName(), s.ErrorPolicy)
+	}
+	if s.MaxFailures < 0 {
+		return fmt.Errorf("foreach %s has a negative failure threshold", s.GetName())
+	}
+	return nil
+}
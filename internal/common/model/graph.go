@@ -0,0 +1,206 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DependentStep is a step that waits for other steps of its workflow to finish before it starts
+type DependentStep interface {
+	Step
+	GetDependsOn() []string
+	SetDependsOn(names ...string) Step
+}
+
+// ErrInvalidGraph reports step dependencies that do not form a directed acyclic graph
+var ErrInvalidGraph = errors.New("invalid step dependencies")
+
+// StepGraph is the dependency graph of the steps of a workflow
+// A workflow is a graph when any of its steps declares dependencies: each step then starts as soon as
+// the steps it depends on have finished, and the steps without dependencies start with the run
+type StepGraph struct {
+	steps      []Step
+	dependsOn  [][]int
+	dependents [][]int
+}
+
+// dependenciesOf returns the names of the steps a step depends on
+func dependenciesOf(step Step) []string {
+	if dependent, ok := step.(DependentStep); ok {
+		return dependent.GetDependsOn()
+	}
+	return nil
+}
+
+// HasDependencies reports whether any of steps declares dependencies
+func HasDependencies(steps []Step) bool {
+	for _, step := range steps {
+		if len(dependenciesOf(step)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStepGraph builds the dependency graph of steps
+// It fails with ErrInvalidGraph when a step name is empty or repeated, a dependency is unknown,
+// or the dependencies form a cycle
+func NewStepGraph(steps []Step) (*StepGraph, error) {
+	index := make(map[string]int, len(steps))
+	for i, step := range steps {
+		name := step.GetName()
+		if name == "" {
+			return nil, fmt.Errorf("%w: step %d has no name", ErrInvalidGraph, i)
+		}
+		if _, exists := index[name]; exists {
+			return nil, fmt.Errorf("%w: step name %s is used twice", ErrInvalidGraph, name)
+		}
+		index[name] = i
+	}
+
+	graph := &StepGraph{
+		steps:      steps,
+		dependsOn:  make([][]int, len(steps)),
+		dependents: make([][]int, len(steps)),
+	}
+	for i, step := range steps {
+		seen := make(map[int]bool)
+		for _, name := range dependenciesOf(step) {
+			dependency, exists := index[name]
+			if !exists {
+				return nil, fmt.Errorf("%w: step %s depends on unknown step %s", ErrInvalidGraph, step.GetName(), name)
+			}
+			if seen[dependency] {
+				continue
+			}
+			seen[dependency] = true
+			graph.dependsOn[i] = append(graph.dependsOn[i], dependency)
+			graph.dependents[dependency] = append(graph.dependents[dependency], i)
+		}
+	}
+	if cycle := graph.findCycle(); cycle != nil {
+		return nil, fmt.Errorf("%w: dependency cycle %s", ErrInvalidGraph, strings.Join(cycle, " -> "))
+	}
+	return graph, nil
+}
+
+// findCycle returns the names along a dependency cycle, starting and ending with the same step, or nil
+func (g *StepGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make([]int, len(g.steps))
+	var path []int
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		states[i] = visiting
+		path = append(path, i)
+		for _, dependency := range g.dependsOn[i] {
+			switch states[dependency] {
+			case visiting:
+				start := 0
+				for path[start] != dependency {
+					start++
+				}
+				var cycle []string
+				for _, j := range path[start:] {
+					cycle = append(cycle, g.steps[j].GetName())
+				}
+				return append(cycle, g.steps[dependency].GetName())
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		states[i] = visited
+		return nil
+	}
+	for i := range g.steps {
+		if states[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Len returns the number of steps of the graph
+func (g *StepGraph) Len() int {
+	return len(g.steps)
+}
+
+// Step returns the step at index i, in declaration order
+func (g *StepGraph) Step(i int) Step {
+	return g.steps[i]
+}
+
+// DependsOn returns the indexes of the steps step i waits for
+func (g *StepGraph) DependsOn(i int) []int {
+	return g.dependsOn[i]
+}
+
+// Dependents returns the indexes of the steps waiting for step i
+func (g *StepGraph) Dependents(i int) []int {
+	return g.dependents[i]
+}
+
+// ValidateWorkflow checks the step dependencies of a workflow and the configuration of its foreach and wait steps
+// Only top-level steps may declare dependencies, and these must form a directed acyclic graph
+func ValidateWorkflow(workflow Workflow) error {
+	steps := workflow.GetSteps()
+	for _, step := range steps {
+		if err := validateStep(step); err != nil {
+			return err
+		}
+		if branchStep, ok := step.(BranchingStep); ok {
+			if err := validateBranchSteps(branchStep); err != nil {
+				return err
+			}
+		}
+	}
+	if !HasDependencies(steps) {
+		return nil
+	}
+	_, err := NewStepGraph(steps)
+	return err
+}
+
+// validateBranchSteps checks that no step within the branches of a branching step declares dependencies
+func validateBranchSteps(step BranchingStep) error {
+	for _, branch := range step.AllBranches() {
+		for _, branchStep := range branch.Steps {
+			if len(dependenciesOf(branchStep)) > 0 {
+				return fmt.Errorf("%w: step %s of branch %s.%s declares dependencies, which only top-level steps may",
+					ErrInvalidGraph, branchStep.GetName(), step.GetName(), branch.Name)
+			}
+			if err := validateStep(branchStep); err != nil {
+				return err
+			}
+			if nested, ok := branchStep.(BranchingStep); ok {
+				if err := validateBranchSteps(nested); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateStep checks the configuration of a foreach or wait step
+func validateStep(step Step) error {
+	switch s := step.(type) {
+	case *ForEachStep:
+		return s.Validate()
+	case *WaitForSignalStep:
+		return s.Validate()
+	case *TimerStep:
+		return s.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// dependent creates a step named name that depends on dependencies
+func dependent(name string, dependencies ...string) Step {
+	step := NewBaseStep(name, false)
+	if len(dependencies) > 0 {
+		step.SetDependsOn(dependencies...)
+	}
+	return step
+}
+
+func TestNewStepGraph(t *testing.T) {
+	graph, err := NewStepGraph([]Step{
+		dependent("finalize", "aml", "fc", "ml"),
+		dependent("store"),
+		dependent("aml", "store"),
+		dependent("fc", "store", "store"),
+		dependent("ml", "store"),
+	})
+	if err != nil {
+		t.Fatalf("NewStepGraph() error = %v", err)
+	}
+	if got := len(graph.DependsOn(0)); got != 3 {
+		t.Errorf("DependsOn(finalize) = %d steps, want 3", got)
+	}
+	if got := len(graph.DependsOn(3)); got != 1 {
+		t.Errorf("DependsOn(fc) = %d steps, want the repeated dependency once", got)
+	}
+	if got := len(graph.Dependents(1)); got != 3 {
+		t.Errorf("Dependents(store) = %d steps, want 3", got)
+	}
+}
+
+func TestNewStepGraphRejectsInvalidDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []Step
+		want  string
+	}{
+		{"cycle", []Step{dependent("a", "c"), dependent("b", "a"), dependent("c", "b")}, "a -> c -> b -> a"},
+		{"self", []Step{dependent("a", "a")}, "a -> a"},
+		{"unknown", []Step{dependent("a", "missing")}, "unknown step missing"},
+		{"duplicate", []Step{dependent("a"), dependent("a", "b"), dependent("b")}, "used twice"},
+		{"unnamed", []Step{dependent(""), dependent("a", "b")}, "no name"},
+	}
+	for _, tt := range tests {
+		_, err := NewStepGraph(tt.steps)
+		if !errors.Is(err, ErrInvalidGraph) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: NewStepGraph() error = %v, want ErrInvalidGraph mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidateWorkflow(t *testing.T) {
+	linear := NewBaseWorkflow("linear", "")
+	linear.AddSteps([]Step{dependent("a"), dependent("a")})
+	if err := ValidateWorkflow(linear); err != nil {
+		t.Errorf("ValidateWorkflow(linear) error = %v, want nil", err)
+	}
+
+	nested := NewBaseWorkflow("nested", "")
+	nested.AddSteps([]Step{
+		dependent("a"),
+		NewIfStep("route", MustParseCondition("risk_score > 70"), []Step{dependent("review", "a")}, nil),
+	})
+	if err := ValidateWorkflow(nested); !errors.Is(err, ErrInvalidGraph) {
+		t.Errorf("ValidateWorkflow(nested) error = %v, want ErrInvalidGraph", err)
+	}
+}
@@ -85,72 +85,44 @@ func (s *SequentialStep) ExecuteChildStepWithTiming(ctx context.Context, childSt
 		return ctx.Err()
 	}
 
-	// 1. Prepare request from context and data using request hook
-	_ = childStep.GetRequestHook()(context, data)
-
-	// 2. Execute response (calls Primitive) using response hook
-	response := childStep.GetResponseHook()(context, data)
-
-	// Check for context cancellation after response hook
-	if ctx.Err() != nil {
-		return ctx.Err()
-	}
-
-	// 3. If response is not nil, validate it using validate hook
-	if response != nil && childStep.GetValidateHook() != nil {
-		if validateErr := childStep.GetValidateHook()(response); validateErr != nil {
-			err = fmt.Errorf("validation failed for child step %s: %w", childStep.GetName(), validateErr)
-			return err
-		}
-	}
-
-	// Store results in workflow data
-	if response != nil {
-		// Try to put response in data if data supports Put method
-		if dataMap, ok := data.(map[string]interface{}); ok {
-			dataMap[childStep.GetName()+"Result"] = response
-			dataMap[childStep.GetName()+"Completed"] = true
-		}
+	// Run the hooks or typed logic of the child step and store its result in the workflow data
+	workflowData := WorkflowDataOf(data)
+	var response interface{}
+	response, err = childStep.Execute(ctx, WorkflowContextOf(context), workflowData)
+	if err != nil {
+		return err
 	}
+	StoreChildStepResult(workflowData, childStep.GetName(), response)
 
 	return nil
 }
 
 // StoreStepMetrics stores step execution metrics
 func (s *SequentialStep) StoreStepMetrics(context interface{}, data interface{}) {
-	// This is a simplified implementation
-	// In a real implementation, you would store metrics in the data
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		stepKey := "step_" + s.Name + "_metrics"
-		metrics := map[string]interface{}{
-			"stepName":       s.Name,
-			"startTime":      s.StartTime,
-			"endTime":        s.EndTime,
-			"childStepCount": s.GetChildStepCount(),
-			"parallel":       s.IsParallel(),
-		}
-		if s.StartTime != nil && s.EndTime != nil {
-			metrics["durationMillis"] = s.EndTime.Sub(*s.StartTime).Milliseconds()
-		}
-		dataMap[stepKey] = metrics
+	metrics := map[string]interface{}{
+		"stepName":       s.Name,
+		"startTime":      s.StartTime,
+		"endTime":        s.EndTime,
+		"childStepCount": s.GetChildStepCount(),
+		"parallel":       s.IsParallel(),
 	}
+	if s.StartTime != nil && s.EndTime != nil {
+		metrics["durationMillis"] = s.EndTime.Sub(*s.StartTime).Milliseconds()
+	}
+	WorkflowDataOf(data).Put("step_"+s.Name+"_metrics", metrics)
 }
 
 // StoreChildStepMetrics stores child step execution metrics
 func (s *SequentialStep) StoreChildStepMetrics(childStep *ChildStep, context interface{}, data interface{}, startTime, endTime time.Time, errorMessage string) {
-	// This is a simplified implementation
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		childStepKey := "childStep_" + childStep.GetName() + "_metrics"
-		metrics := map[string]interface{}{
-			"childStepName":  childStep.GetName(),
-			"parentStepName": s.Name,
-			"startTime":      startTime,
-			"endTime":        endTime,
-			"errorMessage":   errorMessage,
-		}
-		if !startTime.IsZero() && !endTime.IsZero() {
-			metrics["durationMillis"] = endTime.Sub(startTime).Milliseconds()
-		}
-		dataMap[childStepKey] = metrics
+	metrics := map[string]interface{}{
+		"childStepName":  childStep.GetName(),
+		"parentStepName": s.Name,
+		"startTime":      startTime,
+		"endTime":        endTime,
+		"errorMessage":   errorMessage,
+	}
+	if !startTime.IsZero() && !endTime.IsZero() {
+		metrics["durationMillis"] = endTime.Sub(startTime).Milliseconds()
 	}
+	WorkflowDataOf(data).Put("childStep_"+childStep.GetName()+"_metrics", metrics)
 }
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeoutBranch is the name of the branch a WaitForSignalStep takes when its timeout expires
+const TimeoutBranch = "timeout"
+
+// WaitForSignalStep parks a run until a named signal is sent to it, such as the decision of an analyst
+// The payload of the signal is merged into the workflow data; when Timeout expires first, the steps of
+// OnTimeout run instead, and without any the step fails
+type WaitForSignalStep struct {
+	*BaseStep
+	Signal string
+	// Timeout bounds the wait; zero waits until the signal arrives or the run is cancelled
+	Timeout time.Duration
+	// OnTimeout runs when the timeout expires, reported as the "timeout" branch of the step
+	OnTimeout *Branch
+}
+
+// NewWaitForSignalStep creates a step waiting for the signal of the given name
+func NewWaitForSignalStep(name, signal string) *WaitForSignalStep {
+	return &WaitForSignalStep{
+		BaseStep: NewBaseStep(name, false),
+		Signal:   signal,
+	}
+}
+
+// WithTimeout bounds the wait and sets the steps to run when it expires
+func (s *WaitForSignalStep) WithTimeout(timeout time.Duration, onTimeout ...Step) *WaitForSignalStep {
+	s.Timeout = timeout
+	s.OnTimeout = nil
+	if len(onTimeout) > 0 {
+		s.OnTimeout = &Branch{Name: TimeoutBranch, Steps: onTimeout}
+	}
+	return s
+}
+
+// AllBranches returns the timeout branch of the step, if any
+func (s *WaitForSignalStep) AllBranches() []*Branch {
+	if s.OnTimeout == nil {
+		return nil
+	}
+	return []*Branch{s.OnTimeout}
+}
+
+// Validate checks that the step names its signal and that a timeout branch has a timeout
+func (s *WaitForSignalStep) Validate() error {
+	if s.Signal == "" {
+		return fmt.Errorf("wait step %s has no signal", s.GetName())
+	}
+	if s.Timeout < 0 {
+		return fmt.Errorf("wait step %s has a negative timeout", s.GetName())
+	}
+	if s.OnTimeout != nil && s.Timeout == 0 {
+		return fmt.Errorf("wait step %s has timeout steps but no timeout", s.GetName())
+	}
+	return nil
+}
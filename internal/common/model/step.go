@@ -3,6 +3,8 @@ package model
 import (
 	"context"
 	"time"
+
+	primitiveModel "unified-workflow/internal/primitive/model"
 )
 
 // Step is the interface that all steps must implement
@@ -33,6 +35,13 @@ type Step interface {
 	StoreChildStepMetrics(childStep *ChildStep, context interface{}, data interface{}, startTime, endTime time.Time, errorMessage string)
 }
 
+// WorkflowStep is a step with its own logic, which receives the context and data of its run
+// The executor runs the child steps of a step in order and then the logic of a WorkflowStep
+type WorkflowStep interface {
+	Step
+	Execute(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error
+}
+
 // BaseStep is the base implementation of the Step interface
 type BaseStep struct {
 	Name          string
@@ -45,6 +54,14 @@ type BaseStep struct {
 	ContextAfter  interface{} // Will be typed as primitive.WorkflowContext
 	DataBefore    interface{} // Will be typed as primitive.WorkflowData
 	DataAfter     interface{} // Will be typed as primitive.WorkflowData
+	Condition     Condition   // Guard; the step is skipped when it does not hold
+	DependsOn     []string    // Names of the steps that must finish first; see StepGraph
+
+	// Compensation undoes the step when its run fails or is cancelled after it completed
+	Compensation *Compensation
+
+	// Timeout bounds each execution of the step; zero applies the step timeout of the executor
+	Timeout time.Duration
 }
 
 // NewBaseStep creates a new BaseStep
@@ -132,6 +149,50 @@ func (s *BaseStep) AddChildSteps(childSteps []*ChildStep) Step {
 	return s
 }
 
+// SetCondition guards the step with a condition; the step is skipped when it does not hold
+func (s *BaseStep) SetCondition(condition Condition) Step {
+	s.Condition = condition
+	return s
+}
+
+// GetCondition returns the guard of the step, nil when the step always runs
+func (s *BaseStep) GetCondition() Condition {
+	return s.Condition
+}
+
+// SetDependsOn declares the steps of the workflow that must finish before the step starts
+func (s *BaseStep) SetDependsOn(names ...string) Step {
+	s.DependsOn = append([]string(nil), names...)
+	return s
+}
+
+// GetDependsOn returns the names of the steps the step waits for
+func (s *BaseStep) GetDependsOn() []string {
+	return s.DependsOn
+}
+
+// SetCompensation registers the action undoing the step when its run fails or is cancelled after it completed
+func (s *BaseStep) SetCompensation(compensation *Compensation) Step {
+	s.Compensation = compensation
+	return s
+}
+
+// GetCompensation returns the compensating action of the step, nil when it has none
+func (s *BaseStep) GetCompensation() *Compensation {
+	return s.Compensation
+}
+
+// SetTimeout bounds each execution of the step, overriding the step timeout of the executor
+func (s *BaseStep) SetTimeout(timeout time.Duration) Step {
+	s.Timeout = timeout
+	return s
+}
+
+// GetTimeout returns the timeout of the step, zero when the executor's applies
+func (s *BaseStep) GetTimeout() time.Duration {
+	return s.Timeout
+}
+
 // GetChildStepCount returns the number of child steps
 func (s *BaseStep) GetChildStepCount() int {
 	return len(s.ChildSteps)
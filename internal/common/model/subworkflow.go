@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"unified-workflow/internal/common/expr"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// Binding sets a key to the value of an expression, such as an input of a sub-workflow from the data of its parent
+type Binding struct {
+	Key    string
+	Source *expr.Expression
+}
+
+// ParseBindings compiles a mapping of keys to expressions in the language of conditions, such as
+// {"transaction": "payment.transaction"}; the bindings are ordered by key
+func ParseBindings(mapping map[string]string) ([]Binding, error) {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bindings := make([]Binding, 0, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("binding with an empty key")
+		}
+		source, err := expr.Compile(mapping[key])
+		if err != nil {
+			return nil, fmt.Errorf("binding %s: %w", key, err)
+		}
+		bindings = append(bindings, Binding{Key: key, Source: source})
+	}
+	return bindings, nil
+}
+
+// MustParseBindings is ParseBindings for mappings known to be valid; it panics otherwise
+func MustParseBindings(mapping map[string]string) []Binding {
+	bindings, err := ParseBindings(mapping)
+	if err != nil {
+		panic(err.Error())
+	}
+	return bindings
+}
+
+// ApplyBindings evaluates bindings over vars and stores each value in target
+// A binding whose value is null leaves its key unset
+func ApplyBindings(bindings []Binding, vars expr.Vars, target primitiveModel.WorkflowData) error {
+	for _, binding := range bindings {
+		value, err := binding.Source.Eval(vars)
+		if err != nil {
+			return fmt.Errorf("binding %s: %w", binding.Key, err)
+		}
+		if value != nil {
+			target.Put(binding.Key, value)
+		}
+	}
+	return nil
+}
+
+// SubWorkflowStep runs a registered workflow as a linked run with its own run ID
+type SubWorkflowStep struct {
+	*BaseStep
+	WorkflowID string
+	// Version pins a version of the workflow, counted from 1 in registration order; zero runs the latest
+	Version int
+	// Input builds the input of the child run from the data of the parent; without bindings the child gets all of it
+	Input []Binding
+	// Output copies the result of the child run into the data of the parent; without bindings
+	// the whole result is stored under ResultKey of the step name
+	Output []Binding
+}
+
+// NewSubWorkflowStep creates a step running the latest version of a registered workflow
+func NewSubWorkflowStep(name, workflowID string) *SubWorkflowStep {
+	return &SubWorkflowStep{
+		BaseStep:   NewBaseStep(name, false),
+		WorkflowID: workflowID,
+	}
+}
+
+// PinVersion runs the given version of the workflow rather than the latest
+func (s *SubWorkflowStep) PinVersion(version int) *SubWorkflowStep {
+	s.Version = version
+	return s
+}
+
+// ChildInput builds the input of the child run from the data of the parent
+func (s *SubWorkflowStep) ChildInput(data primitiveModel.WorkflowData) (map[string]interface{}, error) {
+	if len(s.Input) == 0 {
+		return data.ToMap(), nil
+	}
+	input := primitiveModel.NewWorkflowData()
+	if err := ApplyBindings(s.Input, data, input); err != nil {
+		return nil, fmt.Errorf("input of sub-workflow %s: %w", s.WorkflowID, err)
+	}
+	return input.ToMap(), nil
+}
+
+// StoreOutput copies the result of the child run into the data of the parent
+func (s *SubWorkflowStep) StoreOutput(result map[string]interface{}, data primitiveModel.WorkflowData) error {
+	if len(s.Output) == 0 {
+		data.Put(ResultKey(s.GetName()), result)
+		return nil
+	}
+	if err := ApplyBindings(s.Output, expr.Map(result), data); err != nil {
+		return fmt.Errorf("output of sub-workflow %s: %w", s.WorkflowID, err)
+	}
+	return nil
+}
+
+// MapOutput applies the output bindings to the result of a child run; without bindings it returns the whole result
+func (s *SubWorkflowStep) MapOutput(result map[string]interface{}) (map[string]interface{}, error) {
+	if len(s.Output) == 0 {
+		return result, nil
+	}
+	output := primitiveModel.NewWorkflowData()
+	if err := ApplyBindings(s.Output, expr.Map(result), output); err != nil {
+		return nil, fmt.Errorf("output of sub-workflow %s: %w", s.WorkflowID, err)
+	}
+	return output.ToMap(), nil
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// TimedStep is a step with its own timeout, overriding the step timeout of the executor
+type TimedStep interface {
+	Step
+	GetTimeout() time.Duration
+	SetTimeout(timeout time.Duration) Step
+}
+
+// TimedWorkflow is a workflow with its own run timeout, overriding the execution timeout of the executor
+type TimedWorkflow interface {
+	Workflow
+	GetTimeout() time.Duration
+	SetTimeout(timeout time.Duration) Workflow
+}
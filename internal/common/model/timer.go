@@ -0,0 +1,31 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimerStep pauses a run for a duration, such as waiting 15 minutes before checking an ML score again
+// In the top-level steps of a run that declares no dependencies the timer is durable: the run is persisted
+// with its wake-up time and resumed by the timer service, releasing its worker meanwhile; elsewhere the run
+// sleeps in place
+type TimerStep struct {
+	*BaseStep
+	Duration time.Duration
+}
+
+// NewTimerStep creates a step pausing the run for the given duration
+func NewTimerStep(name string, duration time.Duration) *TimerStep {
+	return &TimerStep{
+		BaseStep: NewBaseStep(name, false),
+		Duration: duration,
+	}
+}
+
+// Validate checks that the timer has a duration
+func (s *TimerStep) Validate() error {
+	if s.Duration <= 0 {
+		return fmt.Errorf("timer step %s has no duration", s.GetName())
+	}
+	return nil
+}
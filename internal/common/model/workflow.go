@@ -62,6 +62,9 @@ type BaseWorkflow struct {
 	Primitives  interface{}
 	Context     interface{}
 	Data        interface{}
+
+	// Timeout bounds each run of the workflow; zero applies the execution timeout of the executor
+	Timeout time.Duration
 }
 
 // NewBaseWorkflow creates a new BaseWorkflow
@@ -166,6 +169,17 @@ func (w *BaseWorkflow) GetData() interface{} {
 	return w.Data
 }
 
+// SetTimeout bounds each run of the workflow, overriding the execution timeout of the executor
+func (w *BaseWorkflow) SetTimeout(timeout time.Duration) Workflow {
+	w.Timeout = timeout
+	return w
+}
+
+// GetTimeout returns the run timeout of the workflow, zero when the executor's applies
+func (w *BaseWorkflow) GetTimeout() time.Duration {
+	return w.Timeout
+}
+
 // GetTotalChildStepCount returns the total number of child steps across all steps
 func (w *BaseWorkflow) GetTotalChildStepCount() int {
 	total := 0
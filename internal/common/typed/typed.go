@@ -0,0 +1,173 @@
+// Package typed is the generic authoring API for steps and child steps
+// Child steps pass a typed request to a typed response hook and may fail from any hook with an error;
+// steps receive the WorkflowContext and WorkflowData of their run. Both adapt to the untyped model
+// types, so they are added to workflows and run by the executor like any other step
+package typed
+
+import (
+	"context"
+	"fmt"
+
+	"unified-workflow/internal/common/model"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// RequestHook builds the request of a child step from the context and data of its run
+type RequestHook[Req any] func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (Req, error)
+
+// ResponseHook sends the request of a child step, typically to a primitive, and returns the response
+type ResponseHook[Req, Resp any] func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, request Req) (Resp, error)
+
+// ValidateHook checks the response of a child step
+type ValidateHook[Resp any] func(response Resp) error
+
+// CompensateHook undoes a child step that completed, given its response
+type CompensateHook[Resp any] func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, response Resp) error
+
+// ChildStep is a child step with a typed request and response
+type ChildStep[Req, Resp any] struct {
+	name     string
+	request  RequestHook[Req]
+	response ResponseHook[Req, Resp]
+	validate ValidateHook[Resp]
+
+	compensate CompensateHook[Resp]
+	retry      model.RetryPolicy
+}
+
+// NewChildStep creates a typed child step; any hook may be nil
+// Without a request hook the response hook receives the zero request; without a response hook the
+// request is the response, which requires Req and Resp to be the same type
+func NewChildStep[Req, Resp any](name string, request RequestHook[Req], response ResponseHook[Req, Resp], validate ValidateHook[Resp]) *ChildStep[Req, Resp] {
+	return &ChildStep[Req, Resp]{
+		name:     name,
+		request:  request,
+		response: response,
+		validate: validate,
+	}
+}
+
+// GetName returns the name of the child step
+func (cs *ChildStep[Req, Resp]) GetName() string {
+	return cs.name
+}
+
+// Run runs the request, response and validate hooks and returns the validated response
+func (cs *ChildStep[Req, Resp]) Run(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (Resp, error) {
+	var request Req
+	var response Resp
+	if cs.request != nil {
+		var err error
+		if request, err = cs.request(ctx, workflowContext, data); err != nil {
+			return response, fmt.Errorf("request hook of child step %s failed: %w", cs.name, err)
+		}
+	}
+
+	if cs.response != nil {
+		var err error
+		if response, err = cs.response(ctx, workflowContext, request); err != nil {
+			return response, fmt.Errorf("response hook of child step %s failed: %w", cs.name, err)
+		}
+	} else if converted, ok := any(request).(Resp); ok {
+		response = converted
+	} else {
+		return response, fmt.Errorf("child step %s has no response hook and its %T request is not a %T response", cs.name, request, response)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return response, err
+	}
+	if cs.validate != nil {
+		if err := cs.validate(response); err != nil {
+			return response, fmt.Errorf("validation failed for child step %s: %w", cs.name, err)
+		}
+	}
+	return response, nil
+}
+
+// WithCompensation registers the action undoing the child step when its run fails or is cancelled after it completed
+// The action receives the stored response of the child step, or the zero response when none was stored
+func (cs *ChildStep[Req, Resp]) WithCompensation(compensate CompensateHook[Resp], retry model.RetryPolicy) *ChildStep[Req, Resp] {
+	cs.compensate = compensate
+	cs.retry = retry
+	return cs
+}
+
+// Untyped adapts the child step to a model.ChildStep, which steps hold and the executor runs
+// The response is the child step result, stored in the workflow data under model.ResultKey(name)
+func (cs *ChildStep[Req, Resp]) Untyped() *model.ChildStep {
+	childStep := model.NewChildStepFunc(cs.name, func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (interface{}, error) {
+		return cs.Run(ctx, workflowContext, data)
+	})
+	if cs.compensate != nil {
+		childStep.SetCompensation(&model.Compensation{
+			Run: func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+				response, _ := Result[Resp](data, cs.name)
+				return cs.compensate(ctx, workflowContext, response)
+			},
+			Retry: cs.retry,
+		})
+	}
+	return childStep
+}
+
+// Result returns the stored result of a child step as a T
+// It reports false when the child step has not stored a result or the result is not a T
+func Result[T any](data primitiveModel.WorkflowData, childStepName string) (T, bool) {
+	value, ok := data.Get(model.ResultKey(childStepName)).(T)
+	return value, ok
+}
+
+// StepFunc is the logic of a typed step
+type StepFunc func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error
+
+// Step is a step whose logic receives the WorkflowContext and WorkflowData of its run
+// The executor runs its child steps first, then its logic
+type Step struct {
+	*model.BaseStep
+	logic StepFunc
+}
+
+// NewStep creates a sequential typed step; logic may be nil for a step made of child steps only
+func NewStep(name string, logic StepFunc) *Step {
+	return &Step{
+		BaseStep: model.NewBaseStep(name, false),
+		logic:    logic,
+	}
+}
+
+// Execute implements model.WorkflowStep
+func (s *Step) Execute(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+	if s.logic == nil {
+		return nil
+	}
+	return s.logic(ctx, workflowContext, data)
+}
+
+// Run adapts the untyped Step interface: it runs the child steps in order, storing their results, then the logic
+func (s *Step) Run(ctx context.Context, context interface{}, data interface{}) error {
+	workflowContext := model.WorkflowContextOf(context)
+	workflowData := model.WorkflowDataOf(data)
+	for _, childStep := range s.GetChildSteps() {
+		result, err := childStep.Execute(ctx, workflowContext, workflowData)
+		if err != nil {
+			return fmt.Errorf("child step %s failed: %w", childStep.GetName(), err)
+		}
+		model.StoreChildStepResult(workflowData, childStep.GetName(), result)
+	}
+	return s.Execute(ctx, workflowContext, workflowData)
+}
+
+// ChildStepAdapter is implemented by every ChildStep[Req, Resp]
+type ChildStepAdapter interface {
+	GetName() string
+	Untyped() *model.ChildStep
+}
+
+// AddChildSteps adds typed child steps to a step
+func AddChildSteps(step model.Step, childSteps ...ChildStepAdapter) model.Step {
+	for _, childStep := range childSteps {
+		step.AddChildStep(childStep.Untyped())
+	}
+	return step
+}
@@ -0,0 +1,214 @@
+package typed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"unified-workflow/internal/common/model"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+type quote struct {
+	Amount   int
+	Currency string
+}
+
+func newRun() (primitiveModel.WorkflowContext, primitiveModel.WorkflowData) {
+	data := primitiveModel.NewWorkflowData()
+	data.Put("amount", 250)
+	return primitiveModel.NewWorkflowContextForRun("run-1", "wf-1"), data
+}
+
+func TestStepChainsTypedChildStepResults(t *testing.T) {
+	prepare := NewChildStep[int, quote](
+		"prepare",
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (int, error) {
+			amount, _ := data.Get("amount").(int)
+			return amount, nil
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, amount int) (quote, error) {
+			return quote{Amount: amount, Currency: "KZT"}, nil
+		},
+		nil,
+	)
+	confirm := NewChildStep[quote, string](
+		"confirm",
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (quote, error) {
+			q, ok := Result[quote](data, "prepare")
+			if !ok {
+				return q, errors.New("no quote")
+			}
+			return q, nil
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, q quote) (string, error) {
+			return fmt.Sprintf("%s:%d %s", workflowContext.GetRunID(), q.Amount, q.Currency), nil
+		},
+		nil,
+	)
+
+	var seen string
+	step := NewStep("pay", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		seen, _ = Result[string](data, "confirm")
+		return nil
+	})
+	AddChildSteps(step, prepare, confirm)
+
+	workflowContext, data := newRun()
+	if err := step.Run(context.Background(), workflowContext, data); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if seen != "run-1:250 KZT" {
+		t.Errorf("step logic saw %q, want %q", seen, "run-1:250 KZT")
+	}
+	if completed, _ := data.Get("prepareCompleted").(bool); !completed {
+		t.Error("prepareCompleted not set")
+	}
+	var _ model.WorkflowStep = step
+}
+
+func TestHookErrorsFailTheChildStep(t *testing.T) {
+	boom := errors.New("service unavailable")
+	childStep := NewChildStep[int, int](
+		"call",
+		nil,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, request int) (int, error) {
+			return 0, boom
+		},
+		nil,
+	)
+
+	workflowContext, data := newRun()
+	_, err := childStep.Untyped().Execute(context.Background(), workflowContext, data)
+	if !errors.Is(err, boom) {
+		t.Fatalf("Execute() error = %v, want %v", err, boom)
+	}
+}
+
+func TestValidateHookChecksTheTypedResponse(t *testing.T) {
+	childStep := NewChildStep[int, int](
+		"check",
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (int, error) {
+			return 101, nil
+		},
+		nil,
+		func(score int) error {
+			if score > 100 {
+				return fmt.Errorf("score %d out of range", score)
+			}
+			return nil
+		},
+	)
+
+	workflowContext, data := newRun()
+	if _, err := childStep.Run(context.Background(), workflowContext, data); err == nil {
+		t.Fatal("Run() error = nil, want validation error")
+	}
+}
+
+func TestMissingResponseHookRequiresMatchingTypes(t *testing.T) {
+	childStep := NewChildStep[int, string]("mismatch", nil, nil, nil)
+
+	workflowContext, data := newRun()
+	if _, err := childStep.Run(context.Background(), workflowContext, data); err == nil {
+		t.Fatal("Run() error = nil, want type mismatch error")
+	}
+}
+
+func TestUntypedChildStepsKeepWorking(t *testing.T) {
+	var hookContext, hookData interface{}
+	legacy := model.NewChildStep(
+		"legacy",
+		func(context interface{}, data interface{}) interface{} {
+			hookContext, hookData = context, data
+			return map[string]interface{}{"ok": true}
+		},
+		nil,
+		func(response interface{}) error {
+			if ok, _ := response.(map[string]interface{})["ok"].(bool); !ok {
+				return errors.New("not ok")
+			}
+			return nil
+		},
+	)
+	failing := model.NewChildStep(
+		"failing",
+		func(context interface{}, data interface{}) interface{} {
+			return errors.New("request failed")
+		},
+		nil, nil,
+	)
+
+	workflowContext, data := newRun()
+	result, err := legacy.Execute(context.Background(), workflowContext, data)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.(map[string]interface{})["ok"] != true {
+		t.Errorf("Execute() result = %v", result)
+	}
+	if runID := hookContext.(map[string]interface{})["run_id"]; runID != "run-1" {
+		t.Errorf("hook context run_id = %v, want run-1", runID)
+	}
+	if amount := hookData.(map[string]interface{})["amount"]; amount != 250 {
+		t.Errorf("hook data amount = %v, want 250", amount)
+	}
+
+	if _, err := failing.Execute(context.Background(), workflowContext, data); err == nil {
+		t.Fatal("Execute() error = nil, want the error returned by the request hook")
+	}
+}
+
+func TestSequentialStepStoresResultsInDataMap(t *testing.T) {
+	step := model.NewSequentialStep("legacy-step")
+	AddChildSteps(step, NewChildStep[int, int](
+		"double",
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (int, error) {
+			amount, _ := data.Get("amount").(int)
+			return amount * 2, nil
+		},
+		nil, nil,
+	))
+
+	data := map[string]interface{}{"amount": 21}
+	if err := step.Run(context.Background(), map[string]interface{}{"run_id": "run-2"}, data); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if data[model.ResultKey("double")] != 42 {
+		t.Errorf("doubleResult = %v, want 42", data[model.ResultKey("double")])
+	}
+}
+
+func TestCompensationReceivesTheTypedResponse(t *testing.T) {
+	var voided quote
+	hold := NewChildStep[int, quote](
+		"hold",
+		nil,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, _ int) (quote, error) {
+			return quote{Amount: 250, Currency: "KZT"}, nil
+		},
+		nil,
+	).WithCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, q quote) error {
+		voided = q
+		return nil
+	}, model.DefaultCompensationRetry).Untyped()
+
+	workflowContext, data := newRun()
+	result, err := hold.Execute(context.Background(), workflowContext, data)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	model.StoreChildStepResult(data, hold.GetName(), result)
+
+	compensation := hold.GetCompensation()
+	if compensation == nil || compensation.Retry != model.DefaultCompensationRetry {
+		t.Fatalf("GetCompensation() = %v, want the compensation with its retry policy", compensation)
+	}
+	if err := compensation.Run(context.Background(), workflowContext, data); err != nil {
+		t.Fatalf("compensation error = %v", err)
+	}
+	if voided != (quote{Amount: 250, Currency: "KZT"}) {
+		t.Errorf("compensation received %+v, want the stored response", voided)
+	}
+}
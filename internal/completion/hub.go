@@ -0,0 +1,118 @@
+package completion
+
+import (
+	"errors"
+	"sync"
+
+	"unified-workflow/internal/metrics"
+	"unified-workflow/internal/queue"
+)
+
+// ErrTooManyWaiters is returned by Subscribe when the hub already holds its maximum number of waiters
+var ErrTooManyWaiters = errors.New("too many requests waiting for run completion")
+
+// Hub notifies waiters and listeners when workflow runs reach a terminal state
+// Results reach the hub from runs executed in-process and, with NATS, from the result
+// subjects of every worker, so a request parked on any node is woken up
+type Hub struct {
+	mu         sync.Mutex
+	maxWaiters int
+	count      int
+	waiters    map[string]map[*waiter]struct{}
+	listeners  []func(queue.ExecutionResult)
+}
+
+// waiter receives the result of a single run
+type waiter struct {
+	ch chan queue.ExecutionResult
+}
+
+// NewHub creates a completion hub holding at most maxWaiters waiters (unbounded if zero)
+func NewHub(maxWaiters int) *Hub {
+	return &Hub{
+		maxWaiters: maxWaiters,
+		waiters:    make(map[string]map[*waiter]struct{}),
+	}
+}
+
+// SetMaxWaiters changes the waiter limit; waiters already parked are kept
+func (h *Hub) SetMaxWaiters(maxWaiters int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxWaiters = maxWaiters
+}
+
+// Subscribe returns a channel that receives the result of the run once it completes
+// Callers must subscribe before checking the run status to not miss a completion,
+// and call cancel when they stop waiting
+func (h *Hub) Subscribe(runID string) (<-chan queue.ExecutionResult, func(), error) {
+	w := &waiter{ch: make(chan queue.ExecutionResult, 1)}
+
+	h.mu.Lock()
+	if h.maxWaiters > 0 && h.count >= h.maxWaiters {
+		h.mu.Unlock()
+		metrics.CompletionWaitersRejected.Inc()
+		return nil, nil, ErrTooManyWaiters
+	}
+	if h.waiters[runID] == nil {
+		h.waiters[runID] = make(map[*waiter]struct{})
+	}
+	h.waiters[runID][w] = struct{}{}
+	h.count++
+	h.mu.Unlock()
+	metrics.CompletionWaiters.Inc()
+
+	var once sync.Once
+	return w.ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if waiters, ok := h.waiters[runID]; ok {
+				if _, ok := waiters[w]; ok {
+					delete(waiters, w)
+					h.release(1)
+				}
+				if len(waiters) == 0 {
+					delete(h.waiters, runID)
+				}
+			}
+		})
+	}, nil
+}
+
+// Waiters returns the number of parked waiters
+func (h *Hub) Waiters() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// OnCompletion registers a listener called with the result of every completed run
+func (h *Hub) OnCompletion(listener func(queue.ExecutionResult)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, listener)
+}
+
+// Publish delivers the result of a completed run to its waiters and to all listeners
+func (h *Hub) Publish(result queue.ExecutionResult) {
+	h.mu.Lock()
+	waiters := h.waiters[result.RunID]
+	delete(h.waiters, result.RunID)
+	h.release(len(waiters))
+	listeners := append([]func(queue.ExecutionResult){}, h.listeners...)
+	h.mu.Unlock()
+
+	for w := range waiters {
+		w.ch <- result
+	}
+	for _, listener := range listeners {
+		listener(result)
+	}
+}
+
+// release accounts for n waiters leaving the hub; callers hold h.mu
+func (h *Hub) release(n int) {
+	h.count -= n
+	metrics.CompletionWaiters.Add(float64(-n))
+}
@@ -0,0 +1,77 @@
+package completion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/queue"
+)
+
+func TestHubWakesWaitersAndListeners(t *testing.T) {
+	hub := NewHub(0)
+	var heard []string
+	hub.OnCompletion(func(result queue.ExecutionResult) {
+		heard = append(heard, result.RunID)
+	})
+
+	first, cancelFirst, err := hub.Subscribe("run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelFirst()
+	second, cancelSecond, err := hub.Subscribe("run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelSecond()
+
+	hub.Publish(queue.ExecutionResult{RunID: "run-1", Status: "completed"})
+
+	for _, ch := range []<-chan queue.ExecutionResult{first, second} {
+		select {
+		case result := <-ch:
+			if result.Status != "completed" {
+				t.Errorf("waiter got status %q", result.Status)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("waiter was not woken up")
+		}
+	}
+	if len(heard) != 1 || heard[0] != "run-1" {
+		t.Errorf("listener heard %v, want [run-1]", heard)
+	}
+	if hub.Waiters() != 0 {
+		t.Errorf("Waiters() = %d after publish, want 0", hub.Waiters())
+	}
+}
+
+func TestHubBoundsWaiters(t *testing.T) {
+	hub := NewHub(2)
+
+	_, cancelA, err := hub.Subscribe("run-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, cancelB, err := hub.Subscribe("run-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := hub.Subscribe("run-c"); !errors.Is(err, ErrTooManyWaiters) {
+		t.Fatalf("Subscribe() over the limit error = %v, want ErrTooManyWaiters", err)
+	}
+
+	// Cancelled and woken waiters free their slots
+	cancelA()
+	cancelA()
+	hub.Publish(queue.ExecutionResult{RunID: "run-b"})
+	cancelB()
+	if hub.Waiters() != 0 {
+		t.Fatalf("Waiters() = %d, want 0", hub.Waiters())
+	}
+	if _, cancel, err := hub.Subscribe("run-c"); err != nil {
+		t.Fatalf("Subscribe() after release error = %v", err)
+	} else {
+		cancel()
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -14,17 +15,28 @@ import (
 type Config struct {
 	Server              ServerConfig              `yaml:"server"`
 	Queue               QueueConfig               `yaml:"queue"`
+	State               StateConfig               `yaml:"state"`
 	Executor            ExecutorConfig            `yaml:"executor"`
 	Logging             LoggingConfig             `yaml:"logging"`
+	Metrics             MetricsConfig             `yaml:"metrics"`
 	DependencyInjection DependencyInjectionConfig `yaml:"dependency_injection"`
 	Services            ServicesConfig            `yaml:"services"`
 	Clients             ClientsConfig             `yaml:"clients"`
 	Primitives          PrimitivesConfig          `yaml:"primitives"`
+	Resilience          ResilienceConfig          `yaml:"resilience"`
+	DataProtection      DataProtectionConfig      `yaml:"data_protection"`
+	Callbacks           CallbacksConfig           `yaml:"callbacks"`
+	Auth                AuthConfig                `yaml:"auth"`
+	Tenancy             TenancyConfig             `yaml:"tenancy"`
+	Admission           AdmissionConfig           `yaml:"admission"`
 }
 
 // ServerConfig represents server configuration
 type ServerConfig struct {
 	Port int `yaml:"port"`
+
+	// GRPCPort serves the gRPC execution API next to REST; 0 disables it
+	GRPCPort int `yaml:"grpc_port"`
 }
 
 // QueueConfig represents queue configuration
@@ -44,6 +56,22 @@ type NATSConfig struct {
 	ConnectTimeout time.Duration `yaml:"connect_timeout"`
 }
 
+// StateConfig represents the configuration of the store holding run state, timers and signals
+// Every process consuming a NATS queue must share one store, as a run parked on a timer is resumed by any of them
+type StateConfig struct {
+	Type  string           `yaml:"type"`
+	Redis RedisStateConfig `yaml:"redis"`
+}
+
+// RedisStateConfig represents Redis state store configuration
+type RedisStateConfig struct {
+	Addr     string        `yaml:"addr"`
+	Password string        `yaml:"password"`
+	DB       int           `yaml:"db"`
+	Prefix   string        `yaml:"prefix"`
+	TTL      time.Duration `yaml:"ttl"` // How long the state of a finished run is kept
+}
+
 // ExecutorConfig represents executor configuration
 type ExecutorConfig struct {
 	WorkerCount            int           `yaml:"worker_count"`
@@ -55,12 +83,29 @@ type ExecutorConfig struct {
 	EnableMetrics          bool          `yaml:"enable_metrics"`
 	EnableTracing          bool          `yaml:"enable_tracing"`
 	MaxConcurrentWorkflows int           `yaml:"max_concurrent_workflows"`
+	MaxResultWaiters       int           `yaml:"max_result_waiters"` // Requests parked waiting for a run to finish, per node
 }
 
 // LoggingConfig represents logging configuration
 type LoggingConfig struct {
-	Level  string `yaml:"level"`
-	Format string `yaml:"format"`
+	Level      string  `yaml:"level"`
+	Format     string  `yaml:"format"`
+	SampleRate float64 `yaml:"sample_rate"`
+}
+
+// MetricsConfig represents Prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	Path               string `yaml:"path"`
+	MaxSeriesPerMetric int    `yaml:"max_series_per_metric"`
+}
+
+// Endpoint returns the path the metrics are served on, /metrics unless configured otherwise
+func (m MetricsConfig) Endpoint() string {
+	if m.Path == "" {
+		return "/metrics"
+	}
+	return m.Path
 }
 
 // DependencyInjectionConfig represents DI container configuration
@@ -80,6 +125,9 @@ type ServicesConfig struct {
 // RegistryServiceConfig represents registry service configuration
 type RegistryServiceConfig struct {
 	URL string `yaml:"url"`
+
+	// AuthToken is sent as a bearer token when registry-api requires authentication
+	AuthToken string `yaml:"auth_token"`
 }
 
 // ClientsConfig represents client configurations
@@ -89,6 +137,8 @@ type ClientsConfig struct {
 }
 
 // AntifraudClientConfig represents antifraud client configuration
+// Circuit breaking, timeouts and retries are configured under resilience.antifraud;
+// the circuit_breaker_* fields are kept for backwards compatibility only
 type AntifraudClientConfig struct {
 	APIKey                  string `yaml:"api_key"`
 	Host                    string `yaml:"host"`
@@ -110,11 +160,216 @@ type PrimitivesConfig struct {
 	EchoEnabled bool `yaml:"echo_enabled"`
 }
 
+// ResilienceConfig represents per-service resilience policies for primitive services
+type ResilienceConfig struct {
+	Antifraud ServiceResilienceConfig `yaml:"antifraud"`
+	Storage   ServiceResilienceConfig `yaml:"storage"`
+	HTTP      ServiceResilienceConfig `yaml:"http"`
+}
+
+// ServiceResilienceConfig represents the resilience policy of a single service
+type ServiceResilienceConfig struct {
+	Enabled        bool                 `yaml:"enabled"`
+	Timeout        time.Duration        `yaml:"timeout"`
+	MaxConcurrent  int                  `yaml:"max_concurrent"`
+	Retry          RetryConfig          `yaml:"retry"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+}
+
+// RetryConfig represents retry configuration with exponential backoff
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseDelay   time.Duration `yaml:"base_delay"`
+	MaxDelay    time.Duration `yaml:"max_delay"`
+	Jitter      bool          `yaml:"jitter"`
+}
+
+// CircuitBreakerConfig represents circuit breaker configuration
+type CircuitBreakerConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	FailureThreshold int           `yaml:"failure_threshold"`
+	SuccessThreshold int           `yaml:"success_threshold"`
+	OpenTimeout      time.Duration `yaml:"open_timeout"`
+	FailureWindow    time.Duration `yaml:"failure_window"`
+	MinimumRequests  int           `yaml:"minimum_requests"`
+}
+
+// DataProtectionConfig represents classification, encryption at rest and masking of sensitive workflow data
+type DataProtectionConfig struct {
+	// Enabled encrypts classified fields at rest; it requires a key file or a KMS key
+	Enabled bool `yaml:"enabled"`
+
+	// KeyProvider wraps the per-record data keys: "local" (key file) or "kms"
+	KeyProvider string `yaml:"key_provider"`
+
+	// KeyFile holds the local master key
+	KeyFile string `yaml:"key_file"`
+
+	KMS KMSConfig `yaml:"kms"`
+
+	// Fields classifies additional field names (pan, cvv or pii) on top of the built-in policy
+	Fields map[string]string `yaml:"fields"`
+}
+
+// KMSConfig represents a Vault-compatible transit KMS
+type KMSConfig struct {
+	Address string        `yaml:"address"`
+	Token   string        `yaml:"token"`
+	KeyName string        `yaml:"key_name"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// CallbacksConfig represents delivery of execution results to callback_url
+type CallbacksConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// StoreDir persists pending callbacks across restarts; empty keeps them in memory
+	StoreDir string `yaml:"store_dir"`
+
+	// Secrets maps a client ID (X-Client-ID) to the secret its callbacks are signed with
+	Secrets map[string]string `yaml:"secrets"`
+
+	// DefaultSecret signs callbacks of clients without their own secret
+	DefaultSecret string `yaml:"default_secret"`
+
+	Timeout        time.Duration `yaml:"timeout"`
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+}
+
+// AuthConfig represents authentication and role-based authorization of the server APIs
+type AuthConfig struct {
+	// Enabled requires a credential on every API route; changes take effect on restart
+	Enabled bool `yaml:"enabled"`
+
+	// APIKeys authenticate service clients through X-API-Key or a bearer token
+	APIKeys []APIKeyConfig `yaml:"api_keys"`
+
+	JWT JWTConfig `yaml:"jwt"`
+
+	// Roles maps a role name to the permissions it grants, e.g. "executions:cancel" or "workflows:*"
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// APIKeyConfig represents an API key and the principal it authenticates
+type APIKeyConfig struct {
+	// ID names the principal in audit records
+	ID string `yaml:"id"`
+
+	// Key is the key in clear text; KeySHA256 (hex) avoids storing it
+	Key       string `yaml:"key"`
+	KeySHA256 string `yaml:"key_sha256"`
+
+	Roles       []string `yaml:"roles"`
+	Permissions []string `yaml:"permissions"`
+
+	// Tenant binds the key to a tenant; "*" lets it act for the tenant named by each request
+	Tenant string `yaml:"tenant"`
+}
+
+// JWTConfig represents verification of bearer JWTs
+type JWTConfig struct {
+	// HMACSecret verifies HS256/HS384/HS512 tokens
+	HMACSecret string `yaml:"hmac_secret"`
+
+	// JWKSURL serves the keys verifying RS* and ES* tokens
+	JWKSURL     string        `yaml:"jwks_url"`
+	JWKSRefresh time.Duration `yaml:"jwks_refresh"`
+
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+
+	// RolesClaim holds the roles of the subject; the "permissions", "scope" and "scp" claims grant permissions directly
+	RolesClaim string        `yaml:"roles_claim"`
+	ClockSkew  time.Duration `yaml:"clock_skew"`
+
+	// TenantClaim holds the tenant of the subject; tokens without it act for the default tenant
+	TenantClaim string `yaml:"tenant_claim"`
+}
+
+// TenancyConfig represents the quotas limiting the workflow runs of each tenant
+type TenancyConfig struct {
+	// DefaultQuota applies to tenants without an entry in Quotas
+	DefaultQuota TenantQuotaConfig `yaml:"default_quota"`
+
+	// Quotas overrides the default quota per tenant ID
+	Quotas map[string]TenantQuotaConfig `yaml:"quotas"`
+
+	// SlotTTL reclaims the concurrency slot of a run whose completion is never reported
+	SlotTTL time.Duration `yaml:"slot_ttl"`
+}
+
+// TenantQuotaConfig represents the limits of a tenant; zero leaves a limit off
+type TenantQuotaConfig struct {
+	MaxConcurrentRuns int     `yaml:"max_concurrent_runs"`
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// AdmissionConfig represents the limits protecting execution ingress from bursts
+// executor.max_concurrent_workflows caps the runs in flight on each node
+type AdmissionConfig struct {
+	// ClientRate limits the runs started by each API key or token subject; anonymous callers share the limit of their tenant
+	ClientRate RateLimitConfig `yaml:"client_rate"`
+
+	// ClientRates overrides the client rate per API key ID, token subject or tenant
+	ClientRates map[string]RateLimitConfig `yaml:"client_rates"`
+
+	// WorkflowRate limits the runs started of each workflow
+	WorkflowRate RateLimitConfig `yaml:"workflow_rate"`
+
+	// WorkflowRates overrides the workflow rate per workflow ID
+	WorkflowRates map[string]RateLimitConfig `yaml:"workflow_rates"`
+
+	// MaxQueueDepth refuses new runs while more execution requests wait in the queue; zero leaves it off
+	MaxQueueDepth int `yaml:"max_queue_depth"`
+
+	// QueueDepthInterval is how long a sample of the queue depth is reused
+	QueueDepthInterval time.Duration `yaml:"queue_depth_interval"`
+
+	// RetryAfter is suggested to callers refused because the queue or the executor is saturated
+	RetryAfter time.Duration `yaml:"retry_after"`
+
+	// InFlightTTL reclaims the slot of a run whose completion is never reported
+	InFlightTTL time.Duration `yaml:"in_flight_ttl"`
+}
+
+// RateLimitConfig represents a token bucket; zero requests per second leaves it off
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// defaultServiceResilience returns the default resilience policy for a primitive service
+func defaultServiceResilience(timeout time.Duration, maxConcurrent int) ServiceResilienceConfig {
+	return ServiceResilienceConfig{
+		Enabled:       true,
+		Timeout:       timeout,
+		MaxConcurrent: maxConcurrent,
+		Retry: RetryConfig{
+			MaxAttempts: 3,
+			BaseDelay:   100 * time.Millisecond,
+			MaxDelay:    2 * time.Second,
+			Jitter:      true,
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			Enabled:          true,
+			FailureThreshold: 5,
+			SuccessThreshold: 2,
+			OpenTimeout:      60 * time.Second,
+			FailureWindow:    60 * time.Second,
+			MinimumRequests:  10,
+		},
+	}
+}
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: 8080,
+			Port:     8080,
+			GRPCPort: 9090,
 		},
 		Queue: QueueConfig{
 			Type: "in-memory",
@@ -128,6 +383,14 @@ func DefaultConfig() *Config {
 				ConnectTimeout: 5 * time.Second,
 			},
 		},
+		State: StateConfig{
+			Type: "in-memory",
+			Redis: RedisStateConfig{
+				Addr:   "localhost:6379",
+				Prefix: "uwf",
+				TTL:    24 * time.Hour,
+			},
+		},
 		Executor: ExecutorConfig{
 			WorkerCount:            5,
 			QueuePollInterval:      1 * time.Second,
@@ -138,10 +401,17 @@ func DefaultConfig() *Config {
 			EnableMetrics:          true,
 			EnableTracing:          false,
 			MaxConcurrentWorkflows: 10,
+			MaxResultWaiters:       10000,
 		},
 		Logging: LoggingConfig{
-			Level:  "info",
-			Format: "json",
+			Level:      "info",
+			Format:     "json",
+			SampleRate: 1.0,
+		},
+		Metrics: MetricsConfig{
+			Enabled:            true,
+			Path:               "/metrics",
+			MaxSeriesPerMetric: 500,
 		},
 		DependencyInjection: DependencyInjectionConfig{
 			PoolSize:                 1000,
@@ -173,23 +443,67 @@ func DefaultConfig() *Config {
 		Primitives: PrimitivesConfig{
 			EchoEnabled: true,
 		},
+		Resilience: ResilienceConfig{
+			Antifraud: defaultServiceResilience(30*time.Second, 50),
+			Storage:   defaultServiceResilience(10*time.Second, 100),
+			HTTP:      defaultServiceResilience(30*time.Second, 100),
+		},
+		DataProtection: DataProtectionConfig{
+			Enabled:     false,
+			KeyProvider: "local",
+			KMS: KMSConfig{
+				Timeout: 5 * time.Second,
+			},
+		},
+		Callbacks: CallbacksConfig{
+			Enabled:        true,
+			Timeout:        10 * time.Second,
+			MaxAttempts:    8,
+			InitialBackoff: 1 * time.Second,
+			MaxBackoff:     5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				JWKSRefresh: 10 * time.Minute,
+				RolesClaim:  "roles",
+				ClockSkew:   30 * time.Second,
+				TenantClaim: "tenant",
+			},
+			Roles: map[string][]string{
+				"viewer":   {"workflows:read", "executions:read"},
+				"operator": {"workflows:read", "executions:read", "executions:create", "executions:cancel", "executions:control"},
+				"admin":    {"*"},
+			},
+		},
+		Tenancy: TenancyConfig{
+			SlotTTL: 1 * time.Hour,
+		},
+		Admission: AdmissionConfig{
+			QueueDepthInterval: 1 * time.Second,
+			RetryAfter:         1 * time.Second,
+			InFlightTTL:        1 * time.Hour,
+		},
 	}
 }
 
 // LoadConfig loads configuration from file and environment variables
 func LoadConfig() (*Config, error) {
+	config, _, err := LoadConfigWithSource()
+	return config, err
+}
+
+// LoadConfigWithSource loads configuration and returns the file it was read from
+// The returned path is empty when the built-in defaults are used
+func LoadConfigWithSource() (*Config, string, error) {
 	// Try to load from config file
 	configPath := getConfigPath()
 	if configPath != "" {
-		data, err := os.ReadFile(configPath)
-		if err == nil {
-			var config Config
-			if err := yaml.Unmarshal(data, &config); err != nil {
-				return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+		if _, err := os.Stat(configPath); err == nil {
+			config, err := LoadConfigFromFile(configPath)
+			if err != nil {
+				return nil, "", err
 			}
-			// Apply environment variable overrides
-			applyEnvOverrides(&config)
-			return &config, nil
+			return config, configPath, nil
 		}
 	}
 
@@ -197,7 +511,234 @@ func LoadConfig() (*Config, error) {
 	config := DefaultConfig()
 	// Apply environment variable overrides
 	applyEnvOverrides(config)
-	return config, nil
+	return config, "", nil
+}
+
+// LoadConfigFromFile loads configuration from a specific file and applies environment overrides
+func LoadConfigFromFile(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+	}
+
+	// Apply environment variable overrides
+	applyEnvOverrides(&config)
+	return &config, nil
+}
+
+// Validate checks the configuration for values no component can run with
+func (c *Config) Validate() error {
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("server.port must be between 0 and 65535, got %d", c.Server.Port)
+	}
+	if c.Server.GRPCPort < 0 || c.Server.GRPCPort > 65535 {
+		return fmt.Errorf("server.grpc_port must be between 0 and 65535, got %d", c.Server.GRPCPort)
+	}
+	switch c.Queue.Type {
+	case "", "in-memory", "nats":
+	default:
+		return fmt.Errorf("queue.type must be \"in-memory\" or \"nats\", got %q", c.Queue.Type)
+	}
+	switch c.State.Type {
+	case "", "in-memory":
+		if c.Queue.Type == "nats" {
+			return fmt.Errorf("state.type must be \"redis\" when queue.type is \"nats\": workers resuming sleeping runs need a shared state store")
+		}
+	case "redis":
+		if c.State.Redis.Addr == "" {
+			return fmt.Errorf("state.redis.addr is required for the redis state store")
+		}
+	default:
+		return fmt.Errorf("state.type must be \"in-memory\" or \"redis\", got %q", c.State.Type)
+	}
+	if c.Metrics.MaxSeriesPerMetric < 0 {
+		return fmt.Errorf("metrics.max_series_per_metric must not be negative, got %d", c.Metrics.MaxSeriesPerMetric)
+	}
+	if c.DependencyInjection.PoolSize < 0 {
+		return fmt.Errorf("dependency_injection.pool_size must not be negative, got %d", c.DependencyInjection.PoolSize)
+	}
+	switch c.DataProtection.KeyProvider {
+	case "", "local":
+		if c.DataProtection.Enabled && c.DataProtection.KeyFile == "" {
+			return fmt.Errorf("data_protection.key_file is required when data protection is enabled with the local key provider")
+		}
+	case "kms":
+		if c.DataProtection.KMS.Address == "" || c.DataProtection.KMS.KeyName == "" {
+			return fmt.Errorf("data_protection.kms.address and key_name are required for the kms key provider")
+		}
+	default:
+		return fmt.Errorf("data_protection.key_provider must be \"local\" or \"kms\", got %q", c.DataProtection.KeyProvider)
+	}
+	if c.Callbacks.MaxAttempts < 0 {
+		return fmt.Errorf("callbacks.max_attempts must not be negative, got %d", c.Callbacks.MaxAttempts)
+	}
+	if c.Callbacks.InitialBackoff < 0 || c.Callbacks.MaxBackoff < 0 {
+		return fmt.Errorf("callbacks backoff must not be negative")
+	}
+	if c.Auth.Enabled && len(c.Auth.APIKeys) == 0 && c.Auth.JWT.HMACSecret == "" && c.Auth.JWT.JWKSURL == "" {
+		return fmt.Errorf("auth is enabled but no api_keys, jwt.hmac_secret or jwt.jwks_url is configured")
+	}
+	for i, key := range c.Auth.APIKeys {
+		if key.ID == "" {
+			return fmt.Errorf("auth.api_keys[%d].id is required", i)
+		}
+		if (key.Key == "") == (key.KeySHA256 == "") {
+			return fmt.Errorf("auth.api_keys[%d] must set exactly one of key and key_sha256", i)
+		}
+	}
+	if c.Tenancy.SlotTTL < 0 {
+		return fmt.Errorf("tenancy.slot_ttl must not be negative")
+	}
+	if err := c.Tenancy.DefaultQuota.validate("tenancy.default_quota"); err != nil {
+		return err
+	}
+	for id, quota := range c.Tenancy.Quotas {
+		if err := quota.validate("tenancy.quotas." + id); err != nil {
+			return err
+		}
+	}
+	if c.Admission.MaxQueueDepth < 0 {
+		return fmt.Errorf("admission.max_queue_depth must not be negative, got %d", c.Admission.MaxQueueDepth)
+	}
+	if c.Admission.QueueDepthInterval < 0 || c.Admission.RetryAfter < 0 || c.Admission.InFlightTTL < 0 {
+		return fmt.Errorf("admission durations must not be negative")
+	}
+	if err := c.Admission.ClientRate.validate("admission.client_rate"); err != nil {
+		return err
+	}
+	for id, rate := range c.Admission.ClientRates {
+		if err := rate.validate("admission.client_rates." + id); err != nil {
+			return err
+		}
+	}
+	if err := c.Admission.WorkflowRate.validate("admission.workflow_rate"); err != nil {
+		return err
+	}
+	for id, rate := range c.Admission.WorkflowRates {
+		if err := rate.validate("admission.workflow_rates." + id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks that the limits of a quota are not negative
+func (q TenantQuotaConfig) validate(path string) error {
+	if q.MaxConcurrentRuns < 0 || q.RequestsPerSecond < 0 || q.Burst < 0 {
+		return fmt.Errorf("%s limits must not be negative", path)
+	}
+	return nil
+}
+
+// validate checks that the limits of a rate are not negative
+func (r RateLimitConfig) validate(path string) error {
+	if r.RequestsPerSecond < 0 || r.Burst < 0 {
+		return fmt.Errorf("%s limits must not be negative", path)
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration with secrets masked
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	if redacted.Clients.Antifraud.APIKey != "" {
+		redacted.Clients.Antifraud.APIKey = redactedValue
+	}
+	if redacted.DataProtection.KMS.Token != "" {
+		redacted.DataProtection.KMS.Token = redactedValue
+	}
+	if redacted.Callbacks.DefaultSecret != "" {
+		redacted.Callbacks.DefaultSecret = redactedValue
+	}
+	if len(c.Callbacks.Secrets) > 0 {
+		redacted.Callbacks.Secrets = make(map[string]string, len(c.Callbacks.Secrets))
+		for clientID := range c.Callbacks.Secrets {
+			redacted.Callbacks.Secrets[clientID] = redactedValue
+		}
+	}
+	if redacted.Services.Registry.AuthToken != "" {
+		redacted.Services.Registry.AuthToken = redactedValue
+	}
+	if redacted.Auth.JWT.HMACSecret != "" {
+		redacted.Auth.JWT.HMACSecret = redactedValue
+	}
+	if len(c.Auth.APIKeys) > 0 {
+		redacted.Auth.APIKeys = make([]APIKeyConfig, len(c.Auth.APIKeys))
+		for i, key := range c.Auth.APIKeys {
+			if key.Key != "" {
+				key.Key = redactedValue
+			}
+			redacted.Auth.APIKeys[i] = key
+		}
+	}
+	return &redacted
+}
+
+// redactedValue replaces secrets in redacted configuration
+const redactedValue = "***"
+
+// ChangedSections returns the top-level sections (by YAML key) that differ between two configurations
+func ChangedSections(oldConfig, newConfig *Config) []string {
+	if oldConfig == nil || newConfig == nil {
+		return Sections()
+	}
+
+	oldValue := reflect.ValueOf(*oldConfig)
+	newValue := reflect.ValueOf(*newConfig)
+	configType := oldValue.Type()
+
+	changed := make([]string, 0)
+	for i := 0; i < configType.NumField(); i++ {
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			changed = append(changed, sectionName(configType.Field(i)))
+		}
+	}
+	return changed
+}
+
+// Sections returns the YAML keys of all top-level configuration sections
+func Sections() []string {
+	configType := reflect.TypeOf(Config{})
+	sections := make([]string, 0, configType.NumField())
+	for i := 0; i < configType.NumField(); i++ {
+		sections = append(sections, sectionName(configType.Field(i)))
+	}
+	return sections
+}
+
+// sectionName returns the YAML key of a Config field
+func sectionName(field reflect.StructField) string {
+	if tag := strings.Split(field.Tag.Get("yaml"), ",")[0]; tag != "" {
+		return tag
+	}
+	return strings.ToLower(field.Name)
+}
+
+// envOverrideVars lists the environment variables applied by applyEnvOverrides
+var envOverrideVars = []string{
+	"SERVER_PORT", "GRPC_PORT", "QUEUE_TYPE", "NATS_URL", "NATS_CONNECT_TIMEOUT", "NATS_RECONNECT_WAIT",
+	"NATS_MAX_RECONNECTS", "REGISTRY_SERVICE_URL", "ANTIFRAUD_API_KEY", "ANTIFRAUD_HOST",
+	"ANTIFRAUD_ENABLED", "SDK_WORKFLOW_API_ENDPOINT", "DI_POOL_SIZE", "DI_ENABLE_METRICS",
+	"METRICS_ENABLED", "METRICS_MAX_SERIES_PER_METRIC", "PRIMITIVES_ECHO_ENABLED",
+	"RESILIENCE_ENABLED", "LOG_LEVEL", "DATA_PROTECTION_KEY_FILE", "KMS_TOKEN",
+	"CALLBACK_STORE_DIR", "CALLBACK_DEFAULT_SECRET", "REGISTRY_AUTH_TOKEN", "AUTH_ENABLED",
+	"AUTH_JWT_HMAC_SECRET", "AUTH_JWKS_URL",
+}
+
+// ActiveEnvOverrides returns the override environment variables that are currently set
+func ActiveEnvOverrides() []string {
+	active := make([]string, 0)
+	for _, name := range envOverrideVars {
+		if os.Getenv(name) != "" {
+			active = append(active, name)
+		}
+	}
+	return active
 }
 
 // getConfigPath returns the configuration file path
@@ -233,6 +774,11 @@ func applyEnvOverrides(config *Config) {
 			config.Server.Port = port
 		}
 	}
+	if val := os.Getenv("GRPC_PORT"); val != "" {
+		if port, err := strconv.Atoi(val); err == nil {
+			config.Server.GRPCPort = port
+		}
+	}
 
 	// Queue configuration
 	if val := os.Getenv("QUEUE_TYPE"); val != "" {
@@ -259,10 +805,29 @@ func applyEnvOverrides(config *Config) {
 		}
 	}
 
+	// State store configuration
+	if val := os.Getenv("STATE_TYPE"); val != "" {
+		config.State.Type = val
+	}
+	if val := os.Getenv("REDIS_ADDR"); val != "" {
+		config.State.Redis.Addr = val
+	}
+	if val := os.Getenv("REDIS_PASSWORD"); val != "" {
+		config.State.Redis.Password = val
+	}
+	if val := os.Getenv("REDIS_DB"); val != "" {
+		if db, err := strconv.Atoi(val); err == nil {
+			config.State.Redis.DB = db
+		}
+	}
+
 	// Registry service URL
 	if val := os.Getenv("REGISTRY_SERVICE_URL"); val != "" {
 		config.Services.Registry.URL = val
 	}
+	if val := os.Getenv("REGISTRY_AUTH_TOKEN"); val != "" {
+		config.Services.Registry.AuthToken = val
+	}
 
 	// Antifraud client configuration
 	if val := os.Getenv("ANTIFRAUD_API_KEY"); val != "" {
@@ -290,8 +855,58 @@ func applyEnvOverrides(config *Config) {
 		config.DependencyInjection.EnableMetrics = strings.ToLower(val) == "true"
 	}
 
+	// Logging configuration
+	if val := os.Getenv("LOG_LEVEL"); val != "" {
+		config.Logging.Level = val
+	}
+
+	// Metrics configuration
+	if val := os.Getenv("METRICS_ENABLED"); val != "" {
+		config.Metrics.Enabled = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("METRICS_MAX_SERIES_PER_METRIC"); val != "" {
+		if maxSeries, err := strconv.Atoi(val); err == nil {
+			config.Metrics.MaxSeriesPerMetric = maxSeries
+		}
+	}
+
 	// Primitives configuration
 	if val := os.Getenv("PRIMITIVES_ECHO_ENABLED"); val != "" {
 		config.Primitives.EchoEnabled = strings.ToLower(val) == "true"
 	}
+
+	// Resilience configuration
+	if val := os.Getenv("RESILIENCE_ENABLED"); val != "" {
+		enabled := strings.ToLower(val) == "true"
+		config.Resilience.Antifraud.Enabled = enabled
+		config.Resilience.Storage.Enabled = enabled
+		config.Resilience.HTTP.Enabled = enabled
+	}
+
+	// Data protection configuration
+	if val := os.Getenv("DATA_PROTECTION_KEY_FILE"); val != "" {
+		config.DataProtection.KeyFile = val
+	}
+	if val := os.Getenv("KMS_TOKEN"); val != "" {
+		config.DataProtection.KMS.Token = val
+	}
+
+	// Callback configuration
+	if val := os.Getenv("CALLBACK_STORE_DIR"); val != "" {
+		config.Callbacks.StoreDir = val
+	}
+	if val := os.Getenv("CALLBACK_DEFAULT_SECRET"); val != "" {
+		config.Callbacks.DefaultSecret = val
+	}
+
+	// Authentication configuration
+	if val := os.Getenv("AUTH_ENABLED"); val != "" {
+		config.Auth.Enabled = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("AUTH_JWT_HMAC_SECRET"); val != "" {
+		config.Auth.JWT.HMACSecret = val
+	}
+	if val := os.Getenv("AUTH_JWKS_URL"); val != "" {
+		config.Auth.JWT.JWKSURL = val
+	}
 }
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRequiresSharedStateWithNATS(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Queue.Type = "nats"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "state.type") {
+		t.Errorf("Validate() error = %v, want the in-memory state store refused with NATS", err)
+	}
+
+	cfg.State.Type = "redis"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want NATS with Redis accepted", err)
+	}
+
+	cfg.State.Redis.Addr = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should fail without a Redis address")
+	}
+}
+
+func TestMetricsEndpointDefaultsToMetrics(t *testing.T) {
+	for path, want := range map[string]string{"": "/metrics", "/internal/metrics": "/internal/metrics"} {
+		if got := (MetricsConfig{Path: path}).Endpoint(); got != want {
+			t.Errorf("Endpoint() with path %q = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// SectionOwner is implemented by components that own configuration sections and apply them live
+type SectionOwner interface {
+	// Name identifies the component in reload results
+	Name() string
+
+	// Sections returns the top-level config sections (YAML keys) the component owns
+	Sections() []string
+
+	// ValidateConfig checks a candidate configuration before it is swapped in
+	ValidateConfig(config *Config) error
+
+	// ApplyConfig applies a new configuration; oldConfig is nil on the initial apply
+	ApplyConfig(oldConfig, newConfig *Config) error
+}
+
+// ConfigSource describes where the effective configuration came from
+type ConfigSource struct {
+	// Path of the config file, empty when the built-in defaults are used
+	Path string `json:"path"`
+
+	// EnvOverrides lists the environment variables applied on top of the file
+	EnvOverrides []string `json:"env_overrides"`
+
+	// LoadedAt is when the effective configuration was swapped in
+	LoadedAt time.Time `json:"loaded_at"`
+
+	// Version increments on every successful reload
+	Version int `json:"version"`
+}
+
+// ReloadResult records the outcome of a configuration reload
+type ReloadResult struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Path            string    `json:"path"`
+	ChangedSections []string  `json:"changed_sections"`
+	Applied         bool      `json:"applied"`
+	RolledBack      bool      `json:"rolled_back"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// ReloadManager validates configuration changes and applies them to the section owners
+// A change is swapped in only if every affected owner accepts it; if applying fails
+// part-way, owners that already applied it are rolled back to the previous configuration
+type ReloadManager struct {
+	mu         sync.RWMutex
+	current    *Config
+	source     ConfigSource
+	owners     []SectionOwner
+	lastReload *ReloadResult
+	watcher    *ConfigWatcher
+}
+
+// NewReloadManager creates a reload manager for the configuration loaded from path
+func NewReloadManager(config *Config, path string) *ReloadManager {
+	return &ReloadManager{
+		current: config,
+		source: ConfigSource{
+			Path:         path,
+			EnvOverrides: ActiveEnvOverrides(),
+			LoadedAt:     time.Now(),
+			Version:      1,
+		},
+	}
+}
+
+// Register validates and applies the current configuration to an owner and subscribes it to changes
+func (m *ReloadManager) Register(owner SectionOwner) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := owner.ValidateConfig(m.current); err != nil {
+		return fmt.Errorf("%s rejected configuration: %w", owner.Name(), err)
+	}
+	if err := owner.ApplyConfig(nil, m.current); err != nil {
+		return fmt.Errorf("%s failed to apply configuration: %w", owner.Name(), err)
+	}
+
+	m.owners = append(m.owners, owner)
+	return nil
+}
+
+// Apply validates a new configuration and applies it to the owners of the changed sections
+func (m *ReloadManager) Apply(newConfig *Config, path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldConfig := m.current
+	result := &ReloadResult{
+		Timestamp:       time.Now(),
+		Path:            path,
+		ChangedSections: ChangedSections(oldConfig, newConfig),
+	}
+	if len(result.ChangedSections) == 0 {
+		return nil
+	}
+	m.lastReload = result
+
+	fail := func(err error) error {
+		result.Error = err.Error()
+		log.Printf("Configuration reload from %s rejected: %v", path, err)
+		return err
+	}
+
+	if err := newConfig.Validate(); err != nil {
+		return fail(fmt.Errorf("invalid config: %w", err))
+	}
+
+	affected := m.affectedOwners(result.ChangedSections)
+	for _, owner := range affected {
+		if err := owner.ValidateConfig(newConfig); err != nil {
+			return fail(fmt.Errorf("%s rejected configuration: %w", owner.Name(), err))
+		}
+	}
+
+	for i, owner := range affected {
+		if err := owner.ApplyConfig(oldConfig, newConfig); err != nil {
+			rollbackErr := m.rollback(affected[:i], newConfig, oldConfig)
+			result.RolledBack = true
+			return fail(errors.Join(fmt.Errorf("%s failed to apply configuration: %w", owner.Name(), err), rollbackErr))
+		}
+	}
+
+	m.current = newConfig
+	m.source.Path = path
+	m.source.EnvOverrides = ActiveEnvOverrides()
+	m.source.LoadedAt = result.Timestamp
+	m.source.Version++
+	result.Applied = true
+
+	log.Printf("Configuration reloaded from %s (sections: %v)", path, result.ChangedSections)
+	return nil
+}
+
+// rollback re-applies the previous configuration to owners, most recent first
+func (m *ReloadManager) rollback(applied []SectionOwner, failedConfig, previousConfig *Config) error {
+	var errs []error
+	for i := len(applied) - 1; i >= 0; i-- {
+		if err := applied[i].ApplyConfig(failedConfig, previousConfig); err != nil {
+			errs = append(errs, fmt.Errorf("rollback of %s failed: %w", applied[i].Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// affectedOwners returns the owners of any of the changed sections, in registration order
+func (m *ReloadManager) affectedOwners(changedSections []string) []SectionOwner {
+	changed := make(map[string]bool, len(changedSections))
+	for _, section := range changedSections {
+		changed[section] = true
+	}
+
+	affected := make([]SectionOwner, 0)
+	for _, owner := range m.owners {
+		for _, section := range owner.Sections() {
+			if changed[section] {
+				affected = append(affected, owner)
+				break
+			}
+		}
+	}
+	return affected
+}
+
+// Watch applies changes detected by a configuration watcher
+func (m *ReloadManager) Watch(watcher *ConfigWatcher) {
+	watcher.SubscribeFunc(func(change ConfigChange) {
+		if change.Error != nil {
+			m.mu.Lock()
+			m.lastReload = &ReloadResult{
+				Timestamp: change.Timestamp,
+				Path:      change.Path,
+				Error:     change.Error.Error(),
+			}
+			m.mu.Unlock()
+			return
+		}
+		m.Apply(change.NewConfig, change.Path)
+	})
+}
+
+// WatchFile starts a watcher on the config file and applies its changes
+func (m *ReloadManager) WatchFile(path string) (*ConfigWatcher, error) {
+	watcher, err := NewConfigWatcher(path)
+	if err != nil {
+		return nil, err
+	}
+	m.Watch(watcher)
+	if err := watcher.Start(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.watcher = watcher
+	m.mu.Unlock()
+	return watcher, nil
+}
+
+// Stop stops the file watcher started by WatchFile
+func (m *ReloadManager) Stop() {
+	m.mu.Lock()
+	watcher := m.watcher
+	m.watcher = nil
+	m.mu.Unlock()
+
+	if watcher != nil {
+		watcher.Stop()
+	}
+}
+
+// Current returns the effective configuration
+func (m *ReloadManager) Current() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Source returns where the effective configuration came from
+func (m *ReloadManager) Source() ConfigSource {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.source
+}
+
+// LastReload returns the outcome of the most recent reload attempt, or nil
+func (m *ReloadManager) LastReload() *ReloadResult {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.lastReload == nil {
+		return nil
+	}
+	result := *m.lastReload
+	return &result
+}
+
+// Owners returns the registered owners and the sections they own
+func (m *ReloadManager) Owners() map[string][]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	owners := make(map[string][]string, len(m.owners))
+	for _, owner := range m.owners {
+		owners[owner.Name()] = owner.Sections()
+	}
+	return owners
+}
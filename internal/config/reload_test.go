@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+// recordingOwner records applied configs and can be made to reject or fail
+type recordingOwner struct {
+	name        string
+	sections    []string
+	validateErr error
+	applyErr    error
+	applied     []*Config
+}
+
+func (o *recordingOwner) Name() string       { return o.name }
+func (o *recordingOwner) Sections() []string { return o.sections }
+
+func (o *recordingOwner) ValidateConfig(config *Config) error {
+	return o.validateErr
+}
+
+func (o *recordingOwner) ApplyConfig(oldConfig, newConfig *Config) error {
+	if o.applyErr != nil && oldConfig != nil && newConfig.Executor.MaxRetries == 7 {
+		return o.applyErr
+	}
+	o.applied = append(o.applied, newConfig)
+	return nil
+}
+
+func TestReloadManagerAppliesChangedSections(t *testing.T) {
+	initial := DefaultConfig()
+	manager := NewReloadManager(initial, "config.yaml")
+
+	executorOwner := &recordingOwner{name: "executor", sections: []string{"executor"}}
+	loggingOwner := &recordingOwner{name: "logging", sections: []string{"logging"}}
+	for _, owner := range []*recordingOwner{executorOwner, loggingOwner} {
+		if err := manager.Register(owner); err != nil {
+			t.Fatalf("Register(%s) error = %v", owner.name, err)
+		}
+	}
+
+	updated := DefaultConfig()
+	updated.Executor.MaxRetries = 5
+	if err := manager.Apply(updated, "config.yaml"); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if manager.Current() != updated {
+		t.Error("Current() should return the applied config")
+	}
+	if len(executorOwner.applied) != 2 {
+		t.Errorf("executor owner applied %d times, want 2 (register + reload)", len(executorOwner.applied))
+	}
+	if len(loggingOwner.applied) != 1 {
+		t.Errorf("logging owner applied %d times, want 1 (register only)", len(loggingOwner.applied))
+	}
+	if version := manager.Source().Version; version != 2 {
+		t.Errorf("Source().Version = %d, want 2", version)
+	}
+}
+
+func TestReloadManagerRejectsInvalidConfig(t *testing.T) {
+	initial := DefaultConfig()
+	manager := NewReloadManager(initial, "config.yaml")
+
+	owner := &recordingOwner{name: "executor", sections: []string{"executor"}}
+	if err := manager.Register(owner); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	owner.validateErr = errors.New("max_retries too high")
+
+	updated := DefaultConfig()
+	updated.Executor.MaxRetries = 100
+	if err := manager.Apply(updated, "config.yaml"); err == nil {
+		t.Fatal("Apply() should fail when an owner rejects the config")
+	}
+
+	if manager.Current() != initial {
+		t.Error("rejected config must not be swapped in")
+	}
+	if len(owner.applied) != 1 {
+		t.Errorf("owner applied %d times, want 1 (register only)", len(owner.applied))
+	}
+	if last := manager.LastReload(); last == nil || last.Applied || last.Error == "" {
+		t.Errorf("LastReload() = %+v, want a failed reload", last)
+	}
+}
+
+func TestReloadManagerRollsBackOnApplyFailure(t *testing.T) {
+	initial := DefaultConfig()
+	manager := NewReloadManager(initial, "config.yaml")
+
+	first := &recordingOwner{name: "first", sections: []string{"executor"}}
+	second := &recordingOwner{name: "second", sections: []string{"executor"}, applyErr: errors.New("apply failed")}
+	for _, owner := range []*recordingOwner{first, second} {
+		if err := manager.Register(owner); err != nil {
+			t.Fatalf("Register(%s) error = %v", owner.name, err)
+		}
+	}
+
+	updated := DefaultConfig()
+	updated.Executor.MaxRetries = 7
+	if err := manager.Apply(updated, "config.yaml"); err == nil {
+		t.Fatal("Apply() should fail when an owner fails to apply")
+	}
+
+	if manager.Current() != initial {
+		t.Error("failed config must not be swapped in")
+	}
+	if got := first.applied[len(first.applied)-1]; got != initial {
+		t.Error("first owner should be rolled back to the previous config")
+	}
+	if last := manager.LastReload(); last == nil || !last.RolledBack {
+		t.Errorf("LastReload() = %+v, want RolledBack", last)
+	}
+}
@@ -164,6 +164,11 @@ func (cw *ConfigWatcher) checkForChanges() {
 func (cw *ConfigWatcher) reloadConfig(filePath string) {
 	oldConfig := cw.currentConfig
 	newConfig, err := cw.loadConfigFromFile(filePath)
+	if err == nil {
+		if validateErr := newConfig.Validate(); validateErr != nil {
+			newConfig, err = nil, fmt.Errorf("invalid config: %w", validateErr)
+		}
+	}
 
 	change := ConfigChange{
 		Path:      filePath,
@@ -207,8 +212,7 @@ func (cw *ConfigWatcher) loadConfigFromFile(filePath string) (*Config, error) {
 		return nil, fmt.Errorf("config file does not exist: %s", filePath)
 	}
 
-	// Use the existing LoadConfig function
-	config, err := LoadConfig()
+	config, err := LoadConfigFromFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
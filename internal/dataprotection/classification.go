@@ -0,0 +1,116 @@
+package dataprotection
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Class is the sensitivity classification of a data field
+type Class string
+
+const (
+	// ClassPAN is a primary account (card) number
+	ClassPAN Class = "pan"
+
+	// ClassCVV is a card verification value; it is never shown, even partially
+	ClassCVV Class = "cvv"
+
+	// ClassPII is personally identifiable information such as names and phone numbers
+	ClassPII Class = "pii"
+)
+
+// ParseClass parses a classification name
+func ParseClass(name string) (Class, error) {
+	switch class := Class(strings.ToLower(strings.TrimSpace(name))); class {
+	case ClassPAN, ClassCVV, ClassPII:
+		return class, nil
+	default:
+		return "", fmt.Errorf("unknown data class %q", name)
+	}
+}
+
+// defaultFields classifies the card and client fields used by the antifraud workflows
+var defaultFields = map[string]Class{
+	"client_pan":         ClassPAN,
+	"pan":                ClassPAN,
+	"card_number":        ClassPAN,
+	"client_cvv":         ClassCVV,
+	"cvv":                ClassCVV,
+	"cvc":                ClassCVV,
+	"client_card_holder": ClassPII,
+	"card_holder":        ClassPII,
+	"client_phone":       ClassPII,
+	"phone":              ClassPII,
+	"client_name":        ClassPII,
+}
+
+// Policy tags field names with their classification
+// Field names match regardless of case and separators, so ClientPAN and client_pan are the same field
+type Policy struct {
+	fields map[string]Class
+}
+
+// NewPolicy creates a policy from the built-in classification plus additional fields (name -> class)
+func NewPolicy(additional map[string]string) (*Policy, error) {
+	policy := &Policy{fields: make(map[string]Class, len(defaultFields)+len(additional))}
+	for name, class := range defaultFields {
+		policy.fields[normalizeKey(name)] = class
+	}
+	for name, className := range additional {
+		class, err := ParseClass(className)
+		if err != nil {
+			return nil, fmt.Errorf("data_protection.fields.%s: %w", name, err)
+		}
+		policy.fields[normalizeKey(name)] = class
+	}
+	return policy, nil
+}
+
+// DefaultPolicy returns the built-in classification policy
+func DefaultPolicy() *Policy {
+	policy, _ := NewPolicy(nil)
+	return policy
+}
+
+// Classify returns the classification of a field name
+func (p *Policy) Classify(key string) (Class, bool) {
+	if p == nil {
+		return "", false
+	}
+	class, ok := p.fields[normalizeKey(key)]
+	return class, ok
+}
+
+// Fields returns the classified field names (normalized) and their classes
+func (p *Policy) Fields() map[string]Class {
+	fields := make(map[string]Class, len(p.fields))
+	for name, class := range p.fields {
+		fields[name] = class
+	}
+	return fields
+}
+
+// normalizeKey lowercases a field name and strips separators
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(key))
+}
+
+// current is the process-wide policy used by logging and API masking
+var current atomic.Pointer[Policy]
+
+func init() {
+	current.Store(DefaultPolicy())
+}
+
+// CurrentPolicy returns the process-wide classification policy
+func CurrentPolicy() *Policy {
+	return current.Load()
+}
+
+// SetPolicy replaces the process-wide classification policy
+func SetPolicy(policy *Policy) {
+	if policy != nil {
+		current.Store(policy)
+	}
+}
@@ -0,0 +1,71 @@
+package dataprotection
+
+import (
+	"fmt"
+
+	"unified-workflow/internal/config"
+)
+
+// NewKeyProvider creates the key provider selected by the data protection configuration
+func NewKeyProvider(cfg config.DataProtectionConfig) (KeyProvider, error) {
+	switch cfg.KeyProvider {
+	case "", "local":
+		if cfg.KeyFile == "" {
+			return nil, fmt.Errorf("data_protection.key_file is required for the local key provider")
+		}
+		return LoadLocalKeyProvider(cfg.KeyFile)
+	case "kms":
+		client := NewVaultTransitClient(cfg.KMS.Address, cfg.KMS.Token, cfg.KMS.Timeout)
+		return NewKMSKeyProvider(client, cfg.KMS.KeyName), nil
+	default:
+		return nil, fmt.Errorf("unknown key provider %q", cfg.KeyProvider)
+	}
+}
+
+// Init applies the classification policy and returns an encryptor, or nil when data protection is disabled
+func Init(cfg config.DataProtectionConfig) (*Encryptor, error) {
+	policy, err := NewPolicy(cfg.Fields)
+	if err != nil {
+		return nil, err
+	}
+	SetPolicy(policy)
+
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	provider, err := NewKeyProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key provider: %w", err)
+	}
+	return NewEncryptor(provider, nil), nil
+}
+
+// ConfigOwner applies the field classification of the data_protection section at runtime
+// Key provider changes only take effect on restart
+type ConfigOwner struct{}
+
+// Name implements config.SectionOwner
+func (ConfigOwner) Name() string {
+	return "data-protection"
+}
+
+// Sections implements config.SectionOwner
+func (ConfigOwner) Sections() []string {
+	return []string{"data_protection"}
+}
+
+// ValidateConfig implements config.SectionOwner
+func (ConfigOwner) ValidateConfig(cfg *config.Config) error {
+	_, err := NewPolicy(cfg.DataProtection.Fields)
+	return err
+}
+
+// ApplyConfig implements config.SectionOwner
+func (ConfigOwner) ApplyConfig(oldConfig, newConfig *config.Config) error {
+	policy, err := NewPolicy(newConfig.DataProtection.Fields)
+	if err != nil {
+		return err
+	}
+	SetPolicy(policy)
+	return nil
+}
@@ -0,0 +1,147 @@
+package dataprotection_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/dataprotection"
+	"unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/state"
+)
+
+// newKeyFileProvider writes a hex master key to a temp file and loads it
+func newKeyFileProvider(t *testing.T) *dataprotection.LocalKeyProvider {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "master.key")
+	if err := os.WriteFile(path, []byte(hex.EncodeToString([]byte(strings.Repeat("k", 32)))+"\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	provider, err := dataprotection.LoadLocalKeyProvider(path)
+	if err != nil {
+		t.Fatalf("LoadLocalKeyProvider() error = %v", err)
+	}
+	return provider
+}
+
+func transactionInput() map[string]interface{} {
+	return map[string]interface{}{
+		"amount": "100000",
+		"transaction": map[string]interface{}{
+			"client_pan":         "4111111111111111",
+			"client_cvv":         "123",
+			"client_card_holder": "JOHN SMITH",
+			"currency":           "KZT",
+		},
+	}
+}
+
+func TestEncryptedStateStoresCiphertextAndReturnsPlaintext(t *testing.T) {
+	ctx := context.Background()
+	inner := state.NewInMemoryState()
+	encryptor := dataprotection.NewEncryptor(newKeyFileProvider(t), dataprotection.DefaultPolicy())
+	store := state.NewEncryptedState(inner, encryptor)
+
+	data := model.NewWorkflowData()
+	for key, value := range transactionInput() {
+		data.Put(key, value)
+	}
+	if err := store.SaveData(ctx, "run-1", data); err != nil {
+		t.Fatalf("SaveData() error = %v", err)
+	}
+
+	raw, err := inner.GetData(ctx, "run-1")
+	if err != nil {
+		t.Fatalf("inner GetData() error = %v", err)
+	}
+	encoded, _ := json.Marshal(raw.ToMap())
+	for _, secret := range []string{"4111111111111111", "JOHN SMITH"} {
+		if strings.Contains(string(encoded), secret) {
+			t.Errorf("stored data contains %q in clear text: %s", secret, encoded)
+		}
+	}
+	if !strings.Contains(string(encoded), "KZT") {
+		t.Errorf("unclassified fields should be stored as is: %s", encoded)
+	}
+
+	// A JSON round trip (as a remote store would do) must still decrypt
+	var roundTripped map[string]interface{}
+	if err := json.Unmarshal(encoded, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := encryptor.DecryptFields(ctx, roundTripped)
+	if err != nil {
+		t.Fatalf("DecryptFields() error = %v", err)
+	}
+	if pan := decrypted["transaction"].(map[string]interface{})["client_pan"]; pan != "4111111111111111" {
+		t.Errorf("decrypted client_pan = %v", pan)
+	}
+
+	loaded, err := store.GetData(ctx, "run-1")
+	if err != nil {
+		t.Fatalf("GetData() error = %v", err)
+	}
+	transaction, _ := loaded.GetMap("transaction")
+	if transaction["client_cvv"] != "123" {
+		t.Errorf("GetData() client_cvv = %v, want decrypted value", transaction["client_cvv"])
+	}
+}
+
+func TestDecryptFailsWithAnotherMasterKey(t *testing.T) {
+	ctx := context.Background()
+	encrypted, err := dataprotection.NewEncryptor(newKeyFileProvider(t), nil).EncryptFields(ctx, transactionInput())
+	if err != nil {
+		t.Fatalf("EncryptFields() error = %v", err)
+	}
+
+	other, err := dataprotection.GenerateLocalKeyProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dataprotection.NewEncryptor(other, nil).DecryptFields(ctx, encrypted); err == nil {
+		t.Error("DecryptFields() with a different master key should fail")
+	}
+}
+
+func TestMaskForCaller(t *testing.T) {
+	masked := dataprotection.MaskForCaller(context.Background(), transactionInput())
+	transaction := masked["transaction"].(map[string]interface{})
+
+	for key, want := range map[string]string{
+		"client_pan":         "************1111",
+		"client_cvv":         "***",
+		"client_card_holder": "J*********",
+		"currency":           "KZT",
+	} {
+		if got := transaction[key]; got != want {
+			t.Errorf("masked %s = %v, want %q", key, got, want)
+		}
+	}
+
+	ctx := dataprotection.WithPermissions(context.Background(), dataprotection.UnmaskPermission)
+	unmasked := dataprotection.MaskForCaller(ctx, transactionInput())
+	if pan := unmasked["transaction"].(map[string]interface{})["client_pan"]; pan != "4111111111111111" {
+		t.Errorf("caller with %s got client_pan = %v", dataprotection.UnmaskPermission, pan)
+	}
+}
+
+func TestInitRefusesToStartWithoutAMasterKey(t *testing.T) {
+	if _, err := dataprotection.Init(config.DataProtectionConfig{Enabled: true, KeyProvider: "local"}); err == nil {
+		t.Fatal("Init() without a key file should fail rather than generate an ephemeral key")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DataProtection.Enabled = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "data_protection.key_file") {
+		t.Fatalf("Validate() error = %v, want a missing key_file error", err)
+	}
+
+	encryptor, err := dataprotection.Init(config.DataProtectionConfig{})
+	if err != nil || encryptor != nil {
+		t.Fatalf("Init() disabled = %v, %v, want no encryptor", encryptor, err)
+	}
+}
@@ -0,0 +1,256 @@
+package dataprotection
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+)
+
+// encryptedMarker tags a serialized EncryptedField so it is recognized after a JSON round trip
+const encryptedMarker = "$enc"
+
+// EncryptedField replaces a classified value in stored workflow data
+// The value is encrypted with a per-record data key, which is itself wrapped by the key provider
+type EncryptedField struct {
+	Version    string `json:"$enc"`
+	Class      Class  `json:"class"`
+	KeyID      string `json:"key_id"`
+	WrappedKey []byte `json:"wrapped_key"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Encryptor encrypts classified fields using envelope encryption
+type Encryptor struct {
+	provider KeyProvider
+	policy   *Policy
+}
+
+// NewEncryptor creates an encryptor; a nil policy uses the process-wide policy at call time
+func NewEncryptor(provider KeyProvider, policy *Policy) *Encryptor {
+	return &Encryptor{provider: provider, policy: policy}
+}
+
+// Provider returns the key provider
+func (e *Encryptor) Provider() KeyProvider {
+	return e.provider
+}
+
+// EncryptFields returns a copy of data with every classified field encrypted under one fresh data key
+func (e *Encryptor) EncryptFields(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
+	policy := e.policy
+	if policy == nil {
+		policy = CurrentPolicy()
+	}
+	if data == nil || !containsClassified(policy, data) {
+		return data, nil
+	}
+
+	dataKey := make([]byte, masterKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrappedKey, keyID, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	sealer := &fieldSealer{policy: policy, dataKey: dataKey, wrappedKey: wrappedKey, keyID: keyID}
+	encrypted, err := sealer.sealMap(data)
+	if err != nil {
+		return nil, err
+	}
+	return encrypted, nil
+}
+
+// DecryptFields returns a copy of data with every encrypted field restored
+func (e *Encryptor) DecryptFields(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
+	opener := &fieldOpener{provider: e.provider, keys: make(map[string][]byte)}
+	return opener.openMap(ctx, data)
+}
+
+// containsClassified reports whether data has any classified field
+func containsClassified(policy *Policy, data map[string]interface{}) bool {
+	for key, value := range data {
+		if _, ok := policy.Classify(key); ok {
+			return true
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if containsClassified(policy, v) {
+				return true
+			}
+		case []interface{}:
+			for _, item := range v {
+				if nested, ok := item.(map[string]interface{}); ok && containsClassified(policy, nested) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// fieldSealer encrypts the classified fields of one record
+type fieldSealer struct {
+	policy     *Policy
+	dataKey    []byte
+	wrappedKey []byte
+	keyID      string
+}
+
+func (s *fieldSealer) sealMap(data map[string]interface{}) (map[string]interface{}, error) {
+	sealed := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		sealedValue, err := s.sealValue(key, value)
+		if err != nil {
+			return nil, err
+		}
+		sealed[key] = sealedValue
+	}
+	return sealed, nil
+}
+
+func (s *fieldSealer) sealValue(key string, value interface{}) (interface{}, error) {
+	if class, ok := s.policy.Classify(key); ok && value != nil {
+		if _, alreadyEncrypted := asEncryptedField(value); alreadyEncrypted {
+			return value, nil
+		}
+		return s.seal(key, class, value)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return s.sealMap(v)
+	case []interface{}:
+		sealed := make([]interface{}, len(v))
+		for i, item := range v {
+			sealedItem, err := s.sealValue("", item)
+			if err != nil {
+				return nil, err
+			}
+			sealed[i] = sealedItem
+		}
+		return sealed, nil
+	}
+	return value, nil
+}
+
+func (s *fieldSealer) seal(key string, class Class, value interface{}) (*EncryptedField, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode field %s: %w", key, err)
+	}
+	aead, err := newAEAD(s.dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return &EncryptedField{
+		Version:    "v1",
+		Class:      class,
+		KeyID:      s.keyID,
+		WrappedKey: s.wrappedKey,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(normalizeKey(key))),
+	}, nil
+}
+
+// fieldOpener decrypts encrypted fields, unwrapping each data key once
+type fieldOpener struct {
+	provider KeyProvider
+	keys     map[string][]byte
+}
+
+func (o *fieldOpener) openMap(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	opened := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		openedValue, err := o.openValue(ctx, key, value)
+		if err != nil {
+			return nil, err
+		}
+		opened[key] = openedValue
+	}
+	return opened, nil
+}
+
+func (o *fieldOpener) openValue(ctx context.Context, key string, value interface{}) (interface{}, error) {
+	if field, ok := asEncryptedField(value); ok {
+		return o.open(ctx, key, field)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return o.openMap(ctx, v)
+	case []interface{}:
+		opened := make([]interface{}, len(v))
+		for i, item := range v {
+			openedItem, err := o.openValue(ctx, "", item)
+			if err != nil {
+				return nil, err
+			}
+			opened[i] = openedItem
+		}
+		return opened, nil
+	}
+	return value, nil
+}
+
+func (o *fieldOpener) open(ctx context.Context, key string, field *EncryptedField) (interface{}, error) {
+	cacheKey := field.KeyID + ":" + string(field.WrappedKey)
+	dataKey, ok := o.keys[cacheKey]
+	if !ok {
+		var err error
+		dataKey, err = o.provider.UnwrapKey(ctx, field.WrappedKey, field.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt field %s: %w", key, err)
+		}
+		o.keys[cacheKey] = dataKey
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, field.Nonce, field.Ciphertext, []byte(normalizeKey(key)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt field %s: %w", key, err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode field %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// asEncryptedField recognizes an encrypted field, including one decoded from JSON into a map
+func asEncryptedField(value interface{}) (*EncryptedField, bool) {
+	switch v := value.(type) {
+	case *EncryptedField:
+		return v, v != nil
+	case EncryptedField:
+		return &v, true
+	case map[string]interface{}:
+		if _, ok := v[encryptedMarker]; !ok {
+			return nil, false
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		var field EncryptedField
+		if err := json.Unmarshal(encoded, &field); err != nil {
+			return nil, false
+		}
+		return &field, true
+	}
+	return nil, false
+}
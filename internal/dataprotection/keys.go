@@ -0,0 +1,239 @@
+package dataprotection
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// KeyProvider wraps and unwraps the per-record data keys under a master key
+type KeyProvider interface {
+	// Name identifies the provider type
+	Name() string
+
+	// WrapKey encrypts a data key and returns it with the ID of the master key used
+	WrapKey(ctx context.Context, dataKey []byte) (wrapped []byte, keyID string, err error)
+
+	// UnwrapKey decrypts a data key wrapped by WrapKey
+	UnwrapKey(ctx context.Context, wrapped []byte, keyID string) ([]byte, error)
+}
+
+// masterKeySize is the size of AES-256 master and data keys
+const masterKeySize = 32
+
+// LocalKeyProvider wraps data keys with a master key held in process, typically read from a key file
+type LocalKeyProvider struct {
+	aead  cipher.AEAD
+	keyID string
+}
+
+// NewLocalKeyProvider creates a provider from a 32-byte master key
+func NewLocalKeyProvider(masterKey []byte) (*LocalKeyProvider, error) {
+	if len(masterKey) != masterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", masterKeySize, len(masterKey))
+	}
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(masterKey)
+	return &LocalKeyProvider{aead: aead, keyID: "local:" + hex.EncodeToString(sum[:8])}, nil
+}
+
+// LoadLocalKeyProvider reads a master key file holding 32 raw bytes or their hex or base64 encoding
+func LoadLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	key := content
+	if len(content) != masterKeySize {
+		text := strings.TrimSpace(string(content))
+		if decoded, err := hex.DecodeString(text); err == nil && len(decoded) == masterKeySize {
+			key = decoded
+		} else if decoded, err := base64.StdEncoding.DecodeString(text); err == nil && len(decoded) == masterKeySize {
+			key = decoded
+		}
+	}
+
+	provider, err := NewLocalKeyProvider(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+	return provider, nil
+}
+
+// GenerateLocalKeyProvider creates a provider with a random master key; data does not survive a restart
+func GenerateLocalKeyProvider() (*LocalKeyProvider, error) {
+	key := make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate master key: %w", err)
+	}
+	return NewLocalKeyProvider(key)
+}
+
+// Name implements KeyProvider
+func (p *LocalKeyProvider) Name() string {
+	return "local"
+}
+
+// WrapKey implements KeyProvider
+func (p *LocalKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return p.aead.Seal(nonce, nonce, dataKey, []byte(p.keyID)), p.keyID, nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *LocalKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte, keyID string) ([]byte, error) {
+	if keyID != p.keyID {
+		return nil, fmt.Errorf("data key was wrapped with master key %s, have %s", keyID, p.keyID)
+	}
+	nonceSize := p.aead.NonceSize()
+	if len(wrapped) < nonceSize {
+		return nil, fmt.Errorf("wrapped data key is too short")
+	}
+	dataKey, err := p.aead.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// KMSClient encrypts and decrypts small payloads with a named key held by a key management service
+type KMSClient interface {
+	Encrypt(ctx context.Context, keyName string, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, keyName string, ciphertext []byte) ([]byte, error)
+}
+
+// KMSKeyProvider wraps data keys with a KMS; the master key never leaves the KMS
+type KMSKeyProvider struct {
+	client  KMSClient
+	keyName string
+}
+
+// NewKMSKeyProvider creates a provider that wraps data keys with the named KMS key
+func NewKMSKeyProvider(client KMSClient, keyName string) *KMSKeyProvider {
+	return &KMSKeyProvider{client: client, keyName: keyName}
+}
+
+// Name implements KeyProvider
+func (p *KMSKeyProvider) Name() string {
+	return "kms"
+}
+
+// WrapKey implements KeyProvider
+func (p *KMSKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	wrapped, err := p.client.Encrypt(ctx, p.keyName, dataKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("kms encrypt failed: %w", err)
+	}
+	return wrapped, "kms:" + p.keyName, nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *KMSKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte, keyID string) ([]byte, error) {
+	keyName := strings.TrimPrefix(keyID, "kms:")
+	dataKey, err := p.client.Decrypt(ctx, keyName, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt failed: %w", err)
+	}
+	return dataKey, nil
+}
+
+// VaultTransitClient is a KMSClient for the Vault transit secrets engine API
+type VaultTransitClient struct {
+	address    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewVaultTransitClient creates a transit client for the given Vault address
+func NewVaultTransitClient(address, token string, timeout time.Duration) *VaultTransitClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &VaultTransitClient{
+		address:    strings.TrimRight(address, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Encrypt implements KMSClient
+func (c *VaultTransitClient) Encrypt(ctx context.Context, keyName string, plaintext []byte) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}
+	if err := c.post(ctx, "/v1/transit/encrypt/"+keyName, request, &response); err != nil {
+		return nil, err
+	}
+	return []byte(response.Data.Ciphertext), nil
+}
+
+// Decrypt implements KMSClient
+func (c *VaultTransitClient) Decrypt(ctx context.Context, keyName string, ciphertext []byte) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"ciphertext": string(ciphertext)}
+	if err := c.post(ctx, "/v1/transit/decrypt/"+keyName, request, &response); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Plaintext)
+}
+
+// post sends a transit API request and decodes the response
+func (c *VaultTransitClient) post(ctx context.Context, path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.address+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("transit %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// newAEAD creates an AES-GCM cipher
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,98 @@
+package dataprotection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaskValue masks a value according to its classification
+// PANs keep the last four digits, CVVs are fully masked and PII keeps its first character
+func MaskValue(class Class, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if _, encrypted := asEncryptedField(value); encrypted {
+		return maskedPlaceholder
+	}
+
+	text := fmt.Sprint(value)
+	switch class {
+	case ClassPAN:
+		digits := []rune(text)
+		if len(digits) <= 4 {
+			return maskedPlaceholder
+		}
+		return strings.Repeat("*", len(digits)-4) + string(digits[len(digits)-4:])
+	case ClassPII:
+		runes := []rune(text)
+		if len(runes) <= 1 {
+			return maskedPlaceholder
+		}
+		return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+	default:
+		return maskedPlaceholder
+	}
+}
+
+// maskedPlaceholder replaces values that cannot be partially shown
+const maskedPlaceholder = "***"
+
+// Mask returns a copy of data with classified fields masked, descending into nested maps and slices
+func (p *Policy) Mask(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		masked[key] = p.MaskField(key, value)
+	}
+	return masked
+}
+
+// MaskField masks a single field value if its name is classified
+func (p *Policy) MaskField(key string, value interface{}) interface{} {
+	if class, ok := p.Classify(key); ok {
+		return MaskValue(class, value)
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return p.Mask(v)
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = p.MaskField("", item)
+		}
+		return masked
+	}
+	return value
+}
+
+// UnmaskPermission allows a caller to see classified fields in clear text
+const UnmaskPermission = "executions:read-sensitive"
+
+type permissionsKey struct{}
+
+// WithPermissions returns a context carrying the caller's permissions
+func WithPermissions(ctx context.Context, permissions ...string) context.Context {
+	return context.WithValue(ctx, permissionsKey{}, permissions)
+}
+
+// CanUnmask reports whether the caller in ctx holds the unmask permission
+func CanUnmask(ctx context.Context) bool {
+	permissions, _ := ctx.Value(permissionsKey{}).([]string)
+	for _, permission := range permissions {
+		if permission == UnmaskPermission {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskForCaller masks classified fields unless the caller holds the unmask permission
+func MaskForCaller(ctx context.Context, data map[string]interface{}) map[string]interface{} {
+	if CanUnmask(ctx) {
+		return data
+	}
+	return CurrentPolicy().Mask(data)
+}
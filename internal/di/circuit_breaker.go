@@ -1,11 +1,14 @@
 package di
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"unified-workflow/internal/metrics"
 )
 
 // CircuitBreakerState represents the state of a circuit breaker
@@ -34,6 +37,9 @@ func (s CircuitBreakerState) String() string {
 	}
 }
 
+// ErrCircuitOpen is returned when a request is rejected because the circuit is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
 // CircuitBreakerConfig holds configuration for a circuit breaker
 type CircuitBreakerConfig struct {
 	// Name of the circuit breaker
@@ -158,12 +164,10 @@ func (cb *CircuitBreaker) Execute(fn func() error) error {
 	// Check if circuit is open
 	if !cb.allowRequest() {
 		cb.rejectedRequests.Add(1)
-		cb.metrics.RejectedRequests++
-		return fmt.Errorf("circuit breaker '%s' is open", cb.config.Name)
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, cb.config.Name)
 	}
 
 	cb.totalRequests.Add(1)
-	cb.metrics.TotalRequests++
 
 	start := time.Now()
 	err := fn()
@@ -173,11 +177,9 @@ func (cb *CircuitBreaker) Execute(fn func() error) error {
 	if err != nil {
 		cb.recordFailure()
 		cb.failedRequests.Add(1)
-		cb.metrics.FailedRequests++
 	} else {
 		cb.recordSuccess()
 		cb.successRequests.Add(1)
-		cb.metrics.SuccessfulRequests++
 	}
 
 	// Update latency metrics
@@ -191,12 +193,10 @@ func (cb *CircuitBreaker) ExecuteWithTimeout(fn func() error, timeout time.Durat
 	// Check if circuit is open
 	if !cb.allowRequest() {
 		cb.rejectedRequests.Add(1)
-		cb.metrics.RejectedRequests++
-		return fmt.Errorf("circuit breaker '%s' is open", cb.config.Name)
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, cb.config.Name)
 	}
 
 	cb.totalRequests.Add(1)
-	cb.metrics.TotalRequests++
 
 	// Create channel for timeout
 	resultChan := make(chan error, 1)
@@ -216,11 +216,9 @@ func (cb *CircuitBreaker) ExecuteWithTimeout(fn func() error, timeout time.Durat
 		if err != nil {
 			cb.recordFailure()
 			cb.failedRequests.Add(1)
-			cb.metrics.FailedRequests++
 		} else {
 			cb.recordSuccess()
 			cb.successRequests.Add(1)
-			cb.metrics.SuccessfulRequests++
 		}
 
 		// Update latency metrics
@@ -230,7 +228,6 @@ func (cb *CircuitBreaker) ExecuteWithTimeout(fn func() error, timeout time.Durat
 
 	case <-time.After(timeout):
 		cb.timeoutRequests.Add(1)
-		cb.metrics.TimeoutRequests++
 		cb.recordFailure()
 		return fmt.Errorf("circuit breaker '%s': timeout after %v", cb.config.Name, timeout)
 	}
@@ -238,8 +235,8 @@ func (cb *CircuitBreaker) ExecuteWithTimeout(fn func() error, timeout time.Durat
 
 // allowRequest checks if a request should be allowed
 func (cb *CircuitBreaker) allowRequest() bool {
-	cb.mu.RLock()
-	defer cb.mu.RUnlock()
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
 
 	switch cb.state {
 	case CircuitBreakerClosed:
@@ -249,9 +246,7 @@ func (cb *CircuitBreaker) allowRequest() bool {
 		// Check if open timeout has passed
 		if time.Since(cb.stateChanged) > cb.config.OpenTimeout {
 			// Move to half-open state
-			cb.mu.RUnlock()
 			cb.transitionToHalfOpen()
-			cb.mu.RLock()
 			return true
 		}
 		return false
@@ -277,8 +272,8 @@ func (cb *CircuitBreaker) recordFailure() {
 	// Clean old failures
 	cb.cleanOldFailures(now)
 
-	// Check if we should open the circuit
-	if cb.shouldOpenCircuit() {
+	// Any failure while probing re-opens the circuit
+	if cb.state == CircuitBreakerHalfOpen || cb.shouldOpenCircuit() {
 		cb.transitionToOpen()
 	}
 }
@@ -376,9 +371,6 @@ func (cb *CircuitBreaker) transitionToOpen() {
 
 // transitionToHalfOpen transitions to half-open state
 func (cb *CircuitBreaker) transitionToHalfOpen() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
 	if cb.state == CircuitBreakerHalfOpen {
 		return
 	}
@@ -437,6 +429,14 @@ func (cb *CircuitBreaker) updateLatency(latencyNs int64) {
 	}
 }
 
+// UpdateConfig swaps the thresholds and timeouts; the current state and counters are kept
+func (cb *CircuitBreaker) UpdateConfig(config CircuitBreakerConfig) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	config.Name = cb.config.Name
+	cb.config = config
+}
+
 // GetState returns the current circuit breaker state
 func (cb *CircuitBreaker) GetState() CircuitBreakerState {
 	cb.mu.RLock()
@@ -444,25 +444,30 @@ func (cb *CircuitBreaker) GetState() CircuitBreakerState {
 	return cb.state
 }
 
-// GetMetrics returns circuit breaker metrics
+// GetMetrics returns a snapshot of circuit breaker metrics
 func (cb *CircuitBreaker) GetMetrics() CircuitBreakerMetrics {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
 
-	// Update atomic counters
-	cb.metrics.TotalRequests = cb.totalRequests.Load()
-	cb.metrics.SuccessfulRequests = cb.successRequests.Load()
-	cb.metrics.FailedRequests = cb.failedRequests.Load()
-	cb.metrics.RejectedRequests = cb.rejectedRequests.Load()
-	cb.metrics.TimeoutRequests = cb.timeoutRequests.Load()
+	snapshot := cb.metrics
+	snapshot.StateTransitions = make(map[CircuitBreakerState]int64, len(cb.metrics.StateTransitions))
+	for state, count := range cb.metrics.StateTransitions {
+		snapshot.StateTransitions[state] = count
+	}
+
+	// Read atomic counters
+	snapshot.TotalRequests = cb.totalRequests.Load()
+	snapshot.SuccessfulRequests = cb.successRequests.Load()
+	snapshot.FailedRequests = cb.failedRequests.Load()
+	snapshot.RejectedRequests = cb.rejectedRequests.Load()
+	snapshot.TimeoutRequests = cb.timeoutRequests.Load()
 
 	// Calculate failure rate
-	total := cb.metrics.TotalRequests
-	if total > 0 {
-		cb.metrics.FailureRate = float64(cb.metrics.FailedRequests) / float64(total)
+	if snapshot.TotalRequests > 0 {
+		snapshot.FailureRate = float64(snapshot.FailedRequests) / float64(snapshot.TotalRequests)
 	}
 
-	return cb.metrics
+	return snapshot
 }
 
 // Reset resets the circuit breaker
@@ -616,6 +621,48 @@ func (m *CircuitBreakerManager) GetMetrics() map[string]CircuitBreakerMetrics {
 	return metrics
 }
 
+// CircuitBreakerStatus is a health-endpoint view of a circuit breaker
+type CircuitBreakerStatus struct {
+	State            string    `json:"state"`
+	TotalRequests    int64     `json:"total_requests"`
+	FailedRequests   int64     `json:"failed_requests"`
+	RejectedRequests int64     `json:"rejected_requests"`
+	FailureRate      float64   `json:"failure_rate"`
+	LastStateChange  time.Time `json:"last_state_change"`
+}
+
+// GetStatus returns the state of all circuit breakers for health endpoints
+func (m *CircuitBreakerManager) GetStatus() map[string]CircuitBreakerStatus {
+	status := make(map[string]CircuitBreakerStatus)
+	for name, breakerMetrics := range m.GetMetrics() {
+		status[name] = CircuitBreakerStatus{
+			State:            breakerMetrics.CurrentState.String(),
+			TotalRequests:    breakerMetrics.TotalRequests,
+			FailedRequests:   breakerMetrics.FailedRequests,
+			RejectedRequests: breakerMetrics.RejectedRequests,
+			FailureRate:      breakerMetrics.FailureRate,
+			LastStateChange:  breakerMetrics.LastStateChange,
+		}
+	}
+	return status
+}
+
+// DefaultCircuitBreakerManager is the process-wide circuit breaker manager
+var DefaultCircuitBreakerManager = NewCircuitBreakerManager()
+
+// RegisterMetrics exports breaker state and request totals on every metrics scrape
+func (m *CircuitBreakerManager) RegisterMetrics(registry *metrics.Registry) {
+	registry.AddCollector(func() {
+		for name, breakerMetrics := range m.GetMetrics() {
+			metrics.CircuitBreakerState.Set(float64(breakerMetrics.CurrentState), name)
+			metrics.CircuitBreakerRequests.Set(float64(breakerMetrics.SuccessfulRequests), name, "success")
+			metrics.CircuitBreakerRequests.Set(float64(breakerMetrics.FailedRequests), name, "failure")
+			metrics.CircuitBreakerRequests.Set(float64(breakerMetrics.RejectedRequests), name, "rejected")
+			metrics.CircuitBreakerRequests.Set(float64(breakerMetrics.TimeoutRequests), name, "timeout")
+		}
+	})
+}
+
 // CircuitBreakerMiddleware provides circuit breaker middleware for HTTP handlers
 type CircuitBreakerMiddleware struct {
 	manager *CircuitBreakerManager
@@ -119,6 +119,10 @@ func NewWithConfig(config Config) Container {
 		config:       config,
 	}
 
+	if config.EnableMetrics {
+		container.metrics = NewMetricsCollector()
+	}
+
 	// Pre-allocate pools if configured
 	if config.PreAllocatePools {
 		container.initializePools()
@@ -216,7 +216,7 @@ func TestPooledLifecycle(t *testing.T) {
 	}
 
 	// Use the service
-	service := instance.(TestService)
+	service := instance.(interface{ Instance() interface{} }).Instance().(TestService)
 	result := service.DoWork()
 	if result == "" {
 		t.Error("Pooled service should work")
@@ -7,6 +7,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"unified-workflow/internal/metrics"
 )
 
 // highPerfContainer is a high-performance dependency injection container
@@ -20,6 +22,7 @@ type highPerfContainer struct {
 	scopes       map[string]*scopeImpl
 	scopeCounter *atomic.Int64
 	config       Config
+	metrics      MetricsCollector
 	started      bool
 	stopped      bool
 }
@@ -70,9 +73,18 @@ func (s *scopeImpl) Dispose() {
 	if s.disposed {
 		return
 	}
+	s.disposeInstances()
+
+	// Remove from container
+	s.container.mu.Lock()
+	delete(s.container.scopes, s.id)
+	s.container.mu.Unlock()
+}
+
+// disposeInstances cleans up scoped instances without touching the container lock
+func (s *scopeImpl) disposeInstances() {
 	s.disposed = true
 
-	// Clean up scoped instances
 	s.instances.Range(func(key, value interface{}) bool {
 		// Call cleanup if the value has a Dispose method
 		if disposer, ok := value.(interface{ Dispose() }); ok {
@@ -81,11 +93,6 @@ func (s *scopeImpl) Dispose() {
 		s.instances.Delete(key)
 		return true
 	})
-
-	// Remove from container
-	s.container.mu.Lock()
-	delete(s.container.scopes, s.id)
-	s.container.mu.Unlock()
 }
 
 // initializePools pre-allocates object pools
@@ -141,6 +148,10 @@ func (c *highPerfContainer) Register(key interface{}, provider Provider, lifecyc
 
 	c.registry[typeKey{keyType}] = reg
 
+	if c.metrics != nil {
+		c.metrics.RecordRegistration(keyType.String(), lifecycle)
+	}
+
 	// If it's a singleton and we have an instance, store it
 	if lifecycle == Singleton {
 		instance, err := provider.Create(c)
@@ -185,7 +196,38 @@ func (c *highPerfContainer) RegisterInstance(key, instance interface{}) error {
 
 // Resolve resolves a dependency
 func (c *highPerfContainer) Resolve(key interface{}) (interface{}, error) {
-	return c.resolve(key, nil)
+	if c.metrics == nil {
+		return c.resolve(key, nil)
+	}
+
+	start := time.Now()
+	instance, err := c.resolve(key, nil)
+	c.recordResolution(key, time.Since(start), err)
+	return instance, err
+}
+
+// recordResolution records resolution timing in the container collector and the metrics registry
+func (c *highPerfContainer) recordResolution(key interface{}, duration time.Duration, err error) {
+	component := "unknown"
+	if keyType, typeErr := getType(key); typeErr == nil {
+		component = keyType.String()
+	}
+
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	c.metrics.RecordResolution(component, duration, err == nil)
+	metrics.DIResolutionDuration.ObserveDuration(duration, component, result)
+}
+
+// GetMetrics returns a snapshot of the container metrics, or nil if metrics are disabled
+func (c *highPerfContainer) GetMetrics() *ContainerMetrics {
+	if c.metrics == nil {
+		return nil
+	}
+	return c.metrics.GetMetrics()
 }
 
 // MustResolve resolves a dependency or panics
@@ -247,7 +289,7 @@ func (c *highPerfContainer) resolve(key interface{}, scope *scopeImpl) (interfac
 			// Create a temporary scope for this resolution
 			tempScope := c.BeginScope()
 			defer tempScope.Dispose()
-			return c.ResolveScoped(key, tempScope)
+			return c.resolve(key, tempScope.(*scopeImpl))
 		}
 
 		// Check if already resolved in this scope
@@ -306,7 +348,14 @@ func (c *highPerfContainer) ResolveScoped(key interface{}, scope Scope) (interfa
 		return nil, errors.New("invalid scope type")
 	}
 
-	return c.resolve(key, scopeImpl)
+	if c.metrics == nil {
+		return c.resolve(key, scopeImpl)
+	}
+
+	start := time.Now()
+	instance, err := c.resolve(key, scopeImpl)
+	c.recordResolution(key, time.Since(start), err)
+	return instance, err
 }
 
 // BeginScope creates a new scope
@@ -352,9 +401,9 @@ func (c *highPerfContainer) Clear() {
 	c.registry = make(map[typeKey]*registration)
 	c.pools = make(map[reflect.Type]*sync.Pool)
 
-	// Dispose all scopes
+	// Dispose all scopes (the container lock is already held)
 	for _, scope := range c.scopes {
-		scope.Dispose()
+		scope.disposeInstances()
 	}
 	c.scopes = make(map[string]*scopeImpl)
 
@@ -400,14 +449,15 @@ func (c *highPerfContainer) Stop() error {
 
 	c.stopped = true
 
-	// Dispose all scopes
+	// Dispose all scopes (the container lock is already held)
 	for _, scope := range c.scopes {
-		scope.Dispose()
+		scope.disposeInstances()
 	}
 
 	// Clear all pools
 	for _, pool := range c.pools {
-		// Drain the pool
+		// Drain the pool; without New, Get returns nil once the pool is empty
+		pool.New = nil
 		for {
 			item := pool.Get()
 			if item == nil {
@@ -463,6 +513,11 @@ type pooledInstance struct {
 	pool     *sync.Pool
 }
 
+// Instance returns the pooled instance
+func (p *pooledInstance) Instance() interface{} {
+	return p.instance
+}
+
 // Release returns the instance to the pool
 func (p *pooledInstance) Release() {
 	if p.pool != nil && p.instance != nil {
@@ -29,6 +29,11 @@ type MetricsCollector interface {
 	Reset()
 }
 
+// MetricsProvider is implemented by containers that collect performance metrics
+type MetricsProvider interface {
+	GetMetrics() *ContainerMetrics
+}
+
 // ContainerMetrics represents DI container performance metrics
 type ContainerMetrics struct {
 	// Timestamp of metrics collection
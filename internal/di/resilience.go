@@ -0,0 +1,312 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/primitive/clients"
+)
+
+// ErrBulkheadFull is returned when a call is rejected because the concurrency limit is reached
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// ResiliencePolicy combines circuit breaking, timeout, retry and bulkhead limits for a service
+type ResiliencePolicy struct {
+	// Name identifies the service; it is also the circuit breaker name
+	Name string
+
+	// Timeout bounds a single attempt (0 = no timeout)
+	Timeout time.Duration
+
+	// MaxConcurrent limits in-flight calls (0 = unlimited)
+	MaxConcurrent int
+
+	// Retry controls retries with exponential backoff
+	Retry clients.RetryPolicy
+
+	// CircuitBreakerEnabled enables the circuit breaker
+	CircuitBreakerEnabled bool
+
+	// CircuitBreaker configures the circuit breaker
+	CircuitBreaker CircuitBreakerConfig
+}
+
+// ValidateResilienceConfig checks a service resilience configuration
+func ValidateResilienceConfig(name string, cfg config.ServiceResilienceConfig) error {
+	switch {
+	case cfg.Timeout < 0:
+		return fmt.Errorf("resilience.%s.timeout must not be negative", name)
+	case cfg.MaxConcurrent < 0:
+		return fmt.Errorf("resilience.%s.max_concurrent must not be negative", name)
+	case cfg.Retry.MaxAttempts < 0:
+		return fmt.Errorf("resilience.%s.retry.max_attempts must not be negative", name)
+	case cfg.Retry.BaseDelay < 0 || cfg.Retry.MaxDelay < 0:
+		return fmt.Errorf("resilience.%s.retry delays must not be negative", name)
+	case cfg.CircuitBreaker.FailureThreshold < 0 || cfg.CircuitBreaker.SuccessThreshold < 0 || cfg.CircuitBreaker.MinimumRequests < 0:
+		return fmt.Errorf("resilience.%s.circuit_breaker thresholds must not be negative", name)
+	case cfg.CircuitBreaker.OpenTimeout < 0 || cfg.CircuitBreaker.FailureWindow < 0:
+		return fmt.Errorf("resilience.%s.circuit_breaker durations must not be negative", name)
+	}
+	return nil
+}
+
+// DefaultResiliencePolicy returns the default resilience policy for a service
+func DefaultResiliencePolicy(name string) ResiliencePolicy {
+	return ResiliencePolicy{
+		Name:          name,
+		Timeout:       30 * time.Second,
+		MaxConcurrent: 100,
+		Retry: clients.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   100 * time.Millisecond,
+			MaxDelay:    2 * time.Second,
+			Jitter:      true,
+		},
+		CircuitBreakerEnabled: true,
+		CircuitBreaker:        DefaultCircuitBreakerConfig(name),
+	}
+}
+
+// ResiliencePolicyFromConfig builds a resilience policy from service configuration
+// A disabled service gets a pass-through policy; zero-valued circuit breaker settings
+// fall back to DefaultCircuitBreakerConfig
+func ResiliencePolicyFromConfig(name string, cfg config.ServiceResilienceConfig) ResiliencePolicy {
+	if !cfg.Enabled {
+		return ResiliencePolicy{Name: name}
+	}
+
+	breakerConfig := DefaultCircuitBreakerConfig(name)
+	if cfg.CircuitBreaker.FailureThreshold > 0 {
+		breakerConfig.FailureThreshold = cfg.CircuitBreaker.FailureThreshold
+	}
+	if cfg.CircuitBreaker.SuccessThreshold > 0 {
+		breakerConfig.SuccessThreshold = cfg.CircuitBreaker.SuccessThreshold
+	}
+	if cfg.CircuitBreaker.OpenTimeout > 0 {
+		breakerConfig.OpenTimeout = cfg.CircuitBreaker.OpenTimeout
+	}
+	if cfg.CircuitBreaker.FailureWindow > 0 {
+		breakerConfig.FailureWindow = cfg.CircuitBreaker.FailureWindow
+	}
+	if cfg.CircuitBreaker.MinimumRequests > 0 {
+		breakerConfig.MinimumRequests = cfg.CircuitBreaker.MinimumRequests
+	}
+
+	return ResiliencePolicy{
+		Name:          name,
+		Timeout:       cfg.Timeout,
+		MaxConcurrent: cfg.MaxConcurrent,
+		Retry: clients.RetryPolicy{
+			MaxAttempts: cfg.Retry.MaxAttempts,
+			BaseDelay:   cfg.Retry.BaseDelay,
+			MaxDelay:    cfg.Retry.MaxDelay,
+			Jitter:      cfg.Retry.Jitter,
+		},
+		CircuitBreakerEnabled: cfg.CircuitBreaker.Enabled,
+		CircuitBreaker:        breakerConfig,
+	}
+}
+
+// Resilience executes calls under a ResiliencePolicy
+type Resilience struct {
+	mu       sync.RWMutex
+	policy   ResiliencePolicy
+	breaker  *CircuitBreaker
+	bulkhead chan struct{}
+	manager  *CircuitBreakerManager
+}
+
+// NewResilience creates a resilience executor; the breaker is registered with the given manager
+func NewResilience(policy ResiliencePolicy, manager *CircuitBreakerManager) *Resilience {
+	r := &Resilience{manager: manager}
+	r.UpdatePolicy(policy)
+	return r
+}
+
+// UpdatePolicy swaps the policy; in-flight calls finish under the policy they started with
+func (r *Resilience) UpdatePolicy(policy ResiliencePolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.breaker = nil
+	if policy.CircuitBreakerEnabled {
+		breakerConfig := policy.CircuitBreaker
+		breakerConfig.Name = policy.Name
+		if r.manager != nil {
+			r.breaker = r.manager.GetOrCreateWithConfig(breakerConfig)
+		} else {
+			r.breaker = NewCircuitBreaker(breakerConfig)
+		}
+		r.breaker.UpdateConfig(breakerConfig)
+	}
+
+	if policy.MaxConcurrent != r.policy.MaxConcurrent || r.bulkhead == nil {
+		r.bulkhead = nil
+		if policy.MaxConcurrent > 0 {
+			r.bulkhead = make(chan struct{}, policy.MaxConcurrent)
+		}
+	}
+
+	r.policy = policy
+}
+
+// Policy returns the resilience policy
+func (r *Resilience) Policy() ResiliencePolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.policy
+}
+
+// CircuitBreaker returns the circuit breaker, or nil if it is disabled
+func (r *Resilience) CircuitBreaker() *CircuitBreaker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.breaker
+}
+
+// Execute runs fn under the resilience policy
+func (r *Resilience) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	_, err := ExecuteWithResult(ctx, r, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// resilienceSnapshot is the policy state a single call runs under
+type resilienceSnapshot struct {
+	policy   ResiliencePolicy
+	breaker  *CircuitBreaker
+	bulkhead chan struct{}
+}
+
+// snapshot returns the current policy state
+func (r *Resilience) snapshot() resilienceSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return resilienceSnapshot{policy: r.policy, breaker: r.breaker, bulkhead: r.bulkhead}
+}
+
+// ExecuteWithResult runs fn under the resilience policy and returns its result
+// Calls are bounded by the bulkhead, then retried with backoff while the breaker allows them
+func ExecuteWithResult[T any](ctx context.Context, r *Resilience, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	snap := r.snapshot()
+
+	if snap.bulkhead != nil {
+		select {
+		case snap.bulkhead <- struct{}{}:
+			defer func() { <-snap.bulkhead }()
+		default:
+			return zero, fmt.Errorf("%w: %s", ErrBulkheadFull, snap.policy.Name)
+		}
+	}
+
+	attempts := snap.policy.Retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result, err := executeAttempt(ctx, snap, fn)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if attempt == attempts || !retryable(ctx, err) {
+			break
+		}
+
+		select {
+		case <-time.After(snap.backoff(attempt)):
+		case <-ctx.Done():
+			return zero, fmt.Errorf("%s: retry aborted: %w", snap.policy.Name, ctx.Err())
+		}
+	}
+
+	return zero, lastErr
+}
+
+// executeAttempt runs a single attempt through the circuit breaker
+func executeAttempt[T any](ctx context.Context, snap resilienceSnapshot, fn func(ctx context.Context) (T, error)) (T, error) {
+	if snap.breaker == nil {
+		return callWithTimeout(ctx, snap.policy, fn)
+	}
+
+	var result T
+	err := snap.breaker.Execute(func() error {
+		value, err := callWithTimeout(ctx, snap.policy, fn)
+		if err != nil {
+			return err
+		}
+		result = value
+		return nil
+	})
+	return result, err
+}
+
+// callWithTimeout runs fn with the per-attempt timeout
+// Services without context support keep running after the timeout; their result is discarded
+func callWithTimeout[T any](ctx context.Context, policy ResiliencePolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	if policy.Timeout <= 0 && ctx.Done() == nil {
+		return fn(ctx)
+	}
+
+	if policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := fn(ctx)
+		done <- outcome{value: value, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.value, out.err
+	case <-ctx.Done():
+		var zero T
+		return zero, fmt.Errorf("%s: call timed out: %w", policy.Name, ctx.Err())
+	}
+}
+
+// retryable reports whether a failed attempt should be retried
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrBulkheadFull)
+}
+
+// backoff returns the delay before the next attempt
+func (snap resilienceSnapshot) backoff(attempt int) time.Duration {
+	retry := snap.policy.Retry
+	delay := retry.BaseDelay
+	if delay <= 0 {
+		return 0
+	}
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if retry.MaxDelay > 0 && delay >= retry.MaxDelay {
+			delay = retry.MaxDelay
+			break
+		}
+	}
+	if retry.Jitter {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	return delay
+}
@@ -0,0 +1,123 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/di"
+	"unified-workflow/internal/primitive/clients"
+)
+
+// flakyStorage fails a configurable number of calls before succeeding
+type flakyStorage struct {
+	failures atomic.Int32
+	calls    atomic.Int32
+	delay    time.Duration
+}
+
+func (s *flakyStorage) Save(data interface{}) (interface{}, error) {
+	s.calls.Add(1)
+	time.Sleep(s.delay)
+	if s.failures.Add(-1) >= 0 {
+		return nil, errors.New("storage unavailable")
+	}
+	return data, nil
+}
+
+func (s *flakyStorage) Get(id string) (interface{}, error)                      { return s.Save(id) }
+func (s *flakyStorage) Delete(id string) error                                  { _, err := s.Save(id); return err }
+func (s *flakyStorage) List() ([]interface{}, error)                            { return nil, nil }
+func (s *flakyStorage) Update(id string, data interface{}) (interface{}, error) { return s.Save(data) }
+
+func TestResilienceRetriesUntilSuccess(t *testing.T) {
+	storage := &flakyStorage{}
+	storage.failures.Store(2)
+
+	policy := di.ResiliencePolicy{
+		Name:  "test.retry",
+		Retry: clients.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+	service := di.NewResilientStorageService(storage, di.NewResilience(policy, di.NewCircuitBreakerManager()))
+
+	result, err := service.Save("payload")
+	if err != nil {
+		t.Fatalf("Save() error = %v, want success after retries", err)
+	}
+	if result != "payload" {
+		t.Errorf("Save() = %v, want payload", result)
+	}
+	if calls := storage.calls.Load(); calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestResilienceCircuitBreakerOpens(t *testing.T) {
+	storage := &flakyStorage{}
+	storage.failures.Store(100)
+
+	breakerConfig := di.DefaultCircuitBreakerConfig("test.breaker")
+	breakerConfig.FailureThreshold = 2
+	breakerConfig.MinimumRequests = 2
+	policy := di.ResiliencePolicy{
+		Name:                  "test.breaker",
+		Retry:                 clients.RetryPolicy{MaxAttempts: 1},
+		CircuitBreakerEnabled: true,
+		CircuitBreaker:        breakerConfig,
+	}
+	manager := di.NewCircuitBreakerManager()
+	service := di.NewResilientStorageService(storage, di.NewResilience(policy, manager))
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.Save("payload"); err == nil {
+			t.Fatalf("Save() call %d succeeded, want failure", i+1)
+		}
+	}
+
+	_, err := service.Save("payload")
+	if !errors.Is(err, di.ErrCircuitOpen) {
+		t.Fatalf("Save() error = %v, want ErrCircuitOpen", err)
+	}
+	if calls := storage.calls.Load(); calls != 2 {
+		t.Errorf("calls = %d, want 2 (open circuit must not reach the service)", calls)
+	}
+	if status := manager.GetStatus()["test.breaker"]; status.State != "open" {
+		t.Errorf("breaker state = %s, want open", status.State)
+	}
+}
+
+func TestResilienceTimeoutAndBulkhead(t *testing.T) {
+	storage := &flakyStorage{delay: 50 * time.Millisecond}
+
+	policy := di.ResiliencePolicy{
+		Name:          "test.bulkhead",
+		Timeout:       10 * time.Millisecond,
+		MaxConcurrent: 1,
+		Retry:         clients.RetryPolicy{MaxAttempts: 1},
+	}
+	resilience := di.NewResilience(policy, nil)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = resilience.Execute(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	err := resilience.Execute(context.Background(), func(ctx context.Context) error { return nil })
+	if !errors.Is(err, di.ErrBulkheadFull) {
+		t.Errorf("Execute() error = %v, want ErrBulkheadFull", err)
+	}
+	close(release)
+
+	service := di.NewResilientStorageService(storage, di.NewResilience(policy, nil))
+	if _, err := service.Save("payload"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Save() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,247 @@
+package di
+
+import (
+	"context"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/primitive"
+)
+
+// Resilience policy names used for primitive services (also the circuit breaker names)
+const (
+	AntifraudResilienceName = "primitive.antifraud"
+	StorageResilienceName   = "primitive.storage"
+	HTTPResilienceName      = "primitive.http"
+)
+
+// WrapPrimitiveServices decorates the primitive services with their configured resilience policies
+// Disabled policies wrap with a pass-through so they can be enabled by a config reload
+func WrapPrimitiveServices(p *primitive.Primitive, cfg config.ResilienceConfig, manager *CircuitBreakerManager) *PrimitiveResilience {
+	pr := &PrimitiveResilience{policies: make(map[string]*Resilience)}
+	if p == nil {
+		return pr
+	}
+
+	if p.Antifraud != nil {
+		if _, wrapped := p.Antifraud.(*resilientAntifraudService); !wrapped {
+			resilience := NewResilience(ResiliencePolicyFromConfig(AntifraudResilienceName, cfg.Antifraud), manager)
+			p.Antifraud = NewResilientAntifraudService(p.Antifraud, resilience)
+			pr.policies["antifraud"] = resilience
+		}
+	}
+
+	if p.Storage != nil {
+		if _, wrapped := p.Storage.(*resilientStorageService); !wrapped {
+			resilience := NewResilience(ResiliencePolicyFromConfig(StorageResilienceName, cfg.Storage), manager)
+			p.Storage = NewResilientStorageService(p.Storage, resilience)
+			pr.policies["storage"] = resilience
+		}
+	}
+
+	if p.HTTP != nil {
+		if _, wrapped := p.HTTP.(*resilientHTTPService); !wrapped {
+			resilience := NewResilience(ResiliencePolicyFromConfig(HTTPResilienceName, cfg.HTTP), manager)
+			p.HTTP = NewResilientHTTPService(p.HTTP, resilience)
+			pr.policies["http"] = resilience
+		}
+	}
+
+	return pr
+}
+
+// PrimitiveResilience owns the resilience section of the configuration and applies it live
+type PrimitiveResilience struct {
+	// policies maps resilience section keys (antifraud, storage, http) to their executors
+	policies map[string]*Resilience
+}
+
+// Name implements config.SectionOwner
+func (pr *PrimitiveResilience) Name() string {
+	return "primitive-resilience"
+}
+
+// Sections implements config.SectionOwner
+func (pr *PrimitiveResilience) Sections() []string {
+	return []string{"resilience"}
+}
+
+// ValidateConfig implements config.SectionOwner
+func (pr *PrimitiveResilience) ValidateConfig(cfg *config.Config) error {
+	for name, serviceConfig := range resilienceSections(cfg.Resilience) {
+		if err := ValidateResilienceConfig(name, serviceConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyConfig implements config.SectionOwner
+func (pr *PrimitiveResilience) ApplyConfig(oldConfig, newConfig *config.Config) error {
+	sections := resilienceSections(newConfig.Resilience)
+	for section, resilience := range pr.policies {
+		resilience.UpdatePolicy(ResiliencePolicyFromConfig(resilience.Policy().Name, sections[section]))
+	}
+	return nil
+}
+
+// resilienceSections maps the service keys of the resilience section to their configuration
+func resilienceSections(cfg config.ResilienceConfig) map[string]config.ServiceResilienceConfig {
+	return map[string]config.ServiceResilienceConfig{
+		"antifraud": cfg.Antifraud,
+		"storage":   cfg.Storage,
+		"http":      cfg.HTTP,
+	}
+}
+
+// resilientAntifraudService decorates an AntifraudService with a resilience policy
+type resilientAntifraudService struct {
+	service    primitive.AntifraudService
+	resilience *Resilience
+}
+
+// NewResilientAntifraudService wraps an AntifraudService with a resilience policy
+func NewResilientAntifraudService(service primitive.AntifraudService, resilience *Resilience) primitive.AntifraudService {
+	return &resilientAntifraudService{
+		service:    service,
+		resilience: resilience,
+	}
+}
+
+func (s *resilientAntifraudService) StoreTransaction(ctx context.Context, afTransaction interface{}) error {
+	return s.resilience.Execute(ctx, func(ctx context.Context) error {
+		return s.service.StoreTransaction(ctx, afTransaction)
+	})
+}
+
+func (s *resilientAntifraudService) ValidateTransactionByAML(ctx context.Context, afTransaction interface{}) (interface{}, error) {
+	return ExecuteWithResult(ctx, s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.ValidateTransactionByAML(ctx, afTransaction)
+	})
+}
+
+func (s *resilientAntifraudService) ValidateTransactionByFC(ctx context.Context, afTransaction interface{}) (interface{}, error) {
+	return ExecuteWithResult(ctx, s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.ValidateTransactionByFC(ctx, afTransaction)
+	})
+}
+
+func (s *resilientAntifraudService) ValidateTransactionByML(ctx context.Context, afTransaction interface{}) (interface{}, error) {
+	return ExecuteWithResult(ctx, s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.ValidateTransactionByML(ctx, afTransaction)
+	})
+}
+
+func (s *resilientAntifraudService) StoreServiceResolution(ctx context.Context, resolution interface{}) error {
+	return s.resilience.Execute(ctx, func(ctx context.Context) error {
+		return s.service.StoreServiceResolution(ctx, resolution)
+	})
+}
+
+func (s *resilientAntifraudService) AddTransactionServiceCheck(ctx context.Context, resolution interface{}) error {
+	return s.resilience.Execute(ctx, func(ctx context.Context) error {
+		return s.service.AddTransactionServiceCheck(ctx, resolution)
+	})
+}
+
+func (s *resilientAntifraudService) FinalizeTransaction(ctx context.Context, afTransaction interface{}) (interface{}, error) {
+	return ExecuteWithResult(ctx, s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.FinalizeTransaction(ctx, afTransaction)
+	})
+}
+
+func (s *resilientAntifraudService) StoreFinalResolution(ctx context.Context, resolution interface{}) error {
+	return s.resilience.Execute(ctx, func(ctx context.Context) error {
+		return s.service.StoreFinalResolution(ctx, resolution)
+	})
+}
+
+// HealthCheck bypasses the resilience policy so that probes reflect the real service state
+func (s *resilientAntifraudService) HealthCheck(ctx context.Context) (bool, error) {
+	return s.service.HealthCheck(ctx)
+}
+
+func (s *resilientAntifraudService) GetConfig() interface{} {
+	return s.service.GetConfig()
+}
+
+// resilientStorageService decorates a StorageService with a resilience policy
+type resilientStorageService struct {
+	service    primitive.StorageService
+	resilience *Resilience
+}
+
+// NewResilientStorageService wraps a StorageService with a resilience policy
+func NewResilientStorageService(service primitive.StorageService, resilience *Resilience) primitive.StorageService {
+	return &resilientStorageService{
+		service:    service,
+		resilience: resilience,
+	}
+}
+
+func (s *resilientStorageService) Save(data interface{}) (interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.Save(data)
+	})
+}
+
+func (s *resilientStorageService) Get(id string) (interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.Get(id)
+	})
+}
+
+func (s *resilientStorageService) Delete(id string) error {
+	return s.resilience.Execute(context.Background(), func(ctx context.Context) error {
+		return s.service.Delete(id)
+	})
+}
+
+func (s *resilientStorageService) List() ([]interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) ([]interface{}, error) {
+		return s.service.List()
+	})
+}
+
+func (s *resilientStorageService) Update(id string, data interface{}) (interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.Update(id, data)
+	})
+}
+
+// resilientHTTPService decorates an HTTPService with a resilience policy
+type resilientHTTPService struct {
+	service    primitive.HTTPService
+	resilience *Resilience
+}
+
+// NewResilientHTTPService wraps an HTTPService with a resilience policy
+func NewResilientHTTPService(service primitive.HTTPService, resilience *Resilience) primitive.HTTPService {
+	return &resilientHTTPService{
+		service:    service,
+		resilience: resilience,
+	}
+}
+
+func (s *resilientHTTPService) Get(url string, headers map[string]string) (interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.Get(url, headers)
+	})
+}
+
+func (s *resilientHTTPService) Post(url string, body interface{}, headers map[string]string) (interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.Post(url, body, headers)
+	})
+}
+
+func (s *resilientHTTPService) Put(url string, body interface{}, headers map[string]string) (interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.Put(url, body, headers)
+	})
+}
+
+func (s *resilientHTTPService) Delete(url string, headers map[string]string) (interface{}, error) {
+	return ExecuteWithResult(context.Background(), s.resilience, func(ctx context.Context) (interface{}, error) {
+		return s.service.Delete(url, headers)
+	})
+}
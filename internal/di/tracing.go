@@ -3,9 +3,12 @@ package di
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
+
+	"unified-workflow/internal/logging"
 )
 
 // Trace represents a single trace in a distributed system
@@ -303,6 +306,7 @@ func (s *Span) Context(ctx context.Context) context.Context {
 		return ctx
 	}
 
+	ctx = logging.WithTraceID(ctx, s.trace.TraceID)
 	return context.WithValue(ctx, spanContextKey, s)
 }
 
@@ -343,8 +347,7 @@ func (t *Tracer) exportTrace(trace *Trace) {
 	for _, exporter := range exporters {
 		go func(e TraceExporter) {
 			if err := e.Export(trace); err != nil {
-				// Log export error (in production, use proper logging)
-				fmt.Printf("Failed to export trace: %v\n", err)
+				slog.Error("Failed to export trace", "trace_id", trace.TraceID, "error", err)
 			}
 		}(exporter)
 	}
@@ -441,11 +444,11 @@ type ConsoleExporter struct{}
 
 // Export exports a trace to console
 func (e *ConsoleExporter) Export(trace *Trace) error {
-	fmt.Printf("[TRACE] %s %s %s %v\n",
-		trace.TraceID,
-		trace.Operation,
-		trace.Component,
-		trace.Duration)
+	slog.Info("Trace completed",
+		"trace_id", trace.TraceID,
+		"operation", trace.Operation,
+		"component", trace.Component,
+		"duration", trace.Duration)
 	return nil
 }
 
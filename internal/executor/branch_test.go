@@ -0,0 +1,147 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// riskAbove is a Go guard holding when the risk score of a run exceeds threshold
+func riskAbove(threshold int) model.Condition {
+	return model.When(func(data primitiveModel.WorkflowData) bool {
+		score, _ := data.Get("risk_score").(int)
+		return score > threshold
+	})
+}
+
+func TestFalseGuardSkipsStep(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	ran := false
+	review := typed.NewStep("review", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		ran = true
+		return nil
+	})
+	review.SetCondition(riskAbove(70))
+
+	workflow := model.NewBaseWorkflow("guarded", "guarded workflow")
+	workflow.AddSteps([]model.Step{typed.NewStep("score", succeed), review, typed.NewStep("notify", succeed)})
+	result := exec.execute(t, workflow, map[string]interface{}{"risk_score": 10})
+	if result.Status != "completed" {
+		t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+	}
+	if ran {
+		t.Error("review ran although its guard did not hold")
+	}
+	if got := stepStatuses(result); got != "score=completed,review=skipped,notify=completed" {
+		t.Errorf("steps = %s, want review skipped", got)
+	}
+	if reason := result.Steps[1].SkipReason; reason != "condition predicate not met" {
+		t.Errorf("review skip reason = %q, want the guard named", reason)
+	}
+	if states := exec.status(t, result.RunID).StepStates; states["review"] != primitiveModel.StepStatusSkipped {
+		t.Errorf("step_states = %v, want review skipped", states)
+	}
+
+	// The same guard lets the step run once it holds
+	result, _ = exec.ExecuteWorkflow(context.Background(), workflow.GetID(), map[string]interface{}{"risk_score": 90})
+	if got := stepStatuses(result); !ran || got != "score=completed,review=completed,notify=completed" {
+		t.Errorf("steps = %s, want review run", got)
+	}
+}
+
+func TestIfStepTakesElseBranch(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	decide := model.NewIfStep("decide", riskAbove(70),
+		[]model.Step{typed.NewStep("escalate", succeed)},
+		[]model.Step{typed.NewStep("approve", succeed)})
+	workflow := model.NewBaseWorkflow("if", "if workflow")
+	workflow.AddSteps([]model.Step{decide, typed.NewStep("notify", succeed)})
+
+	for _, tc := range []struct {
+		score   int
+		branch  string
+		taken   string
+		skipped string
+	}{
+		{score: 90, branch: "then", taken: "decide.then.escalate", skipped: "decide.else.approve"},
+		{score: 10, branch: "else", taken: "decide.else.approve", skipped: "decide.then.escalate"},
+	} {
+		t.Run(tc.branch, func(t *testing.T) {
+			result := exec.execute(t, workflow, map[string]interface{}{"risk_score": tc.score})
+			if result.Status != "completed" {
+				t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+			}
+			if result.Steps[0].Name != "decide" || result.Steps[0].SelectedBranch != tc.branch {
+				t.Errorf("steps = %+v, want decide to take %s", result.Steps, tc.branch)
+			}
+			states := exec.status(t, result.RunID).StepStates
+			if states[tc.taken] != primitiveModel.StepStatusCompleted || states[tc.skipped] != primitiveModel.StepStatusSkipped || states["notify"] != primitiveModel.StepStatusCompleted {
+				t.Errorf("step_states = %v, want %s run and %s skipped", states, tc.taken, tc.skipped)
+			}
+		})
+	}
+}
+
+func TestSwitchStepFallsBackToDefault(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	route := model.NewSwitchStep("route", []model.Branch{
+		{Name: "high", Condition: riskAbove(70), Steps: []model.Step{typed.NewStep("escalate", succeed)}},
+		{Name: "medium", Condition: riskAbove(40), Steps: []model.Step{typed.NewStep("review", succeed)}},
+	}, []model.Step{typed.NewStep("approve", succeed)})
+	workflow := model.NewBaseWorkflow("switch", "switch workflow")
+	workflow.AddSteps([]model.Step{route})
+
+	result := exec.execute(t, workflow, map[string]interface{}{"risk_score": 10})
+	if result.Status != "completed" {
+		t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+	}
+	if result.Steps[0].SelectedBranch != "default" {
+		t.Errorf("selected branch = %q, want default", result.Steps[0].SelectedBranch)
+	}
+	states := exec.status(t, result.RunID).StepStates
+	if states["route.default.approve"] != primitiveModel.StepStatusCompleted ||
+		states["route.high.escalate"] != primitiveModel.StepStatusSkipped || states["route.medium.review"] != primitiveModel.StepStatusSkipped {
+		t.Errorf("step_states = %v, want only the default branch run", states)
+	}
+	for _, step := range result.Steps {
+		if step.Name == "escalate" && step.SkipReason != "branch high not taken" {
+			t.Errorf("escalate skip reason = %q, want the branch not taken named", step.SkipReason)
+		}
+	}
+}
+
+func TestFailingGuardFailsRun(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	guard := model.Predicate(func(data primitiveModel.WorkflowData) (bool, error) {
+		return false, fmt.Errorf("risk_score missing")
+	})
+	review := typed.NewStep("review", succeed)
+	review.SetCondition(guard)
+	route := model.NewIfStep("route", guard, []model.Step{typed.NewStep("escalate", succeed)}, nil)
+
+	for _, step := range []model.Step{review, route} {
+		t.Run(step.GetName(), func(t *testing.T) {
+			workflow := model.NewBaseWorkflow("failing-guard-"+step.GetName(), "failing guard workflow")
+			workflow.AddSteps([]model.Step{step, typed.NewStep("notify", succeed)})
+			result := exec.execute(t, workflow, nil)
+			if result.Status != "failed" {
+				t.Fatalf("status = %s, want failed", result.Status)
+			}
+			if !strings.Contains(result.Steps[0].ErrorMessage, "risk_score missing") {
+				t.Errorf("error = %q, want the guard error", result.Steps[0].ErrorMessage)
+			}
+			if states := exec.status(t, result.RunID).StepStates; states[step.GetName()] != "failed" {
+				t.Errorf("step_states = %v, want %s failed", states, step.GetName())
+			}
+		})
+	}
+}
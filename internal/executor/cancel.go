@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/state"
+)
+
+// cancellationNamespace is the state store namespace flagging, per run, the cancellations requested through
+// another process than the one executing the run
+const cancellationNamespace = "cancellations"
+
+// requestCancellation flags a run as cancelled in the state store, for whichever process executes or resumes it
+func (e *WorkflowExecutor) requestCancellation(ctx context.Context, runID string) error {
+	if err := e.stateManagement.SaveRecord(ctx, cancellationNamespace, runKey(ctx, runID), []byte(time.Now().UTC().Format(time.RFC3339Nano))); err != nil {
+		return fmt.Errorf("failed to request cancellation of run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// cancellationRequested reports whether a run is flagged as cancelled in the state store
+func (e *WorkflowExecutor) cancellationRequested(ctx context.Context, runID string) bool {
+	if e.stateManagement == nil {
+		return false
+	}
+	_, err := e.stateManagement.GetRecord(ctx, cancellationNamespace, runKey(ctx, runID))
+	if err != nil && !errors.Is(err, state.ErrStateNotFound) {
+		logging.Warn(ctx, "Failed to check for cancellation", "error", err)
+	}
+	return err == nil
+}
+
+// clearCancellation drops the cancellation flag of a run that finished
+func (e *WorkflowExecutor) clearCancellation(ctx context.Context, runID string) {
+	if e.stateManagement == nil {
+		return
+	}
+	if err := e.stateManagement.DeleteRecord(ctx, cancellationNamespace, runKey(ctx, runID)); err != nil {
+		logging.Warn(ctx, "Failed to drop cancellation", "error", err)
+	}
+}
@@ -0,0 +1,162 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/metrics"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// CompensationResult represents the run of the compensation of a step or child step
+type CompensationResult struct {
+	Step           string    `json:"step"`
+	ChildStep      string    `json:"child_step,omitempty"`
+	Status         string    `json:"status"` // "compensated", "compensation_failed"
+	Attempts       int       `json:"attempts"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	DurationMillis int64     `json:"duration_millis"`
+	ErrorMessage   string    `json:"error_message,omitempty"`
+}
+
+// pendingCompensation is the compensation of a step or child step that completed
+type pendingCompensation struct {
+	step         string
+	childStep    string
+	stateKey     string
+	compensation *model.Compensation
+	// workflowContext is the context the step ran with
+	workflowContext primitiveModel.WorkflowContext
+}
+
+// registerCompensation records the compensation of a step or child step that completed
+func (run *stepRun) registerCompensation(pending pendingCompensation) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.compensations = append(run.compensations, pending)
+}
+
+// registerChildStepCompensations records the compensations of the child steps of a step that completed
+func (run *stepRun) registerChildStepCompensations(step model.Step, branch string, childStepResults []ChildStepExecutionResult, workflowContext primitiveModel.WorkflowContext) {
+	for i, childResult := range childStepResults {
+		if childResult.Status != primitiveModel.StepStatusCompleted {
+			continue
+		}
+		childStep := step.GetChildStep(i)
+		if childStep == nil || childStep.GetCompensation() == nil {
+			continue
+		}
+		run.registerCompensation(pendingCompensation{
+			step:            step.GetName(),
+			childStep:       childStep.GetName(),
+			stateKey:        stepStateKey(step.GetName(), branch) + "/" + childStep.GetName(),
+			compensation:    childStep.GetCompensation(),
+			workflowContext: workflowContext,
+		})
+	}
+}
+
+// registerStepCompensation records the compensation of a step once it has completed
+func (run *stepRun) registerStepCompensation(step model.Step, branch string, workflowContext primitiveModel.WorkflowContext) {
+	compensable, ok := step.(model.CompensableStep)
+	if !ok || compensable.GetCompensation() == nil {
+		return
+	}
+	stateKey := stepStateKey(step.GetName(), branch)
+	if run.stepState(stateKey) != primitiveModel.StepStatusCompleted {
+		return
+	}
+	run.registerCompensation(pendingCompensation{
+		step:            step.GetName(),
+		stateKey:        stateKey,
+		compensation:    compensable.GetCompensation(),
+		workflowContext: workflowContext,
+	})
+}
+
+// compensate runs the registered compensations of a failed or cancelled run in the reverse order their steps
+// completed and returns the compensation status of the run
+// Compensations run even when the run was cancelled; one that fails after its retries does not stop the others
+func (e *WorkflowExecutor) compensate(ctx context.Context, run *stepRun) (string, []CompensationResult) {
+	ctx = context.WithoutCancel(ctx)
+	run.mu.Lock()
+	pending := append([]pendingCompensation(nil), run.compensations...)
+	run.runContext = run.runContext.WithCompensationStatus(primitiveModel.StepStatusCompensating)
+	e.saveRunContext(ctx, run.runContext)
+	run.mu.Unlock()
+	logging.Info(ctx, "Compensating run", "compensations", len(pending))
+
+	status := primitiveModel.StepStatusCompensated
+	results := make([]CompensationResult, 0, len(pending))
+	for i := len(pending) - 1; i >= 0; i-- {
+		result := e.runCompensation(ctx, run, pending[i])
+		if result.Status != primitiveModel.StepStatusCompensated {
+			status = primitiveModel.StepStatusCompensationFailed
+		}
+		results = append(results, result)
+	}
+
+	run.mu.Lock()
+	run.runContext = run.runContext.WithCompensationStatus(status)
+	e.saveRunContext(ctx, run.runContext)
+	run.mu.Unlock()
+	return status, results
+}
+
+// runCompensation runs one compensation, retrying it under its retry policy
+// Each attempt is bounded by the step timeout of the executor; an attempt that panics fails
+func (e *WorkflowExecutor) runCompensation(ctx context.Context, run *stepRun, pending pendingCompensation) CompensationResult {
+	ctx = logging.WithStep(ctx, pending.step)
+	if pending.childStep != "" {
+		ctx = logging.WithChildStep(ctx, pending.childStep)
+	}
+	e.setStepState(ctx, run, pending.stateKey, primitiveModel.StepStatusCompensating)
+
+	result := CompensationResult{Step: pending.step, ChildStep: pending.childStep, StartTime: time.Now()}
+	retry := pending.compensation.Retry
+	var err error
+	for result.Attempts < retry.Attempts() {
+		if result.Attempts > 0 {
+			time.Sleep(retry.Delay(result.Attempts))
+		}
+		result.Attempts++
+		if err = e.compensationAttempt(ctx, run, pending); err == nil {
+			break
+		}
+		logging.Warn(ctx, "Compensation attempt failed", "attempt", result.Attempts, "error", err)
+	}
+	result.EndTime = time.Now()
+	result.DurationMillis = result.EndTime.Sub(result.StartTime).Milliseconds()
+	result.Status = primitiveModel.StepStatusCompensated
+	if err != nil {
+		result.Status = primitiveModel.StepStatusCompensationFailed
+		result.ErrorMessage = err.Error()
+	}
+
+	e.setStepState(ctx, run, pending.stateKey, result.Status)
+	metrics.Compensations.Inc(run.workflowID, pending.stateKey, result.Status)
+	metrics.CompensationDuration.ObserveDuration(result.EndTime.Sub(result.StartTime), run.workflowID, pending.stateKey, result.Status)
+	return result
+}
+
+// compensationAttempt runs a compensation once, abandoning it after the step timeout and recovering a panic as an error
+func (e *WorkflowExecutor) compensationAttempt(ctx context.Context, run *stepRun, pending pendingCompensation) error {
+	if timeout := e.Config().StepTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %s", ErrStepTimedOut, timeout))
+		defer cancel()
+	}
+	_, err := callHook(ctx, run.workflowID, pending.stateKey, func() (_ struct{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("compensation panicked: %v", r)
+			}
+		}()
+		return struct{}{}, pending.compensation.Run(ctx, pending.workflowContext, run.data)
+	})
+	return err
+}
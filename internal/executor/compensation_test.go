@@ -0,0 +1,161 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	"unified-workflow/internal/metrics"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// undoLog records the compensations that ran, in order
+type undoLog struct {
+	mu     sync.Mutex
+	undone []string
+}
+
+// compensation creates a compensation of name that fails its first failures attempts
+func (l *undoLog) compensation(name string, failures int) *model.Compensation {
+	attempts := 0
+	return model.NewCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if attempts++; attempts <= failures {
+			return fmt.Errorf("%s unavailable", name)
+		}
+		l.undone = append(l.undone, name)
+		return nil
+	}).WithRetry(model.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+}
+
+func (l *undoLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.undone, ",")
+}
+
+// compensationAttempts lists the compensations of a result as "step/child:status:attempts", in the order they ran
+func compensationAttempts(result *ExecutionResult) string {
+	attempts := make([]string, 0, len(result.Compensations))
+	for _, c := range result.Compensations {
+		attempts = append(attempts, fmt.Sprintf("%s/%s:%s:%d", c.Step, c.ChildStep, c.Status, c.Attempts))
+	}
+	return strings.Join(attempts, " ")
+}
+
+func TestFailedRunCompensatesCompletedStepsInReverseOrder(t *testing.T) {
+	exec := newTestExecutor(t)
+	var log undoLog
+
+	reserve := typed.NewStep("reserve", succeed)
+	reserve.AddChildStep(model.NewChildStepFunc("hold", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (interface{}, error) {
+		return "held", nil
+	}).SetCompensation(log.compensation("hold", 0)))
+	reserve.SetCompensation(log.compensation("reserve", 0))
+	charge := typed.NewStep("charge", succeed)
+	charge.SetCompensation(log.compensation("charge", 1))
+	audit := typed.NewStep("audit", succeed)
+	audit.SetCompensation(log.compensation("audit", 2))
+	notify := typed.NewStep("notify", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		return fmt.Errorf("notification service down")
+	})
+
+	workflow := model.NewBaseWorkflow("order", "reserves, charges and notifies")
+	workflow.AddSteps([]model.Step{reserve, charge, audit, notify, typed.NewStep("ship", succeed)})
+	result := exec.execute(t, workflow, nil)
+	if result.Status != "failed" || result.CompensationStatus != "compensation_failed" {
+		t.Fatalf("status = %s, compensation status = %s, want failed and compensation_failed", result.Status, result.CompensationStatus)
+	}
+	if got := log.String(); got != "charge,reserve,hold" {
+		t.Errorf("compensated = %s, want the completed steps undone in reverse order", got)
+	}
+	if got := compensationAttempts(result); got != "audit/:compensation_failed:2 charge/:compensated:2 reserve/:compensated:1 reserve/hold:compensated:1" {
+		t.Errorf("compensations = %s", got)
+	}
+
+	status := exec.status(t, result.RunID)
+	states := status.StepStates
+	if status.CompensationStatus != "compensation_failed" ||
+		states["reserve"] != "compensated" || states["reserve/hold"] != "compensated" ||
+		states["audit"] != "compensation_failed" || states["notify"] != "failed" {
+		t.Errorf("execution status = %+v, want the compensation states of the run and its steps", status)
+	}
+
+	var exposition strings.Builder
+	if err := metrics.Default.WriteText(&exposition); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`uwf_compensations_total{workflow_id="` + workflow.GetID() + `",step="charge",status="compensated"} 1`,
+		`uwf_compensations_total{workflow_id="` + workflow.GetID() + `",step="audit",status="compensation_failed"} 1`,
+	} {
+		if !strings.Contains(exposition.String(), want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+}
+
+func TestCompletedRunCompensatesNothing(t *testing.T) {
+	exec := newTestExecutor(t)
+	var log undoLog
+
+	reserve := typed.NewStep("reserve", succeed)
+	reserve.SetCompensation(log.compensation("reserve", 0))
+	workflow := model.NewBaseWorkflow("order", "reserves")
+	workflow.AddStep(reserve)
+	result := exec.execute(t, workflow, nil)
+	if result.Status != "completed" || result.CompensationStatus != "" || log.String() != "" {
+		t.Errorf("status = %s, compensation status = %q, compensated = %q, want nothing compensated", result.Status, result.CompensationStatus, log.String())
+	}
+}
+
+func TestCompensationAttemptsAreBoundedAndRecoverPanics(t *testing.T) {
+	exec := newTestExecutor(t)
+	config := exec.Config()
+	config.StepTimeout = 20 * time.Millisecond
+	exec.UpdateConfig(config)
+	var log undoLog
+
+	reserve := typed.NewStep("reserve", succeed)
+	reserve.SetCompensation(log.compensation("reserve", 0))
+	charge := typed.NewStep("charge", succeed)
+	charge.SetCompensation(model.NewCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	}).WithRetry(model.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	notify := typed.NewStep("notify", succeed)
+	notify.SetCompensation(model.NewCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		panic("refund client not configured")
+	}).WithRetry(model.RetryPolicy{MaxAttempts: 1}))
+	fail := typed.NewStep("ship", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		return fmt.Errorf("carrier down")
+	})
+
+	workflow := model.NewBaseWorkflow("order", "reserves, charges, notifies and ships")
+	workflow.AddSteps([]model.Step{reserve, charge, notify, fail})
+	start := time.Now()
+	result := exec.execute(t, workflow, nil)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("run took %s, want the hanging compensation cut off by the step timeout", elapsed)
+	}
+	if result.CompensationStatus != "compensation_failed" || log.String() != "reserve" {
+		t.Fatalf("compensation status = %s, compensated = %s, want reserve compensated despite the others failing", result.CompensationStatus, log.String())
+	}
+	if got := compensationAttempts(result); got != "notify/:compensation_failed:1 charge/:compensation_failed:2 reserve/:compensated:1" {
+		t.Errorf("compensations = %s", got)
+	}
+	for _, c := range result.Compensations {
+		switch {
+		case c.Step == "notify" && !strings.Contains(c.ErrorMessage, "compensation panicked: refund client not configured"):
+			t.Errorf("notify error = %q, want the panic", c.ErrorMessage)
+		case c.Step == "charge" && !strings.Contains(c.ErrorMessage, "step timed out after 20ms"):
+			t.Errorf("charge error = %q, want the step timeout", c.ErrorMessage)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"fmt"
+
+	"unified-workflow/internal/config"
+)
+
+// ConfigFromSettings converts the executor section of the application configuration
+func ConfigFromSettings(settings config.ExecutorConfig) Config {
+	return Config{
+		WorkerCount:            settings.WorkerCount,
+		QueuePollInterval:      settings.QueuePollInterval,
+		MaxRetries:             settings.MaxRetries,
+		RetryDelay:             settings.RetryDelay,
+		ExecutionTimeout:       settings.ExecutionTimeout,
+		StepTimeout:            settings.StepTimeout,
+		EnableMetrics:          settings.EnableMetrics,
+		EnableTracing:          settings.EnableTracing,
+		MaxConcurrentWorkflows: settings.MaxConcurrentWorkflows,
+	}
+}
+
+// ValidateSettings checks the executor limits
+func ValidateSettings(settings config.ExecutorConfig) error {
+	if settings.WorkerCount < 0 {
+		return fmt.Errorf("executor.worker_count must not be negative, got %d", settings.WorkerCount)
+	}
+	if settings.MaxRetries < 0 {
+		return fmt.Errorf("executor.max_retries must not be negative, got %d", settings.MaxRetries)
+	}
+	if settings.MaxConcurrentWorkflows < 0 {
+		return fmt.Errorf("executor.max_concurrent_workflows must not be negative, got %d", settings.MaxConcurrentWorkflows)
+	}
+	if settings.MaxResultWaiters < 0 {
+		return fmt.Errorf("executor.max_result_waiters must not be negative, got %d", settings.MaxResultWaiters)
+	}
+	if settings.RetryDelay < 0 || settings.ExecutionTimeout < 0 || settings.StepTimeout < 0 || settings.QueuePollInterval < 0 {
+		return fmt.Errorf("executor durations must not be negative")
+	}
+	return nil
+}
+
+// configOwner applies the executor section of the configuration to a running executor
+type configOwner struct {
+	executor *WorkflowExecutor
+}
+
+// NewConfigOwner returns a config.SectionOwner that keeps the executor limits in sync with the configuration
+func NewConfigOwner(executor *WorkflowExecutor) config.SectionOwner {
+	return &configOwner{executor: executor}
+}
+
+// Name implements config.SectionOwner
+func (o *configOwner) Name() string {
+	return "executor"
+}
+
+// Sections implements config.SectionOwner
+func (o *configOwner) Sections() []string {
+	return []string{"executor"}
+}
+
+// ValidateConfig implements config.SectionOwner
+func (o *configOwner) ValidateConfig(cfg *config.Config) error {
+	return ValidateSettings(cfg.Executor)
+}
+
+// ApplyConfig implements config.SectionOwner
+func (o *configOwner) ApplyConfig(oldConfig, newConfig *config.Config) error {
+	o.executor.UpdateConfig(ConfigFromSettings(newConfig.Executor))
+	if o.executor.completions != nil {
+		o.executor.completions.SetMaxWaiters(newConfig.Executor.MaxResultWaiters)
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package executor
 
 import (
+	"fmt"
+
 	"unified-workflow/internal/di"
 	"unified-workflow/internal/primitive"
+	"unified-workflow/internal/queue"
 	"unified-workflow/internal/registry"
 	"unified-workflow/internal/state"
 )
@@ -39,6 +42,9 @@ func (f *DIFactory) CreateWorkflowExecutor(config Config) (*WorkflowExecutor, er
 
 	// Create executor with resolved dependencies
 	executor := NewWorkflowExecutor(reg, stateMgmt, config)
+	if q := f.resolveQueue(); q != nil {
+		executor.SetQueue(q)
+	}
 
 	// Set primitive provider if available
 	if primitiveProvider != nil {
@@ -63,14 +69,27 @@ func (f *DIFactory) resolveRegistry() (registry.Registry, error) {
 
 // resolveStateManagement resolves state management from DI container
 func (f *DIFactory) resolveStateManagement() (state.StateManagement, error) {
-	instance, err := f.container.Resolve((*state.StateManagement)(nil))
-	if err != nil {
+	if !f.container.Has((*state.StateManagement)(nil)) {
 		// Fall back to default in-memory state
 		return state.NewInMemoryState(), nil
 	}
+	// A store the application registered but cannot reach is not replaced, so processes keep sharing runs
+	instance, err := f.container.Resolve((*state.StateManagement)(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve state management: %w", err)
+	}
 	return instance.(state.StateManagement), nil
 }
 
+// resolveQueue resolves the queue from DI container, if one is registered
+func (f *DIFactory) resolveQueue() queue.Queue {
+	instance, err := f.container.Resolve((*queue.Queue)(nil))
+	if err != nil {
+		return nil
+	}
+	return instance.(queue.Queue)
+}
+
 // resolvePrimitiveProvider resolves primitive provider from DI container
 func (f *DIFactory) resolvePrimitiveProvider() (*di.PrimitiveProvider, error) {
 	instance, err := f.container.Resolve((*di.PrimitiveProvider)(nil))
@@ -89,16 +108,18 @@ func RegisterExecutorDependencies(container di.Container) error {
 	// Note: Registry is registered in registerCoreServices in main.go
 	// We don't register it here to avoid overwriting the HTTP registry
 
-	// Register state management
-	err := container.RegisterFactory((*state.StateManagement)(nil), func(c di.Container) (interface{}, error) {
-		return state.NewInMemoryState(), nil
-	}, di.Singleton)
-	if err != nil {
-		return err
+	// Register state management unless the application registered its own store
+	if !container.Has((*state.StateManagement)(nil)) {
+		err := container.RegisterFactory((*state.StateManagement)(nil), func(c di.Container) (interface{}, error) {
+			return state.NewInMemoryState(), nil
+		}, di.Singleton)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Register primitive provider
-	err = container.RegisterFactory((*di.PrimitiveProvider)(nil), func(c di.Container) (interface{}, error) {
+	err := container.RegisterFactory((*di.PrimitiveProvider)(nil), func(c di.Container) (interface{}, error) {
 		return di.NewPrimitiveProvider(c), nil
 	}, di.Singleton)
 	if err != nil {
@@ -15,6 +15,9 @@ type Executor interface {
 	// SubmitWorkflowByID submits a workflow by ID for execution and returns a run ID
 	SubmitWorkflowByID(ctx context.Context, workflowID string) (string, error)
 
+	// SubmitWorkflowWithInput submits a workflow with its input data for execution and returns a run ID
+	SubmitWorkflowWithInput(ctx context.Context, workflow model.Workflow, inputData map[string]interface{}) (string, error)
+
 	// GetExecutionStatus gets the status of a workflow execution
 	GetExecutionStatus(ctx context.Context, runID string) (*ExecutionStatus, error)
 
@@ -63,6 +66,11 @@ type ExecutionStatus struct {
 	ErrorMessage          string                 `json:"error_message,omitempty"`
 	LastAttemptedStep     string                 `json:"last_attempted_step,omitempty"`
 	IsTerminal            bool                   `json:"is_terminal"`
+	StepStates            map[string]string      `json:"step_states,omitempty"`         // state of each started or skipped step, by name
+	ParentRunID           string                 `json:"parent_run_id,omitempty"`       // run that started this one as a sub-workflow
+	ChildRunIDs           []string               `json:"child_run_ids,omitempty"`       // sub-workflow runs this one started
+	CompensationStatus    string                 `json:"compensation_status,omitempty"` // compensating, compensated or compensation_failed
+	WakeAt                *time.Time             `json:"wake_at,omitempty"`             // when a sleeping run resumes
 	Metadata              map[string]interface{} `json:"metadata,omitempty"`
 }
 
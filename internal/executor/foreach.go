@@ -0,0 +1,165 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// errTooManyItemFailures cancels the items of a foreach step in flight once its error policy gives up
+var errTooManyItemFailures = errors.New("too many items failed")
+
+// ItemExecutionResult represents the run of a foreach step for one item
+type ItemExecutionResult struct {
+	Index          int       `json:"index"`
+	Status         string    `json:"status"` // "completed", "failed", "cancelled", "skipped"
+	StartTime      time.Time `json:"start_time,omitempty"`
+	EndTime        time.Time `json:"end_time,omitempty"`
+	DurationMillis int64     `json:"duration_millis,omitempty"`
+	ErrorMessage   string    `json:"error_message,omitempty"`
+	// ChildRunID is the run started for the item by a foreach step running a sub-workflow
+	ChildRunID string `json:"child_run_id,omitempty"`
+}
+
+// itemStateKey names an item of a foreach step in the step states of a run context, as "notify[3]"
+func itemStateKey(name, branch string, index int) string {
+	return fmt.Sprintf("%s[%d]", stepStateKey(name, branch), index)
+}
+
+// runForEach runs the child step or sub-workflow of a foreach step for each item of its list
+// Items run on up to Concurrency workers; once the error policy gives up, the items in flight are
+// cancelled and the items not started are skipped
+func (e *WorkflowExecutor) runForEach(ctx context.Context, run *stepRun, step *model.ForEachStep, stepResult StepExecutionResult, stepIndex int, stepContext primitiveModel.WorkflowContext) bool {
+	items, err := step.ItemsOf(run.data)
+	if err != nil {
+		return e.finishStep(ctx, run, stepResult, nil, err)
+	}
+
+	itemCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make([]interface{}, len(items))
+	itemResults := make([]ItemExecutionResult, len(items))
+	for i := range itemResults {
+		itemResults[i] = ItemExecutionResult{Index: i, Status: primitiveModel.StepStatusSkipped}
+	}
+	var mu sync.Mutex
+	failures := 0
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < step.Workers(len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				value, itemResult := e.runItem(itemCtx, run, step, stepResult.Branch, stepIndex, stepContext, i, items[i])
+				mu.Lock()
+				results[i] = value
+				itemResults[i] = itemResult
+				if itemResult.Status == "failed" {
+					failures++
+					if !step.Tolerates(failures) {
+						cancel(errTooManyItemFailures)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := range items {
+		select {
+		case <-itemCtx.Done():
+			break feed
+		case next <- i:
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	var itemErrors []interface{}
+	var firstErr string
+	for _, itemResult := range itemResults {
+		if itemResult.Status != "failed" {
+			continue
+		}
+		itemErrors = append(itemErrors, map[string]interface{}{"index": itemResult.Index, "error": itemResult.ErrorMessage})
+		if firstErr == "" {
+			firstErr = fmt.Sprintf("item %d: %s", itemResult.Index, itemResult.ErrorMessage)
+		}
+	}
+	run.data.Put(step.ResultsKey(), results)
+	if len(itemErrors) > 0 {
+		run.data.Put(step.ErrorsKey(), itemErrors)
+	}
+	stepResult.Items = itemResults
+
+	var stepErr error
+	switch {
+	case ctx.Err() != nil:
+		stepErr = context.Cause(ctx)
+	case !step.Tolerates(failures):
+		stepErr = fmt.Errorf("%d of %d items failed, first %s", failures, len(items), firstErr)
+	}
+	return e.finishStep(ctx, run, stepResult, nil, stepErr)
+}
+
+// runItem runs a foreach step for one item and returns the item result along with the record of its run
+func (e *WorkflowExecutor) runItem(ctx context.Context, run *stepRun, step *model.ForEachStep, branch string, stepIndex int, stepContext primitiveModel.WorkflowContext, index int, item interface{}) (interface{}, ItemExecutionResult) {
+	itemResult := ItemExecutionResult{Index: index, Status: primitiveModel.StepStatusSkipped}
+	if ctx.Err() != nil {
+		return nil, itemResult
+	}
+	key := itemStateKey(step.GetName(), branch, index)
+	e.setStepState(ctx, run, key, primitiveModel.StepStatusRunning)
+	itemResult.StartTime = time.Now()
+
+	var value interface{}
+	var err error
+	itemData := step.ItemData(run.data, index, item)
+	if step.SubWorkflow != nil {
+		var input, output map[string]interface{}
+		if input, err = step.SubWorkflow.ChildInput(itemData); err == nil {
+			itemResult.ChildRunID, output, err = e.runChildWorkflow(ctx, run, step.SubWorkflow, input)
+		}
+		if err == nil {
+			value, err = step.SubWorkflow.MapOutput(output)
+		}
+	} else {
+		childResult := e.executeChildStep(ctx, step.ChildStep, stepIndex, index, stepContext, itemData)
+		value = childResult.Result
+		if childResult.Status == "failed" {
+			err = errors.New(childResult.ErrorMessage)
+		}
+	}
+
+	itemResult.EndTime = time.Now()
+	itemResult.DurationMillis = itemResult.EndTime.Sub(itemResult.StartTime).Milliseconds()
+	itemResult.Status = "completed"
+	if err != nil {
+		itemResult.Status = "failed"
+		if ctx.Err() != nil {
+			itemResult.Status = primitiveModel.StepStatusCancelled
+		}
+		itemResult.ErrorMessage = err.Error()
+		logging.Debug(ctx, "Item failed", "step", step.GetName(), "index", index, "error", err)
+		value = nil
+	}
+	e.setStepState(ctx, run, key, itemResult.Status)
+	return value, itemResult
+}
+
+// setStepState sets the state of a step, or an item of a step, in the run context
+func (e *WorkflowExecutor) setStepState(ctx context.Context, run *stepRun, key, state string) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.runContext = run.runContext.WithStepState(key, state)
+	e.saveRunContext(ctx, run.runContext)
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// doubling creates a child step doubling the amount of its item, failing on negative amounts
+// maxRunning records the largest number of items it ran at once
+func doubling(maxRunning *atomic.Int32) *model.ChildStep {
+	var running atomic.Int32
+	return model.NewChildStepFunc("double", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (interface{}, error) {
+		for n := running.Add(1); ; {
+			if most := maxRunning.Load(); n <= most || maxRunning.CompareAndSwap(most, n) {
+				break
+			}
+		}
+		defer running.Add(-1)
+		time.Sleep(10 * time.Millisecond)
+		amount, _ := data.GetFloat("item")
+		if amount < 0 {
+			return nil, fmt.Errorf("negative amount %v", amount)
+		}
+		return amount * 2, nil
+	})
+}
+
+// executeForEach runs a workflow made of step over amounts
+func executeForEach(t *testing.T, exec *testExecutor, step *model.ForEachStep, amounts ...float64) *ExecutionResult {
+	t.Helper()
+	items := make([]interface{}, len(amounts))
+	for i, amount := range amounts {
+		items[i] = amount
+	}
+	workflow := model.NewBaseWorkflow("batch", "doubles each amount")
+	workflow.AddStep(step)
+	return exec.execute(t, workflow, map[string]interface{}{"amounts": items})
+}
+
+// itemStatuses lists the statuses of the items of the first step of a result
+func itemStatuses(result *ExecutionResult) string {
+	var statuses []string
+	for _, item := range result.Steps[0].Items {
+		statuses = append(statuses, item.Status)
+	}
+	return fmt.Sprint(statuses)
+}
+
+func TestForEachBoundsConcurrencyAndKeepsListOrder(t *testing.T) {
+	exec := newTestExecutor(t)
+	var maxRunning atomic.Int32
+
+	result := executeForEach(t, exec, model.NewForEachStep("double", "amounts", doubling(&maxRunning)).WithConcurrency(2).StoreInto("doubled"), 1, 2, 3, 4, 5, 6)
+	if result.Status != "completed" {
+		t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+	}
+	if got := fmt.Sprint(result.Result["doubled"]); got != "[2 4 6 8 10 12]" {
+		t.Errorf("doubled = %s, want the results in list order", got)
+	}
+	if got := maxRunning.Load(); got != 2 {
+		t.Errorf("items run at once = %d, want 2", got)
+	}
+	if state := exec.status(t, result.RunID).StepStates["double[5]"]; state != "completed" {
+		t.Errorf("step_states[double[5]] = %v, want an entry per item", state)
+	}
+}
+
+func TestForEachFailsFast(t *testing.T) {
+	exec := newTestExecutor(t)
+	var maxRunning atomic.Int32
+
+	result := executeForEach(t, exec, model.NewForEachStep("double", "amounts", doubling(&maxRunning)), 1, -2, 3)
+	if got := itemStatuses(result); result.Status != "failed" || got != "[completed failed skipped]" {
+		t.Errorf("status = %s, items = %s, want failed with the items after the failure skipped", result.Status, got)
+	}
+}
+
+func TestForEachCollectsItemErrors(t *testing.T) {
+	exec := newTestExecutor(t)
+	var maxRunning atomic.Int32
+
+	result := executeForEach(t, exec, model.NewForEachStep("double", "amounts", doubling(&maxRunning)).OnItemError(model.CollectErrors, 0), 1, -2, 3, -4)
+	if result.Status != "completed" || fmt.Sprint(result.Result["doubleResult"]) != "[2 <nil> 6 <nil>]" {
+		t.Errorf("status = %s, results = %v, want completed with the failed items empty", result.Status, result.Result["doubleResult"])
+	}
+	if errs, _ := result.Result["doubleResultErrors"].([]interface{}); len(errs) != 2 {
+		t.Errorf("errors = %v, want two", result.Result["doubleResultErrors"])
+	}
+	if got := itemStatuses(result); got != "[completed failed completed failed]" {
+		t.Errorf("items = %s, want every item run", got)
+	}
+}
+
+func TestForEachFailsOverFailureThreshold(t *testing.T) {
+	exec := newTestExecutor(t)
+	var maxRunning atomic.Int32
+
+	result := executeForEach(t, exec, model.NewForEachStep("double", "amounts", doubling(&maxRunning)).OnItemError(model.FailureThreshold, 1), -1, 2, -3, 4)
+	if result.Status != "failed" {
+		t.Errorf("status = %s, want failed once two items fail", result.Status)
+	}
+	result = executeForEach(t, exec, model.NewForEachStep("double", "amounts", doubling(&maxRunning)).OnItemError(model.FailureThreshold, 1), -1, 2, 3)
+	if result.Status != "completed" {
+		t.Errorf("status = %s %s, want completed with one failed item", result.Status, result.Error)
+	}
+}
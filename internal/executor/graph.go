@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"unified-workflow/internal/common/model"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// runGraph runs the steps of a graph workflow, starting each step as soon as the steps it depends on have finished
+// Steps skipped by their condition count as finished; the dependents of a failed step are skipped, and once a
+// failure stops the run the steps that have not started yet are skipped as well
+func (e *WorkflowExecutor) runGraph(ctx context.Context, run *stepRun, graph *model.StepGraph) {
+	type finished struct {
+		node    int
+		proceed bool
+	}
+	done := make(chan finished)
+
+	waiting := make([]int, graph.Len())      // dependencies of each step that have not finished
+	blockedBy := make([]string, graph.Len()) // a failed or cancelled dependency of each step
+	var ready []int
+	for i := 0; i < graph.Len(); i++ {
+		waiting[i] = len(graph.DependsOn(i))
+		if waiting[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	// release lets the dependents of a finished step go, blocking them when it did not complete
+	release := func(node int, failed string) {
+		for _, dependent := range graph.Dependents(node) {
+			if failed != "" && blockedBy[dependent] == "" {
+				blockedBy[dependent] = failed
+			}
+			waiting[dependent]--
+			if waiting[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	running := 0
+	stoppedBy := "" // the step, and its state, that stopped the run
+	for {
+		for len(ready) > 0 {
+			node := ready[0]
+			ready = ready[1:]
+			step := graph.Step(node)
+			switch {
+			case blockedBy[node] != "":
+				e.skipStep(ctx, run, step, "", fmt.Sprintf("dependency %s did not complete", blockedBy[node]))
+				release(node, blockedBy[node])
+			case stoppedBy != "":
+				e.skipStep(ctx, run, step, "", "run stopped after step "+stoppedBy)
+				release(node, "")
+			default:
+				running++
+				go func() {
+					proceed := e.runStep(ctx, run, step, "")
+					done <- finished{node: node, proceed: proceed}
+				}()
+			}
+		}
+		if running == 0 {
+			return
+		}
+
+		result := <-done
+		running--
+		name := graph.Step(result.node).GetName()
+		state := run.stepState(name)
+		if !result.proceed && stoppedBy == "" {
+			stoppedBy = name + " " + state
+		}
+		failed := ""
+		if state == primitiveModel.StepStatusFailed || state == primitiveModel.StepStatusCancelled || state == primitiveModel.StepStatusTimedOut {
+			failed = name
+		}
+		release(result.node, failed)
+	}
+}
+
+// syncData guards workflow data shared by steps that run concurrently
+type syncData struct {
+	mu   sync.RWMutex
+	data primitiveModel.WorkflowData
+}
+
+// Get returns a value by key
+func (d *syncData) Get(key string) interface{} {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.Get(key)
+}
+
+// Put sets a value by key
+func (d *syncData) Put(key string, value interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.data.Put(key, value)
+}
+
+// Remove removes a value by key
+func (d *syncData) Remove(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.data.Remove(key)
+}
+
+// Contains checks if a key exists
+func (d *syncData) Contains(key string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.Contains(key)
+}
+
+// Size returns the number of entries
+func (d *syncData) Size() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.Size()
+}
+
+// Clear removes all entries
+func (d *syncData) Clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.data.Clear()
+}
+
+// ToMap returns a copy of the data as a map
+func (d *syncData) ToMap() map[string]interface{} {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.ToMap()
+}
+
+// DeepCopy creates a deep copy of the workflow data
+func (d *syncData) DeepCopy() primitiveModel.WorkflowData {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.DeepCopy()
+}
+
+// Merge merges another WorkflowData into this one
+func (d *syncData) Merge(other primitiveModel.WorkflowData) {
+	if other == nil {
+		return
+	}
+	values := other.ToMap()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, v := range values {
+		d.data.Put(k, v)
+	}
+}
+
+// GetString returns a string value by key
+func (d *syncData) GetString(key string) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.GetString(key)
+}
+
+// GetInt returns an int value by key
+func (d *syncData) GetInt(key string) (int, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.GetInt(key)
+}
+
+// GetBool returns a bool value by key
+func (d *syncData) GetBool(key string) (bool, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.GetBool(key)
+}
+
+// GetFloat returns a float64 value by key
+func (d *syncData) GetFloat(key string) (float64, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.GetFloat(key)
+}
+
+// GetMap returns a map value by key
+func (d *syncData) GetMap(key string) (map[string]interface{}, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.GetMap(key)
+}
+
+// GetSlice returns a slice value by key
+func (d *syncData) GetSlice(key string) ([]interface{}, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data.GetSlice(key)
+}
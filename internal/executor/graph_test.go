@@ -0,0 +1,143 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// succeed is the logic of a step that always completes
+func succeed(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+	return nil
+}
+
+func TestGraphRunsIndependentStepsConcurrently(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	// aml and fc only return once both have started, so the run completes only if they run concurrently
+	var started sync.WaitGroup
+	started.Add(2)
+	validation := func(name string) *typed.Step {
+		return typed.NewStep(name, func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+			if data.Get("stored") != true {
+				return fmt.Errorf("%s started before store", name)
+			}
+			started.Done()
+			waited := make(chan struct{})
+			go func() {
+				started.Wait()
+				close(waited)
+			}()
+			select {
+			case <-waited:
+			case <-time.After(5 * time.Second):
+				return fmt.Errorf("%s did not run concurrently", name)
+			}
+			data.Put(name, "PASS")
+			return nil
+		})
+	}
+	store := typed.NewStep("store", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		data.Put("stored", true)
+		return nil
+	})
+	aml, fc := validation("aml"), validation("fc")
+	aml.SetDependsOn("store")
+	fc.SetDependsOn("store")
+	finalize := typed.NewStep("finalize", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		if data.Get("aml") != "PASS" || data.Get("fc") != "PASS" {
+			return fmt.Errorf("finalize started before the validations finished")
+		}
+		return nil
+	})
+	finalize.SetDependsOn("aml", "fc")
+
+	workflow := model.NewBaseWorkflow("graph", "graph workflow")
+	workflow.AddSteps([]model.Step{finalize, aml, fc, store})
+	result := exec.execute(t, workflow, nil)
+	if result.Status != "completed" {
+		t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+	}
+	if len(result.Steps) != 4 || result.Steps[0].Name != "store" || result.Steps[3].Name != "finalize" {
+		t.Errorf("steps = %s, want store first and finalize last", stepStatuses(result))
+	}
+
+	states := exec.status(t, result.RunID).StepStates
+	for _, name := range []string{"store", "aml", "fc", "finalize"} {
+		if states[name] != "completed" {
+			t.Errorf("step_states[%s] = %v, want completed", name, states[name])
+		}
+	}
+}
+
+func TestGraphSkipsDependentsOfFailedStep(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	store := typed.NewStep("store", succeed)
+	aml := typed.NewStep("aml", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		return fmt.Errorf("aml service down")
+	})
+	aml.SetDependsOn("store")
+	fc := typed.NewStep("fc", succeed)
+	fc.SetDependsOn("store")
+	finalize := typed.NewStep("finalize", succeed)
+	finalize.SetDependsOn("aml", "fc")
+	notify := typed.NewStep("notify", succeed)
+	notify.SetDependsOn("finalize")
+
+	workflow := model.NewBaseWorkflow("graph", "graph workflow")
+	workflow.AddSteps([]model.Step{store, aml, fc, finalize, notify})
+	result := exec.execute(t, workflow, nil)
+	if result.Status != "failed" {
+		t.Fatalf("status = %s, want failed", result.Status)
+	}
+
+	states := exec.status(t, result.RunID).StepStates
+	if states["aml"] != "failed" || states["fc"] != "completed" || states["finalize"] != "skipped" || states["notify"] != "skipped" {
+		t.Errorf("step_states = %v, want fc to finish and the dependents of aml skipped", states)
+	}
+	for _, step := range result.Steps {
+		if step.Name == "notify" && step.SkipReason != "dependency aml did not complete" {
+			t.Errorf("notify skip reason = %q, want the failed dependency named", step.SkipReason)
+		}
+	}
+}
+
+func TestGraphStepSkippedByConditionReleasesDependents(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	review := typed.NewStep("review", succeed)
+	review.SetCondition(model.MustParseCondition("risk_score > 70"))
+	finalize := typed.NewStep("finalize", succeed)
+	finalize.SetDependsOn("review")
+
+	workflow := model.NewBaseWorkflow("graph", "graph workflow")
+	workflow.AddSteps([]model.Step{review, finalize})
+	result := exec.execute(t, workflow, map[string]interface{}{"risk_score": 10})
+	if result.Status != "completed" {
+		t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+	}
+	if got := stepStatuses(result); got != "review=skipped,finalize=completed" {
+		t.Errorf("steps = %s, want finalize to run once review was skipped", got)
+	}
+}
+
+func TestCyclicGraphIsRejectedAtRegistration(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	cyclic := model.NewBaseWorkflow("cyclic", "cyclic workflow")
+	a, b := model.NewSequentialStep("a"), model.NewSequentialStep("b")
+	a.SetDependsOn("b")
+	b.SetDependsOn("a")
+	cyclic.AddSteps([]model.Step{a, b})
+	if err := exec.registry.RegisterWorkflow(context.Background(), cyclic); !errors.Is(err, model.ErrInvalidGraph) {
+		t.Errorf("RegisterWorkflow(cyclic) error = %v, want ErrInvalidGraph", err)
+	}
+}
@@ -0,0 +1,392 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/logging"
+	primitiveModel "unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+)
+
+// ErrQueueNotConfigured is returned when a run is submitted to an executor without a queue
+var ErrQueueNotConfigured = errors.New("executor has no queue configured")
+
+// lastRunID is the number of the last run ID generated here
+var lastRunID atomic.Int64
+
+// newRunID generates a new workflow run ID
+// IDs are numbered by the clock and kept increasing, so runs started at once, such as those of foreach items, differ
+func newRunID() string {
+	for {
+		last := lastRunID.Load()
+		next := time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if lastRunID.CompareAndSwap(last, next) {
+			return fmt.Sprintf("run-%d", next)
+		}
+	}
+}
+
+// submitRun persists a pending run with its input data and publishes an execution request for it
+// The run belongs to the tenant of ctx, which namespaces its state and queue subject
+func submitRun(ctx context.Context, stateManagement state.StateManagement, q queue.Queue, runID, workflowID string, inputData map[string]interface{}) error {
+	if q == nil {
+		return ErrQueueNotConfigured
+	}
+	if inputData == nil {
+		inputData = make(map[string]interface{})
+	}
+	if err := savePendingRun(ctx, stateManagement, runID, workflowID, inputData); err != nil {
+		return err
+	}
+
+	reqData, err := queue.MarshalExecutionRequest(queue.ExecutionRequest{
+		RunID:       runID,
+		WorkflowID:  workflowID,
+		Tenant:      tenant.FromContext(ctx),
+		InputData:   inputData,
+		RequestedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution request: %w", err)
+	}
+
+	if err := q.Enqueue(ctx, runID, reqData); err != nil {
+		if stateManagement != nil {
+			failed := primitiveModel.NewWorkflowContextForRun(runID, workflowID).
+				WithStatus(primitiveModel.WorkflowStatusFailed).
+				WithErrorMessage(err.Error()).
+				WithEndTime(time.Now())
+			stateManagement.SaveContext(ctx, failed)
+		}
+		return fmt.Errorf("failed to enqueue workflow: %w", err)
+	}
+	return nil
+}
+
+// savePendingRun persists a pending run with its input data so that it can be polled
+func savePendingRun(ctx context.Context, stateManagement state.StateManagement, runID, workflowID string, inputData map[string]interface{}) error {
+	if stateManagement == nil {
+		return nil
+	}
+	if err := stateManagement.SaveContext(ctx, primitiveModel.NewWorkflowContextForRun(runID, workflowID)); err != nil {
+		return fmt.Errorf("failed to save run %s: %w", runID, err)
+	}
+	if err := stateManagement.SaveData(ctx, runID, workflowDataFromMap(inputData)); err != nil {
+		return fmt.Errorf("failed to save input data for run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// loadExecutionStatus builds the execution status of a run from its persisted context
+func loadExecutionStatus(ctx context.Context, stateManagement state.StateManagement, runID string) (*ExecutionStatus, error) {
+	if stateManagement == nil {
+		return nil, fmt.Errorf("execution %s not found: %w", runID, state.ErrStateNotFound)
+	}
+	workflowContext, err := stateManagement.GetContext(ctx, runID)
+	if err != nil {
+		if err == state.ErrStateNotFound {
+			return nil, fmt.Errorf("execution %s not found: %w", runID, err)
+		}
+		return nil, fmt.Errorf("failed to get execution %s: %w", runID, err)
+	}
+
+	status := &ExecutionStatus{
+		RunID:                 runID,
+		WorkflowID:            workflowContext.GetWorkflowDefinitionID(),
+		Status:                statusName(workflowContext.GetStatus()),
+		CurrentStep:           workflowContext.GetLastAttemptedStep(),
+		CurrentStepIndex:      workflowContext.GetCurrentStepIndex(),
+		CurrentChildStepIndex: workflowContext.GetCurrentChildStepIndex(),
+		StartTime:             workflowContext.GetStartTime(),
+		EndTime:               workflowContext.GetEndTime(),
+		ErrorMessage:          workflowContext.GetErrorMessage(),
+		LastAttemptedStep:     workflowContext.GetLastAttemptedStep(),
+		IsTerminal:            isTerminalStatus(workflowContext.GetStatus()),
+		ParentRunID:           workflowContext.GetParentRunID(),
+		CompensationStatus:    workflowContext.GetCompensationStatus(),
+		WakeAt:                workflowContext.GetWakeAt(),
+	}
+	if childRunIDs := workflowContext.GetChildRunIDs(); len(childRunIDs) > 0 {
+		status.ChildRunIDs = childRunIDs
+	}
+	if states := workflowContext.GetStepStates(); len(states) > 0 {
+		status.StepStates = states
+	}
+	if status.IsTerminal {
+		status.Progress = 1.0
+	}
+	return status, nil
+}
+
+// loadExecutionData reads the persisted data of a run; a run without data yields an empty map
+func loadExecutionData(ctx context.Context, stateManagement state.StateManagement, runID string) (map[string]interface{}, error) {
+	if stateManagement == nil {
+		return map[string]interface{}{}, nil
+	}
+	workflowData, err := stateManagement.GetData(ctx, runID)
+	if err != nil {
+		if err == state.ErrStateNotFound {
+			return map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("failed to get execution data for %s: %w", runID, err)
+	}
+	return workflowData.ToMap(), nil
+}
+
+// LoadResult returns the result of a finished run from the state store, or nil while it is still in progress
+func LoadResult(ctx context.Context, stateManagement state.StateManagement, runID string) (*queue.ExecutionResult, error) {
+	status, err := loadExecutionStatus(ctx, stateManagement, runID)
+	if err != nil {
+		return nil, err
+	}
+	if !status.IsTerminal {
+		return nil, nil
+	}
+	data, err := loadExecutionData(ctx, stateManagement, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &queue.ExecutionResult{
+		RunID:      runID,
+		WorkflowID: status.WorkflowID,
+		Tenant:     tenant.FromContext(ctx),
+		Status:     status.Status,
+		OutputData: data,
+		Error:      status.ErrorMessage,
+	}
+	if status.EndTime != nil {
+		result.CompletedAt = *status.EndTime
+	}
+	return result, nil
+}
+
+// WaitForResult waits up to timeout for a run to finish and returns its result, or nil if it is still running
+// Without a hub, or with a non-positive timeout, only the current state is checked
+func WaitForResult(ctx context.Context, hub *completion.Hub, stateManagement state.StateManagement, runID string, timeout time.Duration) (*queue.ExecutionResult, error) {
+	if hub == nil || timeout <= 0 {
+		return LoadResult(ctx, stateManagement, runID)
+	}
+
+	// Subscribe before checking so a completion between the check and the wait is not missed
+	completed, cancel, err := hub.Subscribe(runID)
+	if err != nil {
+		// Too many parked requests: answer from the current state and let the caller poll
+		logging.Warn(ctx, "Not waiting for run completion", "run_id", runID, "error", err)
+		return LoadResult(ctx, stateManagement, runID)
+	}
+	defer cancel()
+
+	result, err := LoadResult(ctx, stateManagement, runID)
+	if err != nil || result != nil {
+		return result, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-completed:
+		// Read back from the store so callers see the same data as a later poll
+		return LoadResult(ctx, stateManagement, runID)
+	case <-timer.C:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, nil
+	}
+}
+
+// RecordResult stores an execution result reported by a worker as the final state of the run
+// The result is stored for its own tenant, whatever the tenant of ctx
+func RecordResult(ctx context.Context, stateManagement state.StateManagement, result queue.ExecutionResult) error {
+	ctx = tenant.WithTenant(ctx, result.Tenant)
+	var workflowContext primitiveModel.WorkflowContext
+	existing, err := stateManagement.GetContext(ctx, result.RunID)
+	switch {
+	case err == nil:
+		workflowContext = existing
+	case err == state.ErrStateNotFound:
+		workflowContext = primitiveModel.NewWorkflowContextForRun(result.RunID, result.WorkflowID)
+	default:
+		return fmt.Errorf("failed to get run %s: %w", result.RunID, err)
+	}
+
+	completedAt := result.CompletedAt
+	if completedAt.IsZero() {
+		completedAt = time.Now()
+	}
+	workflowContext = workflowContext.
+		WithStatus(statusValue(result.Status)).
+		WithErrorMessage(result.Error).
+		WithEndTime(completedAt)
+	if workflowContext.GetStartTime() == nil {
+		workflowContext = workflowContext.WithStartTime(completedAt)
+	}
+
+	if result.OutputData != nil {
+		if err := stateManagement.SaveData(ctx, result.RunID, workflowDataFromMap(result.OutputData)); err != nil {
+			return fmt.Errorf("failed to save result data for run %s: %w", result.RunID, err)
+		}
+	}
+	if err := stateManagement.SaveContext(ctx, workflowContext); err != nil {
+		return fmt.Errorf("failed to save run %s: %w", result.RunID, err)
+	}
+	return nil
+}
+
+// ListenForResults records the results published by remote workers into the local state store
+// and notifies the completion hub, which may be nil. The returned function stops listening
+func ListenForResults(q *queue.EnhancedNATSQueue, stateManagement state.StateManagement, hub *completion.Hub) (func(), error) {
+	handler := func(data []byte) {
+		result, err := queue.UnmarshalExecutionResult(data)
+		if err != nil {
+			slog.Warn("Failed to decode execution result", "error", err)
+			return
+		}
+		if err := RecordResult(context.Background(), stateManagement, result); err != nil {
+			slog.Warn("Failed to record execution result", "run_id", result.RunID, "error", err)
+			return
+		}
+		if hub != nil {
+			result.Tenant = tenant.Normalize(result.Tenant)
+			result.Status = statusName(statusValue(result.Status))
+			hub.Publish(result)
+		}
+	}
+
+	resultsSub, err := q.SubscribeToAllResults(handler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to execution results: %w", err)
+	}
+	errorsSub, err := q.SubscribeToAllErrors(handler)
+	if err != nil {
+		resultsSub.Unsubscribe()
+		return nil, fmt.Errorf("failed to subscribe to execution errors: %w", err)
+	}
+
+	return func() {
+		resultsSub.Unsubscribe()
+		errorsSub.Unsubscribe()
+	}, nil
+}
+
+// isParked reports whether a run is handed over, sleeping on a timer or waiting for a signal, until it resumes
+func isParked(status string) bool {
+	return status == statusName(primitiveModel.WorkflowStatusSleeping) || status == statusName(primitiveModel.WorkflowStatusWaiting)
+}
+
+// statusName converts a persisted workflow status to its API name
+func statusName(status int) string {
+	switch status {
+	case primitiveModel.WorkflowStatusPending:
+		return "pending"
+	case primitiveModel.WorkflowStatusRunning:
+		return "running"
+	case primitiveModel.WorkflowStatusCompleted:
+		return "completed"
+	case primitiveModel.WorkflowStatusFailed:
+		return "failed"
+	case primitiveModel.WorkflowStatusCancelled:
+		return "cancelled"
+	case primitiveModel.WorkflowStatusPaused:
+		return "paused"
+	case primitiveModel.WorkflowStatusWaiting:
+		return "waiting"
+	case primitiveModel.WorkflowStatusSleeping:
+		return "sleeping"
+	case primitiveModel.WorkflowStatusTimedOut:
+		return "timed_out"
+	default:
+		return "unknown"
+	}
+}
+
+// statusValue converts an execution result status to a persisted workflow status
+func statusValue(status string) int {
+	switch status {
+	case "completed":
+		return primitiveModel.WorkflowStatusCompleted
+	case "cancelled":
+		return primitiveModel.WorkflowStatusCancelled
+	case "running":
+		return primitiveModel.WorkflowStatusRunning
+	case "waiting":
+		return primitiveModel.WorkflowStatusWaiting
+	case "sleeping":
+		return primitiveModel.WorkflowStatusSleeping
+	case "timed_out":
+		return primitiveModel.WorkflowStatusTimedOut
+	case "pending":
+		return primitiveModel.WorkflowStatusPending
+	default:
+		return primitiveModel.WorkflowStatusFailed
+	}
+}
+
+// isTerminalStatus reports whether a run in this status will not change anymore
+func isTerminalStatus(status int) bool {
+	return status == primitiveModel.WorkflowStatusCompleted ||
+		status == primitiveModel.WorkflowStatusFailed ||
+		status == primitiveModel.WorkflowStatusCancelled ||
+		status == primitiveModel.WorkflowStatusTimedOut
+}
+
+// workflowDataFromMap builds workflow data from a map
+func workflowDataFromMap(data map[string]interface{}) primitiveModel.WorkflowData {
+	workflowData := primitiveModel.NewWorkflowData()
+	for key, value := range data {
+		workflowData.Put(key, value)
+	}
+	return workflowData
+}
+
+// plainData converts the typed values in run data, such as the results of typed child steps, to their
+// JSON form, so persistence, encryption and masking see the field names of structs
+func plainData(data map[string]interface{}) map[string]interface{} {
+	plain := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		plain[key] = plainValue(value)
+	}
+	return plain
+}
+
+// plainValue converts a value to maps, slices and scalars; values that do not marshal are kept
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, int, int32, int64, float32, float64:
+		return v
+	case map[string]interface{}:
+		return plainData(v)
+	case []interface{}:
+		plain := make([]interface{}, len(v))
+		for i, item := range v {
+			plain[i] = plainValue(item)
+		}
+		return plain
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var plain interface{}
+	if err := json.Unmarshal(encoded, &plain); err != nil {
+		return value
+	}
+	return plain
+}
+
+// runKey identifies a run of the tenant of ctx, as run IDs are only unique within a tenant
+func runKey(ctx context.Context, runID string) string {
+	return tenant.FromContext(ctx) + "/" + runID
+}
@@ -0,0 +1,484 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
+	primitiveModel "unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+	"unified-workflow/internal/timer"
+)
+
+// ErrRunFinished is returned when a signal or a cancellation is sent to a run that has already finished
+var ErrRunFinished = errors.New("run already finished")
+
+// ErrRunNotWaiting is returned when a signal is sent to a run that no step of is waiting for it
+var ErrRunNotWaiting = errors.New("run not waiting for signal")
+
+// maxPendingSignals bounds the signals of one name a run holds before a wait step takes them
+const maxPendingSignals = 16
+
+// signalNamespace is the state store namespace holding, per run, the signals it waits for and those not yet taken
+const signalNamespace = "signals"
+
+// signalLockTimeout bounds the lock taken to update the signals of a run
+const signalLockTimeout = 5 * time.Second
+
+// Signal is an external input sent to a run, such as the decision of an analyst on a transaction under review
+type Signal struct {
+	Name       string                 `json:"name"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+	ReceivedAt time.Time              `json:"received_at"`
+}
+
+// signalRecord is the record of a run holding the signals it waits for and those sent but not yet taken
+type signalRecord struct {
+	Waits   map[string]*signalWait `json:"waits,omitempty"`   // by signal name
+	Pending map[string][]Signal    `json:"pending,omitempty"` // by signal name, in the order they were sent
+}
+
+// signalWait is the wait of a run for a signal
+type signalWait struct {
+	// Steps counts the steps executing somewhere that wait for the signal
+	Steps int `json:"steps,omitempty"`
+	// Parked is set while the run is saved waiting for the signal and no process executes it
+	Parked bool `json:"parked,omitempty"`
+	// TimeoutAt is when the wait of a parked run expires, if it does
+	TimeoutAt *time.Time `json:"timeout_at,omitempty"`
+}
+
+// wait returns the wait of the run for a signal, registering it if needed
+func (r *signalRecord) wait(name string) *signalWait {
+	if r.Waits == nil {
+		r.Waits = make(map[string]*signalWait)
+	}
+	w, ok := r.Waits[name]
+	if !ok {
+		w = &signalWait{}
+		r.Waits[name] = w
+	}
+	return w
+}
+
+// take removes the oldest signal of a name not yet taken
+func (r *signalRecord) take(name string) (Signal, bool) {
+	pending := r.Pending[name]
+	if len(pending) == 0 {
+		return Signal{}, false
+	}
+	if len(pending) == 1 {
+		delete(r.Pending, name)
+	} else {
+		r.Pending[name] = pending[1:]
+	}
+	return pending[0], true
+}
+
+// awaited reports whether a signal not yet taken is one that a step executing somewhere waits for
+func (r *signalRecord) awaited() bool {
+	for name, w := range r.Waits {
+		if w.Steps > 0 && len(r.Pending[name]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// release ends the wait of one step for a signal; the wait is dropped once no step waits and the run is not parked
+func (r *signalRecord) release(name string) {
+	w, ok := r.Waits[name]
+	if !ok {
+		return
+	}
+	if w.Steps > 0 {
+		w.Steps--
+	}
+	if w.Steps == 0 && !w.Parked {
+		delete(r.Waits, name)
+	}
+}
+
+// signalHub wakes the wait steps executing here as soon as a signal is sent to their run through this process
+// Signals sent through other processes are picked up from the state store every runWatchInterval
+type signalHub struct {
+	mu     sync.Mutex
+	wakeUp map[string]chan struct{} // by run ID, closed when a signal is sent
+}
+
+// channel returns the channel closed when the next signal is sent to a run
+func (h *signalHub) channel(runID string) <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.wakeUp == nil {
+		h.wakeUp = make(map[string]chan struct{})
+	}
+	wakeUp, ok := h.wakeUp[runID]
+	if !ok {
+		wakeUp = make(chan struct{})
+		h.wakeUp[runID] = wakeUp
+	}
+	return wakeUp
+}
+
+// notify wakes the wait steps of a run up
+func (h *signalHub) notify(runID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if wakeUp, ok := h.wakeUp[runID]; ok {
+		close(wakeUp)
+		delete(h.wakeUp, runID)
+	}
+}
+
+// forget drops the channel of a run that finished or was handed over
+func (h *signalHub) forget(runID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.wakeUp, runID)
+}
+
+// SignalExecution sends a named signal with a payload to a run waiting for it
+// The signal is kept in the state store until a wait step takes it, merging the payload into the run's data;
+// a run parked on its wait step is resumed through the queue by whichever worker takes the request
+// It fails with ErrRunFinished for a run that finished and ErrRunNotWaiting for one not waiting for the signal
+func (e *WorkflowExecutor) SignalExecution(ctx context.Context, runID, name string, payload map[string]interface{}) error {
+	status, err := e.GetExecutionStatus(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if status.IsTerminal {
+		return fmt.Errorf("%w: run %s is %s", ErrRunFinished, runID, status.Status)
+	}
+
+	parked := false
+	err = e.updateSignals(ctx, runID, func(record *signalRecord) error {
+		w, ok := record.Waits[name]
+		if !ok {
+			return fmt.Errorf("%w: run %s is %s and does not wait for signal %s", ErrRunNotWaiting, runID, status.Status, name)
+		}
+		if len(record.Pending[name]) >= maxPendingSignals {
+			return fmt.Errorf("run %s already holds %d %s signals that no step has taken", runID, maxPendingSignals, name)
+		}
+		if record.Pending == nil {
+			record.Pending = make(map[string][]Signal)
+		}
+		record.Pending[name] = append(record.Pending[name], Signal{Name: name, Payload: payload, ReceivedAt: time.Now()})
+		parked = w.Parked
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.signals.notify(runID)
+
+	ctx = logging.WithRun(ctx, runID, status.WorkflowID)
+	if parked {
+		if err := e.enqueueResume(ctx, runID, status.WorkflowID); err != nil {
+			return fmt.Errorf("signal %s kept, but run %s could not be resumed: %w", name, runID, err)
+		}
+	}
+	logging.Info(ctx, "Signal sent", "signal", name)
+	return nil
+}
+
+// runWait waits on a wait step until its signal arrives, its timeout expires or the run is cancelled
+// A top-level wait of a run whose timers are durable parks the run: it is saved waiting, its worker is
+// released, and a signal or the timer of its timeout resumes it through the queue, taking the wait step again.
+// Otherwise the step waits in place and the run and the step are reported as waiting meanwhile.
+// On timeout the steps of the timeout branch run
+func (e *WorkflowExecutor) runWait(ctx context.Context, run *stepRun, step *model.WaitForSignalStep, stepResult StepExecutionResult) bool {
+	if e.stateManagement == nil {
+		return e.finishStep(ctx, run, stepResult, nil, fmt.Errorf("waiting for signal %s requires a state store", step.Signal))
+	}
+	key := stepStateKey(step.GetName(), stepResult.Branch)
+	run.mu.Lock()
+	runID := run.runContext.GetRunID()
+	run.mu.Unlock()
+
+	// A run resumed from a parked wait keeps the timeout the wait started with
+	var timeoutAt *time.Time
+	if step.Timeout > 0 {
+		deadline := time.Now().Add(step.Timeout)
+		timeoutAt = &deadline
+	}
+	wakeUp := e.signals.channel(runID)
+	signal, received := Signal{}, false
+	err := e.updateSignals(ctx, runID, func(record *signalRecord) error {
+		w := record.wait(step.Signal)
+		if w.Parked {
+			timeoutAt = w.TimeoutAt
+			w.Parked, w.TimeoutAt = false, nil
+		}
+		w.Steps++
+		signal, received = record.take(step.Signal)
+		return nil
+	})
+	if err != nil {
+		return e.finishStep(ctx, run, stepResult, nil, fmt.Errorf("failed to wait for signal %s: %w", step.Signal, err))
+	}
+
+	if !received && !expired(timeoutAt) && ctx.Err() == nil {
+		if resumeStep := run.resumeStep(step, stepResult.Branch); resumeStep > 0 {
+			var parked bool
+			if signal, received, parked = e.park(ctx, run, step, key, resumeStep, timeoutAt); parked {
+				return false
+			}
+		}
+	}
+	if !received && !expired(timeoutAt) && ctx.Err() == nil {
+		e.setWaiting(ctx, run, key, true)
+		logging.Info(ctx, "Waiting for signal", "signal", step.Signal, "timeout", step.Timeout)
+		signal, received = e.awaitSignal(ctx, runID, step.Signal, timeoutAt, wakeUp)
+		e.setWaiting(ctx, run, key, false)
+	}
+	if err := e.updateSignals(context.WithoutCancel(ctx), runID, func(record *signalRecord) error {
+		record.release(step.Signal)
+		return nil
+	}); err != nil {
+		logging.Warn(ctx, "Failed to release wait", "signal", step.Signal, "error", err)
+	}
+
+	switch {
+	case received:
+		run.data.Merge(primitiveModel.WrapWorkflowData(signal.Payload))
+		logging.Info(ctx, "Signal received", "signal", signal.Name)
+		proceed := e.finishStep(ctx, run, stepResult, nil, nil)
+		for _, branch := range step.AllBranches() {
+			for _, branchStep := range branch.Steps {
+				e.skipStep(ctx, run, branchStep, step.GetName()+"."+branch.Name, fmt.Sprintf("signal %s received", step.Signal))
+			}
+		}
+		return proceed
+	case ctx.Err() != nil:
+		return e.finishStep(ctx, run, stepResult, nil, context.Cause(ctx))
+	}
+
+	logging.Info(ctx, "Timed out waiting for signal", "signal", step.Signal)
+	if step.OnTimeout == nil {
+		return e.finishStep(ctx, run, stepResult, nil, fmt.Errorf("timed out after %s waiting for signal %s", step.Timeout, step.Signal))
+	}
+	stepResult.Status = "completed"
+	stepResult.SelectedBranch = model.TimeoutBranch
+	resultIndex := e.recordStep(ctx, run, stepResult)
+	proceed := e.runSteps(ctx, run, step.OnTimeout.Steps, step.GetName()+"."+model.TimeoutBranch)
+	e.finishBranchingStep(run, resultIndex)
+	return proceed
+}
+
+// awaitSignal waits in place for a signal until timeoutAt or the cancellation of the run
+// Signals sent through this process wake it up at once; those sent through others are polled for
+func (e *WorkflowExecutor) awaitSignal(ctx context.Context, runID, name string, timeoutAt *time.Time, wakeUp <-chan struct{}) (Signal, bool) {
+	var timeout <-chan time.Time
+	if timeoutAt != nil {
+		timer := time.NewTimer(time.Until(*timeoutAt))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return Signal{}, false
+		case <-timeout:
+			return Signal{}, false
+		case <-wakeUp:
+			wakeUp = e.signals.channel(runID)
+		}
+
+		signal, received := Signal{}, false
+		err := e.updateSignals(ctx, runID, func(record *signalRecord) error {
+			signal, received = record.take(name)
+			return nil
+		})
+		if err != nil {
+			logging.Warn(ctx, "Failed to check for signal", "signal", name, "error", err)
+		}
+		if received {
+			return signal, true
+		}
+	}
+}
+
+// park saves a run waiting on a top-level wait step and hands it over until a signal or the timer of its timeout
+// resumes it; it reports whether the run is parked, or the signal sent meanwhile. When the run cannot be saved or
+// its timeout scheduled, it is not parked and the step waits in place
+func (e *WorkflowExecutor) park(ctx context.Context, run *stepRun, step *model.WaitForSignalStep, key string, resumeStep int, timeoutAt *time.Time) (Signal, bool, bool) {
+	run.mu.Lock()
+	runID := run.runContext.GetRunID()
+	run.mu.Unlock()
+	if err := e.stateManagement.SaveData(ctx, runID, workflowDataFromMap(plainData(run.data.ToMap()))); err != nil {
+		logging.Warn(ctx, "Failed to save run data, waiting in place", "error", err)
+		return Signal{}, false, false
+	}
+	if timeoutAt != nil {
+		err := e.timers.Schedule(ctx, timer.Timer{
+			RunID:      runID,
+			WorkflowID: run.workflowID,
+			Tenant:     tenant.FromContext(ctx),
+			WakeAt:     *timeoutAt,
+		})
+		if err != nil {
+			logging.Warn(ctx, "Failed to schedule wait timeout, waiting in place", "error", err)
+			return Signal{}, false, false
+		}
+	}
+
+	signal, received := Signal{}, false
+	err := e.updateSignals(ctx, runID, func(record *signalRecord) error {
+		if signal, received = record.take(step.Signal); received {
+			return nil
+		}
+		w := record.wait(step.Signal)
+		w.Steps--
+		w.Parked, w.TimeoutAt = true, timeoutAt
+		return nil
+	})
+	if err != nil || received {
+		if err != nil {
+			logging.Warn(ctx, "Failed to park run, waiting in place", "error", err)
+		}
+		return signal, received, false
+	}
+
+	run.mu.Lock()
+	run.runContext = run.runContext.
+		WithStatus(primitiveModel.WorkflowStatusWaiting).
+		WithStepState(key, primitiveModel.StepStatusWaiting).
+		WithResumeStep(resumeStep).
+		WithRemainingTimeout(run.deadline.left())
+	e.saveRunContext(ctx, run.runContext)
+	run.parked = statusName(primitiveModel.WorkflowStatusWaiting)
+	run.results = append(run.results, StepExecutionResult{Name: step.GetName(), Status: primitiveModel.StepStatusWaiting, StartTime: time.Now()})
+	run.mu.Unlock()
+
+	// A signal sent or a timeout expiring before the run was saved waiting could not resume it
+	pending := false
+	if err := e.updateSignals(ctx, runID, func(record *signalRecord) error {
+		pending = len(record.Pending[step.Signal]) > 0
+		return nil
+	}); err != nil {
+		logging.Warn(ctx, "Failed to check for signal", "signal", step.Signal, "error", err)
+	}
+	if pending || expired(timeoutAt) {
+		if err := e.enqueueResume(ctx, runID, run.workflowID); err != nil {
+			logging.Warn(ctx, "Failed to resume parked run", "error", err)
+		}
+	}
+	logging.Info(ctx, "Run parked until its signal arrives", "signal", step.Signal, "timeout", step.Timeout)
+	return Signal{}, false, true
+}
+
+// setWaiting marks a step as waiting for a signal, or as no longer waiting
+// The run is waiting while any of its steps is and running again once none is; waiting does not count towards its timeout
+func (e *WorkflowExecutor) setWaiting(ctx context.Context, run *stepRun, key string, waiting bool) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if waiting {
+		run.deadline.park()
+		run.waiting++
+		run.runContext = run.runContext.
+			WithStatus(primitiveModel.WorkflowStatusWaiting).
+			WithStepState(key, primitiveModel.StepStatusWaiting)
+	} else {
+		run.deadline.unpark()
+		if run.waiting--; run.waiting == 0 {
+			run.runContext = run.runContext.WithStatus(primitiveModel.WorkflowStatusRunning)
+		}
+	}
+	e.saveRunContext(ctx, run.runContext)
+}
+
+// updateSignals applies update to the signal record of a run under its lock, shared by every process
+// The record is dropped once it holds no wait and no signal; an error from update leaves it unchanged
+func (e *WorkflowExecutor) updateSignals(ctx context.Context, runID string, update func(record *signalRecord) error) error {
+	if e.stateManagement == nil {
+		return fmt.Errorf("signals require a state store")
+	}
+	lock := "signals/" + runID
+	for {
+		locked, err := e.stateManagement.AcquireLock(ctx, lock, signalLockTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to lock signals of run %s: %w", runID, err)
+		}
+		if locked {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	defer e.stateManagement.ReleaseLock(ctx, lock)
+
+	key := runKey(ctx, runID)
+	record := &signalRecord{}
+	data, err := e.stateManagement.GetRecord(ctx, signalNamespace, key)
+	switch {
+	case errors.Is(err, state.ErrStateNotFound):
+	case err != nil:
+		return fmt.Errorf("failed to get signals of run %s: %w", runID, err)
+	default:
+		if err := json.Unmarshal(data, record); err != nil {
+			return fmt.Errorf("failed to unmarshal signals of run %s: %w", runID, err)
+		}
+	}
+
+	if err := update(record); err != nil {
+		return err
+	}
+	if len(record.Waits) == 0 && len(record.Pending) == 0 {
+		return e.stateManagement.DeleteRecord(ctx, signalNamespace, key)
+	}
+	if data, err = json.Marshal(record); err != nil {
+		return fmt.Errorf("failed to marshal signals of run %s: %w", runID, err)
+	}
+	if err := e.stateManagement.SaveRecord(ctx, signalNamespace, key, data); err != nil {
+		return fmt.Errorf("failed to save signals of run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// forgetSignals drops the waits and the signals not taken of a run that finished
+func (e *WorkflowExecutor) forgetSignals(ctx context.Context, runID string) {
+	e.signals.forget(runID)
+	if e.stateManagement == nil {
+		return
+	}
+	if err := e.stateManagement.DeleteRecord(ctx, signalNamespace, runKey(ctx, runID)); err != nil {
+		logging.Warn(ctx, "Failed to drop signals", "error", err)
+	}
+}
+
+// enqueueResume requests a worker to resume a parked run
+func (e *WorkflowExecutor) enqueueResume(ctx context.Context, runID, workflowID string) error {
+	if e.queue == nil {
+		return ErrQueueNotConfigured
+	}
+	data, err := queue.MarshalExecutionRequest(queue.ExecutionRequest{
+		RunID:       runID,
+		WorkflowID:  workflowID,
+		Tenant:      tenant.FromContext(ctx),
+		Resume:      true,
+		RequestedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal resume request: %w", err)
+	}
+	if err := e.queue.Enqueue(ctx, runID, data); err != nil {
+		return fmt.Errorf("failed to enqueue resume request: %w", err)
+	}
+	return nil
+}
+
+// expired reports whether a wait timeout has passed
+func expired(timeoutAt *time.Time) bool {
+	return timeoutAt != nil && !time.Now().Before(*timeoutAt)
+}
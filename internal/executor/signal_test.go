@@ -0,0 +1,234 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	primitiveModel "unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/timer"
+)
+
+func TestSignalResumesWaitingRun(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	runIDs := make(chan string, 1)
+	workflow := model.NewBaseWorkflow("review", "waits for the decision of an analyst")
+	workflow.AddStep(typed.NewStep("flag", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		runIDs <- workflowContext.GetRunID()
+		return nil
+	}))
+	workflow.AddStep(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(5*time.Second, model.NewSequentialStep("escalate")))
+	workflow.AddStep(model.NewIfStep("apply", model.MustParseCondition(`decision == "approve"`),
+		[]model.Step{model.NewSequentialStep("approve")}, []model.Step{model.NewSequentialStep("decline")}))
+	exec.register(t, workflow)
+
+	done := make(chan *ExecutionResult, 1)
+	go func() {
+		result, _ := exec.ExecuteWorkflow(context.Background(), workflow.GetID(), nil)
+		done <- result
+	}()
+	runID := <-runIDs
+
+	status := exec.awaitStatus(t, runID, "waiting")
+	if status.IsTerminal || status.StepStates["analyst-review"] != "waiting" {
+		t.Errorf("status = %+v, want a waiting run with analyst-review waiting", status)
+	}
+
+	if err := exec.SignalExecution(context.Background(), runID, "decision", map[string]interface{}{"decision": "approve", "analyst": "j.doe"}); err != nil {
+		t.Fatalf("SignalExecution() error = %v", err)
+	}
+
+	result := <-done
+	if result == nil || result.Status != "completed" {
+		t.Fatalf("result = %+v, want completed", result)
+	}
+	if result.Result["decision"] != "approve" || result.Result["analyst"] != "j.doe" {
+		t.Errorf("result = %v, want the payload merged", result.Result)
+	}
+	if got := stepStatuses(result); got != "flag=completed,analyst-review=completed,escalate=skipped,apply=completed,approve=completed,decline=skipped" {
+		t.Errorf("steps = %s", got)
+	}
+
+	if err := exec.SignalExecution(context.Background(), runID, "decision", nil); !errors.Is(err, ErrRunFinished) {
+		t.Errorf("signal to a finished run error = %v, want ErrRunFinished", err)
+	}
+	if err := exec.SignalExecution(context.Background(), "run-unknown", "decision", nil); !errors.Is(err, state.ErrStateNotFound) {
+		t.Errorf("signal to an unknown run error = %v, want ErrStateNotFound", err)
+	}
+}
+
+func TestSignalNotWaitedForIsRefused(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	runIDs := make(chan string, 1)
+	workflow := model.NewBaseWorkflow("review", "waits for the decision of an analyst")
+	workflow.AddStep(typed.NewStep("flag", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		runIDs <- workflowContext.GetRunID()
+		return nil
+	}))
+	workflow.AddStep(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(50*time.Millisecond, model.NewSequentialStep("escalate")))
+	exec.register(t, workflow)
+
+	done := make(chan *ExecutionResult, 1)
+	go func() {
+		result, _ := exec.ExecuteWorkflow(context.Background(), workflow.GetID(), nil)
+		done <- result
+	}()
+	runID := <-runIDs
+	exec.awaitStatus(t, runID, "waiting")
+
+	if err := exec.SignalExecution(context.Background(), runID, "escalation", nil); !errors.Is(err, ErrRunNotWaiting) {
+		t.Errorf("signal not waited for error = %v, want ErrRunNotWaiting", err)
+	}
+	if result := <-done; result == nil || result.Status != "completed" || len(result.Steps) != 3 || result.Steps[1].SelectedBranch != "timeout" {
+		t.Errorf("result = %+v, want the timeout branch taken", result)
+	}
+}
+
+func TestSignalResumesParkedRun(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	workflow := model.NewBaseWorkflow("review", "waits for the decision of an analyst")
+	workflow.AddStep(model.NewSequentialStep("flag"))
+	workflow.AddStep(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(time.Hour, model.NewSequentialStep("escalate")))
+	workflow.AddStep(typed.NewStep("apply", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		data.Put("applied", true)
+		return nil
+	}))
+	runID := exec.submit(t, workflow, map[string]interface{}{"transaction_id": "tx-1"})
+
+	status := exec.awaitStatus(t, runID, "waiting")
+	if status.IsTerminal || status.StepStates["analyst-review"] != "waiting" {
+		t.Errorf("status = %+v, want a waiting run with analyst-review waiting", status)
+	}
+
+	// The signal goes through the state store and the queue rather than to the executor running the wait
+	if err := exec.SignalExecution(context.Background(), runID, "decision", map[string]interface{}{"decision": "approve"}); err != nil {
+		t.Fatalf("SignalExecution() error = %v", err)
+	}
+	result, err := exec.WaitForResult(context.Background(), runID, 5*time.Second)
+	if err != nil || result == nil || result.Status != "completed" {
+		t.Fatalf("WaitForResult() = %+v, %v, want completed", result, err)
+	}
+	if result.OutputData["decision"] != "approve" || result.OutputData["transaction_id"] != "tx-1" || result.OutputData["applied"] != true {
+		t.Errorf("result data = %v, want the payload merged into the data saved before the wait", result.OutputData)
+	}
+
+	status = exec.status(t, runID)
+	if status.StepStates["analyst-review"] != "completed" || status.StepStates["analyst-review.timeout.escalate"] != "skipped" || status.StepStates["apply"] != "completed" {
+		t.Errorf("status = %+v, want the wait completed by the signal", status)
+	}
+	if records, _ := exec.stateMgmt.ListRecords(context.Background(), signalNamespace); len(records) != 0 {
+		t.Errorf("signal records = %v, want none left once the run finished", records)
+	}
+}
+
+func TestParkedWaitTimesOut(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	workflow := model.NewBaseWorkflow("review", "waits briefly for a decision")
+	workflow.AddStep(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(200*time.Millisecond, model.NewSequentialStep("escalate")))
+	runID := exec.submit(t, workflow, nil)
+	exec.awaitStatus(t, runID, "waiting")
+
+	result, err := exec.WaitForResult(context.Background(), runID, 5*time.Second)
+	if err != nil || result == nil || result.Status != "completed" {
+		t.Fatalf("WaitForResult() = %+v, %v, want completed", result, err)
+	}
+	if status := exec.status(t, runID); status.StepStates["analyst-review"] != "completed" || status.StepStates["analyst-review.timeout.escalate"] != "completed" {
+		t.Errorf("status = %+v, want the timeout branch taken", status)
+	}
+}
+
+func TestWaitTimesOut(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	execute := func(step *model.WaitForSignalStep) *ExecutionResult {
+		workflow := model.NewBaseWorkflow("review", "waits briefly for a decision")
+		workflow.AddStep(step)
+		return exec.execute(t, workflow, nil)
+	}
+
+	result := execute(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(20*time.Millisecond, model.NewSequentialStep("escalate")))
+	if result.Status != "completed" {
+		t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+	}
+	if len(result.Steps) != 2 || result.Steps[0].SelectedBranch != "timeout" ||
+		result.Steps[1].Branch != "analyst-review.timeout" || result.Steps[1].Status != "completed" {
+		t.Errorf("steps = %+v, want the timeout branch taken", result.Steps)
+	}
+
+	result = execute(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(20 * time.Millisecond))
+	if result.Status != "failed" || !strings.Contains(result.Error, "timed out after 20ms waiting for signal decision") {
+		t.Errorf("status = %s %s, want failed on timeout", result.Status, result.Error)
+	}
+}
+
+func TestCancelParkedWaitCompensates(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	undone := make(chan string, 1)
+	reserve := typed.NewStep("reserve", succeed)
+	reserve.SetCompensation(model.NewCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		undone <- "reserve"
+		return nil
+	}))
+	workflow := model.NewBaseWorkflow("hold", "reserves, then waits for a decision")
+	workflow.AddSteps([]model.Step{reserve, model.NewWaitForSignalStep("analyst-review", "decision"), model.NewSequentialStep("release")})
+	runID := exec.submit(t, workflow, nil)
+	exec.awaitStatus(t, runID, "waiting")
+
+	if err := exec.CancelExecution(context.Background(), runID); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	status := exec.awaitStatus(t, runID, "cancelled")
+	if got := <-undone; got != "reserve" || status.CompensationStatus != "compensated" {
+		t.Errorf("compensated %s with status %s, want reserve compensated", got, status.CompensationStatus)
+	}
+	if err := exec.SignalExecution(context.Background(), runID, "decision", nil); !errors.Is(err, ErrRunFinished) {
+		t.Errorf("signal to the cancelled run error = %v, want ErrRunFinished", err)
+	}
+}
+
+func TestResumedRunReportsStepsBeforePark(t *testing.T) {
+	exec := newTestExecutor(t)
+	// An executor with durable timers but no worker, so the test resumes the run itself
+	local := exec.newExecutor()
+	local.SetTimers(timer.NewService(exec.stateMgmt, exec.queue))
+
+	workflow := model.NewBaseWorkflow("review", "routes, then waits for the decision of an analyst")
+	workflow.AddStep(model.NewSequentialStep("flag"))
+	workflow.AddStep(model.NewIfStep("route", model.MustParseCondition(`amount > 100`),
+		[]model.Step{model.NewSequentialStep("manual")}, []model.Step{model.NewSequentialStep("auto")}))
+	workflow.AddStep(model.NewWaitForSignalStep("analyst-review", "decision"))
+	workflow.AddStep(model.NewSequentialStep("apply"))
+	exec.register(t, workflow)
+
+	parked, err := local.ExecuteWorkflow(context.Background(), workflow.GetID(), map[string]interface{}{"amount": 500})
+	if err != nil || parked.Status != "waiting" {
+		t.Fatalf("ExecuteWorkflow() = %+v, %v, want the run parked", parked, err)
+	}
+	if err := exec.SignalExecution(context.Background(), parked.RunID, "decision", nil); err != nil {
+		t.Fatalf("SignalExecution() error = %v", err)
+	}
+
+	result, err := local.ResumeRun(context.Background(), parked.RunID, workflow.GetID())
+	if err != nil || result == nil || result.Status != "completed" {
+		t.Fatalf("ResumeRun() = %+v, %v, want completed", result, err)
+	}
+	if got := stepStatuses(result); got != "flag=completed,route=completed,auto=skipped,manual=completed,analyst-review=completed,apply=completed" {
+		t.Errorf("steps = %s, want the steps run before the park listed too", got)
+	}
+	if result.Steps[1].SelectedBranch != "then" || result.Steps[3].Branch != "route.then" {
+		t.Errorf("route = %+v, manual = %+v, want the then branch restored", result.Steps[1], result.Steps[3])
+	}
+}
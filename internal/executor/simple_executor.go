@@ -3,7 +3,6 @@ package executor
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"unified-workflow/internal/common/model"
 	"unified-workflow/internal/queue"
@@ -33,30 +32,7 @@ func NewSimpleExecutor(
 
 // SubmitWorkflow submits a workflow for execution and returns a run ID
 func (e *SimpleExecutor) SubmitWorkflow(ctx context.Context, workflow model.Workflow) (string, error) {
-	// Create a simple run ID
-	runID := fmt.Sprintf("run-%d", time.Now().UnixNano())
-
-	// Create execution request
-	executionReq := queue.ExecutionRequest{
-		RunID:       runID,
-		WorkflowID:  workflow.GetID(),
-		InputData:   make(map[string]interface{}),
-		RequestedAt: time.Now(),
-	}
-
-	// Marshal request
-	reqData, err := queue.MarshalExecutionRequest(executionReq)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal execution request: %w", err)
-	}
-
-	// Enqueue for execution
-	err = e.queue.Enqueue(ctx, runID, reqData)
-	if err != nil {
-		return "", fmt.Errorf("failed to enqueue workflow: %w", err)
-	}
-
-	return runID, nil
+	return e.SubmitWorkflowWithInput(ctx, workflow, nil)
 }
 
 // SubmitWorkflowByID submits a workflow by ID for execution and returns a run ID
@@ -70,22 +46,23 @@ func (e *SimpleExecutor) SubmitWorkflowByID(ctx context.Context, workflowID stri
 	return e.SubmitWorkflow(ctx, workflow)
 }
 
+// SubmitWorkflowWithInput persists a pending run with its input data and queues it for a worker
+func (e *SimpleExecutor) SubmitWorkflowWithInput(ctx context.Context, workflow model.Workflow, inputData map[string]interface{}) (string, error) {
+	runID := newRunID()
+	if err := submitRun(ctx, e.stateManagement, e.queue, runID, workflow.GetID(), inputData); err != nil {
+		return "", err
+	}
+	return runID, nil
+}
+
 // GetExecutionStatus gets the status of a workflow execution
 func (e *SimpleExecutor) GetExecutionStatus(ctx context.Context, runID string) (*ExecutionStatus, error) {
-	// For now, return a simple status
-	return &ExecutionStatus{
-		RunID:     runID,
-		Status:    "pending",
-		Progress:  0.0,
-		StartTime: nil,
-		EndTime:   nil,
-	}, nil
+	return loadExecutionStatus(ctx, e.stateManagement, runID)
 }
 
 // GetExecutionData gets the data of a workflow execution
 func (e *SimpleExecutor) GetExecutionData(ctx context.Context, runID string) (map[string]interface{}, error) {
-	// For now, return empty data
-	return make(map[string]interface{}), nil
+	return loadExecutionData(ctx, e.stateManagement, runID)
 }
 
 // ListExecutions lists workflow executions with optional filters
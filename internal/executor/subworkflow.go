@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"unified-workflow/internal/common/model"
+	workflowRegistry "unified-workflow/internal/registry"
+)
+
+// ErrRunCancelled is the cause of the context of a run cancelled through CancelExecution
+var ErrRunCancelled = errors.New("run cancelled")
+
+// maxSubWorkflowDepth bounds the nesting of sub-workflow runs, which also stops a workflow that invokes itself
+const maxSubWorkflowDepth = 8
+
+// subWorkflowDepthKey holds the nesting depth of the sub-workflow run of a context
+type subWorkflowDepthKey struct{}
+
+// runSubWorkflow runs the workflow of a sub-workflow step as a linked run and returns the child run ID
+// The child run executes within the context of its parent, so cancelling the parent cancels it as well
+func (e *WorkflowExecutor) runSubWorkflow(ctx context.Context, run *stepRun, step *model.SubWorkflowStep) (string, error) {
+	input, err := step.ChildInput(run.data)
+	if err != nil {
+		return "", err
+	}
+	childRunID, result, err := e.runChildWorkflow(ctx, run, step, input)
+	if err != nil {
+		return childRunID, err
+	}
+	return childRunID, step.StoreOutput(result, run.data)
+}
+
+// runChildWorkflow runs the workflow of a sub-workflow step with input as a run linked to run
+// It returns the child run ID and, when the child run completed, its result
+func (e *WorkflowExecutor) runChildWorkflow(ctx context.Context, run *stepRun, step *model.SubWorkflowStep, input map[string]interface{}) (string, map[string]interface{}, error) {
+	depth, _ := ctx.Value(subWorkflowDepthKey{}).(int)
+	if depth >= maxSubWorkflowDepth {
+		return "", nil, fmt.Errorf("sub-workflows are nested deeper than %d levels", maxSubWorkflowDepth)
+	}
+	workflow, err := e.resolveWorkflow(ctx, step.WorkflowID, step.Version)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get sub-workflow %s: %w", step.WorkflowID, err)
+	}
+
+	childRunID := newRunID()
+	parentRunID := e.linkChildRun(ctx, run, childRunID)
+	result, err := e.executeWorkflow(context.WithValue(ctx, subWorkflowDepthKey{}, depth+1), childRunID, workflow, step.Version, input, parentRunID, 0)
+	if err != nil {
+		return childRunID, nil, err
+	}
+	if result.Status != "completed" {
+		return childRunID, nil, fmt.Errorf("sub-workflow %s run %s %s: %s", workflow.GetID(), childRunID, result.Status, result.Error)
+	}
+	return childRunID, result.Result, nil
+}
+
+// resolveWorkflow gets a workflow from the registry, pinned to a version unless version is zero
+func (e *WorkflowExecutor) resolveWorkflow(ctx context.Context, workflowID string, version int) (model.Workflow, error) {
+	if version == 0 {
+		return e.workflowRegistry.GetWorkflow(ctx, workflowID)
+	}
+	versioned, ok := e.workflowRegistry.(workflowRegistry.VersionedRegistry)
+	if !ok {
+		return nil, fmt.Errorf("version %d requested but the registry does not keep workflow versions", version)
+	}
+	return versioned.GetWorkflowVersion(ctx, workflowID, version)
+}
+
+// latestWorkflow gets the latest version of a workflow from the registry, with its version number when the
+// registry keeps versions and zero otherwise
+func (e *WorkflowExecutor) latestWorkflow(ctx context.Context, workflowID string) (model.Workflow, int, error) {
+	if versioned, ok := e.workflowRegistry.(workflowRegistry.VersionedRegistry); ok {
+		return versioned.GetLatestWorkflow(ctx, workflowID)
+	}
+	workflow, err := e.workflowRegistry.GetWorkflow(ctx, workflowID)
+	return workflow, 0, err
+}
+
+// linkChildRun records a sub-workflow run in the context of its parent and returns the parent run ID
+func (e *WorkflowExecutor) linkChildRun(ctx context.Context, run *stepRun, childRunID string) string {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.runContext = run.runContext.WithChildRunID(childRunID)
+	e.saveRunContext(ctx, run.runContext)
+	return run.runContext.GetRunID()
+}
@@ -0,0 +1,208 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+// registerLimits registers a version of the limits workflow, which approves amounts up to limit
+func (e *testExecutor) registerLimits(t *testing.T, limit float64) {
+	t.Helper()
+	workflow := model.NewBaseWorkflow("limits", "limits workflow")
+	workflow.ID = "limits"
+	workflow.AddStep(typed.NewStep("check-limit", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		amount, _ := data.GetFloat("amount")
+		data.Put("approved", amount <= limit)
+		return nil
+	}))
+	e.register(t, workflow)
+}
+
+// paymentWorkflow creates a workflow running step followed by a step of its own
+func paymentWorkflow(step model.Step) *model.BaseWorkflow {
+	workflow := model.NewBaseWorkflow("payment", "payment workflow")
+	workflow.AddSteps([]model.Step{step, typed.NewStep("settle", succeed)})
+	return workflow
+}
+
+func TestSubWorkflowRunIsLinkedToParent(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.registerLimits(t, 1000)
+
+	step := model.NewSubWorkflowStep("limits", "limits")
+	step.Input = model.MustParseBindings(map[string]string{"amount": "payment.amount"})
+	step.Output = model.MustParseBindings(map[string]string{"limit_approved": "approved"})
+	result := exec.execute(t, paymentWorkflow(step), map[string]interface{}{"payment": map[string]interface{}{"amount": 800}})
+	if result.Status != "completed" {
+		t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+	}
+	if result.Result["limit_approved"] != true {
+		t.Errorf("result = %v, want the output of the child run bound", result.Result)
+	}
+
+	childRunID := result.Steps[0].ChildRunID
+	if childRunID == "" || childRunID == result.RunID {
+		t.Fatalf("child_run_id = %q, want a run of its own", childRunID)
+	}
+	if parent := exec.status(t, result.RunID); len(parent.ChildRunIDs) != 1 || parent.ChildRunIDs[0] != childRunID {
+		t.Errorf("child_run_ids = %v, want [%s]", parent.ChildRunIDs, childRunID)
+	}
+	child := exec.status(t, childRunID)
+	if child.ParentRunID != result.RunID || child.WorkflowID != "limits" || child.Status != "completed" {
+		t.Errorf("child run = %+v, want a completed run of limits linked to %s", child, result.RunID)
+	}
+}
+
+func TestSubWorkflowRunsPinnedVersion(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.registerLimits(t, 1000)
+	exec.registerLimits(t, 500)
+
+	for _, tc := range []struct {
+		version int
+		want    bool
+	}{
+		{version: 1, want: true},
+		{version: 2, want: false},
+		{version: 0, want: false},
+	} {
+		t.Run(fmt.Sprintf("version %d", tc.version), func(t *testing.T) {
+			step := model.NewSubWorkflowStep("limits", "limits").PinVersion(tc.version)
+			step.Input = model.MustParseBindings(map[string]string{"amount": "amount"})
+			result := exec.execute(t, paymentWorkflow(step), map[string]interface{}{"amount": 800})
+			if result.Status != "completed" {
+				t.Fatalf("status = %s %s, want completed", result.Status, result.Error)
+			}
+			output, _ := result.Result[model.ResultKey("limits")].(map[string]interface{})
+			if output["approved"] != tc.want {
+				t.Errorf("approved = %v, want %v", output["approved"], tc.want)
+			}
+		})
+	}
+}
+
+func TestSubWorkflowFailures(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.registerLimits(t, 1000)
+
+	for _, tc := range []struct {
+		name string
+		step func() *model.SubWorkflowStep
+		want string
+	}{
+		{
+			name: "missing version",
+			step: func() *model.SubWorkflowStep { return model.NewSubWorkflowStep("limits", "limits").PinVersion(3) },
+			want: "workflow version not found",
+		},
+		{
+			name: "missing workflow",
+			step: func() *model.SubWorkflowStep { return model.NewSubWorkflowStep("limits", "unknown") },
+			want: "failed to get sub-workflow unknown",
+		},
+		{
+			name: "input binding",
+			step: func() *model.SubWorkflowStep {
+				step := model.NewSubWorkflowStep("limits", "limits")
+				step.Input = model.MustParseBindings(map[string]string{"amount": "len(amount)"})
+				return step
+			},
+			want: "input of sub-workflow limits: binding amount: len needs a string, a list or an object",
+		},
+		{
+			name: "output binding",
+			step: func() *model.SubWorkflowStep {
+				step := model.NewSubWorkflowStep("limits", "limits")
+				step.Output = model.MustParseBindings(map[string]string{"limit_approved": "len(approved)"})
+				return step
+			},
+			want: "output of sub-workflow limits: binding limit_approved: len needs a string, a list or an object",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := exec.execute(t, paymentWorkflow(tc.step()), map[string]interface{}{"amount": 800})
+			if result.Status != "failed" {
+				t.Fatalf("status = %s, want failed", result.Status)
+			}
+			if step := result.Steps[0]; step.Status != "failed" || !strings.Contains(step.ErrorMessage, tc.want) {
+				t.Errorf("step = %s %q, want failed with %q", step.Status, step.ErrorMessage, tc.want)
+			}
+		})
+	}
+}
+
+func TestSubWorkflowRecursionIsBounded(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	recursive := model.NewBaseWorkflow("recursive", "invokes itself")
+	recursive.ID = "recursive"
+	recursive.AddStep(model.NewSubWorkflowStep("again", "recursive"))
+	result := exec.execute(t, recursive, nil)
+	if result.Status != "failed" {
+		t.Fatalf("status = %s, want failed", result.Status)
+	}
+	want := fmt.Sprintf("nested deeper than %d levels", maxSubWorkflowDepth)
+	if !strings.Contains(result.Steps[0].ErrorMessage, want) {
+		t.Errorf("error = %q, want %q", result.Steps[0].ErrorMessage, want)
+	}
+
+	// Each run up to the limit started exactly one child run
+	depth := 0
+	for runID := result.RunID; ; depth++ {
+		childRunIDs := exec.status(t, runID).ChildRunIDs
+		if len(childRunIDs) == 0 {
+			break
+		}
+		runID = childRunIDs[0]
+	}
+	if depth != maxSubWorkflowDepth {
+		t.Errorf("nested runs = %d, want %d", depth, maxSubWorkflowDepth)
+	}
+}
+
+func TestCancellingParentCancelsSubWorkflowRun(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	started := make(chan primitiveModel.WorkflowContext, 1)
+	blocking := model.NewBaseWorkflow("blocking", "waits until cancelled")
+	blocking.AddStep(typed.NewStep("wait", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		started <- workflowContext
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("not cancelled")
+		}
+	}))
+	exec.register(t, blocking)
+	parent := paymentWorkflow(model.NewSubWorkflowStep("child", blocking.GetID()))
+	exec.register(t, parent)
+
+	done := make(chan *ExecutionResult, 1)
+	go func() {
+		result, _ := exec.ExecuteWorkflow(context.Background(), parent.GetID(), nil)
+		done <- result
+	}()
+	childContext := <-started
+	if err := exec.CancelExecution(context.Background(), childContext.GetParentRunID()); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+
+	result := <-done
+	if result.Status != "cancelled" {
+		t.Errorf("parent status = %s, want cancelled", result.Status)
+	}
+	if got := stepStatuses(result); got != "child=cancelled" {
+		t.Errorf("steps = %s, want only the sub-workflow step, cancelled", got)
+	}
+	if child := exec.status(t, childContext.GetRunID()); child.Status != "cancelled" {
+		t.Errorf("child status = %s, want cancelled", child.Status)
+	}
+}
@@ -0,0 +1,168 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/metrics"
+)
+
+// ErrRunTimedOut is the cause of the context of a run that exceeded its run timeout
+var ErrRunTimedOut = errors.New("run timed out")
+
+// ErrStepTimedOut is the cause of the context of a step that exceeded its step timeout
+var ErrStepTimedOut = errors.New("step timed out")
+
+// abandonGracePeriod is how long a hook may keep running once its step or run is cancelled before it is abandoned
+const abandonGracePeriod = 100 * time.Millisecond
+
+// runTimeout returns the timeout of a run of workflow: its own, or else the execution timeout of the executor
+func (e *WorkflowExecutor) runTimeout(workflow model.Workflow) time.Duration {
+	if timed, ok := workflow.(model.TimedWorkflow); ok && timed.GetTimeout() > 0 {
+		return timed.GetTimeout()
+	}
+	return e.Config().ExecutionTimeout
+}
+
+// stepTimeout returns the timeout of an execution of step: its own, or else the step timeout of the executor
+func (e *WorkflowExecutor) stepTimeout(step model.Step) time.Duration {
+	if timed, ok := step.(model.TimedStep); ok && timed.GetTimeout() > 0 {
+		return timed.GetTimeout()
+	}
+	return e.Config().StepTimeout
+}
+
+// timedOut reports whether a step ended because its own timeout or the timeout of its run expired
+func timedOut(ctx context.Context, stepErr error) bool {
+	return errors.Is(stepErr, ErrStepTimedOut) || errors.Is(context.Cause(ctx), ErrRunTimedOut)
+}
+
+// runDeadline cancels a run once it has been running for its timeout
+// Time the run spends parked, waiting for a signal or sleeping on a timer, does not count
+type runDeadline struct {
+	mu        sync.Mutex
+	timer     *time.Timer
+	remaining time.Duration
+	since     time.Time
+	parked    int
+	expired   bool
+}
+
+// startRunDeadline arms the deadline of a run, cancelling it with ErrRunTimedOut once remaining of its timeout
+// has passed; a resumed run has only what was left when it parked. It returns nil without a timeout
+func startRunDeadline(timeout, remaining time.Duration, cancel context.CancelCauseFunc) *runDeadline {
+	if timeout <= 0 {
+		return nil
+	}
+	if remaining <= 0 || remaining > timeout {
+		remaining = timeout
+	}
+	d := &runDeadline{remaining: remaining, since: time.Now()}
+	d.timer = time.AfterFunc(remaining, func() {
+		d.mu.Lock()
+		d.expired = true
+		d.mu.Unlock()
+		cancel(fmt.Errorf("%w after %s", ErrRunTimedOut, timeout))
+	})
+	return d
+}
+
+// park stops the clock of the run while one of its steps waits
+func (d *runDeadline) park() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.parked++; d.parked == 1 && !d.expired && d.timer.Stop() {
+		d.remaining -= time.Since(d.since)
+	}
+}
+
+// unpark restarts the clock of the run once none of its steps waits anymore
+func (d *runDeadline) unpark() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.parked--; d.parked == 0 && !d.expired {
+		d.since = time.Now()
+		d.timer.Reset(max(d.remaining, 0))
+	}
+}
+
+// left returns how much of its timeout the run has left, or zero without a timeout
+func (d *runDeadline) left() time.Duration {
+	if d == nil {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case d.expired:
+		return 0
+	case d.parked > 0:
+		return d.remaining
+	}
+	return max(d.remaining-time.Since(d.since), 0)
+}
+
+// stop disarms the deadline of a run that finished
+func (d *runDeadline) stop() {
+	if d == nil {
+		return
+	}
+	d.timer.Stop()
+}
+
+// callHook runs a hook or the logic of a step, returning once it does or once ctx is cancelled and the hook
+// has not returned within abandonGracePeriod; an abandoned hook keeps running, counted by HooksLeaked until it returns
+// A hook that panics fails with an error rather than crashing the process
+func callHook[T any](ctx context.Context, workflowID, step string, hook func() (T, error)) (T, error) {
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.Error(ctx, "Hook panicked", "panic", r, "stack", string(debug.Stack()))
+				done <- outcome{err: fmt.Errorf("%s panicked: %v", step, r)}
+			}
+		}()
+		value, err := hook()
+		done <- outcome{value: value, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-ctx.Done():
+	}
+	grace := time.NewTimer(abandonGracePeriod)
+	defer grace.Stop()
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-grace.C:
+	}
+
+	cause := context.Cause(ctx)
+	logging.Warn(ctx, "Abandoned hook ignoring cancellation", "grace", abandonGracePeriod, "cause", cause)
+	metrics.HooksAbandoned.Inc(workflowID, step)
+	metrics.HooksLeaked.Inc(workflowID)
+	go func() {
+		<-done
+		metrics.HooksLeaked.Dec(workflowID)
+	}()
+	var zero T
+	return zero, fmt.Errorf("abandoned after ignoring cancellation: %w", cause)
+}
@@ -0,0 +1,223 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	"unified-workflow/internal/metrics"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+func TestStepTimeoutCancelsHookContext(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	undone := make(chan string, 1)
+	reserve := typed.NewStep("reserve", succeed)
+	reserve.SetCompensation(model.NewCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		undone <- "reserve"
+		return nil
+	}))
+	screen := model.NewSequentialStep("aml-check")
+	screen.AddChildStep(model.NewChildStep("call-aml", func(context interface{}, data interface{}) interface{} {
+		ctx := model.HookContext(context)
+		<-ctx.Done()
+		return ctx.Err()
+	}, nil, nil))
+	screen.SetTimeout(20 * time.Millisecond)
+	workflow := model.NewBaseWorkflow("screening", "screens with a slow AML service")
+	workflow.AddSteps([]model.Step{reserve, screen, model.NewSequentialStep("approve")})
+
+	result := exec.execute(t, workflow, nil)
+	if result.Status != "timed_out" || !strings.Contains(result.Error, "step timed out after 20ms") {
+		t.Fatalf("status = %s %s, want timed_out by the step timeout", result.Status, result.Error)
+	}
+	if got := <-undone; got != "reserve" || result.CompensationStatus != "compensated" {
+		t.Errorf("compensated %s with status %s, want reserve compensated", got, result.CompensationStatus)
+	}
+
+	status := exec.status(t, result.RunID)
+	if status.Status != "timed_out" || status.StepStates["aml-check"] != "timed_out" || status.StepStates["approve"] != "" {
+		t.Errorf("execution status = %+v, want aml-check timed out and approve not run", status)
+	}
+}
+
+func TestRunTimeoutExcludesWaiting(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	execute := func(workflow *model.BaseWorkflow) *ExecutionResult {
+		workflow.SetTimeout(50 * time.Millisecond)
+		return exec.execute(t, workflow, nil)
+	}
+	work := func(name string) model.Step {
+		return typed.NewStep(name, func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+			select {
+			case <-time.After(30 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+
+	// Waiting for a signal longer than the run timeout does not time the run out
+	waiting := model.NewBaseWorkflow("review", "waits for a decision")
+	waiting.AddSteps([]model.Step{
+		work("score"),
+		model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(80*time.Millisecond, model.NewSequentialStep("escalate")),
+	})
+	if result := execute(waiting); result.Status != "completed" {
+		t.Errorf("waiting run status = %s %s, want completed", result.Status, result.Error)
+	}
+
+	busy := model.NewBaseWorkflow("screening", "screens twice")
+	busy.AddSteps([]model.Step{work("aml"), work("fc"), work("ml")})
+	result := execute(busy)
+	if result.Status != "timed_out" || !strings.Contains(result.Error, "run timed out after 50ms") {
+		t.Fatalf("busy run status = %s %s, want timed_out by the run timeout", result.Status, result.Error)
+	}
+	if got := stepStatuses(result); got != "aml=completed,fc=timed_out" {
+		t.Errorf("steps = %s, want fc timed out and ml not run", got)
+	}
+}
+
+func TestAbandonedHookIsCounted(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	release := make(chan struct{})
+	stubborn := model.NewSequentialStep("aml-check")
+	stubborn.AddChildStep(model.NewChildStep("call-aml", func(context interface{}, data interface{}) interface{} {
+		<-release
+		return nil
+	}, nil, nil))
+	stubborn.SetTimeout(20 * time.Millisecond)
+	workflow := model.NewBaseWorkflow("screening", "screens with a hook ignoring cancellation")
+	workflow.AddStep(stubborn)
+
+	if result := exec.execute(t, workflow, nil); result.Status != "timed_out" {
+		t.Fatalf("status = %s %s, want timed_out", result.Status, result.Error)
+	}
+
+	exposition := func() string {
+		var exposition strings.Builder
+		if err := metrics.Default.WriteText(&exposition); err != nil {
+			t.Fatal(err)
+		}
+		return exposition.String()
+	}
+	for _, want := range []string{
+		`uwf_hooks_abandoned_total{workflow_id="` + workflow.GetID() + `",step="call-aml"} 1`,
+		`uwf_hooks_leaked{workflow_id="` + workflow.GetID() + `"} 1`,
+	} {
+		if !strings.Contains(exposition(), want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+
+	// The leak is released once the hook returns
+	close(release)
+	want := `uwf_hooks_leaked{workflow_id="` + workflow.GetID() + `"} 0`
+	for deadline := time.Now().Add(2 * time.Second); !strings.Contains(exposition(), want); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("metrics do not contain %s", want)
+		}
+	}
+}
+
+func TestPanickingStepFailsRun(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	undone := make(chan string, 1)
+	reserve := typed.NewStep("reserve", succeed)
+	reserve.SetCompensation(model.NewCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		undone <- "reserve"
+		return nil
+	}))
+	screen := model.NewSequentialStep("aml-check")
+	screen.AddChildStep(model.NewChildStep("call-aml", func(context interface{}, data interface{}) interface{} {
+		var client map[string]string
+		client["endpoint"] = "aml"
+		return nil
+	}, nil, nil))
+	score := typed.NewStep("score", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		panic("score model not loaded")
+	})
+
+	for _, tc := range []struct {
+		step model.Step
+		want string
+	}{
+		{screen, "call-aml panicked: assignment to entry in nil map"},
+		{score, "score panicked: score model not loaded"},
+	} {
+		workflow := model.NewBaseWorkflow("screening", "screens with a broken step")
+		workflow.AddSteps([]model.Step{reserve, tc.step})
+		result := exec.execute(t, workflow, nil)
+		if result.Status != "failed" || !strings.Contains(fmt.Sprintf("%+v", result.Steps), tc.want) {
+			t.Errorf("status = %s with steps %+v, want failed with %q", result.Status, result.Steps, tc.want)
+		}
+		if got := <-undone; got != "reserve" {
+			t.Errorf("compensated %s, want reserve", got)
+		}
+	}
+}
+
+func TestResumedRunKeepsRemainingTimeout(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	work := func(name string) model.Step {
+		return typed.NewStep(name, func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+			select {
+			case <-time.After(100 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+	workflow := model.NewBaseWorkflow("rescore", "scores twice around a cool-off")
+	workflow.AddSteps([]model.Step{work("score"), model.NewTimerStep("cool-off", 50*time.Millisecond), work("rescore")})
+	workflow.SetTimeout(150 * time.Millisecond)
+	runID := exec.submit(t, workflow, nil)
+
+	// The run resumes with the 50ms it had left, not with a fresh timeout
+	result, err := exec.WaitForResult(context.Background(), runID, 5*time.Second)
+	if err != nil || result == nil || result.Status != "timed_out" || !strings.Contains(result.Error, "run timed out after 150ms") {
+		t.Fatalf("WaitForResult() = %+v, %v, want timed_out by the run timeout", result, err)
+	}
+	if status := exec.status(t, runID); status.StepStates["score"] != "completed" || status.StepStates["rescore"] != "timed_out" {
+		t.Errorf("status = %+v, want rescore timed out", status)
+	}
+}
+
+func TestSubWorkflowStepTimeout(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	screening := model.NewBaseWorkflow("screening", "screens with a slow AML service")
+	screening.AddStep(typed.NewStep("call-aml", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	}))
+	exec.register(t, screening)
+
+	screen := model.NewSubWorkflowStep("screen", screening.GetID())
+	screen.SetTimeout(20 * time.Millisecond)
+	workflow := model.NewBaseWorkflow("onboarding", "screens the customer")
+	workflow.AddSteps([]model.Step{screen, model.NewSequentialStep("approve")})
+
+	result := exec.execute(t, workflow, nil)
+	if result.Status != "timed_out" || !strings.Contains(result.Error, "step timed out after 20ms") {
+		t.Fatalf("status = %s %s, want timed_out by the step timeout", result.Status, result.Error)
+	}
+	if got := stepStatuses(result); got != "screen=timed_out" {
+		t.Errorf("steps = %s, want the sub-workflow step timed out and approve not run", got)
+	}
+	if child := exec.status(t, result.Steps[0].ChildRunID); !child.IsTerminal || child.Status == "completed" {
+		t.Errorf("child run = %+v, want stopped with its step", child)
+	}
+}
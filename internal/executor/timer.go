@@ -0,0 +1,275 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
+	primitiveModel "unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+	"unified-workflow/internal/timer"
+)
+
+// resumeLockTimeout bounds the lock taken to claim a sleeping run for resumption
+const resumeLockTimeout = 30 * time.Second
+
+// SetTimers makes the timer steps of top-level runs durable: the run is persisted with its wake-up time,
+// its worker is released, and the timer service resumes it through the queue once the timer fires
+// Without a timer service runs sleep in place
+func (e *WorkflowExecutor) SetTimers(timers *timer.Service) {
+	e.timers = timers
+}
+
+// StartTimers makes the timer steps of the runs executed by local durable, resuming them through q
+// Timers kept in stateManagement by an earlier process fire as they come due. The returned function stops them
+func StartTimers(ctx context.Context, q queue.Queue, stateManagement state.StateManagement, local *WorkflowExecutor) (func(), error) {
+	timers := timer.NewService(stateManagement, q)
+	stop, err := timers.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	local.SetTimers(timers)
+	return stop, nil
+}
+
+// runTimer pauses the run for the duration of a timer step
+// A durable timer persists the run and reports false, stopping the steps so executeWorkflow hands the run over
+// to the timer service; otherwise, or when the timer cannot be scheduled, the run sleeps in place
+func (e *WorkflowExecutor) runTimer(ctx context.Context, run *stepRun, step *model.TimerStep, stepResult StepExecutionResult) bool {
+	wakeAt := time.Now().Add(step.Duration)
+	if resumeStep := run.resumeStep(step, stepResult.Branch); resumeStep > 0 {
+		if e.sleep(ctx, run, step, stepResult, wakeAt, resumeStep) {
+			return false
+		}
+	}
+
+	// Sleeping does not count towards the run timeout
+	logging.Info(ctx, "Sleeping", "duration", step.Duration)
+	run.deadline.park()
+	defer run.deadline.unpark()
+	timer := time.NewTimer(step.Duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return e.finishStep(ctx, run, stepResult, nil, nil)
+	case <-ctx.Done():
+		return e.finishStep(ctx, run, stepResult, nil, context.Cause(ctx))
+	}
+}
+
+// sleep persists a run sleeping on a timer step and schedules its timer; it reports whether the run sleeps
+func (e *WorkflowExecutor) sleep(ctx context.Context, run *stepRun, step *model.TimerStep, stepResult StepExecutionResult, wakeAt time.Time, resumeStep int) bool {
+	run.mu.Lock()
+	runID := run.runContext.GetRunID()
+	run.mu.Unlock()
+	if err := e.stateManagement.SaveData(ctx, runID, workflowDataFromMap(plainData(run.data.ToMap()))); err != nil {
+		logging.Warn(ctx, "Failed to save run data, sleeping in place", "error", err)
+		return false
+	}
+
+	// The run must be persisted as sleeping before its timer can fire
+	run.mu.Lock()
+	previous := run.runContext
+	run.runContext = previous.
+		WithStatus(primitiveModel.WorkflowStatusSleeping).
+		WithStepState(stepStateKey(step.GetName(), stepResult.Branch), primitiveModel.StepStatusSleeping).
+		WithTimer(wakeAt, resumeStep).
+		WithRemainingTimeout(run.deadline.left())
+	e.saveRunContext(ctx, run.runContext)
+	run.mu.Unlock()
+
+	err := e.timers.Schedule(ctx, timer.Timer{
+		RunID:      runID,
+		WorkflowID: run.workflowID,
+		Tenant:     tenant.FromContext(ctx),
+		WakeAt:     wakeAt,
+	})
+	if err != nil {
+		logging.Warn(ctx, "Failed to schedule timer, sleeping in place", "error", err)
+		run.mu.Lock()
+		run.runContext = previous
+		e.saveRunContext(ctx, run.runContext)
+		run.mu.Unlock()
+		return false
+	}
+
+	stepResult.Status = primitiveModel.StepStatusSleeping
+	run.mu.Lock()
+	run.parked = statusName(primitiveModel.WorkflowStatusSleeping)
+	run.results = append(run.results, stepResult)
+	run.mu.Unlock()
+	logging.Info(ctx, "Run sleeping until its timer fires", "duration", step.Duration, "wake_at", wakeAt)
+	return true
+}
+
+// ResumeRun resumes a run that slept on a timer step once its timer fired, at the step after the timer, or a run
+// parked on a wait step once its signal arrived or its timeout expired, at the wait step
+// It returns nil when the run is not parked anymore, as when it was cancelled or already resumed
+func (e *WorkflowExecutor) ResumeRun(ctx context.Context, runID, workflowID string) (*ExecutionResult, error) {
+	return e.resumeRun(logging.WithRun(ctx, runID, workflowID), runID)
+}
+
+// resumeRun claims a parked run and executes its remaining steps; a run flagged as cancelled resumes already
+// cancelled, so it stops at once and compensates the steps that completed before it was parked
+func (e *WorkflowExecutor) resumeRun(ctx context.Context, runID string) (*ExecutionResult, error) {
+	if e.stateManagement == nil {
+		return nil, nil
+	}
+	runContext, resumeStep, err := e.claimParkedRun(ctx, runID)
+	if err != nil || runContext == nil {
+		return nil, err
+	}
+	workflowID := runContext.GetWorkflowDefinitionID()
+	if e.timers != nil {
+		// The run resumed, so its timer does not fire again; should the run park again it arms a new one
+		if err := e.timers.Remove(ctx, tenant.FromContext(ctx), runID); err != nil {
+			logging.Warn(ctx, "Failed to remove timer of resumed run", "error", err)
+		}
+	}
+
+	// The run resumes with the version of the workflow it parked in, as its resume step indexes the steps of that version
+	version := runContext.GetWorkflowVersion()
+	workflow, err := e.resolveWorkflow(ctx, workflowID, version)
+	if err != nil {
+		return nil, e.failRun(ctx, runID, workflowID, fmt.Errorf("failed to get workflow %s: %w", workflowID, err))
+	}
+	data, err := loadExecutionData(ctx, e.stateManagement, runID)
+	if err != nil {
+		return nil, e.failRun(ctx, runID, workflowID, err)
+	}
+
+	if e.cancellationRequested(ctx, runID) {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		cancel(ErrRunCancelled)
+	}
+	return e.executeWorkflow(ctx, runID, workflow, version, data, runContext.GetParentRunID(), resumeStep)
+}
+
+// claimParkedRun marks a parked run as running again and returns its context and the step it resumes at
+// It returns a nil context when the run is not parked or another resumption holds its lock
+func (e *WorkflowExecutor) claimParkedRun(ctx context.Context, runID string) (primitiveModel.WorkflowContext, int, error) {
+	locked, err := e.stateManagement.AcquireLock(ctx, runID, resumeLockTimeout)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to lock run %s: %w", runID, err)
+	}
+	if !locked {
+		return nil, 0, nil
+	}
+	defer e.stateManagement.ReleaseLock(ctx, runID)
+
+	runContext, err := e.stateManagement.GetContext(ctx, runID)
+	if err != nil {
+		if err == state.ErrStateNotFound {
+			return nil, 0, fmt.Errorf("execution %s not found: %w", runID, err)
+		}
+		return nil, 0, fmt.Errorf("failed to get execution %s: %w", runID, err)
+	}
+	if !parkedContext(runContext) {
+		return nil, 0, nil
+	}
+	// A timer firing again after its run resumed must not wake the run sleeping on a later timer
+	if wakeAt := runContext.GetWakeAt(); runContext.GetStatus() == primitiveModel.WorkflowStatusSleeping && wakeAt != nil &&
+		time.Now().Before(*wakeAt) && !e.cancellationRequested(ctx, runID) {
+		return nil, 0, nil
+	}
+
+	resumeStep := runContext.GetResumeStep()
+	runContext = runContext.
+		WithStatus(primitiveModel.WorkflowStatusRunning).
+		WithoutTimer()
+	if err := e.stateManagement.SaveContext(ctx, runContext); err != nil {
+		return nil, 0, fmt.Errorf("failed to save run %s: %w", runID, err)
+	}
+	return runContext, resumeStep, nil
+}
+
+// restoreCompensations registers the compensations of the steps that completed before a run was parked, so they
+// are undone should the resumed run fail or be cancelled
+func (run *stepRun) restoreCompensations(steps []model.Step, workflowContext primitiveModel.WorkflowContext) {
+	for _, step := range steps {
+		if run.stepState(step.GetName()) != primitiveModel.StepStatusCompleted {
+			continue
+		}
+		childStepResults := make([]ChildStepExecutionResult, step.GetChildStepCount())
+		for i := range childStepResults {
+			childStepResults[i].Status = primitiveModel.StepStatusCompleted
+		}
+		run.registerChildStepCompensations(step, "", childStepResults, workflowContext)
+		run.registerStepCompensation(step, "", workflowContext)
+	}
+}
+
+// restoreResults rebuilds from the step states of the run context the results of the steps that ran before a
+// run was parked, so the result of the resumed run lists them as well; the steps of a branch follow their branch
+// step, ordered by state key
+func (run *stepRun) restoreResults(steps []model.Step) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	states := run.runContext.GetStepStates()
+	for i, step := range steps {
+		name := step.GetName()
+		state, ok := states[name]
+		if !ok {
+			continue
+		}
+		result := StepExecutionResult{StepIndex: i, Name: name, Status: state}
+
+		// The steps of a branch are stored as "<branch step>.<branch>.<step>"
+		var nested []string
+		for key := range states {
+			if strings.HasPrefix(key, name+".") {
+				nested = append(nested, key)
+			}
+		}
+		sort.Strings(nested)
+		for _, key := range nested {
+			if states[key] != primitiveModel.StepStatusSkipped {
+				result.SelectedBranch, _, _ = strings.Cut(strings.TrimPrefix(key, name+"."), ".")
+				break
+			}
+		}
+		run.results = append(run.results, result)
+		for _, key := range nested {
+			cut := strings.LastIndex(key, ".")
+			run.results = append(run.results, StepExecutionResult{Name: key[cut+1:], Status: states[key], Branch: key[:cut]})
+		}
+	}
+}
+
+// cancelParkedRun cancels a run sleeping on a timer or parked on a wait step, flagged as cancelled, by resuming it
+// through the queue, or here without one
+func (e *WorkflowExecutor) cancelParkedRun(ctx context.Context, runID, workflowID string) error {
+	if e.queue != nil {
+		if err := e.enqueueResume(ctx, runID, workflowID); err != nil {
+			return fmt.Errorf("run %s flagged as cancelled, but could not be resumed: %w", runID, err)
+		}
+		return nil
+	}
+	go func() {
+		ctx := logging.WithRun(context.WithoutCancel(ctx), runID, workflowID)
+		if _, err := e.resumeRun(ctx, runID); err != nil {
+			logging.Warn(ctx, "Failed to cancel parked run", "error", err)
+		}
+	}()
+	return nil
+}
+
+// parkedContext reports whether a run is sleeping on a durable timer or parked on a wait step
+// A run waiting in place on a step is executing somewhere and is not parked
+func parkedContext(runContext primitiveModel.WorkflowContext) bool {
+	switch runContext.GetStatus() {
+	case primitiveModel.WorkflowStatusSleeping:
+		return true
+	case primitiveModel.WorkflowStatusWaiting:
+		return runContext.GetResumeStep() > 0
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	primitiveModel "unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+)
+
+// startTimerWorker starts a worker draining the queue whose timer steps are durable
+func (e *testExecutor) startTimerWorker(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	local := e.newExecutor()
+	stopTimers, err := StartTimers(ctx, e.queue, e.stateMgmt, local)
+	if err != nil {
+		t.Fatalf("StartTimers() error = %v", err)
+	}
+	stopRouting, err := StartResultRouting(ctx, e.queue, e.stateMgmt, local, e.hub)
+	if err != nil {
+		t.Fatalf("StartResultRouting() error = %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		stopRouting()
+		stopTimers()
+	})
+}
+
+// submit registers workflow and queues a run of it with input
+func (e *testExecutor) submit(t *testing.T, workflow model.Workflow, input map[string]interface{}) string {
+	t.Helper()
+	e.register(t, workflow)
+	runID, err := e.SubmitWorkflowWithInput(context.Background(), workflow, input)
+	if err != nil {
+		t.Fatalf("SubmitWorkflowWithInput() error = %v", err)
+	}
+	return runID
+}
+
+func TestTimerStepSleepsAndResumesRun(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	var scored atomic.Int32
+	workflow := model.NewBaseWorkflow("rescore", "checks the ML score again after a cool-off")
+	workflow.AddStep(typed.NewStep("score", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		data.Put("score", int(scored.Add(1)))
+		return nil
+	}))
+	workflow.AddStep(model.NewTimerStep("cool-off", 200*time.Millisecond))
+	workflow.AddStep(typed.NewStep("rescore", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		data.Put("rescored", true)
+		return nil
+	}))
+	runID := exec.submit(t, workflow, map[string]interface{}{"transaction_id": "tx-1"})
+
+	status := exec.awaitStatus(t, runID, "sleeping")
+	if status.IsTerminal || status.WakeAt == nil || status.StepStates["cool-off"] != "sleeping" {
+		t.Errorf("status = %+v, want a sleeping run with its wake-up time", status)
+	}
+
+	result, err := exec.WaitForResult(context.Background(), runID, 5*time.Second)
+	if err != nil || result == nil || result.Status != "completed" {
+		t.Fatalf("WaitForResult() = %+v, %v, want completed", result, err)
+	}
+	if result.OutputData["transaction_id"] != "tx-1" || result.OutputData["rescored"] != true {
+		t.Errorf("result data = %v, want the data saved before the timer and the rescore", result.OutputData)
+	}
+	if score, _ := primitiveModel.WrapWorkflowData(result.OutputData).GetFloat("score"); score != 1 || scored.Load() != 1 {
+		t.Errorf("score ran %d times with result %v, want once", scored.Load(), result.OutputData["score"])
+	}
+
+	status = exec.status(t, runID)
+	if status.StepStates["cool-off"] != "completed" || status.StepStates["rescore"] != "completed" || status.WakeAt != nil {
+		t.Errorf("status = %+v, want the timer and the steps after it completed", status)
+	}
+	if timers, _ := exec.stateMgmt.ListRecords(context.Background(), "timers"); len(timers) != 0 {
+		t.Errorf("timers = %v, want the timer removed once the run resumed", timers)
+	}
+}
+
+func TestCancelSleepingRunCompensates(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	undone := make(chan string, 1)
+	reserve := typed.NewStep("reserve", succeed)
+	reserve.SetCompensation(model.NewCompensation(func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		undone <- "reserve"
+		return nil
+	}))
+	workflow := model.NewBaseWorkflow("hold", "reserves, then waits an hour")
+	workflow.AddSteps([]model.Step{reserve, model.NewTimerStep("cool-off", time.Hour), model.NewSequentialStep("release")})
+	runID := exec.submit(t, workflow, nil)
+	exec.awaitStatus(t, runID, "sleeping")
+
+	if err := exec.CancelExecution(context.Background(), runID); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	status := exec.awaitStatus(t, runID, "cancelled")
+	if got := <-undone; got != "reserve" || status.CompensationStatus != "compensated" {
+		t.Errorf("compensated %s with status %s, want reserve compensated", got, status.CompensationStatus)
+	}
+	if state := status.StepStates["release"]; state != "cancelled" {
+		t.Errorf("release state = %v, want cancelled", state)
+	}
+}
+
+func TestCancelThroughAnotherProcess(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	started := make(chan struct{})
+	workflow := model.NewBaseWorkflow("screening", "screens with a slow AML service")
+	workflow.AddStep(typed.NewStep("aml-check", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		close(started)
+		<-ctx.Done()
+		return context.Cause(ctx)
+	}))
+	runID := exec.submit(t, workflow, nil)
+	<-started
+
+	// The run executes on the worker; exec only reaches it through the state store
+	if err := exec.CancelExecution(context.Background(), runID); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	result, err := exec.WaitForResult(context.Background(), runID, 5*time.Second)
+	if err != nil || result == nil || result.Status != "cancelled" {
+		t.Fatalf("WaitForResult() = %+v, %v, want cancelled", result, err)
+	}
+	if records, _ := exec.stateMgmt.ListRecords(context.Background(), cancellationNamespace); len(records) != 0 {
+		t.Errorf("cancellation records = %v, want none left once the run finished", records)
+	}
+
+	if err := exec.CancelExecution(context.Background(), runID); !errors.Is(err, ErrRunFinished) {
+		t.Errorf("cancel of a finished run error = %v, want ErrRunFinished", err)
+	}
+	if err := exec.CancelExecution(context.Background(), "run-unknown"); !errors.Is(err, state.ErrStateNotFound) {
+		t.Errorf("cancel of an unknown run error = %v, want ErrStateNotFound", err)
+	}
+}
+
+func TestCancelIsScopedToTenant(t *testing.T) {
+	exec := newTestExecutor(t)
+
+	started := make(chan string, 1)
+	workflow := model.NewBaseWorkflow("screening", "screens with a slow AML service")
+	workflow.AddStep(typed.NewStep("aml-check", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		started <- workflowContext.GetRunID()
+		<-ctx.Done()
+		return context.Cause(ctx)
+	}))
+	acme := tenant.WithTenant(context.Background(), "acme")
+	if err := exec.registry.RegisterWorkflow(acme, workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	done := make(chan *ExecutionResult, 1)
+	go func() {
+		result, err := exec.ExecuteWorkflow(acme, workflow.GetID(), nil)
+		if result == nil {
+			t.Errorf("ExecuteWorkflow() error = %v", err)
+			close(started)
+		}
+		done <- result
+	}()
+	runID := <-started
+
+	if err := exec.CancelExecution(tenant.WithTenant(context.Background(), "globex"), runID); !errors.Is(err, state.ErrStateNotFound) {
+		t.Errorf("cancel from another tenant error = %v, want ErrStateNotFound", err)
+	}
+	if err := exec.CancelExecution(acme, runID); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	if result := <-done; result == nil || result.Status != "cancelled" {
+		t.Errorf("result = %+v, want cancelled by its own tenant only", result)
+	}
+}
+
+func TestResumedRunKeepsWorkflowVersion(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.startTimerWorker(t)
+
+	put := func(name string) model.Step {
+		return typed.NewStep(name, func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+			data.Put(name, true)
+			return nil
+		})
+	}
+	v1 := model.NewBaseWorkflow("rescore", "checks the ML score again after a cool-off")
+	v1.AddSteps([]model.Step{put("score"), model.NewTimerStep("cool-off", 200*time.Millisecond), put("rescore")})
+	runID := exec.submit(t, v1, nil)
+	exec.awaitStatus(t, runID, "sleeping")
+
+	// The workflow changes while the run sleeps
+	v2 := model.NewBaseWorkflow("rescore", "scores once")
+	v2.ID = v1.GetID()
+	v2.AddSteps([]model.Step{put("screen"), put("score"), put("approve")})
+	exec.register(t, v2)
+
+	result, err := exec.WaitForResult(context.Background(), runID, 5*time.Second)
+	if err != nil || result == nil || result.Status != "completed" {
+		t.Fatalf("WaitForResult() = %+v, %v, want completed", result, err)
+	}
+	if result.OutputData["rescore"] != true || result.OutputData["approve"] != nil {
+		t.Errorf("result data = %v, want the run resumed at the step after the timer of the version it started with", result.OutputData)
+	}
+}
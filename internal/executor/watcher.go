@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/logging"
+)
+
+// runWatchInterval is how often the state store is checked for what other processes requested of the runs executing here
+const runWatchInterval = time.Second
+
+// runWatcher tracks the runs executing here, so that a single goroutine per executor checks the state store for all
+// of them in one batch rather than each run polling it on its own
+type runWatcher struct {
+	mu       sync.Mutex
+	runs     map[string]watchedRun // by tenant and run ID
+	watching bool
+}
+
+// watchedRun is a run executing here
+type watchedRun struct {
+	ctx    context.Context
+	runID  string
+	cancel context.CancelCauseFunc
+}
+
+// add tracks a run; it reports whether the watching goroutine is to be started
+func (w *runWatcher) add(key string, run watchedRun) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.runs == nil {
+		w.runs = make(map[string]watchedRun)
+	}
+	w.runs[key] = run
+	start := !w.watching
+	w.watching = true
+	return start
+}
+
+// remove stops tracking a run
+func (w *runWatcher) remove(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.runs, key)
+}
+
+// snapshot returns the runs tracked; once there are none the watching goroutine is to stop
+func (w *runWatcher) snapshot() map[string]watchedRun {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.runs) == 0 {
+		w.watching = false
+		return nil
+	}
+	runs := make(map[string]watchedRun, len(w.runs))
+	for key, run := range w.runs {
+		runs[key] = run
+	}
+	return runs
+}
+
+// watchRun has the state store checked for a cancellation of or a signal to a run executing here until unwatchRun
+func (e *WorkflowExecutor) watchRun(ctx context.Context, runID string, cancel context.CancelCauseFunc) {
+	if e.stateManagement == nil {
+		return
+	}
+	if e.watcher.add(runKey(ctx, runID), watchedRun{ctx: ctx, runID: runID, cancel: cancel}) {
+		go e.watchRuns()
+	}
+}
+
+// unwatchRun stops checking the state store for a run that no longer executes here
+func (e *WorkflowExecutor) unwatchRun(ctx context.Context, runID string) {
+	e.watcher.remove(runKey(ctx, runID))
+}
+
+// watchRuns checks the state store for the runs executing here every runWatchInterval, until none is left
+func (e *WorkflowExecutor) watchRuns() {
+	ticker := time.NewTicker(runWatchInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		runs := e.watcher.snapshot()
+		if runs == nil {
+			return
+		}
+		keys := make([]string, 0, len(runs))
+		for key := range runs {
+			keys = append(keys, key)
+		}
+		e.checkCancellations(runs, keys)
+		e.checkSignals(runs, keys)
+	}
+}
+
+// checkCancellations cancels the runs flagged as cancelled in the state store, looking all of them up at once
+func (e *WorkflowExecutor) checkCancellations(runs map[string]watchedRun, keys []string) {
+	ctx := context.Background()
+	cancelled, err := e.stateManagement.GetRecords(ctx, cancellationNamespace, keys)
+	if err != nil {
+		logging.Warn(ctx, "Failed to check for cancellations", "error", err)
+		return
+	}
+	for key := range cancelled {
+		run := runs[key]
+		logging.Info(run.ctx, "Cancellation requested through another process")
+		run.cancel(ErrRunCancelled)
+	}
+}
+
+// checkSignals wakes the wait steps of the runs sent a signal they wait for through another process, looking all of
+// them up at once
+func (e *WorkflowExecutor) checkSignals(runs map[string]watchedRun, keys []string) {
+	ctx := context.Background()
+	records, err := e.stateManagement.GetRecords(ctx, signalNamespace, keys)
+	if err != nil {
+		logging.Warn(ctx, "Failed to check for signals", "error", err)
+		return
+	}
+	for key, data := range records {
+		run := runs[key]
+		record := &signalRecord{}
+		if err := json.Unmarshal(data, record); err != nil {
+			logging.Warn(run.ctx, "Failed to unmarshal signals", "error", err)
+			continue
+		}
+		if record.awaited() {
+			e.signals.notify(run.runID)
+		}
+	}
+}
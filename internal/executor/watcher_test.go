@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	primitiveModel "unified-workflow/internal/primitive/model"
+)
+
+func TestWatcherCancelsOnlyFlaggedRuns(t *testing.T) {
+	exec := newTestExecutor(t)
+	worker := exec.newExecutor()
+
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	workflow := model.NewBaseWorkflow("screening", "screens with a slow AML service")
+	workflow.AddStep(typed.NewStep("aml-check", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		started <- workflowContext.GetRunID()
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-release:
+			return nil
+		}
+	}))
+	exec.register(t, workflow)
+
+	results := make(chan *ExecutionResult, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			result, _ := worker.ExecuteWorkflow(context.Background(), workflow.GetID(), nil)
+			results <- result
+		}()
+	}
+	cancelled, kept := <-started, <-started
+
+	// Both runs execute on the worker; exec only reaches them through the state store
+	if err := exec.CancelExecution(context.Background(), cancelled); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	first := <-results
+	if first == nil || first.RunID != cancelled || first.Status != "cancelled" {
+		t.Fatalf("first result = %+v, want %s cancelled", first, cancelled)
+	}
+	close(release)
+	if second := <-results; second == nil || second.RunID != kept || second.Status != "completed" {
+		t.Errorf("second result = %+v, want %s completed", second, kept)
+	}
+
+	// The watching goroutine stops once no run executes on the worker
+	for deadline := time.Now().Add(3 * runWatchInterval); ; time.Sleep(10 * time.Millisecond) {
+		worker.watcher.mu.Lock()
+		watching := worker.watcher.watching
+		worker.watcher.mu.Unlock()
+		if !watching {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("watcher still running with no run executing")
+		}
+	}
+}
+
+func TestWatcherWakesWaitOnSignalThroughAnotherProcess(t *testing.T) {
+	exec := newTestExecutor(t)
+	worker := exec.newExecutor()
+
+	runIDs := make(chan string, 1)
+	workflow := model.NewBaseWorkflow("review", "waits for the decision of an analyst")
+	workflow.AddStep(typed.NewStep("flag", func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) error {
+		runIDs <- workflowContext.GetRunID()
+		return nil
+	}))
+	workflow.AddStep(model.NewWaitForSignalStep("analyst-review", "decision").WithTimeout(5*time.Second, model.NewSequentialStep("escalate")))
+	exec.register(t, workflow)
+
+	done := make(chan *ExecutionResult, 1)
+	go func() {
+		result, _ := worker.ExecuteWorkflow(context.Background(), workflow.GetID(), nil)
+		done <- result
+	}()
+	runID := <-runIDs
+	exec.awaitStatus(t, runID, "waiting")
+
+	// The run waits on the worker; exec only reaches it through the state store
+	if err := exec.SignalExecution(context.Background(), runID, "decision", map[string]interface{}{"decision": "approve"}); err != nil {
+		t.Fatalf("SignalExecution() error = %v", err)
+	}
+	select {
+	case result := <-done:
+		if result == nil || result.Status != "completed" || result.Result["decision"] != "approve" {
+			t.Errorf("result = %+v, want completed with the signal payload", result)
+		}
+	case <-time.After(3 * runWatchInterval):
+		t.Fatal("wait step not woken by a signal sent through another process")
+	}
+}
@@ -0,0 +1,248 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+)
+
+// workerRetryDelay is how long a failed execution request waits before it is redelivered
+const workerRetryDelay = 5 * time.Second
+
+// notDueError is returned for a request resuming a run received before its timer is due
+type notDueError struct {
+	delay time.Duration
+}
+
+func (e *notDueError) Error() string {
+	return fmt.Sprintf("timer due in %s", e.delay)
+}
+
+// retryDelay returns how long a request that could not be processed waits before it is redelivered
+// A request received before its timer is due is redelivered once it is, which is how NATS delays timers
+func retryDelay(err error) time.Duration {
+	var notDue *notDueError
+	if errors.As(err, &notDue) {
+		return notDue.delay
+	}
+	return workerRetryDelay
+}
+
+// Worker consumes execution requests from a queue and runs them with a WorkflowExecutor
+// Results are written to the executor's state store and, on NATS, published back to the submitter
+type Worker struct {
+	queue    queue.Queue
+	executor *WorkflowExecutor
+}
+
+// NewWorker creates a worker for the given queue and executor
+func NewWorker(q queue.Queue, executor *WorkflowExecutor) *Worker {
+	return &Worker{
+		queue:    q,
+		executor: executor,
+	}
+}
+
+// Run processes execution requests until the context is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		processed, err := w.ProcessNext(ctx)
+		if err != nil {
+			slog.Warn("Failed to dequeue message", "error", err)
+			sleepContext(ctx, 1*time.Second)
+			continue
+		}
+		if !processed {
+			// No messages available, wait before trying again
+			sleepContext(ctx, w.executor.Config().QueuePollInterval)
+		}
+	}
+}
+
+// ProcessNext takes the next execution request from the queue and runs it
+// It reports whether a message was available
+func (w *Worker) ProcessNext(ctx context.Context) (bool, error) {
+	if enhancedQueue, ok := w.queue.(*queue.EnhancedNATSQueue); ok {
+		return w.processEnhanced(ctx, enhancedQueue)
+	}
+
+	msg, err := w.queue.Dequeue(ctx)
+	if err != nil {
+		return false, err
+	}
+	if msg == nil {
+		return false, nil
+	}
+
+	if err := w.Process(ctx, msg); err != nil {
+		slog.Warn("Failed to process workflow execution", "run_id", msg.RunID, "error", err)
+		if err := w.queue.Reject(ctx, msg.ID, retryDelay(err)); err != nil {
+			slog.Warn("Failed to reject message", "run_id", msg.RunID, "error", err)
+		}
+	} else if err := w.queue.Acknowledge(ctx, msg.ID); err != nil {
+		slog.Warn("Failed to acknowledge message", "run_id", msg.RunID, "error", err)
+	}
+	return true, nil
+}
+
+// processEnhanced processes the next message of a NATS queue with per-message acknowledgement
+func (w *Worker) processEnhanced(ctx context.Context, q *queue.EnhancedNATSQueue) (bool, error) {
+	enhancedMsg, err := q.DequeueEnhanced(ctx)
+	if err != nil {
+		return false, err
+	}
+	if enhancedMsg == nil {
+		return false, nil
+	}
+
+	if err := w.Process(ctx, enhancedMsg.Message); err != nil {
+		slog.Warn("Failed to process workflow execution", "run_id", enhancedMsg.RunID, "error", err)
+		if err := q.RejectEnhanced(ctx, enhancedMsg, retryDelay(err)); err != nil {
+			slog.Warn("Failed to reject message", "run_id", enhancedMsg.RunID, "error", err)
+		}
+	} else if err := q.AcknowledgeEnhanced(ctx, enhancedMsg); err != nil {
+		slog.Warn("Failed to acknowledge message", "run_id", enhancedMsg.RunID, "error", err)
+	}
+	return true, nil
+}
+
+// Process runs the execution request carried by a queue message and reports its outcome
+// The run executes for the tenant of the request; a parked run reports nothing until it finishes
+func (w *Worker) Process(ctx context.Context, msg *queue.Message) error {
+	execReq, err := queue.UnmarshalExecutionRequest(msg.Data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal execution request: %w", err)
+	}
+	ctx = tenant.WithTenant(ctx, execReq.Tenant)
+	if execReq.Resume {
+		return w.resume(ctx, execReq)
+	}
+
+	slog.Info("Processing workflow execution", "run_id", execReq.RunID, "workflow_id", execReq.WorkflowID, "tenant", tenant.FromContext(ctx))
+
+	result, err := w.executor.ExecuteRun(ctx, execReq.RunID, execReq.WorkflowID, execReq.InputData)
+	if err != nil {
+		w.publish(ctx, queue.ExecutionResult{
+			RunID:       execReq.RunID,
+			WorkflowID:  execReq.WorkflowID,
+			Status:      "failed",
+			Error:       err.Error(),
+			CompletedAt: time.Now(),
+		})
+		return fmt.Errorf("workflow execution failed: %w", err)
+	}
+
+	w.report(ctx, result)
+	return nil
+}
+
+// resume continues a parked run whose timer fired or signal arrived; a request received early is redelivered
+// once the timer is due
+func (w *Worker) resume(ctx context.Context, execReq queue.ExecutionRequest) error {
+	if execReq.NotBefore != nil {
+		if delay := time.Until(*execReq.NotBefore); delay > 0 {
+			return &notDueError{delay: delay}
+		}
+	}
+
+	slog.Info("Resuming workflow execution", "run_id", execReq.RunID, "workflow_id", execReq.WorkflowID, "tenant", tenant.FromContext(ctx))
+
+	result, err := w.executor.ResumeRun(ctx, execReq.RunID, execReq.WorkflowID)
+	if err != nil {
+		return fmt.Errorf("workflow resumption failed: %w", err)
+	}
+	if result == nil {
+		slog.Info("Workflow execution no longer parked", "run_id", execReq.RunID)
+		return nil
+	}
+	w.report(ctx, result)
+	return nil
+}
+
+// report publishes the result of a run that finished; a run parked again has nothing to report yet
+func (w *Worker) report(ctx context.Context, result *ExecutionResult) {
+	if !isParked(result.Status) {
+		w.publish(ctx, queue.ExecutionResult{
+			RunID:       result.RunID,
+			WorkflowID:  result.WorkflowID,
+			Status:      result.Status,
+			OutputData:  result.Result,
+			Error:       result.Error,
+			CompletedAt: result.EndTime,
+		})
+	}
+	slog.Info("Processed workflow execution", "run_id", result.RunID, "status", result.Status)
+}
+
+// publish sends an execution result back to the submitter when the queue routes results
+func (w *Worker) publish(ctx context.Context, result queue.ExecutionResult) {
+	enhancedQueue, ok := w.queue.(*queue.EnhancedNATSQueue)
+	if !ok {
+		return // the submitter shares the state store
+	}
+	result.Tenant = tenant.FromContext(ctx)
+
+	data, err := queue.MarshalExecutionResult(result)
+	if err != nil {
+		slog.Warn("Failed to marshal execution result", "run_id", result.RunID, "error", err)
+		return
+	}
+
+	if result.Status == "failed" && result.OutputData == nil {
+		err = enhancedQueue.PublishError(ctx, result.RunID, data)
+	} else {
+		err = enhancedQueue.PublishResult(ctx, result.RunID, data)
+	}
+	if err != nil {
+		slog.Warn("Failed to publish execution result", "run_id", result.RunID, "error", err)
+	}
+}
+
+// sleepContext waits for the given duration or until the context is cancelled
+func sleepContext(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		d = 100 * time.Millisecond
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// StartResultRouting makes the outcome of runs submitted through q visible in stateManagement
+// Results published by remote workers over NATS are recorded and published to hub, which may be nil;
+// an in-memory queue cannot be read by another process, so it is drained by an embedded worker running
+// local, which publishes its own completions. The returned function stops routing
+func StartResultRouting(ctx context.Context, q queue.Queue, stateManagement state.StateManagement, local *WorkflowExecutor, hub *completion.Hub) (func(), error) {
+	if enhancedQueue, ok := q.(*queue.EnhancedNATSQueue); ok {
+		return ListenForResults(enhancedQueue, stateManagement, hub)
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewWorker(q, local).Run(workerCtx)
+	}()
+	slog.Info("Started embedded worker for in-memory queue")
+
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
@@ -2,20 +2,44 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"unified-workflow/internal/admission"
 	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/logging"
+	"unified-workflow/internal/metrics"
 	"unified-workflow/internal/primitive"
+	primitiveModel "unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/queue"
 	workflowRegistry "unified-workflow/internal/registry"
 	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+	"unified-workflow/internal/timer"
 )
 
 // WorkflowExecutor is a real executor that actually executes workflows with child-step tracking
 type WorkflowExecutor struct {
 	workflowRegistry workflowRegistry.Registry
 	stateManagement  state.StateManagement
-	config           Config
+	queue            queue.Queue
+	completions      *completion.Hub
+	quotas           *tenant.Quotas
+	admission        *admission.Controller
+	timers           *timer.Service
+
+	configMu sync.RWMutex
+	config   Config
+
+	// active holds a pointer to the cancel function of each run executing here, by tenant and run ID
+	active sync.Map
+	// signals holds the signals sent to runs until their wait steps take them
+	signals signalHub
+	// watcher checks the state store for cancellations of and signals to the runs executing here
+	watcher runWatcher
 }
 
 // NewWorkflowExecutor creates a new workflow executor
@@ -31,15 +55,85 @@ func NewWorkflowExecutor(
 	}
 }
 
+// SetQueue sets the queue that submitted runs are published to
+func (e *WorkflowExecutor) SetQueue(q queue.Queue) {
+	e.queue = q
+}
+
+// SetCompletionHub sets the hub notified when runs executed here finish
+func (e *WorkflowExecutor) SetCompletionHub(hub *completion.Hub) {
+	e.completions = hub
+}
+
+// SetQuotas enforces the per-tenant quotas on the runs started here
+// A queued run holds its concurrency slot until the completion hub reports it, so the hub must be set first
+func (e *WorkflowExecutor) SetQuotas(quotas *tenant.Quotas) {
+	e.quotas = quotas
+	if e.completions != nil {
+		e.completions.OnCompletion(func(result queue.ExecutionResult) {
+			quotas.Release(result.RunID)
+		})
+	}
+}
+
+// SetAdmission enforces admission control on the runs started here
+// A queued run holds its in-flight slot until the completion hub reports it, so the hub must be set first
+func (e *WorkflowExecutor) SetAdmission(controller *admission.Controller) {
+	e.admission = controller
+	if e.completions != nil {
+		e.completions.OnCompletion(func(result queue.ExecutionResult) {
+			controller.Release(result.RunID)
+		})
+	}
+}
+
+// admit passes a new run through admission control and the quota of the tenant of ctx
+func (e *WorkflowExecutor) admit(ctx context.Context, workflowID, runID string) error {
+	if err := e.admission.Admit(ctx, workflowID, runID); err != nil {
+		return err
+	}
+	if err := e.quotas.Admit(tenant.FromContext(ctx), runID); err != nil {
+		e.admission.Release(runID)
+		return err
+	}
+	return nil
+}
+
+// release frees the admission slot and tenant quota slot of a run
+func (e *WorkflowExecutor) release(runID string) {
+	e.admission.Release(runID)
+	e.quotas.Release(runID)
+}
+
+// Config returns the current executor configuration
+func (e *WorkflowExecutor) Config() Config {
+	e.configMu.RLock()
+	defer e.configMu.RUnlock()
+	return e.config
+}
+
+// UpdateConfig swaps the executor configuration; running workflows see it at their next step
+func (e *WorkflowExecutor) UpdateConfig(config Config) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.config = config
+}
+
 // ExecutionResult represents the result of a workflow execution
 type ExecutionResult struct {
 	RunID      string                 `json:"run_id"`
 	WorkflowID string                 `json:"workflow_id"`
 	Status     string                 `json:"status"`
 	Result     map[string]interface{} `json:"result"`
+	Steps      []StepExecutionResult  `json:"steps"`
 	Error      string                 `json:"error,omitempty"`
 	StartTime  time.Time              `json:"start_time"`
 	EndTime    time.Time              `json:"end_time"`
+
+	// CompensationStatus is the state of the compensation of a failed or cancelled run, if it had any to run
+	CompensationStatus string `json:"compensation_status,omitempty"`
+	// Compensations are the compensations run, in the order they ran
+	Compensations []CompensationResult `json:"compensations,omitempty"`
 }
 
 // ChildStepExecutionResult represents the result of a child step execution
@@ -61,7 +155,7 @@ type ChildStepExecutionResult struct {
 type StepExecutionResult struct {
 	StepIndex           int                        `json:"step_index"`
 	Name                string                     `json:"name"`
-	Status              string                     `json:"status"` // "pending", "running", "completed", "failed", "cancelled"
+	Status              string                     `json:"status"` // "pending", "running", "completed", "failed", "skipped", "cancelled"
 	IsParallel          bool                       `json:"is_parallel"`
 	ChildStepCount      int                        `json:"child_step_count"`
 	CompletedChildSteps int                        `json:"completed_child_steps"`
@@ -71,84 +165,216 @@ type StepExecutionResult struct {
 	DurationMillis      int64                      `json:"duration_millis,omitempty"`
 	ChildSteps          []ChildStepExecutionResult `json:"child_steps,omitempty"`
 	ErrorMessage        string                     `json:"error_message,omitempty"`
+	// Branch is the branch step and branch the step belongs to, as "route.manual-review"
+	Branch string `json:"branch,omitempty"`
+	// SelectedBranch is the branch a branch step took
+	SelectedBranch string `json:"selected_branch,omitempty"`
+	// SkipReason explains why a skipped step did not run
+	SkipReason string `json:"skip_reason,omitempty"`
+	// ChildRunID is the run a sub-workflow step started
+	ChildRunID string `json:"child_run_id,omitempty"`
+	// Items are the runs of a foreach step for the items of its list, in list order
+	Items []ItemExecutionResult `json:"items,omitempty"`
 }
 
-// ExecuteWorkflow executes a workflow with child-step tracking
+// ExecuteWorkflow executes a workflow with child-step tracking under a new run ID
 func (e *WorkflowExecutor) ExecuteWorkflow(ctx context.Context, workflowID string, inputData map[string]interface{}) (*ExecutionResult, error) {
-	startTime := time.Now()
+	return e.ExecuteRun(ctx, newRunID(), workflowID, inputData)
+}
 
-	// Load workflow from registry
-	workflow, err := e.workflowRegistry.GetWorkflow(ctx, workflowID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get workflow %s: %w", workflowID, err)
+// WaitForResult waits up to timeout (the configured execution timeout when not positive) for a run to finish
+// and returns its result, or nil if it is still running
+func (e *WorkflowExecutor) WaitForResult(ctx context.Context, runID string, timeout time.Duration) (*queue.ExecutionResult, error) {
+	if timeout <= 0 {
+		timeout = e.Config().ExecutionTimeout
 	}
+	return WaitForResult(ctx, e.completions, e.stateManagement, runID, timeout)
+}
 
-	// Generate run ID
-	runID := fmt.Sprintf("run-%d", time.Now().UnixNano())
+// LoadResult returns the result of a finished run, or nil while it is still in progress
+func (e *WorkflowExecutor) LoadResult(ctx context.Context, runID string) (*queue.ExecutionResult, error) {
+	return LoadResult(ctx, e.stateManagement, runID)
+}
 
-	// Initialize execution context and data
-	executionContext := make(map[string]interface{})
-	executionData := make(map[string]interface{})
+// ExecuteWorkflowWithTimeout executes a workflow and waits at most timeout for it to finish
+// When the timeout expires first, the run keeps executing in the background and a nil result is
+// returned together with the run ID, so the caller can poll for the outcome
+// It fails with an admission.RejectedError or a tenant.QuotaError when the run is refused
+func (e *WorkflowExecutor) ExecuteWorkflowWithTimeout(ctx context.Context, workflowID string, inputData map[string]interface{}, timeout time.Duration) (string, *ExecutionResult, error) {
+	runID := newRunID()
+	if err := e.admit(ctx, workflowID, runID); err != nil {
+		return "", nil, err
+	}
+	if err := savePendingRun(ctx, e.stateManagement, runID, workflowID, inputData); err != nil {
+		e.release(runID)
+		return "", nil, err
+	}
 
-	// Merge input data
-	for k, v := range inputData {
-		executionData[k] = v
+	type outcome struct {
+		result *ExecutionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	// The run must outlive the caller's request if it falls back to async mode
+	runCtx := context.WithoutCancel(ctx)
+	go func() {
+		result, err := e.ExecuteRun(runCtx, runID, workflowID, inputData)
+		e.release(runID)
+		done <- outcome{result: result, err: err}
+	}()
+
+	if timeout <= 0 {
+		timeout = e.Config().ExecutionTimeout
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
 	}
 
-	// Track execution state
-	stepResults := make([]StepExecutionResult, 0)
-	totalSteps := workflow.GetStepCount()
-
-	// Execute each step
-	for stepIndex, step := range workflow.GetSteps() {
-		stepStartTime := time.Now()
-
-		// Create step result
-		stepResult := StepExecutionResult{
-			StepIndex:           stepIndex,
-			Name:                step.GetName(),
-			Status:              "running",
-			IsParallel:          step.IsParallel(),
-			ChildStepCount:      step.GetChildStepCount(),
-			CompletedChildSteps: 0,
-			FailedChildSteps:    0,
-			StartTime:           stepStartTime,
-			ChildSteps:          make([]ChildStepExecutionResult, 0),
+	select {
+	case out := <-done:
+		if out.result != nil && isParked(out.result.Status) {
+			// The run resumes once its timer fires or its signal arrives, so its outcome is polled like that of an async run
+			return runID, nil, nil
 		}
+		return runID, out.result, out.err
+	case <-expired:
+		logging.Info(logging.WithRun(ctx, runID, workflowID), "Workflow execution exceeded the synchronous timeout, continuing asynchronously", "timeout", timeout)
+		return runID, nil, nil
+	case <-ctx.Done():
+		return runID, nil, nil
+	}
+}
 
-		// Execute child steps
-		childStepResults, stepErr := e.executeStep(ctx, step, stepIndex, executionContext, executionData)
+// ExecuteRun executes a workflow for an already submitted run and persists its progress and outcome
+func (e *WorkflowExecutor) ExecuteRun(ctx context.Context, runID, workflowID string, inputData map[string]interface{}) (*ExecutionResult, error) {
+	ctx = logging.WithRun(ctx, runID, workflowID)
 
-		// Update step result
-		stepResult.EndTime = time.Now()
-		stepResult.DurationMillis = stepResult.EndTime.Sub(stepResult.StartTime).Milliseconds()
-		stepResult.ChildSteps = childStepResults
+	// Load workflow from registry; the run keeps executing the version it started with
+	workflow, version, err := e.latestWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, e.failRun(ctx, runID, workflowID, fmt.Errorf("failed to get workflow %s: %w", workflowID, err))
+	}
+	return e.executeWorkflow(ctx, runID, workflow, version, inputData, "", 0)
+}
 
-		// Count completed/failed child steps
-		for _, childResult := range childStepResults {
-			if childResult.Status == "completed" {
-				stepResult.CompletedChildSteps++
-			} else if childResult.Status == "failed" {
-				stepResult.FailedChildSteps++
-			}
+// executeWorkflow executes a version of a workflow under a run ID; parentRunID links the run of a sub-workflow
+// to its parent. A version of zero is not recorded, so a run resumed later executes the latest version
+// A run resumed after sleeping on a timer starts at the top-level step resumeStep, a run parked on a wait step
+// takes it again at resumeStep-1; resumeStep is zero otherwise
+// The run can be cancelled through CancelExecution while it executes
+func (e *WorkflowExecutor) executeWorkflow(ctx context.Context, runID string, workflow model.Workflow, version int, inputData map[string]interface{}, parentRunID string, resumeStep int) (*ExecutionResult, error) {
+	startTime := time.Now()
+	workflowID := workflow.GetID()
+
+	// Workflows whose steps declare dependencies run as a graph
+	var graph *model.StepGraph
+	if model.HasDependencies(workflow.GetSteps()) {
+		var err error
+		if graph, err = model.NewStepGraph(workflow.GetSteps()); err != nil {
+			return nil, e.failRun(ctx, runID, workflowID, fmt.Errorf("workflow %s: %w", workflowID, err))
 		}
+	}
+	if resumeStep > 0 && (graph != nil || resumeStep > len(workflow.GetSteps())) {
+		return nil, e.failRun(ctx, runID, workflowID, fmt.Errorf("workflow %s changed while the run slept and cannot resume at step %d", workflowID, resumeStep))
+	}
 
-		// Determine step status
-		if stepErr != nil {
-			stepResult.Status = "failed"
-			stepResult.ErrorMessage = stepErr.Error()
-		} else if stepResult.CompletedChildSteps == stepResult.ChildStepCount {
-			stepResult.Status = "completed"
+	run := &stepRun{workflowID: workflowID}
+	ctx, cancel := context.WithCancelCause(ctx)
+	e.active.Store(runKey(ctx, runID), &cancel)
+	e.watchRun(ctx, runID, cancel)
+	defer func() {
+		run.deadline.stop()
+		e.unwatchRun(ctx, runID)
+		// Once handed over the run may already execute again elsewhere; it keeps its signals meanwhile
+		e.active.CompareAndDelete(runKey(ctx, runID), &cancel)
+		if run.parked == "" {
+			e.forgetSignals(context.WithoutCancel(ctx), runID)
+			e.clearCancellation(context.WithoutCancel(ctx), runID)
 		} else {
-			stepResult.Status = "failed" // Some child steps failed
+			e.signals.forget(runID)
 		}
+		cancel(nil)
+	}()
+
+	runContext := e.runContext(ctx, runID, workflowID).
+		WithStatus(primitiveModel.WorkflowStatusRunning)
+	// A resumed run has the part of its timeout it had left when it parked
+	var remaining time.Duration
+	if resumeStep > 0 {
+		remaining = runContext.GetRemainingTimeout()
+	}
+	run.deadline = startRunDeadline(e.runTimeout(workflow), remaining, cancel)
+	runContext = runContext.WithRemainingTimeout(0)
+	if resumeStep > 0 && runContext.GetStartTime() != nil {
+		startTime = *runContext.GetStartTime()
+	} else {
+		runContext = runContext.WithStartTime(startTime)
+	}
+	if parentRunID != "" {
+		runContext = runContext.WithParentRunID(parentRunID)
+	}
+	if version > 0 {
+		runContext = runContext.WithWorkflowVersion(version)
+	}
+	e.saveRunContext(ctx, runContext)
+	if resumeStep > 0 {
+		logging.Info(ctx, "Workflow execution resumed", "step_count", workflow.GetStepCount(), "resume_step", resumeStep)
+	} else {
+		logging.Info(ctx, "Workflow execution started", "step_count", workflow.GetStepCount())
+	}
 
-		stepResults = append(stepResults, stepResult)
+	metrics.WorkflowRunsActive.Inc(workflowID)
+	defer metrics.WorkflowRunsActive.Dec(workflowID)
+
+	// Initialize execution data; steps read and write it through WorkflowData
+	executionData := make(map[string]interface{})
+
+	// Merge input data
+	for k, v := range inputData {
+		executionData[k] = v
+	}
+	workflowData := primitiveModel.WrapWorkflowData(executionData)
 
-		// If step failed and we should stop, break
-		if stepErr != nil && e.config.MaxRetries == 0 {
-			break
+	run.runContext = runContext
+	run.data = workflowData
+	if graph != nil {
+		// Steps that do not depend on each other share the data concurrently
+		run.data = &syncData{data: workflowData}
+		e.runGraph(ctx, run, graph)
+	} else {
+		// Execute the steps in order, following the branches taken; timers of top-level runs are durable
+		steps := workflow.GetSteps()
+		if parentRunID == "" && e.timers != nil && e.stateManagement != nil {
+			run.steps = steps
 		}
+		if resumeStep > 0 {
+			run.restoreCompensations(steps[:resumeStep-1], runContext)
+			if _, ok := steps[resumeStep-1].(*model.WaitForSignalStep); ok {
+				// A run parked on a wait step takes it again, receiving its signal or timing out
+				resumeStep--
+			} else {
+				e.setStepState(ctx, run, steps[resumeStep-1].GetName(), primitiveModel.StepStatusCompleted)
+			}
+			run.restoreResults(steps[:resumeStep])
+		}
+		e.runSteps(ctx, run, steps[resumeStep:], "")
+	}
+	stepResults := run.results
+
+	// A run sleeping on a timer or waiting for a signal is handed over until it resumes
+	if run.parked != "" {
+		logging.Info(ctx, "Workflow execution parked", "status", run.parked)
+		return &ExecutionResult{
+			RunID:      runID,
+			WorkflowID: workflowID,
+			Status:     run.parked,
+			Result:     plainData(executionData),
+			Steps:      stepResults,
+			StartTime:  startTime,
+		}, nil
 	}
 
 	// Calculate overall execution result
@@ -156,160 +382,553 @@ func (e *WorkflowExecutor) ExecuteWorkflow(ctx context.Context, workflowID strin
 
 	// Determine overall status
 	completedSteps := 0
+	skippedSteps := 0
 	failedSteps := 0
+	cancelledSteps := 0
+	timedOutSteps := 0
+	errorMessage := ""
 	for _, stepResult := range stepResults {
 		if stepResult.Status == "completed" {
 			completedSteps++
-		} else if stepResult.Status == "failed" {
-			failedSteps++
+		} else if stepResult.Status == primitiveModel.StepStatusSkipped {
+			skippedSteps++
+		} else if stepResult.Status == "failed" || stepResult.Status == primitiveModel.StepStatusCancelled || stepResult.Status == primitiveModel.StepStatusTimedOut {
+			switch stepResult.Status {
+			case "failed":
+				failedSteps++
+			case primitiveModel.StepStatusCancelled:
+				cancelledSteps++
+			default:
+				timedOutSteps++
+			}
+			if errorMessage == "" {
+				errorMessage = fmt.Sprintf("step %s %s", stepResult.Name, stepResult.Status)
+				if stepResult.ErrorMessage != "" {
+					errorMessage += ": " + stepResult.ErrorMessage
+				}
+			}
 		}
 	}
 
 	status := "completed"
-	if failedSteps > 0 {
+	if cancelledSteps > 0 {
+		status = "cancelled"
+	} else if timedOutSteps > 0 {
+		status = "timed_out"
+	} else if failedSteps > 0 {
 		status = "failed"
-	} else if completedSteps < totalSteps {
+	} else if completedSteps+skippedSteps < len(stepResults) {
 		status = "partial"
 	}
 
+	// Undo the steps that completed before the run failed, was cancelled or timed out
+	var compensationStatus string
+	var compensations []CompensationResult
+	if (status == "failed" || status == "cancelled" || status == "timed_out") && len(run.compensations) > 0 {
+		compensationStatus, compensations = e.compensate(ctx, run)
+	}
+	runContext = run.runContext
+
+	metrics.WorkflowRuns.Inc(workflowID, status)
+	metrics.WorkflowRunDuration.ObserveDuration(endTime.Sub(startTime), workflowID, status)
+
+	// Typed child step results leave the run in their JSON form
+	executionData = plainData(executionData)
+
 	result := &ExecutionResult{
 		RunID:      runID,
 		WorkflowID: workflowID,
 		Status:     status,
 		Result:     executionData,
+		Steps:      stepResults,
+		Error:      errorMessage,
 		StartTime:  startTime,
 		EndTime:    endTime,
+
+		CompensationStatus: compensationStatus,
+		Compensations:      compensations,
 	}
 
-	// TODO: Store execution result using state management
-	// For now, just log that we would store it
-	fmt.Printf("Execution completed: %s, status: %s\n", runID, status)
+	// Persist the execution data and outcome; classified fields are encrypted by the state store
+	if e.stateManagement != nil {
+		if err := e.stateManagement.SaveData(ctx, runID, workflowDataFromMap(executionData)); err != nil {
+			logging.Warn(ctx, "Failed to save execution data", "error", err)
+		}
+	}
+	runContext = runContext.
+		WithStatus(statusValue(status)).
+		WithErrorMessage(errorMessage).
+		WithEndTime(endTime)
+	e.saveRunContext(ctx, runContext)
+	e.publishCompletion(ctx, queue.ExecutionResult{
+		RunID:       runID,
+		WorkflowID:  workflowID,
+		Status:      statusName(statusValue(status)),
+		OutputData:  executionData,
+		Error:       errorMessage,
+		CompletedAt: endTime,
+	})
+	logging.Info(ctx, "Workflow execution completed", "status", status, "duration", endTime.Sub(startTime))
 
 	return result, nil
 }
 
-// executeStep executes a single step with its child steps
-func (e *WorkflowExecutor) executeStep(ctx context.Context, step model.Step, stepIndex int, context, data interface{}) ([]ChildStepExecutionResult, error) {
-	childSteps := step.GetChildSteps()
-	childStepResults := make([]ChildStepExecutionResult, len(childSteps))
+// failRun records a run that could not start as failed and returns err
+func (e *WorkflowExecutor) failRun(ctx context.Context, runID, workflowID string, err error) error {
+	endTime := time.Now()
+	e.saveRunContext(ctx, e.runContext(ctx, runID, workflowID).
+		WithStatus(primitiveModel.WorkflowStatusFailed).
+		WithErrorMessage(err.Error()).
+		WithEndTime(endTime))
+	e.publishCompletion(ctx, queue.ExecutionResult{
+		RunID:       runID,
+		WorkflowID:  workflowID,
+		Status:      "failed",
+		Error:       err.Error(),
+		CompletedAt: endTime,
+	})
+	return err
+}
 
-	// Check if step is parallel
-	if step.IsParallel() {
-		// Execute child steps in parallel (simplified - would use goroutines in real implementation)
-		for childStepIndex, childStep := range childSteps {
-			result := e.executeChildStep(ctx, childStep, stepIndex, childStepIndex, context, data)
-			childStepResults[childStepIndex] = result
+// runContext returns the persisted context of a run, or a new pending one
+func (e *WorkflowExecutor) runContext(ctx context.Context, runID, workflowID string) primitiveModel.WorkflowContext {
+	if e.stateManagement != nil {
+		if workflowContext, err := e.stateManagement.GetContext(ctx, runID); err == nil {
+			return workflowContext
+		}
+	}
+	return primitiveModel.NewWorkflowContextForRun(runID, workflowID)
+}
 
-			// If child step failed and we should stop, return early
-			if result.Status == "failed" && e.config.MaxRetries == 0 {
-				return childStepResults, fmt.Errorf("child step %d failed: %s", childStepIndex, result.ErrorMessage)
-			}
+// publishCompletion notifies waiters that a run finished
+// With NATS the result goes out on the run's result subject, so waiters on every API node
+// (including this one, through ListenForResults) are woken up
+func (e *WorkflowExecutor) publishCompletion(ctx context.Context, result queue.ExecutionResult) {
+	if e.completions == nil {
+		return
+	}
+	result.Tenant = tenant.FromContext(ctx)
+	if nq, ok := e.queue.(*queue.EnhancedNATSQueue); ok {
+		data, err := queue.MarshalExecutionResult(result)
+		if err == nil {
+			err = nq.PublishResult(ctx, result.RunID, data)
+		}
+		if err == nil {
+			return
+		}
+		logging.Warn(ctx, "Failed to publish run completion, notifying local waiters only", "error", err)
+	}
+	e.completions.Publish(result)
+}
+
+// saveRunContext persists the context of a run
+func (e *WorkflowExecutor) saveRunContext(ctx context.Context, workflowContext primitiveModel.WorkflowContext) {
+	if e.stateManagement == nil {
+		return
+	}
+	if err := e.stateManagement.SaveContext(ctx, workflowContext); err != nil {
+		logging.Warn(ctx, "Failed to save run context", "error", err)
+	}
+}
+
+// stepRun is the state of the steps of a run; the steps of a graph workflow update it concurrently
+type stepRun struct {
+	workflowID string
+	data       primitiveModel.WorkflowData
+
+	mu            sync.Mutex
+	runContext    primitiveModel.WorkflowContext
+	results       []StepExecutionResult
+	compensations []pendingCompensation
+	// waiting counts the steps waiting for a signal
+	waiting int
+	// steps are the top-level steps of a run whose timers and waits are durable, nil when they happen in place
+	steps []model.Step
+	// parked is the status of a run handed over until its timer fires or its signal arrives, empty otherwise
+	parked string
+	// deadline cancels the run once its timeout expires, nil when it has none
+	deadline *runDeadline
+}
+
+// resumeStep returns the index of the top-level step after a step that hands the run over, or zero when the step
+// is not durable, as within a branch or when timers and waits happen in place
+func (run *stepRun) resumeStep(step model.Step, branch string) int {
+	if branch != "" {
+		return 0
+	}
+	for i, s := range run.steps {
+		if s == step {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// stepStateKey names a step in the step states of a run context
+func stepStateKey(name, branch string) string {
+	if branch == "" {
+		return name
+	}
+	return branch + "." + name
+}
+
+// startStep marks a step as attempted in the run context and returns the context the step runs with
+func (e *WorkflowExecutor) startStep(ctx context.Context, run *stepRun, name, branch string) (int, primitiveModel.WorkflowContext) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	stepIndex := len(run.results)
+	run.runContext = run.runContext.
+		WithIndices(stepIndex, 0).
+		WithLastAttemptedStep(name).
+		WithStepState(stepStateKey(name, branch), primitiveModel.StepStatusRunning)
+	e.saveRunContext(ctx, run.runContext)
+	return stepIndex, run.runContext
+}
+
+// recordStep appends the result of a step to the run and returns its index
+// Steps are numbered in the order they finish, which for graph workflows need not be the order they started
+func (e *WorkflowExecutor) recordStep(ctx context.Context, run *stepRun, stepResult StepExecutionResult) int {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	stepResult.StepIndex = len(run.results)
+	for i := range stepResult.ChildSteps {
+		stepResult.ChildSteps[i].StepIndex = stepResult.StepIndex
+	}
+	run.results = append(run.results, stepResult)
+	run.runContext = run.runContext.WithStepState(stepStateKey(stepResult.Name, stepResult.Branch), stepResult.Status)
+	e.saveRunContext(ctx, run.runContext)
+	return stepResult.StepIndex
+}
+
+// stepState returns the state of a top-level step of the run
+func (run *stepRun) stepState(name string) string {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	return run.runContext.GetStepStates()[name]
+}
+
+// runSteps runs steps in order; it reports false when a failed step stops the run
+func (e *WorkflowExecutor) runSteps(ctx context.Context, run *stepRun, steps []model.Step, branch string) bool {
+	for _, step := range steps {
+		if !e.runStep(ctx, run, step, branch) {
+			return false
+		}
+	}
+	return true
+}
+
+// runStep runs a step unless its condition does not hold, and records its result
+func (e *WorkflowExecutor) runStep(ctx context.Context, run *stepRun, step model.Step, branch string) bool {
+	stepResult := StepExecutionResult{
+		Name:           step.GetName(),
+		Status:         "running",
+		IsParallel:     step.IsParallel(),
+		ChildStepCount: step.GetChildStepCount(),
+		StartTime:      time.Now(),
+		ChildSteps:     make([]ChildStepExecutionResult, 0),
+		Branch:         branch,
+	}
+	stepCtx := logging.WithStep(ctx, step.GetName())
+
+	// A cancelled run starts no further step
+	if ctx.Err() != nil {
+		return e.finishStep(stepCtx, run, stepResult, nil, context.Cause(ctx))
+	}
+
+	if conditional, ok := step.(model.ConditionalStep); ok && conditional.GetCondition() != nil {
+		condition := conditional.GetCondition()
+		holds, err := condition.Evaluate(run.data)
+		if err != nil {
+			return e.finishStep(stepCtx, run, stepResult, nil, fmt.Errorf("condition %s failed: %w", condition, err))
+		}
+		if !holds {
+			logging.Debug(stepCtx, "Step skipped", "condition", condition.String())
+			e.skipStep(stepCtx, run, step, branch, fmt.Sprintf("condition %s not met", condition))
+			return true
+		}
+	}
+
+	stepIndex, stepContext := e.startStep(ctx, run, step.GetName(), branch)
+	defer run.registerStepCompensation(step, branch, stepContext)
+
+	if branchStep, ok := step.(*model.BranchStep); ok {
+		return e.runBranches(stepCtx, run, branchStep, stepResult)
+	}
+	if subWorkflow, ok := step.(*model.SubWorkflowStep); ok {
+		// The child run is cancelled with the step once the step timeout expires
+		subCtx := ctx
+		if timeout := e.stepTimeout(step); timeout > 0 {
+			var cancelStep context.CancelFunc
+			subCtx, cancelStep = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %s", ErrStepTimedOut, timeout))
+			defer cancelStep()
+		}
+		childRunID, err := e.runSubWorkflow(subCtx, run, subWorkflow)
+		if err != nil && errors.Is(context.Cause(subCtx), ErrStepTimedOut) {
+			err = fmt.Errorf("%w: %v", context.Cause(subCtx), err)
+		}
+		stepResult.ChildRunID = childRunID
+		return e.finishStep(stepCtx, run, stepResult, nil, err)
+	}
+	if forEach, ok := step.(*model.ForEachStep); ok {
+		return e.runForEach(stepCtx, run, forEach, stepResult, stepIndex, stepContext)
+	}
+	if wait, ok := step.(*model.WaitForSignalStep); ok {
+		return e.runWait(stepCtx, run, wait, stepResult)
+	}
+	if timerStep, ok := step.(*model.TimerStep); ok {
+		return e.runTimer(stepCtx, run, timerStep, stepResult)
+	}
+
+	// Execute child steps within the step timeout
+	workCtx := stepCtx
+	if timeout := e.stepTimeout(step); timeout > 0 {
+		var cancelStep context.CancelFunc
+		workCtx, cancelStep = context.WithTimeoutCause(stepCtx, timeout, fmt.Errorf("%w after %s", ErrStepTimedOut, timeout))
+		defer cancelStep()
+	}
+	childStepResults, stepErr := e.executeStep(workCtx, step, stepIndex, stepContext, run.data)
+	run.registerChildStepCompensations(step, branch, childStepResults, stepContext)
+	return e.finishStep(stepCtx, run, stepResult, childStepResults, stepErr)
+}
+
+// finishStep records the result of a step that ran; it reports false when the failure stops the run
+func (e *WorkflowExecutor) finishStep(ctx context.Context, run *stepRun, stepResult StepExecutionResult, childStepResults []ChildStepExecutionResult, stepErr error) bool {
+	// Update step result
+	stepResult.EndTime = time.Now()
+	stepResult.DurationMillis = stepResult.EndTime.Sub(stepResult.StartTime).Milliseconds()
+	if childStepResults != nil {
+		stepResult.ChildSteps = childStepResults
+	}
+
+	// Count completed/failed child steps
+	for _, childResult := range childStepResults {
+		if childResult.Status == "completed" {
+			stepResult.CompletedChildSteps++
+		} else if childResult.Status == "failed" {
+			stepResult.FailedChildSteps++
+		}
+	}
+
+	// Determine step status; a step that failed once it or its run timed out or was cancelled counts as such
+	if stepErr != nil {
+		stepResult.Status = "failed"
+		if timedOut(ctx, stepErr) {
+			stepResult.Status = primitiveModel.StepStatusTimedOut
+		} else if ctx.Err() != nil {
+			stepResult.Status = primitiveModel.StepStatusCancelled
 		}
+		stepResult.ErrorMessage = stepErr.Error()
+	} else if stepResult.CompletedChildSteps == stepResult.ChildStepCount {
+		stepResult.Status = "completed"
 	} else {
-		// Execute child steps sequentially
-		for childStepIndex, childStep := range childSteps {
-			result := e.executeChildStep(ctx, childStep, stepIndex, childStepIndex, context, data)
-			childStepResults[childStepIndex] = result
-
-			// If child step failed and we should stop, return early
-			if result.Status == "failed" && e.config.MaxRetries == 0 {
-				return childStepResults, fmt.Errorf("child step %d failed: %s", childStepIndex, result.ErrorMessage)
+		stepResult.Status = "failed" // Some child steps failed
+	}
+
+	e.recordStep(ctx, run, stepResult)
+	logging.Debug(ctx, "Step finished", "status", stepResult.Status, "duration_ms", stepResult.DurationMillis)
+
+	// Record step and child-step latencies
+	metrics.StepDuration.ObserveDuration(stepResult.EndTime.Sub(stepResult.StartTime), run.workflowID, stepResult.Name, stepResult.Status)
+	for _, childResult := range childStepResults {
+		if childResult.StartTime.IsZero() {
+			continue // not reached
+		}
+		metrics.ChildStepDuration.ObserveDuration(childResult.EndTime.Sub(childResult.StartTime), run.workflowID, stepResult.Name, childResult.Name, childResult.Status)
+	}
+
+	// If step failed and we should stop, stop; a run that timed out or was cancelled always stops
+	if stepResult.Status == primitiveModel.StepStatusCancelled || stepResult.Status == primitiveModel.StepStatusTimedOut {
+		return false
+	}
+	return !(stepErr != nil && e.Config().MaxRetries == 0)
+}
+
+// runBranches runs the steps of the branch a branch step selects and skips the steps of the others
+func (e *WorkflowExecutor) runBranches(ctx context.Context, run *stepRun, step *model.BranchStep, stepResult StepExecutionResult) bool {
+	selected, err := step.Select(run.data)
+	if err != nil {
+		return e.finishStep(ctx, run, stepResult, nil, err)
+	}
+
+	stepResult.Status = "completed"
+	if selected != nil {
+		stepResult.SelectedBranch = selected.Name
+		logging.Debug(ctx, "Branch selected", "branch", selected.Name)
+	}
+	resultIndex := e.recordStep(ctx, run, stepResult)
+
+	proceed := true
+	for _, branch := range step.AllBranches() {
+		path := step.GetName() + "." + branch.Name
+		if branch == selected {
+			proceed = e.runSteps(ctx, run, branch.Steps, path)
+			continue
+		}
+		for _, branchStep := range branch.Steps {
+			e.skipStep(ctx, run, branchStep, path, fmt.Sprintf("branch %s not taken", branch.Name))
+		}
+	}
+	e.finishBranchingStep(run, resultIndex)
+	return proceed
+}
+
+// finishBranchingStep sets the end time of a step recorded before the steps of its branch ran
+func (e *WorkflowExecutor) finishBranchingStep(run *stepRun, resultIndex int) {
+	run.mu.Lock()
+	result := &run.results[resultIndex]
+	result.EndTime = time.Now()
+	result.DurationMillis = result.EndTime.Sub(result.StartTime).Milliseconds()
+	finished := *result
+	run.mu.Unlock()
+	metrics.StepDuration.ObserveDuration(finished.EndTime.Sub(finished.StartTime), run.workflowID, finished.Name, finished.Status)
+}
+
+// skipStep records a step, and the steps of its branches, as skipped
+func (e *WorkflowExecutor) skipStep(ctx context.Context, run *stepRun, step model.Step, branch, reason string) {
+	e.recordStep(ctx, run, StepExecutionResult{
+		Name:           step.GetName(),
+		Status:         primitiveModel.StepStatusSkipped,
+		IsParallel:     step.IsParallel(),
+		ChildStepCount: step.GetChildStepCount(),
+		Branch:         branch,
+		SkipReason:     reason,
+	})
+	if branchStep, ok := step.(model.BranchingStep); ok {
+		for _, nested := range branchStep.AllBranches() {
+			for _, nestedStep := range nested.Steps {
+				e.skipStep(ctx, run, nestedStep, branchStep.GetName()+"."+nested.Name, reason)
 			}
 		}
 	}
+}
+
+// executeStep executes a single step with its child steps, then the logic of a WorkflowStep
+// Once ctx is done the step stops at the failed child step or logic and returns the cause of ctx
+func (e *WorkflowExecutor) executeStep(ctx context.Context, step model.Step, stepIndex int, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) ([]ChildStepExecutionResult, error) {
+	childSteps := step.GetChildSteps()
+	childStepResults := make([]ChildStepExecutionResult, len(childSteps))
+
+	// Child steps of parallel steps also run in order (simplified - would use goroutines in real implementation)
+	for childStepIndex, childStep := range childSteps {
+		result := e.executeChildStep(ctx, childStep, stepIndex, childStepIndex, workflowContext, data)
+		childStepResults[childStepIndex] = result
+
+		if result.Status == "failed" && ctx.Err() != nil {
+			return childStepResults, context.Cause(ctx)
+		}
+		// If child step failed and we should stop, return early
+		if result.Status == "failed" && e.Config().MaxRetries == 0 {
+			return childStepResults, fmt.Errorf("child step %d failed: %s", childStepIndex, result.ErrorMessage)
+		}
+	}
 
+	if workflowStep, ok := step.(model.WorkflowStep); ok {
+		_, err := callHook(ctx, workflowContext.GetWorkflowDefinitionID(), step.GetName(), func() (struct{}, error) {
+			return struct{}{}, workflowStep.Execute(ctx, workflowContext, data)
+		})
+		if err != nil && ctx.Err() != nil {
+			return childStepResults, context.Cause(ctx)
+		}
+		if err != nil {
+			return childStepResults, err
+		}
+	}
 	return childStepResults, nil
 }
 
-// executeChildStep executes a single child step with primitive execution
-func (e *WorkflowExecutor) executeChildStep(ctx context.Context, childStep *model.ChildStep, stepIndex, childStepIndex int, context, data interface{}) ChildStepExecutionResult {
+// executeChildStep executes a single child step
+// Child steps with a function or hooks run them and store their result in the workflow data; child steps
+// defined by name only, such as those of remote workflow definitions, run the echo primitive
+func (e *WorkflowExecutor) executeChildStep(ctx context.Context, childStep *model.ChildStep, stepIndex, childStepIndex int, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (result ChildStepExecutionResult) {
 	startTime := time.Now()
-	result := ChildStepExecutionResult{
+	result = ChildStepExecutionResult{
 		StepIndex:      stepIndex,
 		ChildStepIndex: childStepIndex,
 		Name:           childStep.GetName(),
 		Status:         "pending",
 		StartTime:      startTime,
 	}
+	ctx = logging.WithChildStep(ctx, childStep.GetName())
+	defer func() {
+		result.EndTime = time.Now()
+		result.DurationMillis = result.EndTime.Sub(startTime).Milliseconds()
+	}()
 
-	// Get primitive name from child step hooks
-	// For now, use a default echo primitive for testing
-	primitiveName := "primitive.echo.echo"
-
-	// Default parameters for echo primitive
-	params := map[string]interface{}{
-		"message": fmt.Sprintf("Executing step %s", childStep.GetName()),
-	}
-
-	// Try to execute the primitive using global primitive pattern
-	// For echo primitive, use primitive.Default.Echo.Echo()
-	if primitiveName == "primitive.echo.echo" {
-		message, ok := params["message"].(string)
-		if !ok {
-			message = "Default message"
-		}
-
-		primitiveResult, err := primitive.Default.Echo.Echo(message)
+	if childStep.HasLogic() {
+		childResult, err := callHook(ctx, workflowContext.GetWorkflowDefinitionID(), childStep.GetName(), func() (interface{}, error) {
+			return childStep.Execute(ctx, workflowContext, data)
+		})
 		if err != nil {
 			result.Status = "failed"
-			result.ErrorMessage = fmt.Sprintf("Primitive execution failed: %v", err)
-			result.EndTime = time.Now()
-			result.DurationMillis = result.EndTime.Sub(startTime).Milliseconds()
-			result.PrimitiveName = primitiveName
-			result.Parameters = params
+			result.ErrorMessage = err.Error()
+			logging.Warn(ctx, "Child step failed", "error", err)
 			return result
 		}
-
-		endTime := time.Now()
-		result.EndTime = endTime
-		result.DurationMillis = endTime.Sub(startTime).Milliseconds()
+		model.StoreChildStepResult(data, childStep.GetName(), childResult)
 		result.Status = "completed"
-		result.PrimitiveName = primitiveName
-		result.Result = primitiveResult
-		result.Parameters = params
+		result.Result = childResult
 		return result
 	}
 
-	// Primitive not found, use fallback
-	result.Status = "failed"
-	result.ErrorMessage = fmt.Sprintf("Primitive not found or not implemented: %s", primitiveName)
-	result.EndTime = time.Now()
-	result.DurationMillis = result.EndTime.Sub(startTime).Milliseconds()
+	// For now, use a default echo primitive for definition-only child steps
+	primitiveName := "primitive.echo.echo"
+	message := fmt.Sprintf("Executing step %s", childStep.GetName())
 	result.PrimitiveName = primitiveName
-	result.Parameters = params
+	result.Parameters = map[string]interface{}{"message": message}
+	if ctx.Err() != nil {
+		result.Status = "failed"
+		result.ErrorMessage = context.Cause(ctx).Error()
+		return result
+	}
+
+	primitiveResult, err := primitive.Default.Echo.Echo(message)
+	if err != nil {
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Primitive execution failed: %v", err)
+		logging.Warn(ctx, "Child step failed", "primitive", primitiveName, "error", err)
+		return result
+	}
+	result.Status = "completed"
+	result.Result = primitiveResult
 	return result
 }
 
 // GetExecutionStatus gets the status of a workflow execution
 func (e *WorkflowExecutor) GetExecutionStatus(ctx context.Context, runID string) (*ExecutionStatus, error) {
-	// For now, return a simple status since we don't have persistent storage
-	// In a real implementation, we would query the state management
-	return &ExecutionStatus{
-		RunID:      runID,
-		Status:     "completed", // Default status
-		Progress:   1.0,
-		IsTerminal: true,
-	}, nil
+	return loadExecutionStatus(ctx, e.stateManagement, runID)
 }
 
 // SubmitWorkflow submits a workflow for execution and returns a run ID
 func (e *WorkflowExecutor) SubmitWorkflow(ctx context.Context, workflow model.Workflow) (string, error) {
-	// For now, simulate submission
-	runID := fmt.Sprintf("run-%d", time.Now().UnixNano())
-	return runID, nil
+	return e.SubmitWorkflowWithInput(ctx, workflow, nil)
 }
 
 // SubmitWorkflowByID submits a workflow by ID for execution and returns a run ID
 func (e *WorkflowExecutor) SubmitWorkflowByID(ctx context.Context, workflowID string) (string, error) {
-	// For now, simulate submission
-	runID := fmt.Sprintf("run-%d", time.Now().UnixNano())
+	workflow, err := e.workflowRegistry.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get workflow: %w", err)
+	}
+	return e.SubmitWorkflow(ctx, workflow)
+}
+
+// SubmitWorkflowWithInput persists a pending run with its input data and queues it for a worker
+// It fails with an admission.RejectedError or a tenant.QuotaError when the run is refused
+func (e *WorkflowExecutor) SubmitWorkflowWithInput(ctx context.Context, workflow model.Workflow, inputData map[string]interface{}) (string, error) {
+	runID := newRunID()
+	if err := e.admit(ctx, workflow.GetID(), runID); err != nil {
+		return "", err
+	}
+	if err := submitRun(ctx, e.stateManagement, e.queue, runID, workflow.GetID(), inputData); err != nil {
+		e.release(runID)
+		return "", err
+	}
 	return runID, nil
 }
 
 // GetExecutionData gets the data of a workflow execution
 func (e *WorkflowExecutor) GetExecutionData(ctx context.Context, runID string) (map[string]interface{}, error) {
-	// For now, return empty data
-	return map[string]interface{}{}, nil
+	return loadExecutionData(ctx, e.stateManagement, runID)
 }
 
 // ListExecutions lists workflow executions with optional filters
@@ -318,12 +937,52 @@ func (e *WorkflowExecutor) ListExecutions(ctx context.Context, filters Execution
 	return []*ExecutionInfo{}, nil
 }
 
-// CancelExecution cancels a running workflow execution
+// CancelExecution cancels a run of the tenant of ctx, along with the sub-workflow runs it started
+// The step in progress sees its context cancelled and no further step starts. A run executing in another process
+// is flagged in the state store and stops once it sees the flag; a parked run is resumed cancelled
+// It fails with ErrStateNotFound for an unknown run and ErrRunFinished for a run that finished
 func (e *WorkflowExecutor) CancelExecution(ctx context.Context, runID string) error {
-	// For now, just return success
+	if cancel, ok := e.active.Load(runKey(ctx, runID)); ok {
+		(*cancel.(*context.CancelCauseFunc))(ErrRunCancelled)
+		return nil
+	}
+	if e.stateManagement == nil {
+		return fmt.Errorf("execution %s not found: %w", runID, state.ErrStateNotFound)
+	}
+
+	runContext, err := e.unfinishedContext(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if err := e.requestCancellation(ctx, runID); err != nil {
+		return err
+	}
+	// A run finishing meanwhile would not drop the flag
+	if runContext, err = e.unfinishedContext(ctx, runID); err != nil {
+		e.clearCancellation(ctx, runID)
+		return err
+	}
+	if parkedContext(runContext) {
+		return e.cancelParkedRun(ctx, runID, runContext.GetWorkflowDefinitionID())
+	}
 	return nil
 }
 
+// unfinishedContext returns the context of a run, failing with ErrRunFinished once the run finished
+func (e *WorkflowExecutor) unfinishedContext(ctx context.Context, runID string) (primitiveModel.WorkflowContext, error) {
+	runContext, err := e.stateManagement.GetContext(ctx, runID)
+	if err != nil {
+		if err == state.ErrStateNotFound {
+			return nil, fmt.Errorf("execution %s not found: %w", runID, err)
+		}
+		return nil, fmt.Errorf("failed to get execution %s: %w", runID, err)
+	}
+	if isTerminalStatus(runContext.GetStatus()) {
+		return nil, fmt.Errorf("%w: run %s is %s", ErrRunFinished, runID, statusName(runContext.GetStatus()))
+	}
+	return runContext, nil
+}
+
 // PauseExecution pauses a running workflow execution
 func (e *WorkflowExecutor) PauseExecution(ctx context.Context, runID string) error {
 	// For now, just return success
@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/registry"
+	"unified-workflow/internal/state"
+)
+
+// testExecutor runs workflows of an in-memory registry on an in-memory state store and queue
+type testExecutor struct {
+	*WorkflowExecutor
+	registry  *registry.InMemoryRegistry
+	stateMgmt state.StateManagement
+	queue     queue.Queue
+	hub       *completion.Hub
+}
+
+func newTestExecutor(t *testing.T) *testExecutor {
+	t.Helper()
+	e := &testExecutor{
+		registry:  registry.NewInMemoryRegistry(),
+		stateMgmt: state.NewInMemoryState(),
+		queue:     queue.NewInMemoryQueue(),
+		hub:       completion.NewHub(0),
+	}
+	e.WorkflowExecutor = e.newExecutor()
+	return e
+}
+
+// newExecutor creates another executor sharing the registry, state store, queue and completion hub, as a worker would
+func (e *testExecutor) newExecutor() *WorkflowExecutor {
+	exec := NewWorkflowExecutor(e.registry, e.stateMgmt, DefaultConfig())
+	exec.SetQueue(e.queue)
+	exec.SetCompletionHub(e.hub)
+	return exec
+}
+
+// register registers workflow
+func (e *testExecutor) register(t *testing.T, workflow model.Workflow) {
+	t.Helper()
+	if err := e.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+}
+
+// execute registers workflow and runs it to the end with input
+func (e *testExecutor) execute(t *testing.T, workflow model.Workflow, input map[string]interface{}) *ExecutionResult {
+	t.Helper()
+	e.register(t, workflow)
+	result, err := e.ExecuteWorkflow(context.Background(), workflow.GetID(), input)
+	if result == nil {
+		t.Fatalf("ExecuteWorkflow() = nil, %v", err)
+	}
+	return result
+}
+
+// status returns the execution status of a run
+func (e *testExecutor) status(t *testing.T, runID string) *ExecutionStatus {
+	t.Helper()
+	status, err := e.GetExecutionStatus(context.Background(), runID)
+	if err != nil {
+		t.Fatalf("GetExecutionStatus() error = %v", err)
+	}
+	return status
+}
+
+// awaitStatus polls the status of a run until it reaches want
+func (e *testExecutor) awaitStatus(t *testing.T, runID, want string) *ExecutionStatus {
+	t.Helper()
+	var status *ExecutionStatus
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if status, _ = e.GetExecutionStatus(context.Background(), runID); status != nil && status.Status == want {
+			return status
+		}
+	}
+	t.Fatalf("status = %+v, want %s", status, want)
+	return nil
+}
+
+// stepStatuses lists the steps of a result as "name=status", in the order they finished
+func stepStatuses(result *ExecutionResult) string {
+	statuses := make([]string, 0, len(result.Steps))
+	for _, step := range result.Steps {
+		statuses = append(statuses, fmt.Sprintf("%s=%s", step.Name, step.Status))
+	}
+	return strings.Join(statuses, ",")
+}
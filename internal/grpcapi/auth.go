@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/tenant"
+	"unified-workflow/pkg/workflowpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodAccess is the permission of each method and whether allowed calls are audited
+var methodAccess = map[string]struct {
+	permission string
+	audited    bool
+}{
+	workflowpb.ExecutionService_ExecuteWorkflow_FullMethodName:    {auth.PermissionExecutionsCreate, false},
+	workflowpb.ExecutionService_SubmitWorkflow_FullMethodName:     {auth.PermissionExecutionsCreate, false},
+	workflowpb.ExecutionService_GetExecutionStatus_FullMethodName: {auth.PermissionExecutionsRead, false},
+	workflowpb.ExecutionService_GetExecutionResult_FullMethodName: {auth.PermissionExecutionsRead, false},
+	workflowpb.ExecutionService_ListExecutions_FullMethodName:     {auth.PermissionExecutionsRead, false},
+	workflowpb.ExecutionService_WatchExecution_FullMethodName:     {auth.PermissionExecutionsRead, false},
+	workflowpb.ExecutionService_CancelExecution_FullMethodName:    {auth.PermissionExecutionsCancel, true},
+	workflowpb.ExecutionService_PauseExecution_FullMethodName:     {auth.PermissionExecutionsControl, true},
+	workflowpb.ExecutionService_ResumeExecution_FullMethodName:    {auth.PermissionExecutionsControl, true},
+	workflowpb.ExecutionService_RetryExecution_FullMethodName:     {auth.PermissionExecutionsControl, true},
+}
+
+// tenantKey is the metadata key naming the tenant of a call
+var tenantKey = strings.ToLower(tenant.Header)
+
+// AuthOptions returns the server options enforcing the permission of every method and attaching
+// the tenant of each call to its context. When authenticator is nil the service is unauthenticated
+// and calls act for the tenant named by their x-tenant-id metadata
+func AuthOptions(authenticator *auth.Authenticator) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, err := authorize(ctx, authenticator, info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := authorize(stream.Context(), authenticator, info.FullMethod)
+			if err != nil {
+				return err
+			}
+			return handler(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+		}),
+	}
+}
+
+// authorize checks the credentials in the call metadata and attaches the principal and its tenant to ctx
+// Methods missing from methodAccess are denied
+func authorize(ctx context.Context, authenticator *auth.Authenticator, method string) (context.Context, error) {
+	requested := metadataValue(ctx, tenantKey)
+	if requested != "" {
+		if err := tenant.Validate(requested); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if authenticator == nil {
+		return tenant.WithTenant(ctx, requested), nil
+	}
+
+	access, ok := methodAccess[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "method %s is not authorized", method)
+	}
+
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	creds := auth.ParseCredentials(metadataValue(ctx, "x-api-key"), metadataValue(ctx, "authorization"))
+	principal, err := authenticator.Authorize(ctx, creds, auth.Access{
+		Permission: access.permission,
+		Resource:   method,
+		RemoteAddr: remoteAddr,
+		Tenant:     requested,
+		Audited:    access.audited,
+	})
+	switch {
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	case err != nil:
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	tenantID, _ := principal.TenantFor(requested)
+	return tenant.WithTenant(auth.WithPrincipal(ctx, principal), tenantID), nil
+}
+
+// authorizedStream carries the context holding the principal into a streaming handler
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context implements grpc.ServerStream
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
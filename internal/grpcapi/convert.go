@@ -0,0 +1,69 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"unified-workflow/internal/executor"
+	"unified-workflow/pkg/workflowpb"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toExecutionStatus converts the status of a run to its protobuf message
+func toExecutionStatus(s *executor.ExecutionStatus) *workflowpb.ExecutionStatus {
+	return &workflowpb.ExecutionStatus{
+		RunId:                 s.RunID,
+		WorkflowId:            s.WorkflowID,
+		Status:                s.Status,
+		CurrentStep:           s.CurrentStep,
+		CurrentStepIndex:      int32(s.CurrentStepIndex),
+		CurrentChildStepIndex: int32(s.CurrentChildStepIndex),
+		Progress:              s.Progress,
+		StartTime:             timestamp(s.StartTime),
+		EndTime:               timestamp(s.EndTime),
+		ErrorMessage:          s.ErrorMessage,
+		LastAttemptedStep:     s.LastAttemptedStep,
+		IsTerminal:            s.IsTerminal,
+	}
+}
+
+// changed reports whether a watcher should be sent the next status of a run
+func changed(last, next *workflowpb.ExecutionStatus) bool {
+	return last.GetStatus() != next.GetStatus() ||
+		last.GetProgress() != next.GetProgress() ||
+		last.GetCurrentStepIndex() != next.GetCurrentStepIndex() ||
+		last.GetCurrentChildStepIndex() != next.GetCurrentChildStepIndex() ||
+		last.GetIsTerminal() != next.GetIsTerminal()
+}
+
+// timestamp converts an optional time, leaving unset times and zero times empty
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// toStruct converts workflow data to a protobuf Struct
+// The data goes through JSON first so that values such as typed slices and structs are accepted
+func toStruct(data map[string]interface{}) (*structpb.Struct, error) {
+	if data == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode data: %w", err)
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode data: %w", err)
+	}
+	result, err := structpb.NewStruct(generic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert data: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,423 @@
+// Package grpcapi serves the execution API over gRPC, next to the REST routes of internal/api
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"unified-workflow/internal/admission"
+	"unified-workflow/internal/callback"
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/dataprotection"
+	"unified-workflow/internal/executor"
+	"unified-workflow/internal/registry"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+	"unified-workflow/pkg/workflowpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 1000
+)
+
+// watchInterval is how often WatchExecution checks a run for progress between completion notifications
+var watchInterval = 500 * time.Millisecond
+
+// clientIDKey is the metadata key naming the client whose secret signs callbacks
+var clientIDKey = strings.ToLower(callback.ClientIDHeader)
+
+// Server implements workflowpb.ExecutionServiceServer on the executor and registry shared with REST
+type Server struct {
+	workflowpb.UnimplementedExecutionServiceServer
+
+	executor  *executor.WorkflowExecutor
+	registry  registry.Registry
+	callbacks *callback.Dispatcher
+}
+
+// NewServer creates the gRPC execution service
+// callbacks may be nil, in which case submissions with a callback_url are rejected
+func NewServer(executor *executor.WorkflowExecutor, registry registry.Registry, callbacks *callback.Dispatcher) *Server {
+	return &Server{
+		executor:  executor,
+		registry:  registry,
+		callbacks: callbacks,
+	}
+}
+
+// NewGRPCServer creates a gRPC server serving the execution service
+func NewGRPCServer(service *Server, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	workflowpb.RegisterExecutionServiceServer(server, service)
+	return server
+}
+
+// Listen serves the execution service on addr in the background; stop it with GracefulStop
+func Listen(addr string, service *Server, opts ...grpc.ServerOption) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := NewGRPCServer(service, opts...)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			slog.Error("gRPC server stopped", "error", err)
+		}
+	}()
+	slog.Info("gRPC server started", "addr", listener.Addr().String())
+	return server, nil
+}
+
+// ExecuteWorkflow runs a workflow, waiting at most timeout_ms for the outcome
+func (s *Server) ExecuteWorkflow(ctx context.Context, req *workflowpb.ExecuteWorkflowRequest) (*workflowpb.ExecuteWorkflowResponse, error) {
+	if req.GetWorkflowId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "workflow_id is required")
+	}
+	if req.GetTimeoutMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "timeout_ms must not be negative")
+	}
+	if _, err := s.registry.GetWorkflow(ctx, req.GetWorkflowId()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "workflow %s not found", req.GetWorkflowId())
+	}
+
+	timeout := time.Duration(req.GetTimeoutMs()) * time.Millisecond
+	runID, result, err := s.executor.ExecuteWorkflowWithTimeout(ctx, req.GetWorkflowId(), req.GetInputData().AsMap(), timeout)
+	if err != nil {
+		return nil, toStatus(err, "failed to execute workflow")
+	}
+
+	// Still running: hand back the run ID so the caller can poll or watch it
+	if result == nil {
+		current := "running"
+		if status, err := s.executor.GetExecutionStatus(ctx, runID); err == nil {
+			current = status.Status
+		}
+		return &workflowpb.ExecuteWorkflowResponse{
+			RunId:      runID,
+			WorkflowId: req.GetWorkflowId(),
+			Status:     current,
+		}, nil
+	}
+
+	output, err := toStruct(dataprotection.MaskForCaller(ctx, result.Result))
+	if err != nil {
+		return nil, toStatus(err, "failed to encode workflow output")
+	}
+	return &workflowpb.ExecuteWorkflowResponse{
+		RunId:      result.RunID,
+		WorkflowId: result.WorkflowID,
+		Status:     result.Status,
+		Completed:  true,
+		Result: &workflowpb.ExecutionResult{
+			RunId:               result.RunID,
+			WorkflowId:          result.WorkflowID,
+			Status:              result.Status,
+			OutputData:          output,
+			ErrorMessage:        result.Error,
+			StartTime:           timestamp(&result.StartTime),
+			EndTime:             timestamp(&result.EndTime),
+			ExecutionTimeMillis: result.EndTime.Sub(result.StartTime).Milliseconds(),
+			StepCount:           int32(s.stepCount(ctx, result.WorkflowID)),
+		},
+	}, nil
+}
+
+// SubmitWorkflow queues a workflow run, optionally waiting up to timeout_ms for it to finish
+func (s *Server) SubmitWorkflow(ctx context.Context, req *workflowpb.SubmitWorkflowRequest) (*workflowpb.SubmitWorkflowResponse, error) {
+	if req.GetWorkflowId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "workflow_id is required")
+	}
+	if req.GetTimeoutMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "timeout_ms must not be negative")
+	}
+
+	// Reject callbacks that could never be delivered before queuing the run
+	clientID := metadataValue(ctx, clientIDKey)
+	if req.GetCallbackUrl() != "" {
+		if s.callbacks == nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid callback_url: callbacks are disabled")
+		}
+		if err := s.callbacks.Validate(req.GetCallbackUrl(), clientID); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid callback_url: %v", err)
+		}
+	}
+
+	workflow, err := s.registry.GetWorkflow(ctx, req.GetWorkflowId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "workflow %s not found", req.GetWorkflowId())
+	}
+
+	runID, err := s.executor.SubmitWorkflowWithInput(ctx, workflow, req.GetInputData().AsMap())
+	if err != nil {
+		return nil, toStatus(err, "failed to submit workflow")
+	}
+	if req.GetCallbackUrl() != "" {
+		if err := s.callbacks.Register(ctx, runID, req.GetCallbackUrl(), clientID); err != nil {
+			return nil, toStatus(err, "failed to register callback for run "+runID)
+		}
+	}
+
+	response := &workflowpb.SubmitWorkflowResponse{RunId: runID, Status: "queued"}
+	if req.GetWaitForCompletion() {
+		timeout := time.Duration(req.GetTimeoutMs()) * time.Millisecond
+		if finished, ok := s.waitForResult(ctx, runID, timeout); ok {
+			result, err := s.result(ctx, finished)
+			if err != nil {
+				return nil, err
+			}
+			response.Status = finished.Status
+			response.Result = result
+		}
+	}
+	return response, nil
+}
+
+// GetExecutionStatus returns the status of a run
+func (s *Server) GetExecutionStatus(ctx context.Context, req *workflowpb.GetExecutionStatusRequest) (*workflowpb.ExecutionStatus, error) {
+	if req.GetRunId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "run_id is required")
+	}
+	current, err := s.executor.GetExecutionStatus(ctx, req.GetRunId())
+	if err != nil {
+		return nil, toStatus(err, "failed to get execution status")
+	}
+	return toExecutionStatus(current), nil
+}
+
+// GetExecutionResult returns the result of a run, waiting up to wait_ms for an unfinished one
+func (s *Server) GetExecutionResult(ctx context.Context, req *workflowpb.GetExecutionResultRequest) (*workflowpb.GetExecutionResultResponse, error) {
+	if req.GetRunId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "run_id is required")
+	}
+	if req.GetWaitMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "wait_ms must not be negative")
+	}
+
+	current, err := s.executor.GetExecutionStatus(ctx, req.GetRunId())
+	if err != nil {
+		return nil, toStatus(err, "failed to get execution status")
+	}
+	if !current.IsTerminal && req.GetWaitMs() > 0 {
+		if finished, ok := s.waitForResult(ctx, req.GetRunId(), time.Duration(req.GetWaitMs())*time.Millisecond); ok {
+			current = finished
+		}
+	}
+
+	response := &workflowpb.GetExecutionResultResponse{Ready: current.IsTerminal, Status: toExecutionStatus(current)}
+	if current.IsTerminal {
+		if response.Result, err = s.result(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// ListExecutions lists workflow runs
+func (s *Server) ListExecutions(ctx context.Context, req *workflowpb.ListExecutionsRequest) (*workflowpb.ListExecutionsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if limit < 0 || limit > maxListLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxListLimit)
+	}
+	if req.GetOffset() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+
+	executions, err := s.executor.ListExecutions(ctx, executor.ExecutionFilters{
+		WorkflowID: req.GetWorkflowId(),
+		Status:     req.GetStatus(),
+		Limit:      limit,
+		Offset:     int(req.GetOffset()),
+	})
+	if err != nil {
+		return nil, toStatus(err, "failed to list executions")
+	}
+
+	response := &workflowpb.ListExecutionsResponse{Limit: int32(limit), Offset: req.GetOffset()}
+	for _, exec := range executions {
+		response.Executions = append(response.Executions, &workflowpb.ExecutionStatus{
+			RunId:                 exec.RunID,
+			WorkflowId:            exec.WorkflowDefinitionID,
+			Status:                exec.Status,
+			CurrentStepIndex:      int32(exec.CurrentStepIndex),
+			CurrentChildStepIndex: int32(exec.CurrentChildStepIndex),
+			StartTime:             timestamp(exec.StartTime),
+			EndTime:               timestamp(exec.EndTime),
+			ErrorMessage:          exec.ErrorMessage,
+			LastAttemptedStep:     exec.LastAttemptedStep,
+			IsTerminal:            exec.IsTerminal,
+		})
+	}
+	return response, nil
+}
+
+// CancelExecution cancels a running workflow execution
+func (s *Server) CancelExecution(ctx context.Context, req *workflowpb.ExecutionControlRequest) (*workflowpb.ExecutionControlResponse, error) {
+	return s.control(ctx, req, s.executor.CancelExecution, "cancel", "Execution cancelled successfully")
+}
+
+// PauseExecution pauses a running workflow execution
+func (s *Server) PauseExecution(ctx context.Context, req *workflowpb.ExecutionControlRequest) (*workflowpb.ExecutionControlResponse, error) {
+	return s.control(ctx, req, s.executor.PauseExecution, "pause", "Execution paused successfully")
+}
+
+// ResumeExecution resumes a paused workflow execution
+func (s *Server) ResumeExecution(ctx context.Context, req *workflowpb.ExecutionControlRequest) (*workflowpb.ExecutionControlResponse, error) {
+	return s.control(ctx, req, s.executor.ResumeExecution, "resume", "Execution resumed successfully")
+}
+
+// RetryExecution retries a failed workflow execution
+func (s *Server) RetryExecution(ctx context.Context, req *workflowpb.ExecutionControlRequest) (*workflowpb.ExecutionControlResponse, error) {
+	return s.control(ctx, req, s.executor.RetryExecution, "retry", "Execution retry initiated successfully")
+}
+
+// control applies a state change to a run
+func (s *Server) control(ctx context.Context, req *workflowpb.ExecutionControlRequest, action func(ctx context.Context, runID string) error, verb, message string) (*workflowpb.ExecutionControlResponse, error) {
+	if req.GetRunId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "run_id is required")
+	}
+	if err := action(ctx, req.GetRunId()); err != nil {
+		return nil, toStatus(err, fmt.Sprintf("failed to %s execution", verb))
+	}
+	return &workflowpb.ExecutionControlResponse{RunId: req.GetRunId(), Message: message}, nil
+}
+
+// WatchExecution streams the status of a run each time it changes and returns once the run is terminal
+func (s *Server) WatchExecution(req *workflowpb.WatchExecutionRequest, stream workflowpb.ExecutionService_WatchExecutionServer) error {
+	if req.GetRunId() == "" {
+		return status.Error(codes.InvalidArgument, "run_id is required")
+	}
+	ctx := stream.Context()
+
+	// Wake up as soon as the run finishes instead of waiting for the next check
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		_, _ = s.executor.WaitForResult(ctx, req.GetRunId(), 0)
+	}()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var last *workflowpb.ExecutionStatus
+	for {
+		current, err := s.executor.GetExecutionStatus(ctx, req.GetRunId())
+		if err != nil {
+			return toStatus(err, "failed to get execution status")
+		}
+		next := toExecutionStatus(current)
+		if last == nil || changed(last, next) {
+			if err := stream.Send(next); err != nil {
+				return err
+			}
+			last = next
+		}
+		if current.IsTerminal {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return toStatus(ctx.Err(), "watch ended")
+		case <-finished:
+			finished = nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForResult waits up to timeout for a run to finish and returns its final status
+func (s *Server) waitForResult(ctx context.Context, runID string, timeout time.Duration) (*executor.ExecutionStatus, bool) {
+	result, err := s.executor.WaitForResult(ctx, runID, timeout)
+	if err != nil || result == nil {
+		return nil, false
+	}
+	finished, err := s.executor.GetExecutionStatus(ctx, runID)
+	if err != nil || !finished.IsTerminal {
+		return nil, false
+	}
+	return finished, true
+}
+
+// result builds the result of a finished run from its status and data
+func (s *Server) result(ctx context.Context, finished *executor.ExecutionStatus) (*workflowpb.ExecutionResult, error) {
+	data, err := s.executor.GetExecutionData(ctx, finished.RunID)
+	if err != nil {
+		data = make(map[string]interface{})
+	}
+	output, err := toStruct(dataprotection.MaskForCaller(ctx, data))
+	if err != nil {
+		return nil, toStatus(err, "failed to encode workflow output")
+	}
+
+	var elapsed int64
+	if finished.StartTime != nil && finished.EndTime != nil {
+		elapsed = finished.EndTime.Sub(*finished.StartTime).Milliseconds()
+	}
+	return &workflowpb.ExecutionResult{
+		RunId:               finished.RunID,
+		WorkflowId:          finished.WorkflowID,
+		Status:              finished.Status,
+		OutputData:          output,
+		ErrorMessage:        finished.ErrorMessage,
+		StartTime:           timestamp(finished.StartTime),
+		EndTime:             timestamp(finished.EndTime),
+		ExecutionTimeMillis: elapsed,
+		StepCount:           int32(s.stepCount(ctx, finished.WorkflowID)),
+	}, nil
+}
+
+// stepCount returns the number of steps of a workflow, or 0 if it is no longer registered
+func (s *Server) stepCount(ctx context.Context, workflowID string) int {
+	workflow, err := s.registry.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return 0
+	}
+	return workflow.GetStepCount()
+}
+
+// toStatus maps an error to the gRPC status of the same failure
+func toStatus(err error, message string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, state.ErrStateNotFound):
+		code = codes.NotFound
+		message = "execution not found"
+	case errors.Is(err, executor.ErrRunFinished):
+		code = codes.FailedPrecondition
+	case errors.Is(err, completion.ErrTooManyWaiters), errors.Is(err, tenant.ErrQuotaExceeded),
+		errors.Is(err, admission.ErrRejected):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Errorf(code, "%s: %v", message, err)
+}
+
+// metadataValue returns the first value of an incoming metadata key
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
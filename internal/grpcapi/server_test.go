@@ -0,0 +1,278 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/auth"
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/completion"
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/executor"
+	"unified-workflow/internal/primitive"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/registry"
+	"unified-workflow/internal/state"
+	"unified-workflow/pkg/workflowpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// testServer serves the execution API over an in-process bufconn listener
+type testServer struct {
+	client    workflowpb.ExecutionServiceClient
+	registry  *registry.InMemoryRegistry
+	queue     queue.Queue
+	stateMgmt state.StateManagement
+	hub       *completion.Hub
+}
+
+func newTestServer(t *testing.T, opts ...grpc.ServerOption) *testServer {
+	t.Helper()
+	if err := primitive.Init(&primitive.Config{EchoEnabled: true}); err != nil {
+		t.Fatalf("primitive.Init() error = %v", err)
+	}
+
+	reg := registry.NewInMemoryRegistry()
+	q := queue.NewInMemoryQueue()
+	stateMgmt := state.NewInMemoryState()
+	hub := completion.NewHub(0)
+	exec := executor.NewWorkflowExecutor(reg, stateMgmt, executor.DefaultConfig())
+	exec.SetQueue(q)
+	exec.SetCompletionHub(hub)
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(NewServer(exec, reg, nil), opts...)
+	go func() { _ = server.Serve(listener) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return &testServer{
+		client:    workflowpb.NewExecutionServiceClient(conn),
+		registry:  reg,
+		queue:     q,
+		stateMgmt: stateMgmt,
+		hub:       hub,
+	}
+}
+
+// startWorker starts the embedded worker that drains the in-memory queue
+func (s *testServer) startWorker(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	local := executor.NewWorkflowExecutor(s.registry, s.stateMgmt, executor.DefaultConfig())
+	local.SetCompletionHub(s.hub)
+	stop, err := executor.StartResultRouting(ctx, s.queue, s.stateMgmt, local, s.hub)
+	if err != nil {
+		t.Fatalf("StartResultRouting() error = %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		stop()
+	})
+}
+
+func (s *testServer) registerWorkflow(t *testing.T) model.Workflow {
+	t.Helper()
+	step := model.NewBaseStep("validate", false)
+	step.AddChildStep(model.NewChildStep("check-limits", nil, nil, nil))
+	workflow := model.NewBaseWorkflow("payment", "grpc test workflow")
+	workflow.AddStep(step)
+	if err := s.registry.RegisterWorkflow(context.Background(), workflow); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return workflow
+}
+
+func testInput(t *testing.T) *structpb.Struct {
+	t.Helper()
+	input, err := structpb.NewStruct(map[string]interface{}{
+		"amount":     "100000",
+		"client_pan": "4111111111111111",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+func TestExecuteWorkflowReturnsResult(t *testing.T) {
+	server := newTestServer(t)
+	workflow := server.registerWorkflow(t)
+
+	resp, err := server.client.ExecuteWorkflow(context.Background(), &workflowpb.ExecuteWorkflowRequest{
+		WorkflowId: workflow.GetID(),
+		InputData:  testInput(t),
+		TimeoutMs:  5000,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if !resp.GetCompleted() || resp.GetStatus() != "completed" {
+		t.Fatalf("ExecuteWorkflow() = completed %v status %q, want completed", resp.GetCompleted(), resp.GetStatus())
+	}
+	output := resp.GetResult().GetOutputData().AsMap()
+	if output["amount"] != "100000" {
+		t.Errorf("output lost input amount: %v", output)
+	}
+	if output["client_pan"] != "************1111" {
+		t.Errorf("output client_pan = %v, want masked value", output["client_pan"])
+	}
+	if resp.GetResult().GetStepCount() != 1 {
+		t.Errorf("step_count = %d, want 1", resp.GetResult().GetStepCount())
+	}
+}
+
+func TestSubmitWorkflowAndWatchUntilTerminal(t *testing.T) {
+	server := newTestServer(t)
+	workflow := server.registerWorkflow(t)
+	ctx := context.Background()
+
+	submitted, err := server.client.SubmitWorkflow(ctx, &workflowpb.SubmitWorkflowRequest{
+		WorkflowId: workflow.GetID(),
+		InputData:  testInput(t),
+	})
+	if err != nil {
+		t.Fatalf("SubmitWorkflow() error = %v", err)
+	}
+	if submitted.GetRunId() == "" || submitted.GetStatus() != "queued" {
+		t.Fatalf("SubmitWorkflow() = %v, want a queued run", submitted)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	stream, err := server.client.WatchExecution(ctx, &workflowpb.WatchExecutionRequest{RunId: submitted.GetRunId()})
+	if err != nil {
+		t.Fatalf("WatchExecution() error = %v", err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("first watch update error = %v", err)
+	}
+	if first.GetIsTerminal() {
+		t.Fatalf("first watch update is terminal before the worker started: %v", first)
+	}
+
+	server.startWorker(t)
+	var last *workflowpb.ExecutionStatus
+	for {
+		update, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("watch update error = %v", err)
+		}
+		last = update
+	}
+	if last == nil || !last.GetIsTerminal() || last.GetStatus() != "completed" {
+		t.Fatalf("last watch update = %v, want completed", last)
+	}
+
+	result, err := server.client.GetExecutionResult(ctx, &workflowpb.GetExecutionResultRequest{RunId: submitted.GetRunId()})
+	if err != nil {
+		t.Fatalf("GetExecutionResult() error = %v", err)
+	}
+	if !result.GetReady() || result.GetResult().GetStatus() != "completed" {
+		t.Errorf("GetExecutionResult() = %v, want a completed result", result)
+	}
+
+	list, err := server.client.ListExecutions(ctx, &workflowpb.ListExecutionsRequest{WorkflowId: workflow.GetID()})
+	if err != nil {
+		t.Fatalf("ListExecutions() error = %v", err)
+	}
+	if list.GetLimit() != defaultListLimit || list.GetOffset() != 0 {
+		t.Errorf("ListExecutions() limit %d offset %d, want %d and 0", list.GetLimit(), list.GetOffset(), defaultListLimit)
+	}
+}
+
+func TestRequestErrorsMapToStatusCodes(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"missing workflow id", func() error {
+			_, err := server.client.ExecuteWorkflow(ctx, &workflowpb.ExecuteWorkflowRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown workflow", func() error {
+			_, err := server.client.SubmitWorkflow(ctx, &workflowpb.SubmitWorkflowRequest{WorkflowId: "missing"})
+			return err
+		}, codes.NotFound},
+		{"unknown run", func() error {
+			_, err := server.client.GetExecutionStatus(ctx, &workflowpb.GetExecutionStatusRequest{RunId: "run-unknown"})
+			return err
+		}, codes.NotFound},
+		{"callbacks disabled", func() error {
+			_, err := server.client.SubmitWorkflow(ctx, &workflowpb.SubmitWorkflowRequest{WorkflowId: "w", CallbackUrl: "https://example.com/hook"})
+			return err
+		}, codes.InvalidArgument},
+		{"limit too large", func() error {
+			_, err := server.client.ListExecutions(ctx, &workflowpb.ListExecutionsRequest{Limit: maxListLimit + 1})
+			return err
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.code {
+				t.Errorf("status code = %v, want %v", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestAuthOptionsEnforceMethodPermissions(t *testing.T) {
+	cfg := config.DefaultConfig().Auth
+	cfg.Enabled = true
+	cfg.APIKeys = []config.APIKeyConfig{{ID: "viewer", Key: "viewer-key", Roles: []string{"viewer"}}}
+	authenticator, err := auth.NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestServer(t, AuthOptions(authenticator)...)
+	workflow := server.registerWorkflow(t)
+
+	anonymous := context.Background()
+	if _, err := server.client.ListExecutions(anonymous, &workflowpb.ListExecutionsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("anonymous ListExecutions() code = %v, want Unauthenticated", status.Code(err))
+	}
+
+	viewer := metadata.AppendToOutgoingContext(anonymous, "x-api-key", "viewer-key")
+	if _, err := server.client.ListExecutions(viewer, &workflowpb.ListExecutionsRequest{}); err != nil {
+		t.Errorf("viewer ListExecutions() error = %v", err)
+	}
+	if _, err := server.client.SubmitWorkflow(viewer, &workflowpb.SubmitWorkflowRequest{WorkflowId: workflow.GetID()}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer SubmitWorkflow() code = %v, want PermissionDenied", status.Code(err))
+	}
+
+	stream, err := server.client.WatchExecution(anonymous, &workflowpb.WatchExecutionRequest{RunId: "run-unknown"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("anonymous WatchExecution() code = %v, want Unauthenticated", status.Code(err))
+	}
+}
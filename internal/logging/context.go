@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Correlation identifies the run and step a log record belongs to
+type Correlation struct {
+	RunID      string
+	WorkflowID string
+	Step       string
+	ChildStep  string
+	TraceID    string
+}
+
+type correlationKey struct{}
+
+// CorrelationFromContext returns the correlation fields carried by ctx
+func CorrelationFromContext(ctx context.Context) Correlation {
+	if ctx == nil {
+		return Correlation{}
+	}
+	correlation, _ := ctx.Value(correlationKey{}).(Correlation)
+	return correlation
+}
+
+// WithCorrelation returns a context carrying the given correlation fields
+func WithCorrelation(ctx context.Context, correlation Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlation)
+}
+
+// WithRun attaches the run and workflow IDs to ctx
+func WithRun(ctx context.Context, runID, workflowID string) context.Context {
+	correlation := CorrelationFromContext(ctx)
+	correlation.RunID = runID
+	correlation.WorkflowID = workflowID
+	return WithCorrelation(ctx, correlation)
+}
+
+// WithStep attaches the step name to ctx and clears any child step
+func WithStep(ctx context.Context, step string) context.Context {
+	correlation := CorrelationFromContext(ctx)
+	correlation.Step = step
+	correlation.ChildStep = ""
+	return WithCorrelation(ctx, correlation)
+}
+
+// WithChildStep attaches the child step name to ctx
+func WithChildStep(ctx context.Context, childStep string) context.Context {
+	correlation := CorrelationFromContext(ctx)
+	correlation.ChildStep = childStep
+	return WithCorrelation(ctx, correlation)
+}
+
+// WithTraceID attaches the trace ID to ctx
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	correlation := CorrelationFromContext(ctx)
+	correlation.TraceID = traceID
+	return WithCorrelation(ctx, correlation)
+}
+
+// attrs returns the non-empty correlation fields as log attributes
+func (c Correlation) attrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, 5)
+	for _, field := range []struct{ key, value string }{
+		{"run_id", c.RunID},
+		{"workflow_id", c.WorkflowID},
+		{"step", c.Step},
+		{"child_step", c.ChildStep},
+		{"trace_id", c.TraceID},
+	} {
+		if field.value != "" {
+			attrs = append(attrs, slog.String(field.key, field.value))
+		}
+	}
+	return attrs
+}
+
+// contextHandler adds the correlation fields carried by the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle implements slog.Handler
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := CorrelationFromContext(ctx).attrs(); len(attrs) > 0 {
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// Debug logs at debug level with the correlation fields from ctx
+func Debug(ctx context.Context, msg string, args ...any) {
+	slog.Default().Log(ctx, slog.LevelDebug, msg, args...)
+}
+
+// Info logs at info level with the correlation fields from ctx
+func Info(ctx context.Context, msg string, args ...any) {
+	slog.Default().Log(ctx, slog.LevelInfo, msg, args...)
+}
+
+// Warn logs at warn level with the correlation fields from ctx
+func Warn(ctx context.Context, msg string, args ...any) {
+	slog.Default().Log(ctx, slog.LevelWarn, msg, args...)
+}
+
+// Error logs at error level with the correlation fields from ctx
+func Error(ctx context.Context, msg string, args ...any) {
+	slog.Default().Log(ctx, slog.LevelError, msg, args...)
+}
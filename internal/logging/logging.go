@@ -0,0 +1,155 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"unified-workflow/internal/config"
+)
+
+var (
+	// level is the process-wide minimum log level, adjustable at runtime
+	level = new(slog.LevelVar)
+
+	// sampleRate holds the float64 bits of the fraction of debug/info records kept
+	sampleRate atomic.Uint64
+)
+
+func init() {
+	sampleRate.Store(math.Float64bits(1))
+}
+
+// Init installs the process-wide slog handler; the standard log package is routed through it
+func Init(cfg config.LoggingConfig) error {
+	if err := Configure(cfg); err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(NewHandler(os.Stderr, cfg.Format)))
+	return nil
+}
+
+// NewHandler creates a handler honouring the runtime level and sampling rate
+// Records get the correlation fields from their context and sensitive fields are redacted
+func NewHandler(w io.Writer, format string) slog.Handler {
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "text") {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+	return &contextHandler{Handler: &samplingHandler{Handler: handler}}
+}
+
+// Configure applies the level and sampling rate from the logging configuration
+func Configure(cfg config.LoggingConfig) error {
+	parsedLevel, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	rate, err := normalizeSampleRate(cfg.SampleRate)
+	if err != nil {
+		return err
+	}
+
+	level.Set(parsedLevel)
+	sampleRate.Store(math.Float64bits(rate))
+	return nil
+}
+
+// ParseLevel parses a level name ("debug", "info", "warn", "error"); empty means info
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// normalizeSampleRate validates a sampling rate; 0 (unset) means keep everything
+func normalizeSampleRate(rate float64) (float64, error) {
+	if math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("log sample rate must be between 0 and 1, got %v", rate)
+	}
+	if rate == 0 {
+		return 1, nil
+	}
+	return rate, nil
+}
+
+// Level returns the current minimum log level
+func Level() slog.Level {
+	return level.Level()
+}
+
+// SampleRate returns the fraction of debug and info records that are kept
+func SampleRate() float64 {
+	return math.Float64frombits(sampleRate.Load())
+}
+
+// samplingHandler drops a fraction of debug and info records; warnings and errors are always kept
+type samplingHandler struct {
+	slog.Handler
+}
+
+// Handle implements slog.Handler
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < slog.LevelWarn {
+		if rate := SampleRate(); rate < 1 && rand.Float64() >= rate {
+			return nil
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// ConfigOwner applies the logging section of the configuration at runtime
+type ConfigOwner struct{}
+
+// Name implements config.SectionOwner
+func (ConfigOwner) Name() string {
+	return "logging"
+}
+
+// Sections implements config.SectionOwner
+func (ConfigOwner) Sections() []string {
+	return []string{"logging"}
+}
+
+// ValidateConfig implements config.SectionOwner
+func (ConfigOwner) ValidateConfig(cfg *config.Config) error {
+	if _, err := ParseLevel(cfg.Logging.Level); err != nil {
+		return err
+	}
+	_, err := normalizeSampleRate(cfg.Logging.SampleRate)
+	return err
+}
+
+// ApplyConfig implements config.SectionOwner; the output format only changes on restart
+func (ConfigOwner) ApplyConfig(oldConfig, newConfig *config.Config) error {
+	return Configure(newConfig.Logging)
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestHandlerAddsCorrelationAndRedacts(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, "json"))
+
+	ctx := WithRun(context.Background(), "run-1", "wf-1")
+	ctx = WithStep(ctx, "aml-validation")
+	ctx = WithChildStep(ctx, "prepare-request")
+	ctx = WithTraceID(ctx, "trace-1")
+
+	logger.InfoContext(ctx, "calling service",
+		"api_key", "secret-value",
+		"request", map[string]interface{}{"ClientPAN": "4111111111111111", "amount": "100"})
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON log line %q: %v", buf.String(), err)
+	}
+
+	for key, want := range map[string]string{
+		"run_id":      "run-1",
+		"workflow_id": "wf-1",
+		"step":        "aml-validation",
+		"child_step":  "prepare-request",
+		"trace_id":    "trace-1",
+		"api_key":     RedactedValue,
+	} {
+		if got := record[key]; got != want {
+			t.Errorf("%s = %v, want %q", key, got, want)
+		}
+	}
+
+	request, _ := record["request"].(map[string]interface{})
+	if request["ClientPAN"] != "************1111" || request["amount"] != "100" {
+		t.Errorf("request = %v, want PAN masked and amount kept", request)
+	}
+}
+
+func TestWithStepClearsChildStep(t *testing.T) {
+	ctx := WithChildStep(WithStep(context.Background(), "first"), "child")
+	ctx = WithStep(ctx, "second")
+
+	if correlation := CorrelationFromContext(ctx); correlation.Step != "second" || correlation.ChildStep != "" {
+		t.Errorf("correlation = %+v, want step=second without child step", correlation)
+	}
+}
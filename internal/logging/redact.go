@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"unified-workflow/internal/dataprotection"
+)
+
+// RedactedValue replaces the value of sensitive fields
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeys are normalized names of credential fields whose values are never logged
+// Card data and PII are masked according to the data protection policy instead
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"apikey":        true,
+	"authorization": true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"clientsecret":  true,
+	"privatekey":    true,
+}
+
+// IsSensitiveKey reports whether a field name holds a credential
+func IsSensitiveKey(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(key))
+	if sensitiveKeys[normalized] {
+		return true
+	}
+	return strings.HasSuffix(normalized, "password") || strings.HasSuffix(normalized, "secret") || strings.HasSuffix(normalized, "token")
+}
+
+// Redact returns a copy of fields with credentials replaced and classified data masked, descending into nested maps
+func Redact(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		redacted[key] = redactValue(key, value)
+	}
+	return redacted
+}
+
+// redactValue redacts a single field value
+func redactValue(key string, value interface{}) interface{} {
+	if IsSensitiveKey(key) {
+		return RedactedValue
+	}
+	if class, ok := dataprotection.CurrentPolicy().Classify(key); ok {
+		return dataprotection.MaskValue(class, value)
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return Redact(v)
+	case map[string]string:
+		redacted := make(map[string]string, len(v))
+		for nestedKey, nestedValue := range v {
+			redacted[nestedKey] = fmt.Sprint(redactValue(nestedKey, nestedValue))
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue("", item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// redactAttr is the handler ReplaceAttr hook that masks sensitive attributes
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+	if class, ok := dataprotection.CurrentPolicy().Classify(attr.Key); ok {
+		return slog.Any(attr.Key, dataprotection.MaskValue(class, attr.Value.Any()))
+	}
+	if attr.Value.Kind() == slog.KindAny {
+		switch attr.Value.Any().(type) {
+		case map[string]interface{}, map[string]string, []interface{}:
+			return slog.Any(attr.Key, redactValue(attr.Key, attr.Value.Any()))
+		}
+	}
+	return attr
+}
+
+// FieldAttrs converts a field map to redacted log arguments
+func FieldAttrs(fields map[string]interface{}) []any {
+	args := make([]any, 0, len(fields))
+	for key, value := range fields {
+		args = append(args, slog.Any(key, redactValue(key, value)))
+	}
+	return args
+}
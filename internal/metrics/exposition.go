@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the Prometheus text exposition content type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// overflowMetricName reports how many observations were folded into the overflow series
+const overflowMetricName = "uwf_metrics_series_overflow_total"
+
+// Handler returns an HTTP handler serving the registry in the Prometheus text format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// WriteText runs the collectors and writes all families in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.collect()
+
+	bw := bufio.NewWriter(w)
+	overflows := make(map[string]int64)
+
+	for _, f := range r.sortedFamilies() {
+		f.writeText(bw)
+		if n := f.overflowed.Load(); n > 0 {
+			overflows[f.name] = n
+		}
+	}
+
+	if len(overflows) > 0 {
+		bw.WriteString("# HELP " + overflowMetricName + " Observations folded into the overflow series by the label cardinality guard\n")
+		bw.WriteString("# TYPE " + overflowMetricName + " counter\n")
+		names := make([]string, 0, len(overflows))
+		for name := range overflows {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			bw.WriteString(overflowMetricName + `{metric="` + escapeLabelValue(name) + `"} ` + formatFloat(float64(overflows[name])) + "\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeText writes a single family
+func (f *family) writeText(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+		return
+	}
+
+	w.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	w.WriteString("# TYPE " + f.name + " " + string(f.typ) + "\n")
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.typ != HistogramType {
+			w.WriteString(f.name + formatLabels(f.labelNames, s.labelValues, "", "") + " " + formatFloat(s.value) + "\n")
+			continue
+		}
+
+		for i, upperBound := range f.buckets {
+			w.WriteString(f.name + "_bucket" + formatLabels(f.labelNames, s.labelValues, "le", formatFloat(upperBound)) +
+				" " + strconv.FormatUint(s.bucketCounts[i], 10) + "\n")
+		}
+		w.WriteString(f.name + "_bucket" + formatLabels(f.labelNames, s.labelValues, "le", "+Inf") +
+			" " + strconv.FormatUint(s.count, 10) + "\n")
+		w.WriteString(f.name + "_sum" + formatLabels(f.labelNames, s.labelValues, "", "") + " " + formatFloat(s.sum) + "\n")
+		w.WriteString(f.name + "_count" + formatLabels(f.labelNames, s.labelValues, "", "") + " " + strconv.FormatUint(s.count, 10) + "\n")
+	}
+}
+
+// formatLabels renders a label set, optionally with an extra label (used for histogram "le")
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(values[i]))
+		b.WriteByte('"')
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extraName)
+		b.WriteString(`="`)
+		b.WriteString(extraValue)
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// formatFloat formats a sample value the way Prometheus expects
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// escapeLabelValue escapes backslashes, newlines and quotes in label values
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// escapeHelp escapes backslashes and newlines in help text
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
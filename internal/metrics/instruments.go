@@ -0,0 +1,97 @@
+package metrics
+
+// Built-in instruments shared by the executor, queue and DI packages
+
+var (
+	// WorkflowRuns counts finished workflow runs by final status
+	WorkflowRuns = Default.Counter("uwf_workflow_runs_total",
+		"Workflow runs by final status", "workflow_id", "status")
+
+	// WorkflowRunsActive tracks workflow runs currently executing
+	WorkflowRunsActive = Default.Gauge("uwf_workflow_runs_active",
+		"Workflow runs currently executing", "workflow_id")
+
+	// WorkflowRunDuration observes end-to-end workflow run latency
+	WorkflowRunDuration = Default.Histogram("uwf_workflow_run_duration_seconds",
+		"Workflow run latency in seconds", DefBuckets, "workflow_id", "status")
+
+	// StepDuration observes step latency by step name
+	StepDuration = Default.Histogram("uwf_step_duration_seconds",
+		"Step latency in seconds", DefBuckets, "workflow_id", "step", "status")
+
+	// ChildStepDuration observes child-step latency by step and child-step name
+	ChildStepDuration = Default.Histogram("uwf_child_step_duration_seconds",
+		"Child-step latency in seconds", DefBuckets, "workflow_id", "step", "child_step", "status")
+
+	// HooksAbandoned counts hooks and step logic abandoned after ignoring the cancellation of their step or run
+	HooksAbandoned = Default.Counter("uwf_hooks_abandoned_total",
+		"Hooks abandoned after ignoring cancellation", "workflow_id", "step")
+
+	// HooksLeaked tracks abandoned hooks that have not returned yet
+	HooksLeaked = Default.Gauge("uwf_hooks_leaked",
+		"Abandoned hooks still running", "workflow_id")
+
+	// Compensations counts compensations of steps and child steps by outcome (compensated, compensation_failed)
+	Compensations = Default.Counter("uwf_compensations_total",
+		"Compensations of steps and child steps by outcome", "workflow_id", "step", "status")
+
+	// CompensationDuration observes compensation latency, retries included
+	CompensationDuration = Default.Histogram("uwf_compensation_duration_seconds",
+		"Compensation latency in seconds, retries included", DefBuckets, "workflow_id", "step", "status")
+
+	// QueueDepth reports the number of messages waiting in a queue
+	QueueDepth = Default.Gauge("uwf_queue_depth",
+		"Messages waiting in the queue", "queue")
+
+	// QueueMessages counts queue operations
+	QueueMessages = Default.Counter("uwf_queue_messages_total",
+		"Queue operations by type", "queue", "operation")
+
+	// QueueRedeliveries counts messages delivered more than once
+	QueueRedeliveries = Default.Counter("uwf_queue_redeliveries_total",
+		"Messages delivered more than once", "queue")
+
+	// DIResolutionDuration observes DI container resolution latency by component
+	DIResolutionDuration = Default.Histogram("uwf_di_resolution_duration_seconds",
+		"DI resolution latency in seconds", FastBuckets, "component", "result")
+
+	// CircuitBreakerState reports breaker state (0 = closed, 1 = open, 2 = half-open)
+	CircuitBreakerState = Default.Gauge("uwf_circuit_breaker_state",
+		"Circuit breaker state (0 = closed, 1 = open, 2 = half-open)", "breaker")
+
+	// CircuitBreakerRequests mirrors breaker request totals by outcome
+	CircuitBreakerRequests = Default.Counter("uwf_circuit_breaker_requests_total",
+		"Circuit breaker requests by outcome", "breaker", "result")
+
+	// CompletionWaiters tracks requests parked waiting for a run to finish
+	CompletionWaiters = Default.Gauge("uwf_completion_waiters",
+		"Requests waiting for a run to finish")
+
+	// CompletionWaitersRejected counts waits refused because the waiter limit was reached
+	CompletionWaitersRejected = Default.Counter("uwf_completion_waiters_rejected_total",
+		"Waits refused because the waiter limit was reached")
+
+	// CallbackDeliveries counts callback delivery attempts by outcome (delivered, retry, failed)
+	CallbackDeliveries = Default.Counter("uwf_callback_deliveries_total",
+		"Callback delivery attempts by outcome", "result")
+
+	// TenantRunsActive tracks the runs admitted per tenant that have not finished
+	TenantRunsActive = Default.Gauge("uwf_tenant_runs_active",
+		"Admitted workflow runs that have not finished, per tenant", "tenant")
+
+	// TenantQuotaRejections counts runs refused by a tenant quota (rate, concurrency)
+	TenantQuotaRejections = Default.Counter("uwf_tenant_quota_rejections_total",
+		"Workflow runs refused by a tenant quota", "tenant", "limit")
+
+	// Timers counts the durable timers of sleeping runs by outcome (scheduled, fired)
+	Timers = Default.Counter("uwf_timers_total",
+		"Durable timers of sleeping runs by outcome", "result")
+
+	// AdmissionRunsInFlight tracks the runs admitted on this node that have not finished
+	AdmissionRunsInFlight = Default.Gauge("uwf_admission_runs_in_flight",
+		"Workflow runs admitted on this node that have not finished")
+
+	// AdmissionRejections counts runs refused by admission control (client_rate, workflow_rate, queue_depth, saturated)
+	AdmissionRejections = Default.Counter("uwf_admission_rejections_total",
+		"Workflow runs refused by admission control", "reason")
+)
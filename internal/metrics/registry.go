@@ -0,0 +1,298 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricType represents the Prometheus type of a metric family
+type MetricType string
+
+const (
+	CounterType   MetricType = "counter"
+	GaugeType     MetricType = "gauge"
+	HistogramType MetricType = "histogram"
+)
+
+const (
+	// DefaultMaxSeries is the default number of label combinations kept per metric
+	DefaultMaxSeries = 500
+
+	// OverflowLabelValue replaces every label value once a metric exceeds its series limit
+	OverflowLabelValue = "__overflow__"
+
+	// maxLabelValueLength truncates label values to keep series keys bounded
+	maxLabelValueLength = 128
+)
+
+var (
+	// DefBuckets are latency buckets (in seconds) for workflow, step and child-step timings
+	DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+	// FastBuckets are latency buckets (in seconds) for in-process operations such as DI resolution
+	FastBuckets = []float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1}
+)
+
+// Registry holds metric families and renders them in the Prometheus text format
+type Registry struct {
+	mu         sync.RWMutex
+	families   map[string]*family
+	collectors []func()
+	maxSeries  int
+}
+
+// Default is the process-wide registry used by the built-in instruments
+var Default = NewRegistry()
+
+// NewRegistry creates a new, empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families:  make(map[string]*family),
+		maxSeries: DefaultMaxSeries,
+	}
+}
+
+// SetMaxSeries sets the per-metric label cardinality limit for all families
+func (r *Registry) SetMaxSeries(maxSeries int) {
+	if maxSeries <= 0 {
+		maxSeries = DefaultMaxSeries
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.maxSeries = maxSeries
+	for _, f := range r.families {
+		f.mu.Lock()
+		f.maxSeries = maxSeries
+		f.mu.Unlock()
+	}
+}
+
+// AddCollector registers a function that refreshes gauges or mirrored counters before every scrape
+func (r *Registry) AddCollector(collector func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collector)
+}
+
+// Counter registers (or returns the existing) counter family
+func (r *Registry) Counter(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{family: r.register(name, help, CounterType, nil, labelNames)}
+}
+
+// Gauge registers (or returns the existing) gauge family
+func (r *Registry) Gauge(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{family: r.register(name, help, GaugeType, nil, labelNames)}
+}
+
+// Histogram registers (or returns the existing) histogram family
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &HistogramVec{family: r.register(name, help, HistogramType, sorted, labelNames)}
+}
+
+// register creates a metric family or returns the one already registered under the name
+func (r *Registry) register(name, help string, typ MetricType, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, exists := r.families[name]; exists {
+		if f.typ != typ {
+			panic(fmt.Sprintf("metric %s already registered as %s", name, f.typ))
+		}
+		return f
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		buckets:    buckets,
+		maxSeries:  r.maxSeries,
+		series:     make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// collect runs all registered collectors
+func (r *Registry) collect() {
+	r.mu.RLock()
+	collectors := append([]func(){}, r.collectors...)
+	r.mu.RUnlock()
+
+	for _, collector := range collectors {
+		collector()
+	}
+}
+
+// sortedFamilies returns the registered families ordered by name
+func (r *Registry) sortedFamilies() []*family {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+	return families
+}
+
+// family is a named metric with a fixed label set
+type family struct {
+	name       string
+	help       string
+	typ        MetricType
+	labelNames []string
+	buckets    []float64
+
+	mu         sync.Mutex
+	maxSeries  int
+	series     map[string]*series
+	overflowed atomic.Int64
+}
+
+// series is a single label combination of a family
+type series struct {
+	labelValues  []string
+	value        float64
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// getSeries returns the series for the label values, applying the cardinality guard
+// The caller must hold f.mu
+func (f *family) getSeries(labelValues []string) *series {
+	values := normalizeLabelValues(labelValues, len(f.labelNames))
+	key := strings.Join(values, "\xff")
+
+	if s, exists := f.series[key]; exists {
+		return s
+	}
+
+	if len(f.series) >= f.maxSeries {
+		f.overflowed.Add(1)
+		for i := range values {
+			values[i] = OverflowLabelValue
+		}
+		key = strings.Join(values, "\xff")
+		if s, exists := f.series[key]; exists {
+			return s
+		}
+	}
+
+	s := &series{labelValues: values}
+	if f.typ == HistogramType {
+		s.bucketCounts = make([]uint64, len(f.buckets))
+	}
+	f.series[key] = s
+	return s
+}
+
+// normalizeLabelValues pads or trims values to the label count and truncates long values
+func normalizeLabelValues(labelValues []string, count int) []string {
+	values := make([]string, count)
+	for i := 0; i < count && i < len(labelValues); i++ {
+		value := labelValues[i]
+		if len(value) > maxLabelValueLength {
+			value = value[:maxLabelValueLength]
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// CounterVec is a monotonically increasing metric partitioned by labels
+type CounterVec struct {
+	family *family
+}
+
+// Inc increments the counter by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter by a non-negative value
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.family.mu.Lock()
+	c.family.getSeries(labelValues).value += value
+	c.family.mu.Unlock()
+}
+
+// Set mirrors a monotonic total kept by another component (e.g. circuit breaker counters)
+func (c *CounterVec) Set(value float64, labelValues ...string) {
+	c.family.mu.Lock()
+	c.family.getSeries(labelValues).value = value
+	c.family.mu.Unlock()
+}
+
+// GaugeVec is a metric that can go up and down, partitioned by labels
+type GaugeVec struct {
+	family *family
+}
+
+// Set sets the gauge value
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.family.mu.Lock()
+	g.family.getSeries(labelValues).value = value
+	g.family.mu.Unlock()
+}
+
+// Add adds a (possibly negative) delta to the gauge
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.family.mu.Lock()
+	g.family.getSeries(labelValues).value += delta
+	g.family.mu.Unlock()
+}
+
+// Inc increments the gauge by one
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrements the gauge by one
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// HistogramVec samples observations into buckets, partitioned by labels
+type HistogramVec struct {
+	family *family
+}
+
+// Observe records a single observation
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.family.mu.Lock()
+	defer h.family.mu.Unlock()
+
+	s := h.family.getSeries(labelValues)
+	for i, upperBound := range h.family.buckets {
+		if value <= upperBound {
+			s.bucketCounts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// ObserveDuration records a duration in seconds
+func (h *HistogramVec) ObserveDuration(duration time.Duration, labelValues ...string) {
+	h.Observe(duration.Seconds(), labelValues...)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCardinalityGuard(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetMaxSeries(2)
+	counter := registry.Counter("test_total", "Test counter", "name")
+
+	counter.Inc("a")
+	counter.Inc("b")
+	counter.Inc("c")
+	counter.Inc("d")
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	text := out.String()
+
+	for _, want := range []string{
+		`test_total{name="a"} 1`,
+		`test_total{name="b"} 1`,
+		`test_total{name="__overflow__"} 2`,
+		`uwf_metrics_series_overflow_total{metric="test_total"} 2`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output:\n%s", want, text)
+		}
+	}
+}
+
+func TestHistogramExposition(t *testing.T) {
+	registry := NewRegistry()
+	histogram := registry.Histogram("test_seconds", "Test histogram", []float64{0.1, 1}, "step")
+
+	histogram.Observe(0.05, "s1")
+	histogram.Observe(0.5, "s1")
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	text := out.String()
+
+	for _, want := range []string{
+		"# TYPE test_seconds histogram",
+		`test_seconds_bucket{step="s1",le="0.1"} 1`,
+		`test_seconds_bucket{step="s1",le="1"} 2`,
+		`test_seconds_bucket{step="s1",le="+Inf"} 2`,
+		`test_seconds_count{step="s1"} 2`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output:\n%s", want, text)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package primitive
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"unified-workflow/internal/primitive/services/antifraud/models"
@@ -232,7 +233,7 @@ func initAntifraudService(config *Config) (AntifraudService, error) {
 		return nil, fmt.Errorf("failed to create antifraud client: %w", err)
 	}
 
-	// Wrap with proxy for logging, metrics, and circuit breaking
+	// Wrap with proxy for timing and error context (resilience is applied by the DI layer)
 	antifraudProxy := serviceclientsantifraud.NewProxy(antifraudClient, afConfig)
 
 	// Create adapter to convert between concrete types and interface{}
@@ -245,88 +246,88 @@ func initAntifraudService(config *Config) (AntifraudService, error) {
 // antifraudAdapter adapts the concrete antifraud service to the primitive.AntifraudService interface
 type antifraudAdapter struct {
 	service interface {
-		StoreTransaction(models.AF_Transaction) error
-		ValidateTransactionByAML(models.AF_Transaction) (models.ServiceResolution, error)
-		ValidateTransactionByFC(models.AF_Transaction) (models.ServiceResolution, error)
-		ValidateTransactionByML(models.AF_Transaction) (models.ServiceResolution, error)
-		StoreServiceResolution(models.ServiceResolution) error
-		AddTransactionServiceCheck(models.ServiceResolution) error
-		FinalizeTransaction(models.AF_Transaction) (models.FinalResolution, error)
-		StoreFinalResolution(models.FinalResolution) error
-		HealthCheck() (bool, error)
+		StoreTransaction(context.Context, models.AF_Transaction) error
+		ValidateTransactionByAML(context.Context, models.AF_Transaction) (models.ServiceResolution, error)
+		ValidateTransactionByFC(context.Context, models.AF_Transaction) (models.ServiceResolution, error)
+		ValidateTransactionByML(context.Context, models.AF_Transaction) (models.ServiceResolution, error)
+		StoreServiceResolution(context.Context, models.ServiceResolution) error
+		AddTransactionServiceCheck(context.Context, models.ServiceResolution) error
+		FinalizeTransaction(context.Context, models.AF_Transaction) (models.FinalResolution, error)
+		StoreFinalResolution(context.Context, models.FinalResolution) error
+		HealthCheck(context.Context) (bool, error)
 		GetConfig() models.ClientConfig
 	}
 	config *Config
 }
 
-func (a *antifraudAdapter) StoreTransaction(afTransaction interface{}) error {
+func (a *antifraudAdapter) StoreTransaction(ctx context.Context, afTransaction interface{}) error {
 	// Convert interface{} to models.AF_Transaction
 	tx, ok := afTransaction.(models.AF_Transaction)
 	if !ok {
 		// Try to convert from map or other types
 		return fmt.Errorf("invalid transaction type: %T", afTransaction)
 	}
-	return a.service.StoreTransaction(tx)
+	return a.service.StoreTransaction(ctx, tx)
 }
 
-func (a *antifraudAdapter) ValidateTransactionByAML(afTransaction interface{}) (interface{}, error) {
+func (a *antifraudAdapter) ValidateTransactionByAML(ctx context.Context, afTransaction interface{}) (interface{}, error) {
 	tx, ok := afTransaction.(models.AF_Transaction)
 	if !ok {
 		return nil, fmt.Errorf("invalid transaction type: %T", afTransaction)
 	}
-	return a.service.ValidateTransactionByAML(tx)
+	return a.service.ValidateTransactionByAML(ctx, tx)
 }
 
-func (a *antifraudAdapter) ValidateTransactionByFC(afTransaction interface{}) (interface{}, error) {
+func (a *antifraudAdapter) ValidateTransactionByFC(ctx context.Context, afTransaction interface{}) (interface{}, error) {
 	tx, ok := afTransaction.(models.AF_Transaction)
 	if !ok {
 		return nil, fmt.Errorf("invalid transaction type: %T", afTransaction)
 	}
-	return a.service.ValidateTransactionByFC(tx)
+	return a.service.ValidateTransactionByFC(ctx, tx)
 }
 
-func (a *antifraudAdapter) ValidateTransactionByML(afTransaction interface{}) (interface{}, error) {
+func (a *antifraudAdapter) ValidateTransactionByML(ctx context.Context, afTransaction interface{}) (interface{}, error) {
 	tx, ok := afTransaction.(models.AF_Transaction)
 	if !ok {
 		return nil, fmt.Errorf("invalid transaction type: %T", afTransaction)
 	}
-	return a.service.ValidateTransactionByML(tx)
+	return a.service.ValidateTransactionByML(ctx, tx)
 }
 
-func (a *antifraudAdapter) StoreServiceResolution(resolution interface{}) error {
+func (a *antifraudAdapter) StoreServiceResolution(ctx context.Context, resolution interface{}) error {
 	res, ok := resolution.(models.ServiceResolution)
 	if !ok {
 		return fmt.Errorf("invalid resolution type: %T", resolution)
 	}
-	return a.service.StoreServiceResolution(res)
+	return a.service.StoreServiceResolution(ctx, res)
 }
 
-func (a *antifraudAdapter) AddTransactionServiceCheck(resolution interface{}) error {
+func (a *antifraudAdapter) AddTransactionServiceCheck(ctx context.Context, resolution interface{}) error {
 	res, ok := resolution.(models.ServiceResolution)
 	if !ok {
 		return fmt.Errorf("invalid resolution type: %T", resolution)
 	}
-	return a.service.AddTransactionServiceCheck(res)
+	return a.service.AddTransactionServiceCheck(ctx, res)
 }
 
-func (a *antifraudAdapter) FinalizeTransaction(afTransaction interface{}) (interface{}, error) {
+func (a *antifraudAdapter) FinalizeTransaction(ctx context.Context, afTransaction interface{}) (interface{}, error) {
 	tx, ok := afTransaction.(models.AF_Transaction)
 	if !ok {
 		return nil, fmt.Errorf("invalid transaction type: %T", afTransaction)
 	}
-	return a.service.FinalizeTransaction(tx)
+	return a.service.FinalizeTransaction(ctx, tx)
 }
 
-func (a *antifraudAdapter) StoreFinalResolution(resolution interface{}) error {
+func (a *antifraudAdapter) StoreFinalResolution(ctx context.Context, resolution interface{}) error {
 	res, ok := resolution.(models.FinalResolution)
 	if !ok {
 		return fmt.Errorf("invalid resolution type: %T", resolution)
 	}
-	return a.service.StoreFinalResolution(res)
+	return a.service.StoreFinalResolution(ctx, res)
 }
 
-func (a *antifraudAdapter) HealthCheck() (bool, error) {
-	return a.service.HealthCheck()
+func (a *antifraudAdapter) HealthCheck(ctx context.Context) (bool, error) {
+	return a.service.HealthCheck(ctx)
 }
 
 func (a *antifraudAdapter) GetConfig() interface{} {
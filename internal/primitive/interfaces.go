@@ -118,6 +118,9 @@ const (
 	WorkflowStatusFailed
 	WorkflowStatusCancelled
 	WorkflowStatusPaused
+	WorkflowStatusWaiting
+	WorkflowStatusSleeping
+	WorkflowStatusTimedOut
 )
 
 // StepLogic is a functional interface for step execution logic
@@ -0,0 +1,58 @@
+package primitive
+
+import (
+	"context"
+	"log/slog"
+
+	"unified-workflow/internal/logging"
+)
+
+// structuredLogger implements Logger on top of the process-wide slog logger
+type structuredLogger struct {
+	fields map[string]interface{}
+}
+
+// NewLogger returns a structured Logger tagged with the component name
+// Fields are redacted and records go through the handler installed by logging.Init
+func NewLogger(component string) Logger {
+	return &structuredLogger{fields: map[string]interface{}{"component": component}}
+}
+
+func (l *structuredLogger) Debug(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelDebug, msg, fields)
+}
+
+func (l *structuredLogger) Info(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelInfo, msg, fields)
+}
+
+func (l *structuredLogger) Warn(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelWarn, msg, fields)
+}
+
+func (l *structuredLogger) Error(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelError, msg, fields)
+}
+
+// WithFields returns a logger that adds fields to every record
+func (l *structuredLogger) WithFields(fields map[string]interface{}) Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return &structuredLogger{fields: merged}
+}
+
+// log resolves the default logger per call so that loggers created before logging.Init use it
+func (l *structuredLogger) log(level slog.Level, msg string, fields map[string]interface{}) {
+	logger := slog.Default()
+	if !logger.Enabled(context.Background(), level) {
+		return
+	}
+	args := logging.FieldAttrs(l.fields)
+	args = append(args, logging.FieldAttrs(fields)...)
+	logger.Log(context.Background(), level, msg, args...)
+}
@@ -8,6 +8,9 @@ const (
 	WorkflowStatusFailed    = 3
 	WorkflowStatusCancelled = 4
 	WorkflowStatusPaused    = 5
+	WorkflowStatusWaiting   = 6
+	WorkflowStatusSleeping  = 7
+	WorkflowStatusTimedOut  = 8
 )
 
 // StepStatus constants (similar to Java's StepStatus enum)
@@ -18,4 +21,14 @@ const (
 	StepStatusFailed    = "failed"
 	StepStatusSkipped   = "skipped"
 	StepStatusCancelled = "cancelled"
+	StepStatusWaiting   = "waiting"
+	StepStatusSleeping  = "sleeping"
+	StepStatusTimedOut  = "timed_out"
+)
+
+// Compensation states of steps, child steps and runs undone after their run failed or was cancelled
+const (
+	StepStatusCompensating       = "compensating"
+	StepStatusCompensated        = "compensated"
+	StepStatusCompensationFailed = "compensation_failed"
 )
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -57,6 +58,57 @@ type WorkflowContext interface {
 
 	// WithLastAttemptedStep creates a new context with updated last attempted step
 	WithLastAttemptedStep(stepName string) WorkflowContext
+
+	// GetStepStates returns the state of each step that has started or been skipped, by step name
+	GetStepStates() map[string]string
+
+	// WithStepState creates a new context with the state of a step updated
+	WithStepState(stepName, state string) WorkflowContext
+
+	// GetParentRunID returns the run that started this run as a sub-workflow, if any
+	GetParentRunID() string
+
+	// WithParentRunID creates a new context linked to the run that started it as a sub-workflow
+	WithParentRunID(parentRunID string) WorkflowContext
+
+	// GetChildRunIDs returns the runs this run started as sub-workflows, in the order they started
+	GetChildRunIDs() []string
+
+	// WithChildRunID creates a new context linked to a run it started as a sub-workflow
+	WithChildRunID(childRunID string) WorkflowContext
+
+	// GetCompensationStatus returns the state of the compensation of a failed or cancelled run, if any
+	GetCompensationStatus() string
+
+	// WithCompensationStatus creates a new context with the state of its compensation updated
+	WithCompensationStatus(status string) WorkflowContext
+
+	// GetWakeAt returns when a run sleeping on a timer step resumes, if it is
+	GetWakeAt() *time.Time
+
+	// GetResumeStep returns the index of the top-level step a sleeping or parked run resumes at
+	GetResumeStep() int
+
+	// WithTimer creates a new context sleeping until wakeAt, then resuming at the top-level step resumeStep
+	WithTimer(wakeAt time.Time, resumeStep int) WorkflowContext
+
+	// WithResumeStep creates a new context parked until a signal, then resuming at the top-level step resumeStep
+	WithResumeStep(resumeStep int) WorkflowContext
+
+	// WithoutTimer creates a new context no longer sleeping on a timer or parked until a signal
+	WithoutTimer() WorkflowContext
+
+	// GetWorkflowVersion returns the version of the workflow the run executes, or zero when it is not pinned
+	GetWorkflowVersion() int
+
+	// WithWorkflowVersion creates a new context executing a version of its workflow
+	WithWorkflowVersion(version int) WorkflowContext
+
+	// GetRemainingTimeout returns how much of its timeout a parked run has left, or zero when it is not recorded
+	GetRemainingTimeout() time.Duration
+
+	// WithRemainingTimeout creates a new context parked with remaining of its timeout left
+	WithRemainingTimeout(remaining time.Duration) WorkflowContext
 }
 
 // WorkflowContextImpl implements the WorkflowContext interface
@@ -70,6 +122,14 @@ type WorkflowContextImpl struct {
 	endTime               *time.Time
 	errorMessage          string
 	lastAttemptedStep     string
+	stepStates            map[string]string // never modified once set, as contexts share it
+	parentRunID           string
+	childRunIDs           []string // never modified once set, as contexts share it
+	compensationStatus    string
+	wakeAt                *time.Time
+	resumeStep            int
+	workflowVersion       int
+	remainingTimeout      time.Duration
 }
 
 // NewWorkflowContext creates a new workflow context
@@ -83,6 +143,13 @@ func NewWorkflowContext(workflowDefinitionID string) *WorkflowContextImpl {
 	}
 }
 
+// NewWorkflowContextForRun creates a pending workflow context for an already assigned run ID
+func NewWorkflowContextForRun(runID, workflowDefinitionID string) *WorkflowContextImpl {
+	wc := NewWorkflowContext(workflowDefinitionID)
+	wc.runID = runID
+	return wc
+}
+
 // GetRunID returns the workflow run ID
 func (wc *WorkflowContextImpl) GetRunID() string {
 	return wc.runID
@@ -128,124 +195,256 @@ func (wc *WorkflowContextImpl) GetLastAttemptedStep() string {
 	return wc.lastAttemptedStep
 }
 
+// with returns a copy of the context changed by update
+func (wc *WorkflowContextImpl) with(update func(next *WorkflowContextImpl)) WorkflowContext {
+	next := *wc
+	update(&next)
+	return &next
+}
+
 // WithStatus creates a new context with updated status
-func (wc *WorkflowContextImpl) WithStatus(status int) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                status,
-		currentStepIndex:      wc.currentStepIndex,
-		currentChildStepIndex: wc.currentChildStepIndex,
-		startTime:             wc.startTime,
-		endTime:               wc.endTime,
-		errorMessage:          wc.errorMessage,
-		lastAttemptedStep:     wc.lastAttemptedStep,
-	}
+func (wc *WorkflowContextImpl) WithStatus(status int) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.status = status
+	})
 }
 
 // WithIndices creates a new context with updated indices
-func (wc *WorkflowContextImpl) WithIndices(stepIndex, childStepIndex int) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                wc.status,
-		currentStepIndex:      stepIndex,
-		currentChildStepIndex: childStepIndex,
-		startTime:             wc.startTime,
-		endTime:               wc.endTime,
-		errorMessage:          wc.errorMessage,
-		lastAttemptedStep:     wc.lastAttemptedStep,
-	}
+func (wc *WorkflowContextImpl) WithIndices(stepIndex, childStepIndex int) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.currentStepIndex = stepIndex
+		next.currentChildStepIndex = childStepIndex
+	})
 }
 
 // WithErrorMessage creates a new context with updated error message
-func (wc *WorkflowContextImpl) WithErrorMessage(errorMessage string) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                wc.status,
-		currentStepIndex:      wc.currentStepIndex,
-		currentChildStepIndex: wc.currentChildStepIndex,
-		startTime:             wc.startTime,
-		endTime:               wc.endTime,
-		errorMessage:          errorMessage,
-		lastAttemptedStep:     wc.lastAttemptedStep,
-	}
+func (wc *WorkflowContextImpl) WithErrorMessage(errorMessage string) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.errorMessage = errorMessage
+	})
 }
 
 // WithStartTime creates a new context with updated start time
-func (wc *WorkflowContextImpl) WithStartTime(startTime time.Time) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                wc.status,
-		currentStepIndex:      wc.currentStepIndex,
-		currentChildStepIndex: wc.currentChildStepIndex,
-		startTime:             &startTime,
-		endTime:               wc.endTime,
-		errorMessage:          wc.errorMessage,
-		lastAttemptedStep:     wc.lastAttemptedStep,
-	}
+func (wc *WorkflowContextImpl) WithStartTime(startTime time.Time) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.startTime = &startTime
+	})
 }
 
 // WithEndTime creates a new context with updated end time
-func (wc *WorkflowContextImpl) WithEndTime(endTime time.Time) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                wc.status,
-		currentStepIndex:      wc.currentStepIndex,
-		currentChildStepIndex: wc.currentChildStepIndex,
-		startTime:             wc.startTime,
-		endTime:               &endTime,
-		errorMessage:          wc.errorMessage,
-		lastAttemptedStep:     wc.lastAttemptedStep,
-	}
+func (wc *WorkflowContextImpl) WithEndTime(endTime time.Time) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.endTime = &endTime
+	})
 }
 
 // WithCurrentStepIndex creates a new context with updated step index
-func (wc *WorkflowContextImpl) WithCurrentStepIndex(stepIndex int) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                wc.status,
-		currentStepIndex:      stepIndex,
-		currentChildStepIndex: wc.currentChildStepIndex,
-		startTime:             wc.startTime,
-		endTime:               wc.endTime,
-		errorMessage:          wc.errorMessage,
-		lastAttemptedStep:     wc.lastAttemptedStep,
-	}
+func (wc *WorkflowContextImpl) WithCurrentStepIndex(stepIndex int) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.currentStepIndex = stepIndex
+	})
 }
 
 // WithCurrentChildStepIndex creates a new context with updated child step index
-func (wc *WorkflowContextImpl) WithCurrentChildStepIndex(childStepIndex int) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                wc.status,
-		currentStepIndex:      wc.currentStepIndex,
-		currentChildStepIndex: childStepIndex,
-		startTime:             wc.startTime,
-		endTime:               wc.endTime,
-		errorMessage:          wc.errorMessage,
-		lastAttemptedStep:     wc.lastAttemptedStep,
-	}
+func (wc *WorkflowContextImpl) WithCurrentChildStepIndex(childStepIndex int) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.currentChildStepIndex = childStepIndex
+	})
 }
 
 // WithLastAttemptedStep creates a new context with updated last attempted step
-func (wc *WorkflowContextImpl) WithLastAttemptedStep(stepName string) *WorkflowContextImpl {
-	return &WorkflowContextImpl{
-		runID:                 wc.runID,
-		workflowDefinitionID:  wc.workflowDefinitionID,
-		status:                wc.status,
-		currentStepIndex:      wc.currentStepIndex,
-		currentChildStepIndex: wc.currentChildStepIndex,
-		startTime:             wc.startTime,
-		endTime:               wc.endTime,
-		errorMessage:          wc.errorMessage,
-		lastAttemptedStep:     stepName,
+func (wc *WorkflowContextImpl) WithLastAttemptedStep(stepName string) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.lastAttemptedStep = stepName
+	})
+}
+
+// GetStepStates returns the state of each step that has started or been skipped
+// The steps of a graph workflow run concurrently, so their progress is tracked per step rather than by index
+func (wc *WorkflowContextImpl) GetStepStates() map[string]string {
+	states := make(map[string]string, len(wc.stepStates))
+	for name, state := range wc.stepStates {
+		states[name] = state
+	}
+	return states
+}
+
+// WithStepState creates a new context with the state of a step updated
+func (wc *WorkflowContextImpl) WithStepState(stepName, state string) WorkflowContext {
+	states := make(map[string]string, len(wc.stepStates)+1)
+	for name, current := range wc.stepStates {
+		states[name] = current
+	}
+	states[stepName] = state
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.stepStates = states
+	})
+}
+
+// GetParentRunID returns the run that started this run as a sub-workflow, if any
+func (wc *WorkflowContextImpl) GetParentRunID() string {
+	return wc.parentRunID
+}
+
+// WithParentRunID creates a new context linked to the run that started it as a sub-workflow
+func (wc *WorkflowContextImpl) WithParentRunID(parentRunID string) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.parentRunID = parentRunID
+	})
+}
+
+// GetChildRunIDs returns the runs this run started as sub-workflows
+func (wc *WorkflowContextImpl) GetChildRunIDs() []string {
+	return append([]string(nil), wc.childRunIDs...)
+}
+
+// WithChildRunID creates a new context linked to a run it started as a sub-workflow
+func (wc *WorkflowContextImpl) WithChildRunID(childRunID string) WorkflowContext {
+	childRunIDs := append(append(make([]string, 0, len(wc.childRunIDs)+1), wc.childRunIDs...), childRunID)
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.childRunIDs = childRunIDs
+	})
+}
+
+// GetCompensationStatus returns the state of the compensation of a failed or cancelled run, if any
+func (wc *WorkflowContextImpl) GetCompensationStatus() string {
+	return wc.compensationStatus
+}
+
+// WithCompensationStatus creates a new context with the state of its compensation updated
+func (wc *WorkflowContextImpl) WithCompensationStatus(status string) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.compensationStatus = status
+	})
+}
+
+// GetWakeAt returns when a run sleeping on a timer step resumes, if it is
+func (wc *WorkflowContextImpl) GetWakeAt() *time.Time {
+	return wc.wakeAt
+}
+
+// GetResumeStep returns the index of the top-level step a sleeping or parked run resumes at
+func (wc *WorkflowContextImpl) GetResumeStep() int {
+	return wc.resumeStep
+}
+
+// WithTimer creates a new context sleeping until wakeAt, then resuming at the top-level step resumeStep
+func (wc *WorkflowContextImpl) WithTimer(wakeAt time.Time, resumeStep int) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.wakeAt = &wakeAt
+		next.resumeStep = resumeStep
+	})
+}
+
+// WithResumeStep creates a new context parked until a signal, then resuming at the top-level step resumeStep
+func (wc *WorkflowContextImpl) WithResumeStep(resumeStep int) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.resumeStep = resumeStep
+	})
+}
+
+// WithoutTimer creates a new context no longer sleeping on a timer or parked until a signal
+func (wc *WorkflowContextImpl) WithoutTimer() WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.wakeAt = nil
+		next.resumeStep = 0
+	})
+}
+
+// GetWorkflowVersion returns the version of the workflow the run executes, or zero when it is not pinned
+func (wc *WorkflowContextImpl) GetWorkflowVersion() int {
+	return wc.workflowVersion
+}
+
+// WithWorkflowVersion creates a new context executing a version of its workflow
+func (wc *WorkflowContextImpl) WithWorkflowVersion(version int) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.workflowVersion = version
+	})
+}
+
+// GetRemainingTimeout returns how much of its timeout a parked run has left, or zero when it is not recorded
+func (wc *WorkflowContextImpl) GetRemainingTimeout() time.Duration {
+	return wc.remainingTimeout
+}
+
+// WithRemainingTimeout creates a new context parked with remaining of its timeout left
+func (wc *WorkflowContextImpl) WithRemainingTimeout(remaining time.Duration) WorkflowContext {
+	return wc.with(func(next *WorkflowContextImpl) {
+		next.remainingTimeout = remaining
+	})
+}
+
+// workflowContextJSON is the stored form of a workflow context, so a run can be resumed by another process
+type workflowContextJSON struct {
+	RunID                 string            `json:"run_id"`
+	WorkflowDefinitionID  string            `json:"workflow_definition_id"`
+	Status                int               `json:"status"`
+	CurrentStepIndex      int               `json:"current_step_index"`
+	CurrentChildStepIndex int               `json:"current_child_step_index"`
+	StartTime             *time.Time        `json:"start_time,omitempty"`
+	EndTime               *time.Time        `json:"end_time,omitempty"`
+	ErrorMessage          string            `json:"error_message,omitempty"`
+	LastAttemptedStep     string            `json:"last_attempted_step,omitempty"`
+	StepStates            map[string]string `json:"step_states,omitempty"`
+	ParentRunID           string            `json:"parent_run_id,omitempty"`
+	ChildRunIDs           []string          `json:"child_run_ids,omitempty"`
+	CompensationStatus    string            `json:"compensation_status,omitempty"`
+	WakeAt                *time.Time        `json:"wake_at,omitempty"`
+	ResumeStep            int               `json:"resume_step,omitempty"`
+	WorkflowVersion       int               `json:"workflow_version,omitempty"`
+	RemainingTimeout      time.Duration     `json:"remaining_timeout,omitempty"`
+}
+
+// MarshalJSON encodes the context for a state store shared between processes
+func (wc *WorkflowContextImpl) MarshalJSON() ([]byte, error) {
+	return json.Marshal(workflowContextJSON{
+		RunID:                 wc.runID,
+		WorkflowDefinitionID:  wc.workflowDefinitionID,
+		Status:                wc.status,
+		CurrentStepIndex:      wc.currentStepIndex,
+		CurrentChildStepIndex: wc.currentChildStepIndex,
+		StartTime:             wc.startTime,
+		EndTime:               wc.endTime,
+		ErrorMessage:          wc.errorMessage,
+		LastAttemptedStep:     wc.lastAttemptedStep,
+		StepStates:            wc.stepStates,
+		ParentRunID:           wc.parentRunID,
+		ChildRunIDs:           wc.childRunIDs,
+		CompensationStatus:    wc.compensationStatus,
+		WakeAt:                wc.wakeAt,
+		ResumeStep:            wc.resumeStep,
+		WorkflowVersion:       wc.workflowVersion,
+		RemainingTimeout:      wc.remainingTimeout,
+	})
+}
+
+// UnmarshalJSON decodes a context encoded by MarshalJSON
+func (wc *WorkflowContextImpl) UnmarshalJSON(data []byte) error {
+	var stored workflowContextJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	*wc = WorkflowContextImpl{
+		runID:                 stored.RunID,
+		workflowDefinitionID:  stored.WorkflowDefinitionID,
+		status:                stored.Status,
+		currentStepIndex:      stored.CurrentStepIndex,
+		currentChildStepIndex: stored.CurrentChildStepIndex,
+		startTime:             stored.StartTime,
+		endTime:               stored.EndTime,
+		errorMessage:          stored.ErrorMessage,
+		lastAttemptedStep:     stored.LastAttemptedStep,
+		stepStates:            stored.StepStates,
+		parentRunID:           stored.ParentRunID,
+		childRunIDs:           stored.ChildRunIDs,
+		compensationStatus:    stored.CompensationStatus,
+		wakeAt:                stored.WakeAt,
+		resumeStep:            stored.ResumeStep,
+		workflowVersion:       stored.WorkflowVersion,
+		remainingTimeout:      stored.RemainingTimeout,
 	}
+	return nil
 }
 
 // Helper function to generate UUID
@@ -61,6 +61,14 @@ func NewWorkflowData() *WorkflowDataImpl {
 	}
 }
 
+// WrapWorkflowData returns workflow data backed by m without copying it, so writes through either stay visible
+func WrapWorkflowData(m map[string]interface{}) *WorkflowDataImpl {
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+	return &WorkflowDataImpl{data: m}
+}
+
 // Get returns a value by key
 func (wd *WorkflowDataImpl) Get(key string) interface{} {
 	return wd.data[key]
@@ -102,7 +110,7 @@ func (wd *WorkflowDataImpl) ToMap() map[string]interface{} {
 }
 
 // DeepCopy creates a deep copy of the workflow data
-func (wd *WorkflowDataImpl) DeepCopy() *WorkflowDataImpl {
+func (wd *WorkflowDataImpl) DeepCopy() WorkflowData {
 	copy := NewWorkflowData()
 	for k, v := range wd.data {
 		// Simple copy - for complex nested structures, a more sophisticated copy would be needed
@@ -112,8 +120,11 @@ func (wd *WorkflowDataImpl) DeepCopy() *WorkflowDataImpl {
 }
 
 // Merge merges another WorkflowData into this one
-func (wd *WorkflowDataImpl) Merge(other *WorkflowDataImpl) {
-	for k, v := range other.data {
+func (wd *WorkflowDataImpl) Merge(other WorkflowData) {
+	if other == nil {
+		return
+	}
+	for k, v := range other.ToMap() {
 		wd.Put(k, v)
 	}
 }
@@ -5,7 +5,7 @@ import (
 	"time"
 )
 
-// ProxyLogger defines the logging interface for proxies
+// ProxyLogger defines the logging interface for proxies; every Logger satisfies it
 type ProxyLogger interface {
 	Debug(msg string, fields map[string]interface{})
 	Info(msg string, fields map[string]interface{})
@@ -13,25 +13,6 @@ type ProxyLogger interface {
 	Error(msg string, fields map[string]interface{})
 }
 
-// DefaultProxyLogger is a simple logger implementation for proxies
-type DefaultProxyLogger struct{}
-
-func (l *DefaultProxyLogger) Debug(msg string, fields map[string]interface{}) {
-	fmt.Printf("[DEBUG] %s: %v\n", msg, fields)
-}
-
-func (l *DefaultProxyLogger) Info(msg string, fields map[string]interface{}) {
-	fmt.Printf("[INFO] %s: %v\n", msg, fields)
-}
-
-func (l *DefaultProxyLogger) Warn(msg string, fields map[string]interface{}) {
-	fmt.Printf("[WARN] %s: %v\n", msg, fields)
-}
-
-func (l *DefaultProxyLogger) Error(msg string, fields map[string]interface{}) {
-	fmt.Printf("[ERROR] %s: %v\n", msg, fields)
-}
-
 // StorageProxy is a proxy layer for storage operations
 type StorageProxy struct {
 	executor StorageService
@@ -42,7 +23,7 @@ type StorageProxy struct {
 func NewStorageProxy(executor StorageService) *StorageProxy {
 	return &StorageProxy{
 		executor: executor,
-		logger:   &DefaultProxyLogger{},
+		logger:   NewLogger("storage-proxy"),
 	}
 }
 
@@ -279,7 +260,7 @@ type EchoProxy struct {
 func NewEchoProxy(executor EchoService) *EchoProxy {
 	return &EchoProxy{
 		executor: executor,
-		logger:   &DefaultProxyLogger{},
+		logger:   NewLogger("echo-proxy"),
 	}
 }
 
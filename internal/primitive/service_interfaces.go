@@ -1,5 +1,7 @@
 package primitive
 
+import "context"
+
 // StorageService defines the interface for storage operations
 type StorageService interface {
 	// Save saves data to storage
@@ -61,33 +63,34 @@ type DatabaseService interface {
 }
 
 // AntifraudService defines the interface for antifraud operations
+// Every call but GetConfig takes a context whose deadline and cancellation reach the antifraud API
 type AntifraudService interface {
 	// StoreTransaction stores a transaction in the antifraud system
-	StoreTransaction(afTransaction interface{}) error
+	StoreTransaction(ctx context.Context, afTransaction interface{}) error
 
 	// ValidateTransactionByAML validates a transaction using the AML service
-	ValidateTransactionByAML(afTransaction interface{}) (interface{}, error)
+	ValidateTransactionByAML(ctx context.Context, afTransaction interface{}) (interface{}, error)
 
 	// ValidateTransactionByFC validates a transaction using the FC service
-	ValidateTransactionByFC(afTransaction interface{}) (interface{}, error)
+	ValidateTransactionByFC(ctx context.Context, afTransaction interface{}) (interface{}, error)
 
 	// ValidateTransactionByML validates a transaction using the ML service
-	ValidateTransactionByML(afTransaction interface{}) (interface{}, error)
+	ValidateTransactionByML(ctx context.Context, afTransaction interface{}) (interface{}, error)
 
 	// StoreServiceResolution stores the resolution from a service check (AML, FC, LST)
-	StoreServiceResolution(resolution interface{}) error
+	StoreServiceResolution(ctx context.Context, resolution interface{}) error
 
 	// AddTransactionServiceCheck adds a completed service check resolution to the transaction aggregation process
-	AddTransactionServiceCheck(resolution interface{}) error
+	AddTransactionServiceCheck(ctx context.Context, resolution interface{}) error
 
 	// FinalizeTransaction finalizes the transaction validation process and retrieves the final resolution
-	FinalizeTransaction(afTransaction interface{}) (interface{}, error)
+	FinalizeTransaction(ctx context.Context, afTransaction interface{}) (interface{}, error)
 
 	// StoreFinalResolution stores the final resolution of the transaction
-	StoreFinalResolution(resolution interface{}) error
+	StoreFinalResolution(ctx context.Context, resolution interface{}) error
 
 	// HealthCheck checks the health of the antifraud service
-	HealthCheck() (bool, error)
+	HealthCheck(ctx context.Context) (bool, error)
 
 	// GetConfig returns the current configuration
 	GetConfig() interface{}
@@ -27,10 +27,11 @@ type Transaction struct {
 
 // ServiceResolution represents service validation result
 type ServiceResolution struct {
-	ServiceName string `json:"service_name"`
-	Resolution  string `json:"resolution"`
-	Score       int    `json:"score"`
-	Details     string `json:"details"`
+	TransactionId string `json:"transaction_id,omitempty"` // AF id of the transaction the check belongs to
+	ServiceName   string `json:"service_name"`
+	Resolution    string `json:"resolution"`
+	Score         int    `json:"score"`
+	Details       string `json:"details"`
 }
 
 // FinalResolution represents final transaction validation result
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"unified-workflow/internal/metrics"
 )
 
 // InMemoryQueue implements the Queue interface using in-memory storage
@@ -36,6 +38,7 @@ func (q *InMemoryQueue) Enqueue(ctx context.Context, runID string, data []byte)
 	q.messages = append(q.messages, msg)
 	q.runIDMap[runID] = msg
 
+	metrics.QueueMessages.Inc(inMemoryQueueName, "enqueue")
 	return nil
 }
 
@@ -52,6 +55,7 @@ func (q *InMemoryQueue) Dequeue(ctx context.Context) (*Message, error) {
 	q.messages = q.messages[1:]
 	delete(q.runIDMap, msg.RunID)
 
+	metrics.QueueMessages.Inc(inMemoryQueueName, "dequeue")
 	return msg, nil
 }
 
@@ -0,0 +1,38 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"unified-workflow/internal/metrics"
+)
+
+// inMemoryQueueName is the metrics label used for in-memory queues
+const inMemoryQueueName = "in-memory"
+
+// RegisterMetrics samples the queue depth on every metrics scrape
+func RegisterMetrics(registry *metrics.Registry, q Queue) {
+	name := Name(q)
+	registry.AddCollector(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		size, err := q.Size(ctx)
+		if err != nil {
+			return
+		}
+		metrics.QueueDepth.Set(float64(size), name)
+	})
+}
+
+// Name returns the metrics label for a queue implementation
+func Name(q Queue) string {
+	switch v := q.(type) {
+	case *NATSQueue:
+		return v.streamName
+	case *EnhancedNATSQueue:
+		return v.streamName
+	default:
+		return inMemoryQueueName
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"unified-workflow/internal/metrics"
+
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 )
@@ -43,10 +46,10 @@ func NewNATSQueue(config NATSConfig) (*NATSQueue, error) {
 		nats.ReconnectWait(config.ReconnectWait),
 		nats.Timeout(config.ConnectTimeout),
 		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
-			fmt.Printf("NATS disconnected: %v\n", err)
+			slog.Warn("NATS disconnected", "error", err)
 		}),
 		nats.ReconnectHandler(func(nc *nats.Conn) {
-			fmt.Printf("NATS reconnected to %s\n", nc.ConnectedUrl())
+			slog.Info("NATS reconnected", "url", nc.ConnectedUrl())
 		}),
 	}
 
@@ -87,13 +90,13 @@ func NewNATSQueue(config NATSConfig) (*NATSQueue, error) {
 		return nil, fmt.Errorf("failed to create stream: %w", err)
 	}
 
-	// Create or get consumer
+	// Create or get consumer for the execution requests of every tenant
 	consumerCfg := jetstream.ConsumerConfig{
-		Durable:       config.DurableName,
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		AckWait:       30 * time.Second,
-		MaxDeliver:    3,
-		FilterSubject: fmt.Sprintf("%s.execution.requests", config.SubjectPrefix),
+		Durable:        config.DurableName,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        30 * time.Second,
+		MaxDeliver:     3,
+		FilterSubjects: requestSubjects(config.SubjectPrefix),
 	}
 
 	consumer, err := stream.CreateOrUpdateConsumer(context.Background(), consumerCfg)
@@ -113,9 +116,9 @@ func NewNATSQueue(config NATSConfig) (*NATSQueue, error) {
 	}, nil
 }
 
-// Enqueue adds a workflow run ID to the queue for processing
+// Enqueue adds a workflow run ID to the queue for processing on the request subject of the tenant of ctx
 func (q *NATSQueue) Enqueue(ctx context.Context, runID string, data []byte) error {
-	subject := fmt.Sprintf("%s.execution.requests", q.subjectPrefix)
+	subject := requestSubject(ctx, q.subjectPrefix)
 
 	msg := &nats.Msg{
 		Subject: subject,
@@ -130,6 +133,7 @@ func (q *NATSQueue) Enqueue(ctx context.Context, runID string, data []byte) erro
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
+	metrics.QueueMessages.Inc(q.streamName, "enqueue")
 	return nil
 }
 
@@ -150,6 +154,11 @@ func (q *NATSQueue) Dequeue(ctx context.Context) (*Message, error) {
 			return nil, fmt.Errorf("failed to get message metadata: %w", err)
 		}
 
+		metrics.QueueMessages.Inc(q.streamName, "dequeue")
+		if metadata.NumDelivered > 1 {
+			metrics.QueueRedeliveries.Inc(q.streamName)
+		}
+
 		headers := msg.Headers()
 		var timestamp time.Time
 		var runID string
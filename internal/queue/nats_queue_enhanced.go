@@ -3,8 +3,12 @@ package queue
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"unified-workflow/internal/metrics"
+	"unified-workflow/internal/tenant"
+
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 )
@@ -50,10 +54,10 @@ func NewEnhancedNATSQueue(config EnhancedNATSConfig) (*EnhancedNATSQueue, error)
 		nats.ReconnectWait(config.ReconnectWait),
 		nats.Timeout(config.ConnectTimeout),
 		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
-			fmt.Printf("NATS disconnected: %v\n", err)
+			slog.Warn("NATS disconnected", "error", err)
 		}),
 		nats.ReconnectHandler(func(nc *nats.Conn) {
-			fmt.Printf("NATS reconnected to %s\n", nc.ConnectedUrl())
+			slog.Info("NATS reconnected", "url", nc.ConnectedUrl())
 		}),
 	}
 
@@ -79,11 +83,10 @@ func NewEnhancedNATSQueue(config EnhancedNATSConfig) (*EnhancedNATSQueue, error)
 	// Create or get stream with multiple subjects for request/response
 	streamCfg := jetstream.StreamConfig{
 		Name: config.StreamName,
-		Subjects: []string{
-			fmt.Sprintf("%s.execution.requests", config.SubjectPrefix),
+		Subjects: append(requestSubjects(config.SubjectPrefix),
 			fmt.Sprintf("%s.execution.results.>", config.SubjectPrefix),
 			fmt.Sprintf("%s.execution.errors.>", config.SubjectPrefix),
-		},
+		),
 		Retention: jetstream.WorkQueuePolicy,
 		MaxMsgs:   -1,
 		MaxBytes:  -1,
@@ -98,13 +101,13 @@ func NewEnhancedNATSQueue(config EnhancedNATSConfig) (*EnhancedNATSQueue, error)
 		return nil, fmt.Errorf("failed to create stream: %w", err)
 	}
 
-	// Create or get consumer for execution requests
+	// Create or get consumer for the execution requests of every tenant
 	consumerCfg := jetstream.ConsumerConfig{
-		Durable:       config.DurableName,
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		AckWait:       30 * time.Second,
-		MaxDeliver:    3,
-		FilterSubject: fmt.Sprintf("%s.execution.requests", config.SubjectPrefix),
+		Durable:        config.DurableName,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        30 * time.Second,
+		MaxDeliver:     3,
+		FilterSubjects: requestSubjects(config.SubjectPrefix),
 	}
 
 	consumer, err := stream.CreateOrUpdateConsumer(context.Background(), consumerCfg)
@@ -126,8 +129,8 @@ func NewEnhancedNATSQueue(config EnhancedNATSConfig) (*EnhancedNATSQueue, error)
 
 // EnqueueWithResponse publishes a message and returns a response channel
 func (q *EnhancedNATSQueue) EnqueueWithResponse(ctx context.Context, runID string, data []byte, responseTimeout time.Duration) (chan []byte, error) {
-	subject := fmt.Sprintf("%s.execution.requests", q.subjectPrefix)
-	responseSubject := fmt.Sprintf("%s.execution.results.%s", q.subjectPrefix, runID)
+	subject := requestSubject(ctx, q.subjectPrefix)
+	responseSubject := runSubject(ctx, q.subjectPrefix, "results", runID)
 
 	// Create a channel for the response
 	responseCh := make(chan []byte, 1)
@@ -154,6 +157,7 @@ func (q *EnhancedNATSQueue) EnqueueWithResponse(ctx context.Context, runID strin
 	natsMsg.Header.Set("timestamp", time.Now().Format(time.RFC3339))
 	natsMsg.Header.Set("response_subject", responseSubject)
 	natsMsg.Header.Set("correlation_id", runID)
+	natsMsg.Header.Set("tenant", tenant.FromContext(ctx))
 
 	// Publish message
 	_, err = q.js.PublishMsg(ctx, natsMsg)
@@ -162,6 +166,7 @@ func (q *EnhancedNATSQueue) EnqueueWithResponse(ctx context.Context, runID strin
 		close(responseCh)
 		return nil, fmt.Errorf("failed to publish message: %w", err)
 	}
+	metrics.QueueMessages.Inc(q.streamName, "enqueue")
 
 	// Set up timeout for response
 	if responseTimeout > 0 {
@@ -180,9 +185,9 @@ func (q *EnhancedNATSQueue) EnqueueWithResponse(ctx context.Context, runID strin
 	return responseCh, nil
 }
 
-// Enqueue adds a workflow run ID to the queue for processing
+// Enqueue adds a workflow run ID to the queue for processing on the request subject of the tenant of ctx
 func (q *EnhancedNATSQueue) Enqueue(ctx context.Context, runID string, data []byte) error {
-	subject := fmt.Sprintf("%s.execution.requests", q.subjectPrefix)
+	subject := requestSubject(ctx, q.subjectPrefix)
 
 	msg := &nats.Msg{
 		Subject: subject,
@@ -192,18 +197,20 @@ func (q *EnhancedNATSQueue) Enqueue(ctx context.Context, runID string, data []by
 	msg.Header.Set("run_id", runID)
 	msg.Header.Set("timestamp", time.Now().Format(time.RFC3339))
 	msg.Header.Set("correlation_id", runID)
+	msg.Header.Set("tenant", tenant.FromContext(ctx))
 
 	_, err := q.js.PublishMsg(ctx, msg)
 	if err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
+	metrics.QueueMessages.Inc(q.streamName, "enqueue")
 	return nil
 }
 
-// PublishResult publishes execution result to response subject
+// PublishResult publishes execution result to the response subject of the run in the tenant of ctx
 func (q *EnhancedNATSQueue) PublishResult(ctx context.Context, runID string, resultData []byte) error {
-	subject := fmt.Sprintf("%s.execution.results.%s", q.subjectPrefix, runID)
+	subject := runSubject(ctx, q.subjectPrefix, "results", runID)
 
 	msg := &nats.Msg{
 		Subject: subject,
@@ -222,9 +229,9 @@ func (q *EnhancedNATSQueue) PublishResult(ctx context.Context, runID string, res
 	return nil
 }
 
-// PublishError publishes execution error to error subject
+// PublishError publishes execution error to the error subject of the run in the tenant of ctx
 func (q *EnhancedNATSQueue) PublishError(ctx context.Context, runID string, errorData []byte) error {
-	subject := fmt.Sprintf("%s.execution.errors.%s", q.subjectPrefix, runID)
+	subject := runSubject(ctx, q.subjectPrefix, "errors", runID)
 
 	msg := &nats.Msg{
 		Subject: subject,
@@ -260,6 +267,11 @@ func (q *EnhancedNATSQueue) DequeueEnhanced(ctx context.Context) (*EnhancedMessa
 			return nil, fmt.Errorf("failed to get message metadata: %w", err)
 		}
 
+		metrics.QueueMessages.Inc(q.streamName, "dequeue")
+		if metadata.NumDelivered > 1 {
+			metrics.QueueRedeliveries.Inc(q.streamName)
+		}
+
 		headers := msg.Headers()
 		var timestamp time.Time
 		var runID string
@@ -328,6 +340,7 @@ func (q *EnhancedNATSQueue) AcknowledgeEnhanced(ctx context.Context, msg *Enhanc
 		return fmt.Errorf("failed to acknowledge message: %w", err)
 	}
 
+	metrics.QueueMessages.Inc(q.streamName, "ack")
 	return nil
 }
 
@@ -353,6 +366,7 @@ func (q *EnhancedNATSQueue) RejectEnhanced(ctx context.Context, msg *EnhancedMes
 		}
 	}
 
+	metrics.QueueMessages.Inc(q.streamName, "reject")
 	return nil
 }
 
@@ -404,17 +418,28 @@ func (q *EnhancedNATSQueue) Close() error {
 	return nil
 }
 
-// SubscribeToResults subscribes to results for a specific run ID
-func (q *EnhancedNATSQueue) SubscribeToResults(runID string, handler func([]byte)) (*nats.Subscription, error) {
-	subject := fmt.Sprintf("%s.execution.results.%s", q.subjectPrefix, runID)
-	return q.conn.Subscribe(subject, func(msg *nats.Msg) {
-		handler(msg.Data)
-	})
+// SubscribeToResults subscribes to results for a specific run ID of the tenant of ctx
+func (q *EnhancedNATSQueue) SubscribeToResults(ctx context.Context, runID string, handler func([]byte)) (*nats.Subscription, error) {
+	return q.subscribe(runSubject(ctx, q.subjectPrefix, "results", runID), handler)
+}
+
+// SubscribeToErrors subscribes to errors for a specific run ID of the tenant of ctx
+func (q *EnhancedNATSQueue) SubscribeToErrors(ctx context.Context, runID string, handler func([]byte)) (*nats.Subscription, error) {
+	return q.subscribe(runSubject(ctx, q.subjectPrefix, "errors", runID), handler)
+}
+
+// SubscribeToAllResults subscribes to the results of every run of every tenant
+func (q *EnhancedNATSQueue) SubscribeToAllResults(handler func([]byte)) (*nats.Subscription, error) {
+	return q.subscribe(fmt.Sprintf("%s.execution.results.>", q.subjectPrefix), handler)
+}
+
+// SubscribeToAllErrors subscribes to the errors of every run of every tenant
+func (q *EnhancedNATSQueue) SubscribeToAllErrors(handler func([]byte)) (*nats.Subscription, error) {
+	return q.subscribe(fmt.Sprintf("%s.execution.errors.>", q.subjectPrefix), handler)
 }
 
-// SubscribeToErrors subscribes to errors for a specific run ID
-func (q *EnhancedNATSQueue) SubscribeToErrors(runID string, handler func([]byte)) (*nats.Subscription, error) {
-	subject := fmt.Sprintf("%s.execution.errors.%s", q.subjectPrefix, runID)
+// subscribe delivers the data of the messages published on subject to handler
+func (q *EnhancedNATSQueue) subscribe(subject string, handler func([]byte)) (*nats.Subscription, error) {
 	return q.conn.Subscribe(subject, func(msg *nats.Msg) {
 		handler(msg.Data)
 	})
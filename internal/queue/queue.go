@@ -51,14 +51,20 @@ type Queue interface {
 type ExecutionRequest struct {
 	RunID       string                 `json:"run_id"`
 	WorkflowID  string                 `json:"workflow_id"`
+	Tenant      string                 `json:"tenant,omitempty"`
 	InputData   map[string]interface{} `json:"input_data"`
 	RequestedAt time.Time              `json:"requested_at"`
+	// Resume continues a run that slept on a timer step rather than starting it
+	Resume bool `json:"resume,omitempty"`
+	// NotBefore delays the request until its timer is due; a worker receiving it early has it redelivered
+	NotBefore *time.Time `json:"not_before,omitempty"`
 }
 
 // ExecutionResult represents a workflow execution result
 type ExecutionResult struct {
 	RunID       string                 `json:"run_id"`
 	WorkflowID  string                 `json:"workflow_id"`
+	Tenant      string                 `json:"tenant,omitempty"`
 	Status      string                 `json:"status"`
 	OutputData  map[string]interface{} `json:"output_data,omitempty"`
 	Error       string                 `json:"error,omitempty"`
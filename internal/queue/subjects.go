@@ -0,0 +1,35 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"unified-workflow/internal/tenant"
+)
+
+// requestSubject returns the subject the execution requests of the tenant of ctx are published to
+// The default tenant keeps the subject used before tenancy was introduced
+func requestSubject(ctx context.Context, prefix string) string {
+	tenantID := tenant.FromContext(ctx)
+	if tenant.IsDefault(tenantID) {
+		return fmt.Sprintf("%s.execution.requests", prefix)
+	}
+	return fmt.Sprintf("%s.execution.requests.%s", prefix, tenantID)
+}
+
+// requestSubjects returns the subjects carrying the execution requests of every tenant
+func requestSubjects(prefix string) []string {
+	return []string{
+		fmt.Sprintf("%s.execution.requests", prefix),
+		fmt.Sprintf("%s.execution.requests.*", prefix),
+	}
+}
+
+// runSubject returns the subject the results or errors (kind) of a run of the tenant of ctx are published to
+func runSubject(ctx context.Context, prefix, kind, runID string) string {
+	tenantID := tenant.FromContext(ctx)
+	if tenant.IsDefault(tenantID) {
+		return fmt.Sprintf("%s.execution.%s.%s", prefix, kind, runID)
+	}
+	return fmt.Sprintf("%s.execution.%s.%s.%s", prefix, kind, tenantID, runID)
+}
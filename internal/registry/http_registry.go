@@ -9,29 +9,38 @@ import (
 	"net/http"
 
 	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/tenant"
 	registryClient "unified-workflow/pkg/client/registry"
 )
 
 // HTTPRegistry is an HTTP implementation of the Registry interface
 type HTTPRegistry struct {
-	client registryClient.Client
+	client    registryClient.Client
+	authToken string
 }
 
 // NewHTTPRegistry creates a new HTTP registry
-func NewHTTPRegistry(endpoint string) (*HTTPRegistry, error) {
+// authToken is sent as a bearer token when the registry service requires authentication
+func NewHTTPRegistry(endpoint, authToken string) (*HTTPRegistry, error) {
 	log.Printf("[HTTPRegistry] Creating HTTP registry for endpoint: %s", endpoint)
 	config := registryClient.DefaultConfig()
 	config.Endpoint = endpoint
+	config.AuthToken = authToken
 
 	client := registryClient.NewHTTPClient(config)
 	log.Printf("[HTTPRegistry] HTTP registry created successfully")
 	return &HTTPRegistry{
-		client: client,
+		client:    client,
+		authToken: authToken,
 	}, nil
 }
 
 // RegisterWorkflow registers a workflow with the registry
 func (r *HTTPRegistry) RegisterWorkflow(ctx context.Context, workflow model.Workflow) error {
+	if err := model.ValidateWorkflow(workflow); err != nil {
+		return fmt.Errorf("invalid workflow %s: %w", workflow.GetID(), err)
+	}
+
 	req := &registryClient.CreateWorkflowRequest{
 		Name:        workflow.GetName(),
 		Description: workflow.GetDescription(),
@@ -74,6 +83,12 @@ func (r *HTTPRegistry) getWorkflowDirect(ctx context.Context, workflowID string)
 		log.Printf("[HTTPRegistry] Failed to create request: %v", err)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	if tenantID := tenant.FromContext(ctx); !tenant.IsDefault(tenantID) {
+		req.Header.Set(tenant.Header, tenantID)
+	}
+	if r.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.authToken)
+	}
 
 	// Make request
 	client := &http.Client{}
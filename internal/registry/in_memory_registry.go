@@ -2,42 +2,64 @@ package registry
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/tenant"
 )
 
+// workflowKey identifies a workflow within its tenant
+type workflowKey struct {
+	tenant     string
+	workflowID string
+}
+
+// keyOf returns the key of a workflow of the tenant of ctx
+func keyOf(ctx context.Context, workflowID string) workflowKey {
+	return workflowKey{tenant: tenant.FromContext(ctx), workflowID: workflowID}
+}
+
 // InMemoryRegistry implements the Registry interface using in-memory storage
+// Workflows are namespaced by the tenant of the calling context
 type InMemoryRegistry struct {
 	mu        sync.RWMutex
-	workflows map[string]model.Workflow
-	createdAt map[string]time.Time
-	updatedAt map[string]time.Time
+	workflows map[workflowKey]model.Workflow
+	versions  map[workflowKey][]model.Workflow // every registration of each workflow, oldest first
+	createdAt map[workflowKey]time.Time
+	updatedAt map[workflowKey]time.Time
 }
 
 // NewInMemoryRegistry creates a new in-memory registry
 func NewInMemoryRegistry() *InMemoryRegistry {
 	return &InMemoryRegistry{
-		workflows: make(map[string]model.Workflow),
-		createdAt: make(map[string]time.Time),
-		updatedAt: make(map[string]time.Time),
+		workflows: make(map[workflowKey]model.Workflow),
+		versions:  make(map[workflowKey][]model.Workflow),
+		createdAt: make(map[workflowKey]time.Time),
+		updatedAt: make(map[workflowKey]time.Time),
 	}
 }
 
 // RegisterWorkflow registers a workflow with the registry
+// It fails when the step dependencies of the workflow do not form a directed acyclic graph
 func (r *InMemoryRegistry) RegisterWorkflow(ctx context.Context, workflow model.Workflow) error {
+	if err := model.ValidateWorkflow(workflow); err != nil {
+		return fmt.Errorf("invalid workflow %s: %w", workflow.GetID(), err)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	workflowID := workflow.GetID()
+	key := keyOf(ctx, workflow.GetID())
 	now := time.Now()
 
-	if _, exists := r.workflows[workflowID]; !exists {
-		r.createdAt[workflowID] = now
+	if _, exists := r.workflows[key]; !exists {
+		r.createdAt[key] = now
 	}
-	r.workflows[workflowID] = workflow
-	r.updatedAt[workflowID] = now
+	r.workflows[key] = workflow
+	r.versions[key] = append(r.versions[key], workflow)
+	r.updatedAt[key] = now
 
 	return nil
 }
@@ -46,8 +68,9 @@ func (r *InMemoryRegistry) RegisterWorkflow(ctx context.Context, workflow model.
 func (r *InMemoryRegistry) GetWorkflow(ctx context.Context, workflowID string) (model.Workflow, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	key := keyOf(ctx, workflowID)
 
-	workflow, exists := r.workflows[workflowID]
+	workflow, exists := r.workflows[key]
 	if !exists {
 		return nil, ErrWorkflowNotFound
 	}
@@ -59,8 +82,9 @@ func (r *InMemoryRegistry) GetWorkflow(ctx context.Context, workflowID string) (
 func (r *InMemoryRegistry) ContainsWorkflow(ctx context.Context, workflowID string) (bool, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	key := keyOf(ctx, workflowID)
 
-	_, exists := r.workflows[workflowID]
+	_, exists := r.workflows[key]
 	return exists, nil
 }
 
@@ -68,47 +92,92 @@ func (r *InMemoryRegistry) ContainsWorkflow(ctx context.Context, workflowID stri
 func (r *InMemoryRegistry) RemoveWorkflow(ctx context.Context, workflowID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := keyOf(ctx, workflowID)
 
-	if _, exists := r.workflows[workflowID]; !exists {
+	if _, exists := r.workflows[key]; !exists {
 		return ErrWorkflowNotFound
 	}
 
-	delete(r.workflows, workflowID)
-	delete(r.createdAt, workflowID)
-	delete(r.updatedAt, workflowID)
+	delete(r.workflows, key)
+	delete(r.versions, key)
+	delete(r.createdAt, key)
+	delete(r.updatedAt, key)
 
 	return nil
 }
 
-// GetAllWorkflowIDs gets all registered workflow IDs
+// GetWorkflowVersion retrieves a version of a workflow; each registration under the same ID is a new version
+func (r *InMemoryRegistry) GetWorkflowVersion(ctx context.Context, workflowID string, version int) (model.Workflow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions, exists := r.versions[keyOf(ctx, workflowID)]
+	if !exists {
+		return nil, ErrWorkflowNotFound
+	}
+	if version < 1 || version > len(versions) {
+		return nil, ErrWorkflowVersionNotFound
+	}
+	return versions[version-1], nil
+}
+
+// GetLatestWorkflow retrieves the latest version of a workflow together with its version number
+func (r *InMemoryRegistry) GetLatestWorkflow(ctx context.Context, workflowID string) (model.Workflow, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions, exists := r.versions[keyOf(ctx, workflowID)]
+	if !exists || len(versions) == 0 {
+		return nil, 0, ErrWorkflowNotFound
+	}
+	return versions[len(versions)-1], len(versions), nil
+}
+
+// GetAllWorkflowIDs gets the workflow IDs registered by the tenant of ctx
 func (r *InMemoryRegistry) GetAllWorkflowIDs(ctx context.Context) ([]string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	tenantID := tenant.FromContext(ctx)
 	ids := make([]string, 0, len(r.workflows))
-	for id := range r.workflows {
-		ids = append(ids, id)
+	for key := range r.workflows {
+		if key.tenant == tenantID {
+			ids = append(ids, key.workflowID)
+		}
 	}
 
 	return ids, nil
 }
 
-// GetWorkflowCount gets the number of registered workflows
+// GetWorkflowCount gets the number of workflows registered by the tenant of ctx
 func (r *InMemoryRegistry) GetWorkflowCount(ctx context.Context) (int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return len(r.workflows), nil
+	tenantID := tenant.FromContext(ctx)
+	count := 0
+	for key := range r.workflows {
+		if key.tenant == tenantID {
+			count++
+		}
+	}
+	return count, nil
 }
 
-// Clear removes all workflows from the registry
+// Clear removes all workflows of the tenant of ctx from the registry
 func (r *InMemoryRegistry) Clear(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.workflows = make(map[string]model.Workflow)
-	r.createdAt = make(map[string]time.Time)
-	r.updatedAt = make(map[string]time.Time)
+	tenantID := tenant.FromContext(ctx)
+	for key := range r.workflows {
+		if key.tenant == tenantID {
+			delete(r.workflows, key)
+			delete(r.versions, key)
+			delete(r.createdAt, key)
+			delete(r.updatedAt, key)
+		}
+	}
 
 	return nil
 }
@@ -129,19 +198,20 @@ func (r *InMemoryRegistry) Shutdown(ctx context.Context) error {
 func (r *InMemoryRegistry) GetWorkflowInfo(ctx context.Context, workflowID string) (*WorkflowInfo, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	key := keyOf(ctx, workflowID)
 
-	workflow, exists := r.workflows[workflowID]
+	workflow, exists := r.workflows[key]
 	if !exists {
 		return nil, ErrWorkflowNotFound
 	}
 
 	createdAt := ""
-	if t, ok := r.createdAt[workflowID]; ok {
+	if t, ok := r.createdAt[key]; ok {
 		createdAt = t.Format(time.RFC3339)
 	}
 
 	updatedAt := ""
-	if t, ok := r.updatedAt[workflowID]; ok {
+	if t, ok := r.updatedAt[key]; ok {
 		updatedAt = t.Format(time.RFC3339)
 	}
 
@@ -155,25 +225,29 @@ func (r *InMemoryRegistry) GetWorkflowInfo(ctx context.Context, workflowID strin
 	}, nil
 }
 
-// GetAllWorkflowInfos gets information for all workflows
+// GetAllWorkflowInfos gets information for the workflows of the tenant of ctx
 func (r *InMemoryRegistry) GetAllWorkflowInfos(ctx context.Context) ([]*WorkflowInfo, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	tenantID := tenant.FromContext(ctx)
 	infos := make([]*WorkflowInfo, 0, len(r.workflows))
-	for workflowID, workflow := range r.workflows {
+	for key, workflow := range r.workflows {
+		if key.tenant != tenantID {
+			continue // another tenant's workflow
+		}
 		createdAt := ""
-		if t, ok := r.createdAt[workflowID]; ok {
+		if t, ok := r.createdAt[key]; ok {
 			createdAt = t.Format(time.RFC3339)
 		}
 
 		updatedAt := ""
-		if t, ok := r.updatedAt[workflowID]; ok {
+		if t, ok := r.updatedAt[key]; ok {
 			updatedAt = t.Format(time.RFC3339)
 		}
 
 		infos = append(infos, &WorkflowInfo{
-			ID:          workflowID,
+			ID:          key.workflowID,
 			Name:        workflow.GetName(),
 			Description: workflow.GetDescription(),
 			StepCount:   workflow.GetStepCount(),
@@ -187,7 +261,8 @@ func (r *InMemoryRegistry) GetAllWorkflowInfos(ctx context.Context) ([]*Workflow
 
 // Errors
 var (
-	ErrWorkflowNotFound = &RegistryError{Message: "workflow not found", Code: "NOT_FOUND"}
+	ErrWorkflowNotFound        = &RegistryError{Message: "workflow not found", Code: "NOT_FOUND"}
+	ErrWorkflowVersionNotFound = &RegistryError{Message: "workflow version not found", Code: "VERSION_NOT_FOUND"}
 )
 
 // RegistryError represents a registry error
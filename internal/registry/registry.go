@@ -8,6 +8,7 @@ import (
 
 // Registry is the interface for workflow registry implementations
 // Provides abstraction for different storage backends (in-memory, database, etc.)
+// Workflows are namespaced by the tenant of the context (tenant.FromContext)
 type Registry interface {
 	// RegisterWorkflow registers a workflow with the registry
 	RegisterWorkflow(ctx context.Context, workflow model.Workflow) error
@@ -37,6 +38,17 @@ type Registry interface {
 	Shutdown(ctx context.Context) error
 }
 
+// VersionedRegistry is a registry that keeps every version of a workflow registered more than once
+type VersionedRegistry interface {
+	Registry
+
+	// GetWorkflowVersion retrieves a version of a workflow, counted from 1 in registration order
+	GetWorkflowVersion(ctx context.Context, workflowID string, version int) (model.Workflow, error)
+
+	// GetLatestWorkflow retrieves the latest version of a workflow together with its version number
+	GetLatestWorkflow(ctx context.Context, workflowID string) (model.Workflow, int, error)
+}
+
 // WorkflowInfo represents simplified workflow information for listing
 type WorkflowInfo struct {
 	ID          string `json:"id"`
@@ -0,0 +1,230 @@
+// Package antifraudtest provides an in-process fake of the antifraud API, so the antifraud client and the
+// workflows calling it can be tested offline
+package antifraudtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	af "github.com/baraic-io/antifraud-go"
+	"github.com/google/uuid"
+)
+
+// Services validating transactions, by the path of their endpoint
+var validationServices = map[string]string{
+	"/api/amlsvc/validate": "AML",
+	"/api/fcsvc/validate":  "FC",
+	"/api/mlsvc/validate":  "ML",
+}
+
+// Server is a fake antifraud API listening on a local address
+// Validations approve transactions unless told otherwise with SetResolution; finalizing a transaction
+// aggregates the service checks added to it. Every endpoint answers after the delay set with SetDelay
+type Server struct {
+	*httptest.Server
+	APIKey string
+
+	mu          sync.Mutex
+	delay       time.Duration
+	resolutions map[string]af.ServiceResolution // by service
+	checks      map[string][]af.ServiceResolution
+	calls       map[string]int // by path
+	inFlight    int
+	maxInFlight int
+}
+
+// NewServer starts a fake antifraud API accepting apiKey; close it once done
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		APIKey:      apiKey,
+		resolutions: make(map[string]af.ServiceResolution),
+		checks:      make(map[string][]af.ServiceResolution),
+		calls:       make(map[string]int),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/storagesvc/store/transaction", s.handle(s.store))
+	mux.HandleFunc("POST /api/storagesvc/store/service-resolution", s.handle(s.store))
+	mux.HandleFunc("POST /api/storagesvc/store/final-resolution", s.handle(s.store))
+	for path := range validationServices {
+		mux.HandleFunc("POST "+path, s.handle(s.validate))
+	}
+	mux.HandleFunc("POST /api/fzrsvc/transaction/add-service-check", s.handle(s.addServiceCheck))
+	mux.HandleFunc("POST /api/fzrsvc/transaction/finalize", s.handle(s.finalize))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetDelay makes every endpoint answer after d, or once its request is cancelled
+func (s *Server) SetDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// SetResolution sets the resolution the validation service ("AML", "FC" or "ML") answers with
+func (s *Server) SetResolution(service string, resolution af.ServiceResolution) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolutions[service] = resolution
+}
+
+// Calls returns the number of requests received by the endpoint at path
+func (s *Server) Calls(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[path]
+}
+
+// MaxInFlight returns the largest number of requests the server was handling at once
+func (s *Server) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxInFlight
+}
+
+// handle authenticates a request, counts it, waits for the delay and then serves it with serve
+func (s *Server) handle(serve func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != s.APIKey {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		s.mu.Lock()
+		s.calls[r.URL.Path]++
+		s.inFlight++
+		s.maxInFlight = max(s.maxInFlight, s.inFlight)
+		delay := s.delay
+		s.mu.Unlock()
+		defer func() {
+			s.mu.Lock()
+			s.inFlight--
+			s.mu.Unlock()
+		}()
+
+		// The connection is watched for the client going away only once the body was read
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		serve(w, r)
+	}
+}
+
+// store accepts a transaction or a resolution to store
+func (s *Server) store(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// validate answers a validation with the resolution set for its service
+func (s *Server) validate(w http.ResponseWriter, r *http.Request) {
+	var transaction af.AF_Transaction
+	if err := json.NewDecoder(r.Body).Decode(&transaction); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	service := validationServices[r.URL.Path]
+	s.mu.Lock()
+	resolution, ok := s.resolutions[service]
+	s.mu.Unlock()
+	if !ok {
+		resolution = af.ServiceResolution{Validated: true, Details: map[string]string{"rules": "passed"}}
+	}
+	resolution.AF_Id = transaction.AF_Id
+	resolution.TxnId = transaction.Transaction.Id
+	resolution.Id = uuid.New()
+	resolution.Date = time.Now()
+	resolution.Service = service
+	writeJSON(w, resolution)
+}
+
+// addServiceCheck adds a service check to the aggregation of its transaction
+func (s *Server) addServiceCheck(w http.ResponseWriter, r *http.Request) {
+	var check af.ServiceResolution
+	if err := json.NewDecoder(r.Body).Decode(&check); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if check.AF_Id == "" {
+		http.Error(w, "af_id is required", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.checks[check.AF_Id] = append(s.checks[check.AF_Id], check)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+// finalize resolves a transaction from its service checks: rejected on fraud or blocking, under review on an
+// alert and approved otherwise
+func (s *Server) finalize(w http.ResponseWriter, r *http.Request) {
+	var transaction af.AF_Transaction
+	if err := json.NewDecoder(r.Body).Decode(&transaction); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	checks := s.checks[transaction.AF_Id]
+	s.mu.Unlock()
+	if len(checks) == 0 {
+		http.Error(w, "no service checks added to transaction "+transaction.AF_Id, http.StatusConflict)
+		return
+	}
+
+	resolution := af.FinalResolution{
+		AF_Id:          transaction.AF_Id,
+		AF_Transaction: transaction,
+		Id:             uuid.NewString(),
+		FinalizedDate:  time.Now(),
+	}
+	for _, check := range checks {
+		resolution.Fraud = resolution.Fraud || check.Fraud
+		resolution.Blocked = resolution.Blocked || check.Blocked
+		resolution.Alert = resolution.Alert || check.Alert
+		if check.Validated {
+			resolution.ValidatedServices = append(resolution.ValidatedServices, check.Service)
+		} else {
+			resolution.UnvalidatedServices = append(resolution.UnvalidatedServices, check.Service)
+		}
+	}
+	switch {
+	case resolution.Fraud || resolution.Blocked:
+		resolution.FinalizedAction = "REJECTED"
+	case resolution.Alert:
+		resolution.FinalizedAction = "REVIEW"
+	default:
+		resolution.FinalizedAction = "APPROVED"
+		resolution.Validated = true
+	}
+	writeJSON(w, resolution)
+}
+
+// writeJSON writes v as the JSON body of a 200 OK response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -1,7 +1,14 @@
 package antifraud
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	primitiveantifraud "unified-workflow/internal/primitive/services/antifraud"
@@ -10,11 +17,24 @@ import (
 	af "github.com/baraic-io/antifraud-go"
 )
 
+// Paths of the antifraud API endpoints, as called by the github.com/baraic-io/antifraud-go SDK
+const (
+	storeTransactionPath       = "/api/storagesvc/store/transaction"
+	storeServiceResolutionPath = "/api/storagesvc/store/service-resolution"
+	storeFinalResolutionPath   = "/api/storagesvc/store/final-resolution"
+	validateAMLPath            = "/api/amlsvc/validate"
+	validateFCPath             = "/api/fcsvc/validate"
+	validateMLPath             = "/api/mlsvc/validate"
+	addServiceCheckPath        = "/api/fzrsvc/transaction/add-service-check"
+	finalizeTransactionPath    = "/api/fzrsvc/transaction/finalize"
+)
+
 // antifraudClientImpl is the implementation of AntifraudService
-// This wraps the actual github.com/baraic-io/antifraud-go SDK
+// It speaks the wire format of the github.com/baraic-io/antifraud-go SDK, whose calls take no context, so
+// that the context of every call, bounded by the configured timeout, cancels its HTTP request
 type antifraudClientImpl struct {
-	config models.ClientConfig
-	client af.Client
+	config     models.ClientConfig
+	httpClient *http.Client
 }
 
 // NewClient creates a new antifraud client
@@ -40,20 +60,66 @@ func NewClient(config models.ClientConfig) (primitiveantifraud.AntifraudService,
 		config.MaxRetries = 3
 	}
 
-	// Initialize the SDK client
-	afConfig := af.ClientConfig{
-		Host:   config.Host,
-		APIKey: config.APIKey,
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 10
+	transport.IdleConnTimeout = 30 * time.Second
+
+	return &antifraudClientImpl{
+		config:     config,
+		httpClient: &http.Client{Transport: transport},
+	}, nil
+}
+
+// call posts body as JSON to an endpoint of the antifraud API and decodes the response into out, if any
+// The request is cancelled with ctx and once the configured timeout expires; a response other than 200 OK
+// is returned as an af.CodeError
+func (c *antifraudClientImpl) call(ctx context.Context, path string, body, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.config.Timeout)*time.Second)
+	defer cancel()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
 	}
-	client, err := af.NewClient(afConfig)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.config.Host, "/")+path, bytes.NewReader(payload))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create antifraud client: %w", err)
+		return err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", c.config.APIKey)
 
-	return &antifraudClientImpl{
-		config: config,
-		client: client,
-	}, nil
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		if err != nil {
+			return af.CodeError{Code: resp.StatusCode, Msg: fmt.Sprintf("failed to read response body: %s", err)}
+		}
+		return af.CodeError{Code: resp.StatusCode, Msg: string(data)}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// validate validates a transaction with the validation service at path
+func (c *antifraudClientImpl) validate(ctx context.Context, path string, afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
+	var result af.ServiceResolution
+	if err := c.call(ctx, path, convertToSDKTransaction(afTransaction), &result); err != nil {
+		return models.ServiceResolution{}, err
+	}
+	return convertFromSDKServiceResolution(result), nil
 }
 
 // convertToSDKTransaction converts our AF_Transaction to SDK's AF_Transaction
@@ -114,10 +180,11 @@ func convertFromSDKServiceResolution(res af.ServiceResolution) models.ServiceRes
 	}
 
 	return models.ServiceResolution{
-		ServiceName: res.Service,
-		Resolution:  resolution,
-		Score:       score,
-		Details:     detailsStr,
+		TransactionId: res.AF_Id,
+		ServiceName:   res.Service,
+		Resolution:    resolution,
+		Score:         score,
+		Details:       detailsStr,
 	}
 }
 
@@ -137,6 +204,7 @@ func convertToSDKServiceResolution(res models.ServiceResolution) af.ServiceResol
 	validated := res.Resolution == "APPROVED"
 
 	return af.ServiceResolution{
+		AF_Id:       res.TransactionId,
 		Service:     res.ServiceName,
 		Details:     details,
 		Fraud:       fraud,
@@ -208,106 +276,64 @@ func convertToSDKFinalResolution(res models.FinalResolution) af.FinalResolution
 }
 
 // StoreTransaction stores a transaction in the antifraud system
-func (c *antifraudClientImpl) StoreTransaction(afTransaction models.AF_Transaction) error {
-	// Convert to SDK type
-	sdkTransaction := convertToSDKTransaction(afTransaction)
-
-	// Call the SDK
-	return c.client.StoreTransaction(sdkTransaction)
+func (c *antifraudClientImpl) StoreTransaction(ctx context.Context, afTransaction models.AF_Transaction) error {
+	return c.call(ctx, storeTransactionPath, convertToSDKTransaction(afTransaction), nil)
 }
 
 // ValidateTransactionByAML validates a transaction using the AML service
-func (c *antifraudClientImpl) ValidateTransactionByAML(afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
-	// Convert to SDK type
-	sdkTransaction := convertToSDKTransaction(afTransaction)
-
-	// Call the SDK
-	result, err := c.client.ValidateTransactionByAML(sdkTransaction)
+func (c *antifraudClientImpl) ValidateTransactionByAML(ctx context.Context, afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
+	result, err := c.validate(ctx, validateAMLPath, afTransaction)
 	if err != nil {
 		return models.ServiceResolution{}, fmt.Errorf("AML validation failed: %w", err)
 	}
-
-	// Convert back to our type
-	return convertFromSDKServiceResolution(result), nil
+	return result, nil
 }
 
 // ValidateTransactionByFC validates a transaction using the FC service
-func (c *antifraudClientImpl) ValidateTransactionByFC(afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
-	// Convert to SDK type
-	sdkTransaction := convertToSDKTransaction(afTransaction)
-
-	// Call the SDK
-	result, err := c.client.ValidateTransactionByFC(sdkTransaction)
+func (c *antifraudClientImpl) ValidateTransactionByFC(ctx context.Context, afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
+	result, err := c.validate(ctx, validateFCPath, afTransaction)
 	if err != nil {
 		return models.ServiceResolution{}, fmt.Errorf("FC validation failed: %w", err)
 	}
-
-	// Convert back to our type
-	return convertFromSDKServiceResolution(result), nil
+	return result, nil
 }
 
 // ValidateTransactionByML validates a transaction using the ML service
-func (c *antifraudClientImpl) ValidateTransactionByML(afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
-	// Convert to SDK type
-	sdkTransaction := convertToSDKTransaction(afTransaction)
-
-	// Call the SDK
-	result, err := c.client.ValidateTransactionByML(sdkTransaction)
+func (c *antifraudClientImpl) ValidateTransactionByML(ctx context.Context, afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
+	result, err := c.validate(ctx, validateMLPath, afTransaction)
 	if err != nil {
 		return models.ServiceResolution{}, fmt.Errorf("ML validation failed: %w", err)
 	}
-
-	// Convert back to our type
-	return convertFromSDKServiceResolution(result), nil
+	return result, nil
 }
 
 // StoreServiceResolution stores the resolution from a service check
-func (c *antifraudClientImpl) StoreServiceResolution(resolution models.ServiceResolution) error {
-	// Convert to SDK type
-	sdkResolution := convertToSDKServiceResolution(resolution)
-
-	// Call the SDK
-	return c.client.StoreServiceResolution(sdkResolution)
+func (c *antifraudClientImpl) StoreServiceResolution(ctx context.Context, resolution models.ServiceResolution) error {
+	return c.call(ctx, storeServiceResolutionPath, convertToSDKServiceResolution(resolution), nil)
 }
 
 // AddTransactionServiceCheck adds a completed service check resolution
-func (c *antifraudClientImpl) AddTransactionServiceCheck(resolution models.ServiceResolution) error {
-	// Convert to SDK type
-	sdkResolution := convertToSDKServiceResolution(resolution)
-
-	// Call the SDK
-	return c.client.AddTransactionServiceCheck(sdkResolution)
+func (c *antifraudClientImpl) AddTransactionServiceCheck(ctx context.Context, resolution models.ServiceResolution) error {
+	return c.call(ctx, addServiceCheckPath, convertToSDKServiceResolution(resolution), nil)
 }
 
 // FinalizeTransaction finalizes the transaction validation process
-func (c *antifraudClientImpl) FinalizeTransaction(afTransaction models.AF_Transaction) (models.FinalResolution, error) {
-	// Convert to SDK type
-	sdkTransaction := convertToSDKTransaction(afTransaction)
-
-	// Call the SDK
-	result, err := c.client.FinalizeTransaction(sdkTransaction)
-	if err != nil {
+func (c *antifraudClientImpl) FinalizeTransaction(ctx context.Context, afTransaction models.AF_Transaction) (models.FinalResolution, error) {
+	var result af.FinalResolution
+	if err := c.call(ctx, finalizeTransactionPath, convertToSDKTransaction(afTransaction), &result); err != nil {
 		return models.FinalResolution{}, fmt.Errorf("failed to finalize transaction: %w", err)
 	}
-
-	// Convert back to our type
 	return convertFromSDKFinalResolution(result), nil
 }
 
 // StoreFinalResolution stores the final resolution of the transaction
-func (c *antifraudClientImpl) StoreFinalResolution(resolution models.FinalResolution) error {
-	// Convert to SDK type
-	sdkResolution := convertToSDKFinalResolution(resolution)
-
-	// Call the SDK
-	return c.client.StoreFinalResolution(sdkResolution)
+func (c *antifraudClientImpl) StoreFinalResolution(ctx context.Context, resolution models.FinalResolution) error {
+	return c.call(ctx, storeFinalResolutionPath, convertToSDKFinalResolution(resolution), nil)
 }
 
 // HealthCheck checks the health of the antifraud service
-func (c *antifraudClientImpl) HealthCheck() (bool, error) {
-	// Note: The SDK doesn't have a HealthCheck method
-	// We'll implement a simple health check by trying to make a lightweight API call
-	// For now, we'll assume the service is healthy if we can create a simple transaction
+func (c *antifraudClientImpl) HealthCheck(ctx context.Context) (bool, error) {
+	// The API has no health endpoint, so the service is considered healthy if it stores a test transaction
 	testTransaction := models.AF_Transaction{
 		AF_Id:      "health-check",
 		AF_AddDate: time.Now().Format(time.RFC3339Nano),
@@ -317,14 +343,7 @@ func (c *antifraudClientImpl) HealthCheck() (bool, error) {
 		},
 	}
 
-	// Try to store a test transaction (this should fail gracefully if service is down)
-	err := c.StoreTransaction(testTransaction)
-	if err != nil {
-		// Check if it's an authentication error vs connection error
-		if err.Error() == "antifraud service is disabled" {
-			return false, fmt.Errorf("service disabled")
-		}
-		// For health check purposes, we consider any error as unhealthy
+	if err := c.StoreTransaction(ctx, testTransaction); err != nil {
 		return false, fmt.Errorf("health check failed: %w", err)
 	}
 
@@ -336,45 +355,48 @@ func (c *antifraudClientImpl) GetConfig() models.ClientConfig {
 	return c.config
 }
 
+// errDisabled is returned by every call to a disabled antifraud service
+var errDisabled = errors.New("antifraud service is disabled")
+
 // disabledClient is used when the antifraud service is disabled
 type disabledClient struct {
 	config models.ClientConfig
 }
 
-func (c *disabledClient) StoreTransaction(afTransaction models.AF_Transaction) error {
-	return fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) StoreTransaction(ctx context.Context, afTransaction models.AF_Transaction) error {
+	return errDisabled
 }
 
-func (c *disabledClient) ValidateTransactionByAML(afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
-	return models.ServiceResolution{}, fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) ValidateTransactionByAML(ctx context.Context, afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
+	return models.ServiceResolution{}, errDisabled
 }
 
-func (c *disabledClient) ValidateTransactionByFC(afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
-	return models.ServiceResolution{}, fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) ValidateTransactionByFC(ctx context.Context, afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
+	return models.ServiceResolution{}, errDisabled
 }
 
-func (c *disabledClient) ValidateTransactionByML(afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
-	return models.ServiceResolution{}, fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) ValidateTransactionByML(ctx context.Context, afTransaction models.AF_Transaction) (models.ServiceResolution, error) {
+	return models.ServiceResolution{}, errDisabled
 }
 
-func (c *disabledClient) StoreServiceResolution(resolution models.ServiceResolution) error {
-	return fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) StoreServiceResolution(ctx context.Context, resolution models.ServiceResolution) error {
+	return errDisabled
 }
 
-func (c *disabledClient) AddTransactionServiceCheck(resolution models.ServiceResolution) error {
-	return fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) AddTransactionServiceCheck(ctx context.Context, resolution models.ServiceResolution) error {
+	return errDisabled
 }
 
-func (c *disabledClient) FinalizeTransaction(afTransaction models.AF_Transaction) (models.FinalResolution, error) {
-	return models.FinalResolution{}, fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) FinalizeTransaction(ctx context.Context, afTransaction models.AF_Transaction) (models.FinalResolution, error) {
+	return models.FinalResolution{}, errDisabled
 }
 
-func (c *disabledClient) StoreFinalResolution(resolution models.FinalResolution) error {
-	return fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) StoreFinalResolution(ctx context.Context, resolution models.FinalResolution) error {
+	return errDisabled
 }
 
-func (c *disabledClient) HealthCheck() (bool, error) {
-	return false, fmt.Errorf("antifraud service is disabled")
+func (c *disabledClient) HealthCheck(ctx context.Context) (bool, error) {
+	return false, errDisabled
 }
 
 func (c *disabledClient) GetConfig() models.ClientConfig {
@@ -1,9 +1,17 @@
 package antifraud
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
+
+	primitiveantifraud "unified-workflow/internal/primitive/services/antifraud"
 	"unified-workflow/internal/primitive/services/antifraud/models"
+	"unified-workflow/internal/serviceclients/antifraud/antifraudtest"
+
+	af "github.com/baraic-io/antifraud-go"
 )
 
 func TestNewClient(t *testing.T) {
@@ -105,7 +113,7 @@ func TestAntifraudClientImpl_StoreTransaction(t *testing.T) {
 		},
 	}
 
-	err = client.StoreTransaction(transaction)
+	err = client.StoreTransaction(context.Background(), transaction)
 	if err != nil {
 		t.Errorf("StoreTransaction() error = %v", err)
 	}
@@ -149,7 +157,7 @@ func TestAntifraudClientImpl_ValidateTransactionByAML(t *testing.T) {
 		},
 	}
 
-	result, err := client.ValidateTransactionByAML(transaction)
+	result, err := client.ValidateTransactionByAML(context.Background(), transaction)
 	if err != nil {
 		t.Errorf("ValidateTransactionByAML() error = %v", err)
 		return
@@ -187,27 +195,27 @@ func TestDisabledClient(t *testing.T) {
 	}
 
 	// All operations should return "service disabled" error
-	err = client.StoreTransaction(transaction)
+	err = client.StoreTransaction(context.Background(), transaction)
 	if err == nil || err.Error() != "antifraud service is disabled" {
 		t.Errorf("StoreTransaction() error = %v, want 'antifraud service is disabled'", err)
 	}
 
-	_, err = client.ValidateTransactionByAML(transaction)
+	_, err = client.ValidateTransactionByAML(context.Background(), transaction)
 	if err == nil || err.Error() != "antifraud service is disabled" {
 		t.Errorf("ValidateTransactionByAML() error = %v, want 'antifraud service is disabled'", err)
 	}
 
-	_, err = client.ValidateTransactionByFC(transaction)
+	_, err = client.ValidateTransactionByFC(context.Background(), transaction)
 	if err == nil || err.Error() != "antifraud service is disabled" {
 		t.Errorf("ValidateTransactionByFC() error = %v, want 'antifraud service is disabled'", err)
 	}
 
-	_, err = client.ValidateTransactionByML(transaction)
+	_, err = client.ValidateTransactionByML(context.Background(), transaction)
 	if err == nil || err.Error() != "antifraud service is disabled" {
 		t.Errorf("ValidateTransactionByML() error = %v, want 'antifraud service is disabled'", err)
 	}
 
-	healthy, err := client.HealthCheck()
+	healthy, err := client.HealthCheck(context.Background())
 	if err == nil || err.Error() != "antifraud service is disabled" {
 		t.Errorf("HealthCheck() error = %v, want 'antifraud service is disabled'", err)
 	}
@@ -216,12 +224,12 @@ func TestDisabledClient(t *testing.T) {
 	}
 }
 
-func TestProxyCircuitBreaker(t *testing.T) {
+func TestProxyPassesErrorsThrough(t *testing.T) {
 	config := models.ClientConfig{
 		APIKey:                  "test-api-key",
 		Host:                    "https://api.example.com",
 		Timeout:                 30,
-		Enabled:                 true,
+		Enabled:                 false,
 		CircuitBreakerEnabled:   true,
 		CircuitBreakerThreshold: 2,
 		CircuitBreakerTimeout:   1, // 1 second
@@ -236,18 +244,128 @@ func TestProxyCircuitBreaker(t *testing.T) {
 	// Create proxy
 	proxy := NewProxy(underlyingClient, config)
 
-	// Get the proxy instance to access circuit breaker methods
-	_, ok := proxy.(interface {
-		GetCircuitBreakerStatus() (bool, int, time.Time)
+	// Circuit breaking lives in the DI resilience decorator, so the proxy
+	// must keep surfacing the underlying error past the old threshold
+	for i := 0; i < 5; i++ {
+		err := proxy.StoreTransaction(context.Background(), models.AF_Transaction{})
+		if err == nil || !strings.Contains(err.Error(), "antifraud service is disabled") {
+			t.Fatalf("StoreTransaction() call %d error = %v, want underlying 'antifraud service is disabled'", i+1, err)
+		}
+	}
+}
+
+// fakeClient returns a client of a fake antifraud API
+func fakeClient(t *testing.T) (*antifraudtest.Server, primitiveantifraud.AntifraudService) {
+	t.Helper()
+	server := antifraudtest.NewServer("test-api-key")
+	t.Cleanup(server.Close)
+	client, err := NewClient(models.ClientConfig{
+		APIKey:  server.APIKey,
+		Host:    server.URL,
+		Timeout: 5,
+		Enabled: true,
 	})
-	if !ok {
-		t.Fatal("Failed to cast proxy to antifraudProxy")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return server, client
+}
+
+func testTransaction() models.AF_Transaction {
+	return models.AF_Transaction{
+		AF_Id:      "test-transaction-id",
+		AF_AddDate: time.Now().Format(time.RFC3339Nano),
+		Transaction: models.Transaction{
+			Id:       "txn-123",
+			Type:     "deposit",
+			Amount:   "100000",
+			Currency: "KZT",
+			ClientId: "client-123",
+		},
+	}
+}
+
+func TestClientAgainstFakeServer(t *testing.T) {
+	server, client := fakeClient(t)
+	server.SetResolution("FC", af.ServiceResolution{Alert: true})
+	ctx := context.Background()
+	transaction := testTransaction()
+
+	if err := client.StoreTransaction(ctx, transaction); err != nil {
+		t.Fatalf("StoreTransaction() error = %v", err)
+	}
+	aml, err := client.ValidateTransactionByAML(ctx, transaction)
+	if err != nil {
+		t.Fatalf("ValidateTransactionByAML() error = %v", err)
+	}
+	if aml.ServiceName != "AML" || aml.Resolution != "APPROVED" || aml.TransactionId != transaction.AF_Id {
+		t.Errorf("ValidateTransactionByAML() = %+v, want an approved AML resolution of %s", aml, transaction.AF_Id)
+	}
+	fc, err := client.ValidateTransactionByFC(ctx, transaction)
+	if err != nil {
+		t.Fatalf("ValidateTransactionByFC() error = %v", err)
+	}
+	if fc.Resolution != "ALERT" {
+		t.Errorf("ValidateTransactionByFC() Resolution = %s, want ALERT", fc.Resolution)
+	}
+
+	for _, check := range []models.ServiceResolution{aml, fc} {
+		if err := client.AddTransactionServiceCheck(ctx, check); err != nil {
+			t.Fatalf("AddTransactionServiceCheck() error = %v", err)
+		}
+	}
+	final, err := client.FinalizeTransaction(ctx, transaction)
+	if err != nil {
+		t.Fatalf("FinalizeTransaction() error = %v", err)
 	}
+	if final.TransactionId != transaction.AF_Id || final.FinalDecision != "REVIEW" {
+		t.Errorf("FinalizeTransaction() = %+v, want a REVIEW of %s", final, transaction.AF_Id)
+	}
+	if err := client.StoreFinalResolution(ctx, final); err != nil {
+		t.Fatalf("StoreFinalResolution() error = %v", err)
+	}
+
+	healthy, err := client.HealthCheck(ctx)
+	if err != nil || !healthy {
+		t.Errorf("HealthCheck() = %v, %v, want healthy", healthy, err)
+	}
+}
+
+func TestClientCallsAreCancelledWithTheirContext(t *testing.T) {
+	server, client := fakeClient(t)
+	server.SetDelay(time.Second)
 
-	// We can't test circuit breaker directly without exposing internal state
-	// This test is now limited since we can't access the internal antifraudProxy type
-	// from outside the package
-	t.Log("Circuit breaker test limited due to package encapsulation")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := client.ValidateTransactionByML(ctx, testTransaction())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ValidateTransactionByML() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("ValidateTransactionByML() returned after %s, want it to stop at the deadline", elapsed)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if err := client.StoreTransaction(ctx, testTransaction()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("StoreTransaction() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestClientReportsStatusCodes(t *testing.T) {
+	server := antifraudtest.NewServer("test-api-key")
+	defer server.Close()
+	client, err := NewClient(models.ClientConfig{APIKey: "wrong-key", Host: server.URL, Enabled: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.ValidateTransactionByAML(context.Background(), testTransaction())
+	var codeErr af.CodeError
+	if !errors.As(err, &codeErr) || codeErr.Code != 401 {
+		t.Fatalf("ValidateTransactionByAML() error = %v, want a 401 CodeError", err)
+	}
 }
 
 func TestGetConfig(t *testing.T) {
@@ -0,0 +1,30 @@
+package state
+
+import (
+	"fmt"
+
+	"unified-workflow/internal/config"
+)
+
+// NewStateManagement creates the state store selected by the state configuration
+// A Redis store that cannot be reached is an error rather than a fallback, as processes would no longer share runs
+func NewStateManagement(cfg config.StateConfig) (StateManagement, error) {
+	switch cfg.Type {
+	case "", "in-memory":
+		return NewInMemoryState(), nil
+	case "redis":
+		store, err := NewRedisState(RedisConfig{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+			Prefix:   cfg.Redis.Prefix,
+			TTL:      cfg.Redis.TTL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown state store type %q", cfg.Type)
+	}
+}
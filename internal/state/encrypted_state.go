@@ -0,0 +1,54 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"unified-workflow/internal/dataprotection"
+	"unified-workflow/internal/primitive/model"
+)
+
+// EncryptedState decorates a StateManagement so that classified workflow data fields are encrypted at rest
+type EncryptedState struct {
+	StateManagement
+	encryptor *dataprotection.Encryptor
+}
+
+// NewEncryptedState wraps a state store; a nil encryptor stores data unchanged
+func NewEncryptedState(inner StateManagement, encryptor *dataprotection.Encryptor) StateManagement {
+	if encryptor == nil {
+		return inner
+	}
+	return &EncryptedState{StateManagement: inner, encryptor: encryptor}
+}
+
+// SaveData encrypts classified fields before saving the workflow data
+func (s *EncryptedState) SaveData(ctx context.Context, runID string, workflowData model.WorkflowData) error {
+	encrypted, err := s.encryptor.EncryptFields(ctx, workflowData.ToMap())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt workflow data for %s: %w", runID, err)
+	}
+	return s.StateManagement.SaveData(ctx, runID, workflowDataFromMap(encrypted))
+}
+
+// GetData retrieves the workflow data and decrypts its classified fields
+func (s *EncryptedState) GetData(ctx context.Context, runID string) (model.WorkflowData, error) {
+	workflowData, err := s.StateManagement.GetData(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := s.encryptor.DecryptFields(ctx, workflowData.ToMap())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt workflow data for %s: %w", runID, err)
+	}
+	return workflowDataFromMap(decrypted), nil
+}
+
+// workflowDataFromMap builds workflow data from a map
+func workflowDataFromMap(data map[string]interface{}) model.WorkflowData {
+	workflowData := model.NewWorkflowData()
+	for key, value := range data {
+		workflowData.Put(key, value)
+	}
+	return workflowData
+}
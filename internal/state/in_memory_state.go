@@ -6,28 +6,43 @@ import (
 	"time"
 
 	"unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/tenant"
 )
 
+// runKey identifies the state of a run within its tenant
+type runKey struct {
+	tenant string
+	runID  string
+}
+
+// keyOf returns the key of a run of the tenant of ctx
+func keyOf(ctx context.Context, runID string) runKey {
+	return runKey{tenant: tenant.FromContext(ctx), runID: runID}
+}
+
 // InMemoryState implements the StateManagement interface using in-memory storage
+// Runs are namespaced by the tenant of the calling context
 type InMemoryState struct {
 	mu               sync.RWMutex
-	contexts         map[string]model.WorkflowContext
-	data             map[string]model.WorkflowData
-	locks            map[string]bool
-	ttl              map[string]time.Time
-	contextCreatedAt map[string]time.Time
-	contextUpdatedAt map[string]time.Time
+	contexts         map[runKey]model.WorkflowContext
+	data             map[runKey]model.WorkflowData
+	locks            map[runKey]bool
+	ttl              map[runKey]time.Time
+	contextCreatedAt map[runKey]time.Time
+	contextUpdatedAt map[runKey]time.Time
+	records          map[string]map[string][]byte // by namespace and key
 }
 
 // NewInMemoryState creates a new in-memory state management
 func NewInMemoryState() *InMemoryState {
 	return &InMemoryState{
-		contexts:         make(map[string]model.WorkflowContext),
-		data:             make(map[string]model.WorkflowData),
-		locks:            make(map[string]bool),
-		ttl:              make(map[string]time.Time),
-		contextCreatedAt: make(map[string]time.Time),
-		contextUpdatedAt: make(map[string]time.Time),
+		contexts:         make(map[runKey]model.WorkflowContext),
+		data:             make(map[runKey]model.WorkflowData),
+		locks:            make(map[runKey]bool),
+		ttl:              make(map[runKey]time.Time),
+		contextCreatedAt: make(map[runKey]time.Time),
+		contextUpdatedAt: make(map[runKey]time.Time),
+		records:          make(map[string]map[string][]byte),
 	}
 }
 
@@ -36,22 +51,22 @@ func (s *InMemoryState) SaveContext(ctx context.Context, workflowContext model.W
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	runID := workflowContext.GetRunID()
+	key := keyOf(ctx, workflowContext.GetRunID())
 	now := time.Now()
 
-	if _, exists := s.contexts[runID]; !exists {
-		s.contextCreatedAt[runID] = now
+	if _, exists := s.contexts[key]; !exists {
+		s.contextCreatedAt[key] = now
 	}
-	s.contexts[runID] = workflowContext
-	s.contextUpdatedAt[runID] = now
+	s.contexts[key] = workflowContext
+	s.contextUpdatedAt[key] = now
 
 	// Check TTL
-	if expiry, ok := s.ttl[runID]; ok && now.After(expiry) {
-		delete(s.contexts, runID)
-		delete(s.data, runID)
-		delete(s.ttl, runID)
-		delete(s.contextCreatedAt, runID)
-		delete(s.contextUpdatedAt, runID)
+	if expiry, ok := s.ttl[key]; ok && now.After(expiry) {
+		delete(s.contexts, key)
+		delete(s.data, key)
+		delete(s.ttl, key)
+		delete(s.contextCreatedAt, key)
+		delete(s.contextUpdatedAt, key)
 	}
 
 	return nil
@@ -61,18 +76,19 @@ func (s *InMemoryState) SaveContext(ctx context.Context, workflowContext model.W
 func (s *InMemoryState) GetContext(ctx context.Context, runID string) (model.WorkflowContext, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	key := keyOf(ctx, runID)
 
 	// Check TTL
-	if expiry, ok := s.ttl[runID]; ok && time.Now().After(expiry) {
-		delete(s.contexts, runID)
-		delete(s.data, runID)
-		delete(s.ttl, runID)
-		delete(s.contextCreatedAt, runID)
-		delete(s.contextUpdatedAt, runID)
+	if expiry, ok := s.ttl[key]; ok && time.Now().After(expiry) {
+		delete(s.contexts, key)
+		delete(s.data, key)
+		delete(s.ttl, key)
+		delete(s.contextCreatedAt, key)
+		delete(s.contextUpdatedAt, key)
 		return nil, ErrStateNotFound
 	}
 
-	context, exists := s.contexts[runID]
+	context, exists := s.contexts[key]
 	if !exists {
 		return nil, ErrStateNotFound
 	}
@@ -84,18 +100,19 @@ func (s *InMemoryState) GetContext(ctx context.Context, runID string) (model.Wor
 func (s *InMemoryState) SaveData(ctx context.Context, runID string, workflowData model.WorkflowData) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	key := keyOf(ctx, runID)
 
 	// Check TTL
-	if expiry, ok := s.ttl[runID]; ok && time.Now().After(expiry) {
-		delete(s.contexts, runID)
-		delete(s.data, runID)
-		delete(s.ttl, runID)
-		delete(s.contextCreatedAt, runID)
-		delete(s.contextUpdatedAt, runID)
+	if expiry, ok := s.ttl[key]; ok && time.Now().After(expiry) {
+		delete(s.contexts, key)
+		delete(s.data, key)
+		delete(s.ttl, key)
+		delete(s.contextCreatedAt, key)
+		delete(s.contextUpdatedAt, key)
 		return ErrStateExpired
 	}
 
-	s.data[runID] = workflowData
+	s.data[key] = workflowData
 	return nil
 }
 
@@ -103,18 +120,19 @@ func (s *InMemoryState) SaveData(ctx context.Context, runID string, workflowData
 func (s *InMemoryState) GetData(ctx context.Context, runID string) (model.WorkflowData, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	key := keyOf(ctx, runID)
 
 	// Check TTL
-	if expiry, ok := s.ttl[runID]; ok && time.Now().After(expiry) {
-		delete(s.contexts, runID)
-		delete(s.data, runID)
-		delete(s.ttl, runID)
-		delete(s.contextCreatedAt, runID)
-		delete(s.contextUpdatedAt, runID)
+	if expiry, ok := s.ttl[key]; ok && time.Now().After(expiry) {
+		delete(s.contexts, key)
+		delete(s.data, key)
+		delete(s.ttl, key)
+		delete(s.contextCreatedAt, key)
+		delete(s.contextUpdatedAt, key)
 		return nil, ErrStateNotFound
 	}
 
-	data, exists := s.data[runID]
+	data, exists := s.data[key]
 	if !exists {
 		return nil, ErrStateNotFound
 	}
@@ -126,13 +144,14 @@ func (s *InMemoryState) GetData(ctx context.Context, runID string) (model.Workfl
 func (s *InMemoryState) RemoveState(ctx context.Context, runID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	key := keyOf(ctx, runID)
 
-	delete(s.contexts, runID)
-	delete(s.data, runID)
-	delete(s.locks, runID)
-	delete(s.ttl, runID)
-	delete(s.contextCreatedAt, runID)
-	delete(s.contextUpdatedAt, runID)
+	delete(s.contexts, key)
+	delete(s.data, key)
+	delete(s.locks, key)
+	delete(s.ttl, key)
+	delete(s.contextCreatedAt, key)
+	delete(s.contextUpdatedAt, key)
 
 	return nil
 }
@@ -141,18 +160,19 @@ func (s *InMemoryState) RemoveState(ctx context.Context, runID string) error {
 func (s *InMemoryState) ContainsContext(ctx context.Context, runID string) (bool, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	key := keyOf(ctx, runID)
 
 	// Check TTL
-	if expiry, ok := s.ttl[runID]; ok && time.Now().After(expiry) {
-		delete(s.contexts, runID)
-		delete(s.data, runID)
-		delete(s.ttl, runID)
-		delete(s.contextCreatedAt, runID)
-		delete(s.contextUpdatedAt, runID)
+	if expiry, ok := s.ttl[key]; ok && time.Now().After(expiry) {
+		delete(s.contexts, key)
+		delete(s.data, key)
+		delete(s.ttl, key)
+		delete(s.contextCreatedAt, key)
+		delete(s.contextUpdatedAt, key)
 		return false, nil
 	}
 
-	_, exists := s.contexts[runID]
+	_, exists := s.contexts[key]
 	return exists, nil
 }
 
@@ -160,18 +180,19 @@ func (s *InMemoryState) ContainsContext(ctx context.Context, runID string) (bool
 func (s *InMemoryState) ContainsData(ctx context.Context, runID string) (bool, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	key := keyOf(ctx, runID)
 
 	// Check TTL
-	if expiry, ok := s.ttl[runID]; ok && time.Now().After(expiry) {
-		delete(s.contexts, runID)
-		delete(s.data, runID)
-		delete(s.ttl, runID)
-		delete(s.contextCreatedAt, runID)
-		delete(s.contextUpdatedAt, runID)
+	if expiry, ok := s.ttl[key]; ok && time.Now().After(expiry) {
+		delete(s.contexts, key)
+		delete(s.data, key)
+		delete(s.ttl, key)
+		delete(s.contextCreatedAt, key)
+		delete(s.contextUpdatedAt, key)
 		return false, nil
 	}
 
-	_, exists := s.data[runID]
+	_, exists := s.data[key]
 	return exists, nil
 }
 
@@ -179,13 +200,14 @@ func (s *InMemoryState) ContainsData(ctx context.Context, runID string) (bool, e
 func (s *InMemoryState) AcquireLock(ctx context.Context, runID string, timeout time.Duration) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	key := keyOf(ctx, runID)
 
 	// Check if already locked
-	if locked, ok := s.locks[runID]; ok && locked {
+	if locked, ok := s.locks[key]; ok && locked {
 		return false, nil
 	}
 
-	s.locks[runID] = true
+	s.locks[key] = true
 	return true, nil
 }
 
@@ -193,8 +215,9 @@ func (s *InMemoryState) AcquireLock(ctx context.Context, runID string, timeout t
 func (s *InMemoryState) ReleaseLock(ctx context.Context, runID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	key := keyOf(ctx, runID)
 
-	delete(s.locks, runID)
+	delete(s.locks, key)
 	return nil
 }
 
@@ -202,17 +225,18 @@ func (s *InMemoryState) ReleaseLock(ctx context.Context, runID string) error {
 func (s *InMemoryState) SetTTL(ctx context.Context, runID string, ttl time.Duration) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	key := keyOf(ctx, runID)
 
 	if ttl <= 0 {
-		delete(s.ttl, runID)
+		delete(s.ttl, key)
 	} else {
-		s.ttl[runID] = time.Now().Add(ttl)
+		s.ttl[key] = time.Now().Add(ttl)
 	}
 
 	return nil
 }
 
-// GetAllContexts gets all workflow contexts stored in the state management
+// GetAllContexts gets the workflow contexts of the tenant of ctx
 func (s *InMemoryState) GetAllContexts(ctx context.Context) ([]model.WorkflowContext, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -220,9 +244,13 @@ func (s *InMemoryState) GetAllContexts(ctx context.Context) ([]model.WorkflowCon
 	now := time.Now()
 	contexts := make([]model.WorkflowContext, 0, len(s.contexts))
 
-	for runID, context := range s.contexts {
+	tenantID := tenant.FromContext(ctx)
+	for key, context := range s.contexts {
+		if key.tenant != tenantID {
+			continue // another tenant's run
+		}
 		// Check TTL
-		if expiry, ok := s.ttl[runID]; ok && now.After(expiry) {
+		if expiry, ok := s.ttl[key]; ok && now.After(expiry) {
 			continue // Skip expired contexts
 		}
 		contexts = append(contexts, context)
@@ -231,6 +259,67 @@ func (s *InMemoryState) GetAllContexts(ctx context.Context) ([]model.WorkflowCon
 	return contexts, nil
 }
 
+// SaveRecord saves a record under key in a namespace kept apart from run state
+func (s *InMemoryState) SaveRecord(ctx context.Context, namespace, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, ok := s.records[namespace]
+	if !ok {
+		records = make(map[string][]byte)
+		s.records[namespace] = records
+	}
+	records[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// GetRecord retrieves a record, or ErrStateNotFound if there is none
+func (s *InMemoryState) GetRecord(ctx context.Context, namespace, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.records[namespace][key]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// DeleteRecord removes a record
+func (s *InMemoryState) DeleteRecord(ctx context.Context, namespace, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records[namespace], key)
+	return nil
+}
+
+// GetRecords gets the records of a namespace stored under keys, by key
+func (s *InMemoryState) GetRecords(ctx context.Context, namespace string, keys []string) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if value, ok := s.records[namespace][key]; ok {
+			records[key] = append([]byte(nil), value...)
+		}
+	}
+	return records, nil
+}
+
+// ListRecords gets the records of a namespace by key
+func (s *InMemoryState) ListRecords(ctx context.Context, namespace string) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make(map[string][]byte, len(s.records[namespace]))
+	for key, value := range s.records[namespace] {
+		records[key] = append([]byte(nil), value...)
+	}
+	return records, nil
+}
+
 // Close closes the state management connection
 func (s *InMemoryState) Close() error {
 	// Nothing to close for in-memory state
@@ -241,29 +330,30 @@ func (s *InMemoryState) Close() error {
 func (s *InMemoryState) GetExecutionInfo(ctx context.Context, runID string) (*ExecutionInfo, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	key := keyOf(ctx, runID)
 
 	// Check TTL
-	if expiry, ok := s.ttl[runID]; ok && time.Now().After(expiry) {
-		delete(s.contexts, runID)
-		delete(s.data, runID)
-		delete(s.ttl, runID)
-		delete(s.contextCreatedAt, runID)
-		delete(s.contextUpdatedAt, runID)
+	if expiry, ok := s.ttl[key]; ok && time.Now().After(expiry) {
+		delete(s.contexts, key)
+		delete(s.data, key)
+		delete(s.ttl, key)
+		delete(s.contextCreatedAt, key)
+		delete(s.contextUpdatedAt, key)
 		return nil, ErrStateNotFound
 	}
 
-	context, exists := s.contexts[runID]
+	context, exists := s.contexts[key]
 	if !exists {
 		return nil, ErrStateNotFound
 	}
 
 	createdAt := time.Time{}
-	if t, ok := s.contextCreatedAt[runID]; ok {
+	if t, ok := s.contextCreatedAt[key]; ok {
 		createdAt = t
 	}
 
 	updatedAt := time.Time{}
-	if t, ok := s.contextUpdatedAt[runID]; ok {
+	if t, ok := s.contextUpdatedAt[key]; ok {
 		updatedAt = t
 	}
 
@@ -297,7 +387,7 @@ func (s *InMemoryState) GetExecutionInfo(ctx context.Context, runID string) (*Ex
 	}, nil
 }
 
-// GetAllExecutionInfos gets execution information for all runs
+// GetAllExecutionInfos gets execution information for all runs of the tenant of ctx
 func (s *InMemoryState) GetAllExecutionInfos(ctx context.Context) ([]*ExecutionInfo, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -305,19 +395,23 @@ func (s *InMemoryState) GetAllExecutionInfos(ctx context.Context) ([]*ExecutionI
 	now := time.Now()
 	infos := make([]*ExecutionInfo, 0, len(s.contexts))
 
-	for runID, context := range s.contexts {
+	tenantID := tenant.FromContext(ctx)
+	for key, context := range s.contexts {
+		if key.tenant != tenantID {
+			continue // another tenant's run
+		}
 		// Check TTL
-		if expiry, ok := s.ttl[runID]; ok && now.After(expiry) {
+		if expiry, ok := s.ttl[key]; ok && now.After(expiry) {
 			continue // Skip expired contexts
 		}
 
 		createdAt := time.Time{}
-		if t, ok := s.contextCreatedAt[runID]; ok {
+		if t, ok := s.contextCreatedAt[key]; ok {
 			createdAt = t
 		}
 
 		updatedAt := time.Time{}
-		if t, ok := s.contextUpdatedAt[runID]; ok {
+		if t, ok := s.contextUpdatedAt[key]; ok {
 			updatedAt = t
 		}
 
@@ -334,7 +428,7 @@ func (s *InMemoryState) GetAllExecutionInfos(ctx context.Context) ([]*ExecutionI
 		}
 
 		infos = append(infos, &ExecutionInfo{
-			RunID:                 runID,
+			RunID:                 key.runID,
 			WorkflowDefinitionID:  context.GetWorkflowDefinitionID(),
 			Status:                statusStr,
 			CurrentStepIndex:      context.GetCurrentStepIndex(),
@@ -369,6 +463,12 @@ func workflowStatusToString(status int) string {
 		return "cancelled"
 	case model.WorkflowStatusPaused:
 		return "paused"
+	case model.WorkflowStatusWaiting:
+		return "waiting"
+	case model.WorkflowStatusSleeping:
+		return "sleeping"
+	case model.WorkflowStatusTimedOut:
+		return "timed_out"
 	default:
 		return "unknown"
 	}
@@ -377,7 +477,8 @@ func workflowStatusToString(status int) string {
 func isWorkflowStatusTerminal(status int) bool {
 	return status == model.WorkflowStatusCompleted ||
 		status == model.WorkflowStatusFailed ||
-		status == model.WorkflowStatusCancelled
+		status == model.WorkflowStatusCancelled ||
+		status == model.WorkflowStatusTimedOut
 }
 
 // Errors
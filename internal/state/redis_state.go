@@ -3,13 +3,21 @@ package state
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"unified-workflow/internal/primitive/model"
+	"unified-workflow/internal/tenant"
+
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisState implements StateManagement using Redis
+// defaultLockTimeout bounds a run lock acquired without a timeout, so a crashed holder does not keep it forever
+const defaultLockTimeout = 30 * time.Second
+
+// RedisState implements StateManagement using Redis, so every process of a deployment sees the same runs
+// Runs that have not finished are kept until they do; the state of a finished run expires after the TTL
 type RedisState struct {
 	client *redis.Client
 	prefix string
@@ -38,6 +46,7 @@ func NewRedisState(config RedisConfig) (*RedisState, error) {
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
@@ -47,16 +56,21 @@ func NewRedisState(config RedisConfig) (*RedisState, error) {
 		ttl = 24 * time.Hour // Default 24 hours
 	}
 
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = "uwf"
+	}
+
 	return &RedisState{
 		client: client,
-		prefix: config.Prefix,
+		prefix: prefix,
 		ttl:    ttl,
 	}, nil
 }
 
 // StoreResult stores an execution result in Redis
 func (s *RedisState) StoreResult(ctx context.Context, runID string, result interface{}) error {
-	key := s.getResultKey(runID)
+	key := s.getResultKey(ctx, runID)
 
 	data, err := json.Marshal(result)
 	if err != nil {
@@ -72,7 +86,7 @@ func (s *RedisState) StoreResult(ctx context.Context, runID string, result inter
 
 // GetResult retrieves an execution result from Redis
 func (s *RedisState) GetResult(ctx context.Context, runID string, result interface{}) (bool, error) {
-	key := s.getResultKey(runID)
+	key := s.getResultKey(ctx, runID)
 
 	data, err := s.client.Get(ctx, key).Bytes()
 	if err != nil {
@@ -91,7 +105,7 @@ func (s *RedisState) GetResult(ctx context.Context, runID string, result interfa
 
 // DeleteResult deletes an execution result from Redis
 func (s *RedisState) DeleteResult(ctx context.Context, runID string) error {
-	key := s.getResultKey(runID)
+	key := s.getResultKey(ctx, runID)
 
 	if err := s.client.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete result from Redis: %w", err)
@@ -102,7 +116,7 @@ func (s *RedisState) DeleteResult(ctx context.Context, runID string) error {
 
 // StoreExecutionStatus stores execution status in Redis
 func (s *RedisState) StoreExecutionStatus(ctx context.Context, runID string, status interface{}) error {
-	key := s.getStatusKey(runID)
+	key := s.getStatusKey(ctx, runID)
 
 	data, err := json.Marshal(status)
 	if err != nil {
@@ -118,7 +132,7 @@ func (s *RedisState) StoreExecutionStatus(ctx context.Context, runID string, sta
 
 // GetExecutionStatus retrieves execution status from Redis
 func (s *RedisState) GetExecutionStatus(ctx context.Context, runID string, status interface{}) (bool, error) {
-	key := s.getStatusKey(runID)
+	key := s.getStatusKey(ctx, runID)
 
 	data, err := s.client.Get(ctx, key).Bytes()
 	if err != nil {
@@ -137,7 +151,7 @@ func (s *RedisState) GetExecutionStatus(ctx context.Context, runID string, statu
 
 // StoreExecutionData stores execution data in Redis
 func (s *RedisState) StoreExecutionData(ctx context.Context, runID string, data interface{}) error {
-	key := s.getDataKey(runID)
+	key := s.getDataKey(ctx, runID)
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -153,7 +167,7 @@ func (s *RedisState) StoreExecutionData(ctx context.Context, runID string, data
 
 // GetExecutionData retrieves execution data from Redis
 func (s *RedisState) GetExecutionData(ctx context.Context, runID string, data interface{}) (bool, error) {
-	key := s.getDataKey(runID)
+	key := s.getDataKey(ctx, runID)
 
 	jsonData, err := s.client.Get(ctx, key).Bytes()
 	if err != nil {
@@ -170,36 +184,289 @@ func (s *RedisState) GetExecutionData(ctx context.Context, runID string, data in
 	return true, nil
 }
 
-// SetTTL sets TTL for a key
-func (s *RedisState) SetTTL(ctx context.Context, runID string, ttl time.Duration) error {
-	key := s.getResultKey(runID)
+// SaveContext saves the workflow context to the store
+func (s *RedisState) SaveContext(ctx context.Context, workflowContext model.WorkflowContext) error {
+	runID := workflowContext.GetRunID()
+	data, err := json.Marshal(workflowContext)
+	if err != nil {
+		return fmt.Errorf("failed to marshal context: %w", err)
+	}
+
+	// A parked run may sleep or wait for longer than the TTL, so only finished runs expire
+	var ttl time.Duration
+	if isWorkflowStatusTerminal(workflowContext.GetStatus()) {
+		ttl = s.ttl
+	}
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.getContextKey(ctx, runID), data, ttl)
+	pipe.SAdd(ctx, s.getRunsKey(ctx), runID)
+	if ttl > 0 {
+		pipe.Expire(ctx, s.getDataKey(ctx, runID), ttl)
+	} else {
+		pipe.Persist(ctx, s.getDataKey(ctx, runID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store context in Redis: %w", err)
+	}
+	return nil
+}
+
+// GetContext retrieves the workflow context for the given run ID
+func (s *RedisState) GetContext(ctx context.Context, runID string) (model.WorkflowContext, error) {
+	data, err := s.client.Get(ctx, s.getContextKey(ctx, runID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrStateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get context from Redis: %w", err)
+	}
+
+	workflowContext := &model.WorkflowContextImpl{}
+	if err := json.Unmarshal(data, workflowContext); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal context: %w", err)
+	}
+	return workflowContext, nil
+}
+
+// SaveData saves the workflow data to the store, keeping the expiry set by SaveContext
+func (s *RedisState) SaveData(ctx context.Context, runID string, workflowData model.WorkflowData) error {
+	data, err := json.Marshal(workflowData.ToMap())
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	if err := s.client.Set(ctx, s.getDataKey(ctx, runID), data, redis.KeepTTL).Err(); err != nil {
+		return fmt.Errorf("failed to store data in Redis: %w", err)
+	}
+	return nil
+}
+
+// GetData retrieves the workflow data for the given run ID
+func (s *RedisState) GetData(ctx context.Context, runID string) (model.WorkflowData, error) {
+	data, err := s.client.Get(ctx, s.getDataKey(ctx, runID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrStateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data from Redis: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+	return workflowDataFromMap(values), nil
+}
+
+// RemoveState removes all state (both context and data) for the given run ID
+func (s *RedisState) RemoveState(ctx context.Context, runID string) error {
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, s.getContextKey(ctx, runID), s.getDataKey(ctx, runID), s.getLockKey(ctx, runID))
+	pipe.SRem(ctx, s.getRunsKey(ctx), runID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove state from Redis: %w", err)
+	}
+	return nil
+}
+
+// ContainsContext checks if a workflow context exists for the given run ID
+func (s *RedisState) ContainsContext(ctx context.Context, runID string) (bool, error) {
+	exists, err := s.client.Exists(ctx, s.getContextKey(ctx, runID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check existence: %w", err)
+	}
+	return exists > 0, nil
+}
+
+// ContainsData checks if workflow data exists for the given run ID
+func (s *RedisState) ContainsData(ctx context.Context, runID string) (bool, error) {
+	exists, err := s.client.Exists(ctx, s.getDataKey(ctx, runID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check existence: %w", err)
+	}
+	return exists > 0, nil
+}
+
+// AcquireLock acquires a lock for a workflow run, shared by every process, held for at most timeout
+func (s *RedisState) AcquireLock(ctx context.Context, runID string, timeout time.Duration) (bool, error) {
+	if timeout <= 0 {
+		timeout = defaultLockTimeout
+	}
+	acquired, err := s.client.SetNX(ctx, s.getLockKey(ctx, runID), 1, timeout).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	return acquired, nil
+}
 
-	if err := s.client.Expire(ctx, key, ttl).Err(); err != nil {
+// ReleaseLock releases a lock for a workflow run
+func (s *RedisState) ReleaseLock(ctx context.Context, runID string) error {
+	if err := s.client.Del(ctx, s.getLockKey(ctx, runID)).Err(); err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// SetTTL sets time-to-live for workflow state; a TTL of zero keeps it
+func (s *RedisState) SetTTL(ctx context.Context, runID string, ttl time.Duration) error {
+	pipe := s.client.TxPipeline()
+	for _, key := range []string{s.getContextKey(ctx, runID), s.getDataKey(ctx, runID), s.getResultKey(ctx, runID)} {
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		} else {
+			pipe.Persist(ctx, key)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("failed to set TTL: %w", err)
 	}
+	return nil
+}
+
+// GetAllContexts gets the workflow contexts of the tenant of ctx, forgetting the runs that expired
+func (s *RedisState) GetAllContexts(ctx context.Context) ([]model.WorkflowContext, error) {
+	runIDs, err := s.client.SMembers(ctx, s.getRunsKey(ctx)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	if len(runIDs) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(runIDs))
+	for i, runID := range runIDs {
+		keys[i] = s.getContextKey(ctx, runID)
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contexts from Redis: %w", err)
+	}
+
+	contexts := make([]model.WorkflowContext, 0, len(values))
+	var expired []interface{}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			expired = append(expired, runIDs[i])
+			continue
+		}
+		workflowContext := &model.WorkflowContextImpl{}
+		if err := json.Unmarshal([]byte(data), workflowContext); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal context of %s: %w", runIDs[i], err)
+		}
+		contexts = append(contexts, workflowContext)
+	}
+	if len(expired) > 0 {
+		s.client.SRem(ctx, s.getRunsKey(ctx), expired...)
+	}
+	return contexts, nil
+}
+
+// SaveRecord saves a record under key in a namespace kept apart from run state
+func (s *RedisState) SaveRecord(ctx context.Context, namespace, key string, value []byte) error {
+	if err := s.client.HSet(ctx, s.getRecordsKey(namespace), key, value).Err(); err != nil {
+		return fmt.Errorf("failed to store %s record in Redis: %w", namespace, err)
+	}
+	return nil
+}
+
+// GetRecord retrieves a record, or ErrStateNotFound if there is none
+func (s *RedisState) GetRecord(ctx context.Context, namespace, key string) ([]byte, error) {
+	value, err := s.client.HGet(ctx, s.getRecordsKey(namespace), key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrStateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s record from Redis: %w", namespace, err)
+	}
+	return value, nil
+}
 
+// DeleteRecord removes a record
+func (s *RedisState) DeleteRecord(ctx context.Context, namespace, key string) error {
+	if err := s.client.HDel(ctx, s.getRecordsKey(namespace), key).Err(); err != nil {
+		return fmt.Errorf("failed to delete %s record from Redis: %w", namespace, err)
+	}
 	return nil
 }
 
+// GetRecords gets the records of a namespace stored under keys, by key, in one round trip
+func (s *RedisState) GetRecords(ctx context.Context, namespace string, keys []string) (map[string][]byte, error) {
+	records := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return records, nil
+	}
+	values, err := s.client.HMGet(ctx, s.getRecordsKey(namespace), keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s records from Redis: %w", namespace, err)
+	}
+	for i, value := range values {
+		if value, ok := value.(string); ok {
+			records[keys[i]] = []byte(value)
+		}
+	}
+	return records, nil
+}
+
+// ListRecords gets the records of a namespace by key
+func (s *RedisState) ListRecords(ctx context.Context, namespace string) (map[string][]byte, error) {
+	values, err := s.client.HGetAll(ctx, s.getRecordsKey(namespace)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s records from Redis: %w", namespace, err)
+	}
+	records := make(map[string][]byte, len(values))
+	for key, value := range values {
+		records[key] = []byte(value)
+	}
+	return records, nil
+}
+
 // Close closes the Redis connection
 func (s *RedisState) Close() error {
 	return s.client.Close()
 }
 
 // Helper methods for key generation
-func (s *RedisState) getResultKey(runID string) string {
-	return fmt.Sprintf("%s:result:%s", s.prefix, runID)
+func (s *RedisState) getResultKey(ctx context.Context, runID string) string {
+	return fmt.Sprintf("%s:result:%s", s.tenantPrefix(ctx), runID)
+}
+
+func (s *RedisState) getStatusKey(ctx context.Context, runID string) string {
+	return fmt.Sprintf("%s:status:%s", s.tenantPrefix(ctx), runID)
+}
+
+func (s *RedisState) getDataKey(ctx context.Context, runID string) string {
+	return fmt.Sprintf("%s:data:%s", s.tenantPrefix(ctx), runID)
 }
 
-func (s *RedisState) getStatusKey(runID string) string {
-	return fmt.Sprintf("%s:status:%s", s.prefix, runID)
+func (s *RedisState) getContextKey(ctx context.Context, runID string) string {
+	return fmt.Sprintf("%s:context:%s", s.tenantPrefix(ctx), runID)
 }
 
-func (s *RedisState) getDataKey(runID string) string {
-	return fmt.Sprintf("%s:data:%s", s.prefix, runID)
+func (s *RedisState) getLockKey(ctx context.Context, runID string) string {
+	return fmt.Sprintf("%s:lock:%s", s.tenantPrefix(ctx), runID)
+}
+
+// getRunsKey is the set of the run IDs of the tenant of ctx
+func (s *RedisState) getRunsKey(ctx context.Context) string {
+	return fmt.Sprintf("%s:runs", s.tenantPrefix(ctx))
+}
+
+// getRecordsKey is the hash holding the records of a namespace, outside every tenant's run keys
+func (s *RedisState) getRecordsKey(namespace string) string {
+	return fmt.Sprintf("%s:records:%s", s.prefix, namespace)
+}
+
+// tenantPrefix namespaces the keys of the tenant of ctx; the default tenant keeps the bare prefix
+func (s *RedisState) tenantPrefix(ctx context.Context) string {
+	tenantID := tenant.FromContext(ctx)
+	if tenant.IsDefault(tenantID) {
+		return s.prefix
+	}
+	return s.prefix + ":tenant:" + tenantID
 }
 
-// Implement StateManagement interface methods
+// Store, Retrieve, Delete and Exists are key-value helpers over the data and result keys
 func (s *RedisState) Store(ctx context.Context, key string, value interface{}) error {
 	return s.StoreExecutionData(ctx, key, value)
 }
@@ -213,7 +480,7 @@ func (s *RedisState) Delete(ctx context.Context, key string) error {
 }
 
 func (s *RedisState) Exists(ctx context.Context, key string) (bool, error) {
-	resultKey := s.getResultKey(key)
+	resultKey := s.getResultKey(ctx, key)
 	exists, err := s.client.Exists(ctx, resultKey).Result()
 	if err != nil {
 		return false, fmt.Errorf("failed to check existence: %w", err)
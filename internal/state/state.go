@@ -9,6 +9,7 @@ import (
 
 // StateManagement is the interface for workflow state management implementations
 // Provides abstraction for different state storage backends (in-memory, database, distributed cache)
+// Runs are namespaced by the tenant of the context (tenant.FromContext); other tenants' runs are not found
 type StateManagement interface {
 	// SaveContext saves the workflow context to the store
 	SaveContext(ctx context.Context, workflowContext model.WorkflowContext) error
@@ -40,9 +41,25 @@ type StateManagement interface {
 	// SetTTL sets time-to-live for workflow state
 	SetTTL(ctx context.Context, runID string, ttl time.Duration) error
 
-	// GetAllContexts gets all workflow contexts stored in the state management
+	// GetAllContexts gets the workflow contexts of the tenant of ctx
 	GetAllContexts(ctx context.Context) ([]model.WorkflowContext, error)
 
+	// SaveRecord saves a record under key in a namespace kept apart from run state, such as the armed timers
+	// Records are not namespaced by tenant; keys carry the tenant where it matters
+	SaveRecord(ctx context.Context, namespace, key string, value []byte) error
+
+	// GetRecord retrieves a record, or ErrStateNotFound if there is none
+	GetRecord(ctx context.Context, namespace, key string) ([]byte, error)
+
+	// DeleteRecord removes a record; removing a missing record is not an error
+	DeleteRecord(ctx context.Context, namespace, key string) error
+
+	// GetRecords gets the records of a namespace stored under keys, by key; missing records are left out
+	GetRecords(ctx context.Context, namespace string, keys []string) (map[string][]byte, error)
+
+	// ListRecords gets the records of a namespace by key
+	ListRecords(ctx context.Context, namespace string) (map[string][]byte, error)
+
 	// Close closes the state management connection
 	Close() error
 }
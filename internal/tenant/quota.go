@@ -0,0 +1,232 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/config"
+	"unified-workflow/internal/metrics"
+)
+
+// ErrQuotaExceeded is returned when admitting a run would exceed the quota of its tenant
+var ErrQuotaExceeded = errors.New("tenant quota exceeded")
+
+// Limits reported by a QuotaError
+const (
+	LimitRate        = "rate"
+	LimitConcurrency = "concurrency"
+)
+
+// QuotaError describes the limit that refused a run; it matches ErrQuotaExceeded
+type QuotaError struct {
+	Tenant string
+	Limit  string
+	// RetryAfter estimates when the rate limit admits the next run; zero for the concurrency limit
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	if e.Limit == LimitRate {
+		return fmt.Sprintf("%s: tenant %s exceeds its request rate", ErrQuotaExceeded, e.Tenant)
+	}
+	return fmt.Sprintf("%s: tenant %s exceeds its concurrent runs", ErrQuotaExceeded, e.Tenant)
+}
+
+// Is makes errors.Is(err, ErrQuotaExceeded) hold
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Quotas admits workflow runs against the request rate and concurrent run limits of their tenant
+// A run holds its concurrency slot until Release, or until the slot TTL reclaims it when its
+// completion is never reported. Limits are enforced per process
+type Quotas struct {
+	mu    sync.Mutex
+	cfg   config.TenancyConfig
+	usage map[string]*usage
+	slots map[string]slot
+	now   func() time.Time
+}
+
+// usage is the rate bucket and the running runs of a tenant
+type usage struct {
+	tokens   float64
+	refilled time.Time
+	active   int
+}
+
+// slot is the concurrency slot held by a run
+type slot struct {
+	tenant   string
+	admitted time.Time
+}
+
+// NewQuotas creates the quotas of the tenancy configuration
+func NewQuotas(cfg config.TenancyConfig) (*Quotas, error) {
+	q := &Quotas{
+		usage: make(map[string]*usage),
+		slots: make(map[string]slot),
+		now:   time.Now,
+	}
+	if err := q.Apply(cfg); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Apply replaces the limits; runs already admitted keep their slots
+func (q *Quotas) Apply(cfg config.TenancyConfig) error {
+	if err := validateQuotas(cfg); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cfg = cfg
+	return nil
+}
+
+// Admit reserves a concurrency slot for runID and takes a token from the rate bucket of its tenant
+// It returns a *QuotaError when a limit is reached. A nil Quotas admits every run
+func (q *Quotas) Admit(tenantID, runID string) error {
+	if q == nil {
+		return nil
+	}
+	tenantID = Normalize(tenantID)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	quota := q.quota(tenantID)
+	u := q.usage[tenantID]
+	if u == nil {
+		u = &usage{tokens: float64(burst(quota)), refilled: now}
+		q.usage[tenantID] = u
+	}
+
+	if quota.MaxConcurrentRuns > 0 && u.active >= quota.MaxConcurrentRuns {
+		q.reclaim(tenantID, now)
+		if u.active >= quota.MaxConcurrentRuns {
+			metrics.TenantQuotaRejections.Inc(tenantID, LimitConcurrency)
+			return &QuotaError{Tenant: tenantID, Limit: LimitConcurrency}
+		}
+	}
+
+	if quota.RequestsPerSecond > 0 {
+		u.tokens = math.Min(float64(burst(quota)), u.tokens+now.Sub(u.refilled).Seconds()*quota.RequestsPerSecond)
+		u.refilled = now
+		if u.tokens < 1 {
+			metrics.TenantQuotaRejections.Inc(tenantID, LimitRate)
+			retryAfter := time.Duration((1 - u.tokens) / quota.RequestsPerSecond * float64(time.Second))
+			return &QuotaError{Tenant: tenantID, Limit: LimitRate, RetryAfter: retryAfter}
+		}
+		u.tokens--
+	}
+
+	u.active++
+	q.slots[runID] = slot{tenant: tenantID, admitted: now}
+	metrics.TenantRunsActive.Inc(tenantID)
+	return nil
+}
+
+// Release frees the concurrency slot of a run; unknown runs are ignored
+func (q *Quotas) Release(runID string) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if s, ok := q.slots[runID]; ok {
+		q.free(runID, s)
+	}
+}
+
+// Active returns the number of admitted runs of a tenant that have not been released
+func (q *Quotas) Active(tenantID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if u := q.usage[Normalize(tenantID)]; u != nil {
+		return u.active
+	}
+	return 0
+}
+
+// quota returns the limits of a tenant; callers hold q.mu
+func (q *Quotas) quota(tenantID string) config.TenantQuotaConfig {
+	if quota, ok := q.cfg.Quotas[tenantID]; ok {
+		return quota
+	}
+	return q.cfg.DefaultQuota
+}
+
+// reclaim frees the slots of a tenant held longer than the slot TTL; callers hold q.mu
+func (q *Quotas) reclaim(tenantID string, now time.Time) {
+	if q.cfg.SlotTTL <= 0 {
+		return
+	}
+	for runID, s := range q.slots {
+		if s.tenant == tenantID && now.Sub(s.admitted) > q.cfg.SlotTTL {
+			q.free(runID, s)
+		}
+	}
+}
+
+// free releases a slot; callers hold q.mu
+func (q *Quotas) free(runID string, s slot) {
+	delete(q.slots, runID)
+	if u := q.usage[s.tenant]; u != nil && u.active > 0 {
+		u.active--
+	}
+	metrics.TenantRunsActive.Dec(s.tenant)
+}
+
+// burst returns the bucket size of a rate limit, at least one request
+func burst(quota config.TenantQuotaConfig) int {
+	if quota.Burst > 0 {
+		return quota.Burst
+	}
+	return int(math.Max(1, math.Ceil(quota.RequestsPerSecond)))
+}
+
+// validateQuotas checks the tenant IDs the quotas are keyed by
+func validateQuotas(cfg config.TenancyConfig) error {
+	for id := range cfg.Quotas {
+		if err := Validate(id); err != nil {
+			return fmt.Errorf("tenancy.quotas: %w", err)
+		}
+	}
+	return nil
+}
+
+// ConfigOwner applies the tenancy section to running quotas
+type ConfigOwner struct {
+	quotas *Quotas
+}
+
+// NewConfigOwner creates the section owner of quotas
+func NewConfigOwner(quotas *Quotas) *ConfigOwner {
+	return &ConfigOwner{quotas: quotas}
+}
+
+// Name implements config.SectionOwner
+func (o *ConfigOwner) Name() string {
+	return "tenancy"
+}
+
+// Sections implements config.SectionOwner
+func (o *ConfigOwner) Sections() []string {
+	return []string{"tenancy"}
+}
+
+// ValidateConfig implements config.SectionOwner
+func (o *ConfigOwner) ValidateConfig(cfg *config.Config) error {
+	return validateQuotas(cfg.Tenancy)
+}
+
+// ApplyConfig implements config.SectionOwner
+func (o *ConfigOwner) ApplyConfig(oldConfig, newConfig *config.Config) error {
+	return o.quotas.Apply(newConfig.Tenancy)
+}
@@ -0,0 +1,53 @@
+// Package tenant carries the tenant a request acts for and enforces per-tenant quotas
+// Workflows, runs, state keys and queue subjects are namespaced by tenant; the default tenant
+// keeps the names used before tenancy was introduced, so single-tenant deployments are unchanged
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Default is the tenant of requests that do not name one
+const Default = "default"
+
+// Header names the tenant of an HTTP request; gRPC calls use the x-tenant-id metadata key
+const Header = "X-Tenant-ID"
+
+// idPattern restricts tenant IDs to characters safe in state keys, URLs and NATS subject tokens
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Validate checks that id can name a tenant
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("invalid tenant %q: use up to 63 lowercase letters, digits, '-' and '_'", id)
+	}
+	return nil
+}
+
+// Normalize returns the tenant named by id, the default tenant when id is empty
+func Normalize(id string) string {
+	if id == "" {
+		return Default
+	}
+	return id
+}
+
+// IsDefault reports whether id names the default tenant
+func IsDefault(id string) bool {
+	return Normalize(id) == Default
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context acting for the tenant
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, Normalize(id))
+}
+
+// FromContext returns the tenant ctx acts for, the default tenant when none is set
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return Normalize(id)
+}
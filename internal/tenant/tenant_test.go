@@ -0,0 +1,117 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/config"
+)
+
+func TestValidate(t *testing.T) {
+	for _, id := range []string{"acme", "acme-eu_1", "0"} {
+		if err := Validate(id); err != nil {
+			t.Errorf("Validate(%q) error = %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "Acme", "-acme", "acme.eu", "acme eu", "acme>"} {
+		if err := Validate(id); err == nil {
+			t.Errorf("Validate(%q) error = nil, want error", id)
+		}
+	}
+}
+
+func TestContextDefaultsToDefaultTenant(t *testing.T) {
+	if got := FromContext(context.Background()); got != Default {
+		t.Errorf("FromContext() = %q, want %q", got, Default)
+	}
+	if got := FromContext(WithTenant(context.Background(), "acme")); got != "acme" {
+		t.Errorf("FromContext() = %q, want acme", got)
+	}
+}
+
+func newTestQuotas(t *testing.T, cfg config.TenancyConfig) (*Quotas, *time.Time) {
+	t.Helper()
+	q, err := NewQuotas(cfg)
+	if err != nil {
+		t.Fatalf("NewQuotas() error = %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	q.now = func() time.Time { return now }
+	return q, &now
+}
+
+func TestQuotasLimitConcurrentRuns(t *testing.T) {
+	q, _ := newTestQuotas(t, config.TenancyConfig{
+		Quotas: map[string]config.TenantQuotaConfig{"acme": {MaxConcurrentRuns: 2}},
+	})
+
+	for _, runID := range []string{"run-1", "run-2"} {
+		if err := q.Admit("acme", runID); err != nil {
+			t.Fatalf("Admit(%s) error = %v", runID, err)
+		}
+	}
+	err := q.Admit("acme", "run-3")
+	var quotaErr *QuotaError
+	if !errors.Is(err, ErrQuotaExceeded) || !errors.As(err, &quotaErr) || quotaErr.Limit != LimitConcurrency {
+		t.Fatalf("Admit(run-3) error = %v, want concurrency QuotaError", err)
+	}
+	if err := q.Admit("globex", "run-4"); err != nil {
+		t.Errorf("other tenant Admit() error = %v", err)
+	}
+
+	q.Release("run-1")
+	if err := q.Admit("acme", "run-3"); err != nil {
+		t.Errorf("Admit() after Release error = %v", err)
+	}
+	if got := q.Active("acme"); got != 2 {
+		t.Errorf("Active(acme) = %d, want 2", got)
+	}
+}
+
+func TestQuotasReclaimExpiredSlots(t *testing.T) {
+	q, now := newTestQuotas(t, config.TenancyConfig{
+		DefaultQuota: config.TenantQuotaConfig{MaxConcurrentRuns: 1},
+		SlotTTL:      time.Minute,
+	})
+
+	if err := q.Admit("", "lost-run"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Admit("", "run-2"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Admit() error = %v, want ErrQuotaExceeded", err)
+	}
+	*now = now.Add(2 * time.Minute)
+	if err := q.Admit("", "run-2"); err != nil {
+		t.Errorf("Admit() after slot TTL error = %v", err)
+	}
+}
+
+func TestQuotasLimitRequestRate(t *testing.T) {
+	q, now := newTestQuotas(t, config.TenancyConfig{
+		DefaultQuota: config.TenantQuotaConfig{RequestsPerSecond: 2, Burst: 2},
+	})
+
+	for i, runID := range []string{"run-1", "run-2"} {
+		if err := q.Admit("acme", runID); err != nil {
+			t.Fatalf("Admit(%d) error = %v", i, err)
+		}
+	}
+	err := q.Admit("acme", "run-3")
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || quotaErr.Limit != LimitRate || quotaErr.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("Admit(run-3) error = %v, want rate QuotaError retrying after 500ms", err)
+	}
+	*now = now.Add(500 * time.Millisecond)
+	if err := q.Admit("acme", "run-3"); err != nil {
+		t.Errorf("Admit() after refill error = %v", err)
+	}
+}
+
+func TestNewQuotasRejectsInvalidTenant(t *testing.T) {
+	_, err := NewQuotas(config.TenancyConfig{Quotas: map[string]config.TenantQuotaConfig{"Acme Corp": {}}})
+	if err == nil {
+		t.Error("NewQuotas() error = nil, want invalid tenant error")
+	}
+}
@@ -0,0 +1,290 @@
+package timer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"unified-workflow/internal/metrics"
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/state"
+	"unified-workflow/internal/tenant"
+)
+
+// recordNamespace is the state store namespace holding the timers not yet fired, one record per run
+const recordNamespace = "timers"
+
+// reloadInterval is how often the timers are read again from the state store, picking up those armed by other processes
+const reloadInterval = 30 * time.Second
+
+// retryDelay is how long a timer whose resume request could not be submitted waits before it fires again
+const retryDelay = 5 * time.Second
+
+// refireDelay is how long a fired timer waits for its run to resume before it fires again, in case the resume
+// request was lost
+const refireDelay = time.Minute
+
+// maxDeliveryDelay is the longest timer published to NATS right away; the stream keeps requests for a day,
+// so longer timers wait in the state store until they are due
+const maxDeliveryDelay = 12 * time.Hour
+
+// Timer resumes a sleeping run when it fires
+type Timer struct {
+	RunID      string    `json:"run_id"`
+	WorkflowID string    `json:"workflow_id"`
+	Tenant     string    `json:"tenant"`
+	WakeAt     time.Time `json:"wake_at"`
+}
+
+// Service fires durable timers by submitting requests to resume their runs
+// Timers are kept in the state store until their run resumes, so they outlive a restart of the process; the loop
+// of Start fires them as they come due and again should the run not resume. On NATS the resume request is also
+// published right away and delivered by JetStream once it is due
+type Service struct {
+	stateManagement state.StateManagement
+	queue           queue.Queue
+
+	mu      sync.Mutex
+	timers  map[string]Timer     // by tenant and run ID
+	refire  map[string]time.Time // when a fired timer fires again, by tenant and run ID
+	changed chan struct{}
+}
+
+// NewService creates a timer service submitting resume requests to q
+func NewService(stateManagement state.StateManagement, q queue.Queue) *Service {
+	return &Service{
+		stateManagement: stateManagement,
+		queue:           q,
+		timers:          make(map[string]Timer),
+		refire:          make(map[string]time.Time),
+		changed:         make(chan struct{}, 1),
+	}
+}
+
+// Schedule arms a timer for a run; scheduling a run again replaces its timer
+func (s *Service) Schedule(ctx context.Context, t Timer) error {
+	if err := s.save(ctx, t); err != nil {
+		return err
+	}
+	k := key(t.Tenant, t.RunID)
+	var refire time.Time
+	if _, ok := s.queue.(*queue.EnhancedNATSQueue); ok && time.Until(t.WakeAt) <= maxDeliveryDelay {
+		if err := s.submit(t); err != nil {
+			if removeErr := s.remove(ctx, k); removeErr != nil {
+				slog.Warn("Failed to remove timer", "run_id", t.RunID, "tenant", t.Tenant, "error", removeErr)
+			}
+			return err
+		}
+		refire = t.WakeAt.Add(refireDelay)
+	}
+
+	s.mu.Lock()
+	s.timers[k] = t
+	if refire.IsZero() {
+		delete(s.refire, k)
+	} else {
+		s.refire[k] = refire
+	}
+	s.mu.Unlock()
+	metrics.Timers.Inc("scheduled")
+	s.notify()
+	return nil
+}
+
+// Remove drops the timer of a run once the run resumed; a timer left in place fires again
+func (s *Service) Remove(ctx context.Context, tenantID, runID string) error {
+	k := key(tenantID, runID)
+	s.mu.Lock()
+	delete(s.timers, k)
+	delete(s.refire, k)
+	s.mu.Unlock()
+	if err := s.remove(ctx, k); err != nil {
+		return fmt.Errorf("failed to remove timer: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the number of timers the service holds that have not fired
+func (s *Service) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.timers)
+}
+
+// Start loads the timers kept in the state store and fires them as they come due until ctx is cancelled
+// The returned function stops the service
+func (s *Service) Start(ctx context.Context) (func(), error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.run(loopCtx)
+	}()
+	slog.Info("Started timer service", "pending", s.Pending())
+
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
+
+// run fires the due timers, then waits for the next one to come due, for a timer to be scheduled or for the
+// timers to be reloaded
+func (s *Service) run(ctx context.Context) {
+	reload := time.NewTicker(reloadInterval)
+	defer reload.Stop()
+	for {
+		next := s.fireDue(ctx)
+
+		var wake <-chan time.Time
+		var timer *time.Timer
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			wake = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.changed:
+		case <-wake:
+		case <-reload.C:
+			if err := s.load(ctx); err != nil {
+				slog.Warn("Failed to reload timers", "error", err)
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// fireDue submits the resume requests of the due timers and returns when the next timer comes due
+// A fired timer stays until its run resumes and removes it, firing again after refireDelay meanwhile
+func (s *Service) fireDue(ctx context.Context) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var next time.Time
+	for k, t := range s.timers {
+		due := t.WakeAt
+		if refire, ok := s.refire[k]; ok {
+			due = refire
+		}
+		if due.After(now) {
+			if next.IsZero() || due.Before(next) {
+				next = due
+			}
+			continue
+		}
+		due = now.Add(refireDelay)
+		if err := s.submit(t); err != nil {
+			slog.Warn("Failed to resume run, retrying", "run_id", t.RunID, "tenant", t.Tenant, "error", err)
+			due = now.Add(retryDelay)
+		} else {
+			metrics.Timers.Inc("fired")
+		}
+		s.refire[k] = due
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return next
+}
+
+// submit enqueues the request resuming the run of a timer, due at its wake-up time
+func (s *Service) submit(t Timer) error {
+	wakeAt := t.WakeAt
+	data, err := queue.MarshalExecutionRequest(queue.ExecutionRequest{
+		RunID:       t.RunID,
+		WorkflowID:  t.WorkflowID,
+		Tenant:      t.Tenant,
+		Resume:      true,
+		NotBefore:   &wakeAt,
+		RequestedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal resume request: %w", err)
+	}
+	if err := s.queue.Enqueue(tenant.WithTenant(context.Background(), t.Tenant), t.RunID, data); err != nil {
+		return fmt.Errorf("failed to enqueue resume request: %w", err)
+	}
+	return nil
+}
+
+// notify wakes the loop up to consider a newly scheduled timer
+func (s *Service) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// save writes a timer to the state store
+func (s *Service) save(ctx context.Context, t Timer) error {
+	if s.stateManagement == nil {
+		return nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to marshal timer: %w", err)
+	}
+	if err := s.stateManagement.SaveRecord(ctx, recordNamespace, key(t.Tenant, t.RunID), data); err != nil {
+		return fmt.Errorf("failed to save timer: %w", err)
+	}
+	return nil
+}
+
+// remove drops a timer from the state store
+func (s *Service) remove(ctx context.Context, k string) error {
+	if s.stateManagement == nil {
+		return nil
+	}
+	return s.stateManagement.DeleteRecord(ctx, recordNamespace, k)
+}
+
+// load reads the timers kept in the state store, which holds every timer not yet fired by any process
+func (s *Service) load(ctx context.Context) error {
+	if s.stateManagement == nil {
+		return nil
+	}
+	records, err := s.stateManagement.ListRecords(ctx, recordNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to load timers: %w", err)
+	}
+
+	timers := make(map[string]Timer, len(records))
+	for k, data := range records {
+		var t Timer
+		if err := json.Unmarshal(data, &t); err != nil || t.RunID == "" {
+			slog.Warn("Dropping unreadable timer", "key", k)
+			continue
+		}
+		timers[k] = t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, t := range s.timers {
+		// A timer removed or replaced meanwhile no longer waits to fire again
+		if loaded, ok := timers[k]; !ok || !loaded.WakeAt.Equal(t.WakeAt) {
+			delete(s.refire, k)
+		}
+	}
+	s.timers = timers
+	return nil
+}
+
+// key identifies the timer of a run of a tenant
+func key(tenantID, runID string) string {
+	return tenantID + "/" + runID
+}
@@ -0,0 +1,173 @@
+package timer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/queue"
+	"unified-workflow/internal/state"
+)
+
+func TestTimersSurviveRestart(t *testing.T) {
+	stateManagement := state.NewInMemoryState()
+	q := queue.NewInMemoryQueue()
+	ctx := context.Background()
+
+	// The first process schedules a timer and stops before it fires
+	first := NewService(stateManagement, q)
+	wakeAt := time.Now().Add(50 * time.Millisecond)
+	if err := first.Schedule(ctx, Timer{RunID: "run-1", WorkflowID: "rescore", Tenant: "acme", WakeAt: wakeAt}); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	records, err := stateManagement.ListRecords(ctx, recordNamespace)
+	if _, ok := records["acme/run-1"]; err != nil || len(records) != 1 || !ok {
+		t.Fatalf("timer records = %v, %v, want the timer of acme/run-1", records, err)
+	}
+	if runs, _ := stateManagement.GetAllContexts(ctx); len(runs) != 0 {
+		t.Errorf("runs = %d, want timers kept apart from run state", len(runs))
+	}
+
+	second := NewService(stateManagement, q)
+	stop, err := second.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer stop()
+	if second.Pending() != 1 {
+		t.Fatalf("Pending() = %d after restart, want 1", second.Pending())
+	}
+
+	var msg *queue.Message
+	for deadline := time.Now().Add(2 * time.Second); msg == nil && time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if msg, err = q.Dequeue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if msg == nil {
+		t.Fatal("timer did not fire")
+	}
+	if time.Now().Before(wakeAt) {
+		t.Errorf("timer fired before %s", wakeAt)
+	}
+	request, err := queue.UnmarshalExecutionRequest(msg.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !request.Resume || request.RunID != "run-1" || request.WorkflowID != "rescore" || request.Tenant != "acme" ||
+		request.NotBefore == nil || !request.NotBefore.Equal(wakeAt) {
+		t.Errorf("request = %+v, want the run resumed once due", request)
+	}
+
+	// A fired timer stays in the state store until its run resumes and removes it
+	if second.Pending() != 1 {
+		t.Errorf("Pending() = %d after firing, want 1 until the run resumes", second.Pending())
+	}
+	if err := second.Remove(ctx, "acme", "run-1"); err != nil || second.Pending() != 0 {
+		t.Errorf("Remove() = %v with %d pending, want none", err, second.Pending())
+	}
+	third := NewService(stateManagement, q)
+	if err := third.load(ctx); err != nil || third.Pending() != 0 {
+		t.Errorf("timers after firing = %d, %v, want none", third.Pending(), err)
+	}
+}
+
+func TestReloadPicksUpTimersOfOtherProcesses(t *testing.T) {
+	stateManagement := state.NewInMemoryState()
+	ctx := context.Background()
+	local := NewService(stateManagement, queue.NewInMemoryQueue())
+	if err := local.load(ctx); err != nil || local.Pending() != 0 {
+		t.Fatalf("timers = %d, %v, want none", local.Pending(), err)
+	}
+
+	other := NewService(stateManagement, queue.NewInMemoryQueue())
+	if err := other.Schedule(ctx, Timer{RunID: "run-1", WorkflowID: "rescore", Tenant: "acme", WakeAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if err := local.load(ctx); err != nil || local.Pending() != 1 {
+		t.Errorf("timers after reload = %d, %v, want the timer armed by the other process", local.Pending(), err)
+	}
+}
+
+func TestScheduleReplacesTimerOfRun(t *testing.T) {
+	q := queue.NewInMemoryQueue()
+	ctx := context.Background()
+	service := NewService(state.NewInMemoryState(), q)
+	stop, err := service.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer stop()
+
+	first := time.Now().Add(20 * time.Millisecond)
+	second := time.Now().Add(60 * time.Millisecond)
+	for _, wakeAt := range []time.Time{first, second} {
+		if err := service.Schedule(ctx, Timer{RunID: "run-1", WorkflowID: "rescore", WakeAt: wakeAt}); err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+	}
+	if service.Pending() != 1 {
+		t.Fatalf("Pending() = %d, want the timer of the run replaced", service.Pending())
+	}
+
+	var fired []*queue.Message
+	for deadline := time.Now().Add(300 * time.Millisecond); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		msg, err := q.Dequeue(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg != nil {
+			if time.Now().Before(second) {
+				t.Errorf("timer fired before its replacement was due")
+			}
+			fired = append(fired, msg)
+		}
+	}
+	if len(fired) != 1 {
+		t.Errorf("timer fired %d times, want once", len(fired))
+	}
+}
+
+func TestFiredTimerFiresAgainUntilRemoved(t *testing.T) {
+	q := queue.NewInMemoryQueue()
+	ctx := context.Background()
+	service := NewService(state.NewInMemoryState(), q)
+	if err := service.Schedule(ctx, Timer{RunID: "run-1", WorkflowID: "rescore", Tenant: "acme", WakeAt: time.Now()}); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	dequeue := func() int {
+		count := 0
+		for {
+			msg, err := q.Dequeue(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg == nil {
+				return count
+			}
+			count++
+		}
+	}
+	if next := service.fireDue(ctx); dequeue() != 1 || time.Until(next) < refireDelay-time.Second {
+		t.Fatalf("first firing: next = %s, want a single request and the timer due again after %s", next, refireDelay)
+	}
+	if service.fireDue(ctx); dequeue() != 0 {
+		t.Errorf("timer fired again before %s", refireDelay)
+	}
+
+	// The resume request was lost: the timer fires again once refireDelay passed
+	service.mu.Lock()
+	service.refire[key("acme", "run-1")] = time.Now()
+	service.mu.Unlock()
+	if service.fireDue(ctx); dequeue() != 1 {
+		t.Errorf("timer did not fire again for a run that did not resume")
+	}
+
+	if err := service.Remove(ctx, "acme", "run-1"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if next := service.fireDue(ctx); dequeue() != 0 || !next.IsZero() {
+		t.Errorf("removed timer fired again")
+	}
+}
@@ -49,6 +49,9 @@ type Config struct {
 	// AuthToken is the authentication token
 	AuthToken string `json:"auth_token" yaml:"auth_token"`
 
+	// Tenant is the tenant requests act for unless their context names one
+	Tenant string `json:"tenant" yaml:"tenant"`
+
 	// EnableCircuitBreaker enables circuit breaker pattern
 	EnableCircuitBreaker bool `json:"enable_circuit_breaker" yaml:"enable_circuit_breaker"`
 
@@ -23,6 +23,9 @@ type Client interface {
 	// CancelExecution cancels a running workflow execution
 	CancelExecution(ctx context.Context, req *CancelExecutionRequest) (*CancelExecutionResponse, error)
 
+	// SignalExecution sends a named signal to a run, resuming the step waiting for it
+	SignalExecution(ctx context.Context, req *SignalExecutionRequest) (*SignalExecutionResponse, error)
+
 	// PauseExecution pauses a running workflow execution
 	PauseExecution(ctx context.Context, req *PauseExecutionRequest) (*PauseExecutionResponse, error)
 
@@ -169,6 +172,31 @@ type CancelExecutionResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// SignalExecutionRequest is the request for sending a signal to an execution
+type SignalExecutionRequest struct {
+	client.Request
+
+	// RunID is the execution run ID
+	RunID string `json:"run_id"`
+
+	// Name is the name of the signal
+	Name string `json:"name"`
+
+	// Payload is merged into the execution data when a wait step takes the signal
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// SignalExecutionResponse is the response for sending a signal to an execution
+type SignalExecutionResponse struct {
+	client.Response
+
+	// RunID is the execution run ID
+	RunID string `json:"run_id"`
+
+	// Message is the signal message
+	Message string `json:"message,omitempty"`
+}
+
 // PauseExecutionRequest is the request for pausing execution
 type PauseExecutionRequest struct {
 	client.Request
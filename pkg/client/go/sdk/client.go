@@ -2,8 +2,8 @@ package sdk
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"unified-workflow/pkg/client"
@@ -36,6 +36,9 @@ type WorkflowSDKClient interface {
 	// Cancel execution
 	CancelExecution(ctx context.Context, runID string) error
 
+	// Send a signal to an execution, merging payload into its data
+	SignalExecution(ctx context.Context, runID, name string, payload map[string]interface{}) error
+
 	// Health check
 	Ping(ctx context.Context) error
 
@@ -66,6 +69,7 @@ func NewClient(config *SDKConfig) (WorkflowSDKClient, error) {
 		MaxRetries:              config.MaxRetries,
 		RetryDelay:              config.RetryDelay,
 		AuthToken:               config.AuthToken,
+		Tenant:                  config.Tenant,
 		EnableCircuitBreaker:    config.EnableCircuitBreaker,
 		CircuitBreakerThreshold: config.CircuitBreakerThreshold,
 		CircuitBreakerTimeout:   config.CircuitBreakerTimeout,
@@ -276,6 +280,22 @@ func (c *workflowSDKClient) CancelExecution(ctx context.Context, runID string) e
 	return err
 }
 
+// SignalExecution sends a named signal to a workflow execution
+func (c *workflowSDKClient) SignalExecution(ctx context.Context, runID, name string, payload map[string]interface{}) error {
+	req := &executor.SignalExecutionRequest{
+		Request: client.Request{
+			ID:        generateRequestID(),
+			Timestamp: time.Now(),
+		},
+		RunID:   runID,
+		Name:    name,
+		Payload: payload,
+	}
+
+	_, err := c.executor.SignalExecution(ctx, req)
+	return err
+}
+
 // Ping performs a health check
 func (c *workflowSDKClient) Ping(ctx context.Context) error {
 	return c.httpClient.Ping(ctx)
@@ -334,7 +354,11 @@ func (ec *executorClient) IsHealthy() bool {
 // ExecuteWorkflow executes a workflow
 func (ec *executorClient) ExecuteWorkflow(ctx context.Context, req *executor.ExecuteWorkflowRequest) (*executor.ExecuteWorkflowResponse, error) {
 	// Make actual HTTP call to workflow API
-	resp, err := ec.httpClient.DoRequest(ctx, "POST", "/api/v1/workflows/"+req.WorkflowID+"/execute", req)
+	path := "/api/v1/workflows/" + req.WorkflowID + "/execute"
+	if req.Async {
+		path = "/api/v1/workflows/" + req.WorkflowID + "/async-execute"
+	}
+	resp, err := ec.httpClient.DoRequest(ctx, "POST", path, req)
 	if err != nil {
 		return nil, err
 	}
@@ -351,7 +375,7 @@ func (ec *executorClient) ExecuteWorkflow(ctx context.Context, req *executor.Exe
 // GetExecutionStatus gets the status of a workflow execution
 func (ec *executorClient) GetExecutionStatus(ctx context.Context, req *executor.GetExecutionStatusRequest) (*executor.GetExecutionStatusResponse, error) {
 	// Make actual HTTP call to workflow API
-	resp, err := ec.httpClient.DoRequest(ctx, "GET", "/api/v1/executions/"+req.RunID+"/status", nil)
+	resp, err := ec.httpClient.DoRequest(ctx, "GET", "/api/v1/executions/"+req.RunID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -397,6 +421,22 @@ func (ec *executorClient) CancelExecution(ctx context.Context, req *executor.Can
 	return &cancelResp, nil
 }
 
+// SignalExecution sends a named signal to a running workflow execution
+func (ec *executorClient) SignalExecution(ctx context.Context, req *executor.SignalExecutionRequest) (*executor.SignalExecutionResponse, error) {
+	resp, err := ec.httpClient.DoRequest(ctx, "POST", "/api/v1/executions/"+req.RunID+"/signals/"+url.PathEscape(req.Name), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var signalResp executor.SignalExecutionResponse
+	if err := ec.httpClient.ParseResponse(resp, &signalResp); err != nil {
+		return nil, err
+	}
+
+	return &signalResp, nil
+}
+
 // PauseExecution pauses a running workflow execution
 func (ec *executorClient) PauseExecution(ctx context.Context, req *executor.PauseExecutionRequest) (*executor.PauseExecutionResponse, error) {
 	resp, err := ec.httpClient.DoRequest(ctx, "POST", "/api/v1/executions/"+req.RunID+"/pause", req)
@@ -25,6 +25,9 @@ type SDKConfig struct {
 	AuthToken string   `json:"auth_token" yaml:"auth_token"`
 	AuthType  AuthType `json:"auth_type" yaml:"auth_type"`
 
+	// Tenant is sent as X-Tenant-ID; empty acts for the default tenant or the tenant bound to the token
+	Tenant string `json:"tenant" yaml:"tenant"`
+
 	// Validation
 	EnableValidation   bool `json:"enable_validation" yaml:"enable_validation"`
 	EnableSanitization bool `json:"enable_sanitization" yaml:"enable_sanitization"`
@@ -287,6 +290,9 @@ func applySDKEnvOverrides(sdkConfig *SDKConfig) {
 	if val := os.Getenv("SDK_AUTH_TOKEN"); val != "" {
 		sdkConfig.AuthToken = val
 	}
+	if val := os.Getenv("SDK_TENANT"); val != "" {
+		sdkConfig.Tenant = val
+	}
 	if val := os.Getenv("SDK_AUTH_TYPE"); val != "" {
 		sdkConfig.AuthType = AuthType(val)
 	}
@@ -38,6 +38,10 @@ const (
 	ErrCodeTimeout              = "TIMEOUT"
 	ErrCodeCircuitBreaker       = "CIRCUIT_BREAKER_OPEN"
 	ErrCodeRetryExhausted       = "RETRY_EXHAUSTED"
+	ErrCodeNotFound             = "NOT_FOUND"
+	ErrCodeUnavailable          = "SERVICE_UNAVAILABLE"
+	ErrCodeUnauthorized         = "UNAUTHORIZED"
+	ErrCodeQuotaExceeded        = "QUOTA_EXCEEDED"
 )
 
 // NewSDKError creates a new SDK error
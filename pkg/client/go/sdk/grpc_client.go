@@ -0,0 +1,249 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"unified-workflow/pkg/workflowpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// GRPCClient calls the execution API over gRPC
+type GRPCClient struct {
+	conn      *grpc.ClientConn
+	client    workflowpb.ExecutionServiceClient
+	clientID  string
+	authToken string
+	tenant    string
+}
+
+// GRPCOption configures a GRPCClient
+type GRPCOption func(*GRPCClient)
+
+// WithClientID sends the client ID whose secret signs callbacks with every call
+func WithClientID(clientID string) GRPCOption {
+	return func(c *GRPCClient) {
+		c.clientID = clientID
+	}
+}
+
+// WithAuthToken sends an API key or JWT as the bearer token of every call
+func WithAuthToken(token string) GRPCOption {
+	return func(c *GRPCClient) {
+		c.authToken = token
+	}
+}
+
+// WithTenant sends the tenant every call acts for
+func WithTenant(tenant string) GRPCOption {
+	return func(c *GRPCClient) {
+		c.tenant = tenant
+	}
+}
+
+// NewGRPCClient connects to the execution API at target
+// Without dial options the connection is made without transport security
+func NewGRPCClient(target string, dialOpts []grpc.DialOption, opts ...GRPCOption) (*GRPCClient, error) {
+	if target == "" {
+		return nil, NewSDKErrorWithField(ErrCodeInvalidConfig, "gRPC target is required", "target")
+	}
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, WrapSDKError(err, ErrCodeInvalidConfig, "Failed to create gRPC connection")
+	}
+	return NewGRPCClientFromConn(conn, opts...), nil
+}
+
+// NewGRPCClientFromConn creates a client on an existing connection, which Close then closes
+func NewGRPCClientFromConn(conn *grpc.ClientConn, opts ...GRPCOption) *GRPCClient {
+	c := &GRPCClient{
+		conn:   conn,
+		client: workflowpb.NewExecutionServiceClient(conn),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ExecuteWorkflow runs a workflow and waits up to timeout for its result
+// A response with Completed false means the run continues asynchronously
+func (c *GRPCClient) ExecuteWorkflow(ctx context.Context, workflowID string, data map[string]interface{}, timeout time.Duration) (*workflowpb.ExecuteWorkflowResponse, error) {
+	input, err := toInputStruct(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.ExecuteWorkflow(c.outgoing(ctx), &workflowpb.ExecuteWorkflowRequest{
+		WorkflowId: workflowID,
+		InputData:  input,
+		TimeoutMs:  timeout.Milliseconds(),
+	})
+	if err != nil {
+		return nil, wrapGRPCError(err, "Failed to execute workflow")
+	}
+	return resp, nil
+}
+
+// SubmitWorkflow queues a workflow run and returns its run ID
+func (c *GRPCClient) SubmitWorkflow(ctx context.Context, workflowID string, data map[string]interface{}, callbackURL string) (*workflowpb.SubmitWorkflowResponse, error) {
+	input, err := toInputStruct(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.SubmitWorkflow(c.outgoing(ctx), &workflowpb.SubmitWorkflowRequest{
+		WorkflowId:  workflowID,
+		InputData:   input,
+		CallbackUrl: callbackURL,
+	})
+	if err != nil {
+		return nil, wrapGRPCError(err, "Failed to submit workflow")
+	}
+	return resp, nil
+}
+
+// GetExecutionStatus gets the status of a workflow execution
+func (c *GRPCClient) GetExecutionStatus(ctx context.Context, runID string) (*workflowpb.ExecutionStatus, error) {
+	resp, err := c.client.GetExecutionStatus(c.outgoing(ctx), &workflowpb.GetExecutionStatusRequest{RunId: runID})
+	if err != nil {
+		return nil, wrapGRPCError(err, "Failed to get execution status")
+	}
+	return resp, nil
+}
+
+// GetExecutionResult gets the result of a workflow execution, waiting up to wait for an unfinished one
+func (c *GRPCClient) GetExecutionResult(ctx context.Context, runID string, wait time.Duration) (*workflowpb.GetExecutionResultResponse, error) {
+	resp, err := c.client.GetExecutionResult(c.outgoing(ctx), &workflowpb.GetExecutionResultRequest{
+		RunId:  runID,
+		WaitMs: wait.Milliseconds(),
+	})
+	if err != nil {
+		return nil, wrapGRPCError(err, "Failed to get execution result")
+	}
+	return resp, nil
+}
+
+// ListExecutions lists workflow executions with optional filters
+func (c *GRPCClient) ListExecutions(ctx context.Context, req *workflowpb.ListExecutionsRequest) (*workflowpb.ListExecutionsResponse, error) {
+	if req == nil {
+		req = &workflowpb.ListExecutionsRequest{}
+	}
+	resp, err := c.client.ListExecutions(c.outgoing(ctx), req)
+	if err != nil {
+		return nil, wrapGRPCError(err, "Failed to list executions")
+	}
+	return resp, nil
+}
+
+// CancelExecution cancels a running workflow execution
+func (c *GRPCClient) CancelExecution(ctx context.Context, runID string) error {
+	_, err := c.client.CancelExecution(c.outgoing(ctx), &workflowpb.ExecutionControlRequest{RunId: runID})
+	return wrapGRPCError(err, "Failed to cancel execution")
+}
+
+// PauseExecution pauses a running workflow execution
+func (c *GRPCClient) PauseExecution(ctx context.Context, runID string) error {
+	_, err := c.client.PauseExecution(c.outgoing(ctx), &workflowpb.ExecutionControlRequest{RunId: runID})
+	return wrapGRPCError(err, "Failed to pause execution")
+}
+
+// ResumeExecution resumes a paused workflow execution
+func (c *GRPCClient) ResumeExecution(ctx context.Context, runID string) error {
+	_, err := c.client.ResumeExecution(c.outgoing(ctx), &workflowpb.ExecutionControlRequest{RunId: runID})
+	return wrapGRPCError(err, "Failed to resume execution")
+}
+
+// RetryExecution retries a failed workflow execution
+func (c *GRPCClient) RetryExecution(ctx context.Context, runID string) error {
+	_, err := c.client.RetryExecution(c.outgoing(ctx), &workflowpb.ExecutionControlRequest{RunId: runID})
+	return wrapGRPCError(err, "Failed to retry execution")
+}
+
+// WatchExecution calls fn with the status of a run each time it changes until the run is terminal
+// Returning an error from fn stops the watch and returns that error
+func (c *GRPCClient) WatchExecution(ctx context.Context, runID string, fn func(*workflowpb.ExecutionStatus) error) error {
+	ctx, cancel := context.WithCancel(c.outgoing(ctx))
+	defer cancel()
+
+	stream, err := c.client.WatchExecution(ctx, &workflowpb.WatchExecutionRequest{RunId: runID})
+	if err != nil {
+		return wrapGRPCError(err, "Failed to watch execution")
+	}
+	for {
+		update, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return wrapGRPCError(err, "Failed to watch execution")
+		}
+		if err := fn(update); err != nil {
+			return err
+		}
+	}
+}
+
+// Close closes the connection
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// outgoing attaches the client ID and credentials to the metadata of a call
+func (c *GRPCClient) outgoing(ctx context.Context) context.Context {
+	if c.clientID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-client-id", c.clientID)
+	}
+	if c.authToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.authToken)
+	}
+	if c.tenant != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", c.tenant)
+	}
+	return ctx
+}
+
+// toInputStruct converts workflow input data to a protobuf Struct
+func toInputStruct(data map[string]interface{}) (*structpb.Struct, error) {
+	if data == nil {
+		return nil, nil
+	}
+	input, err := structpb.NewStruct(data)
+	if err != nil {
+		return nil, WrapSDKError(err, ErrCodeRequestParsingFailed, "Failed to encode input data")
+	}
+	return input, nil
+}
+
+// wrapGRPCError converts a gRPC status error to an SDK error
+func wrapGRPCError(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	code := ErrCodeWorkflowExecution
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		code = ErrCodeValidationFailed
+	case codes.NotFound:
+		code = ErrCodeNotFound
+	case codes.DeadlineExceeded:
+		code = ErrCodeTimeout
+	case codes.Unavailable:
+		code = ErrCodeUnavailable
+	case codes.Unauthenticated, codes.PermissionDenied:
+		code = ErrCodeUnauthorized
+	case codes.ResourceExhausted:
+		code = ErrCodeQuotaExceeded
+	}
+	return WrapSDKError(err, code, message+": "+status.Convert(err).Message())
+}
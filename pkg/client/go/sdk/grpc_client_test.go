@@ -0,0 +1,101 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/executor"
+	"unified-workflow/internal/grpcapi"
+	"unified-workflow/internal/primitive"
+	"unified-workflow/internal/registry"
+	"unified-workflow/internal/state"
+	"unified-workflow/pkg/workflowpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newBufconnClient connects a GRPCClient to an in-process execution service
+func newBufconnClient(t *testing.T) (*GRPCClient, *registry.InMemoryRegistry) {
+	t.Helper()
+	if err := primitive.Init(&primitive.Config{EchoEnabled: true}); err != nil {
+		t.Fatalf("primitive.Init() error = %v", err)
+	}
+
+	reg := registry.NewInMemoryRegistry()
+	exec := executor.NewWorkflowExecutor(reg, state.NewInMemoryState(), executor.DefaultConfig())
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpcapi.NewGRPCServer(grpcapi.NewServer(exec, reg, nil))
+	go func() { _ = server.Serve(listener) }()
+
+	client, err := NewGRPCClient("passthrough:///bufnet", []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, WithClientID("merchant-1"))
+	if err != nil {
+		t.Fatalf("NewGRPCClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return client, reg
+}
+
+func TestGRPCClientExecutesAndWatchesWorkflow(t *testing.T) {
+	client, reg := newBufconnClient(t)
+	step := model.NewBaseStep("validate", false)
+	step.AddChildStep(model.NewChildStep("check-limits", nil, nil, nil))
+	workflow := model.NewBaseWorkflow("payment", "sdk grpc test workflow")
+	workflow.AddStep(step)
+	if err := reg.RegisterWorkflow(context.Background(), workflow); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := client.ExecuteWorkflow(ctx, workflow.GetID(), map[string]interface{}{"amount": "250"}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if !resp.GetCompleted() || resp.GetResult().GetOutputData().AsMap()["amount"] != "250" {
+		t.Fatalf("ExecuteWorkflow() = %v, want a completed run with its input", resp)
+	}
+
+	var updates []*workflowpb.ExecutionStatus
+	err = client.WatchExecution(ctx, resp.GetRunId(), func(update *workflowpb.ExecutionStatus) error {
+		updates = append(updates, update)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WatchExecution() error = %v", err)
+	}
+	if len(updates) != 1 || !updates[0].GetIsTerminal() {
+		t.Errorf("WatchExecution() of a finished run = %v, want a single terminal update", updates)
+	}
+}
+
+func TestGRPCClientWrapsStatusErrors(t *testing.T) {
+	client, _ := newBufconnClient(t)
+
+	_, err := client.GetExecutionStatus(context.Background(), "run-unknown")
+	var sdkErr *SDKError
+	if !errors.As(err, &sdkErr) || sdkErr.Code != ErrCodeNotFound {
+		t.Errorf("GetExecutionStatus() error = %v, want %s", err, ErrCodeNotFound)
+	}
+
+	_, err = client.ExecuteWorkflow(context.Background(), "", nil, 0)
+	if !errors.As(err, &sdkErr) || sdkErr.Code != ErrCodeValidationFailed {
+		t.Errorf("ExecuteWorkflow() without a workflow error = %v, want %s", err, ErrCodeValidationFailed)
+	}
+
+	if _, err := NewGRPCClient("", nil); err == nil {
+		t.Error("NewGRPCClient() without a target error = nil, want error")
+	}
+}
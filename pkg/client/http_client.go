@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"unified-workflow/internal/tenant"
 )
 
 // HTTPClient is a base HTTP client implementation
@@ -123,6 +125,13 @@ func (c *HTTPClient) doRequestOnce(ctx context.Context, method, path string, bod
 	if c.config.AuthToken != "" {
 		req.Header.Set("Authorization", "Bearer "+c.config.AuthToken)
 	}
+	tenantID := c.config.Tenant
+	if ctxTenant := tenant.FromContext(ctx); !tenant.IsDefault(ctxTenant) {
+		tenantID = ctxTenant
+	}
+	if !tenant.IsDefault(tenantID) {
+		req.Header.Set(tenant.Header, tenantID)
+	}
 
 	// Add tracing headers from context if available
 	if traceID := getTraceIDFromContext(ctx); traceID != "" {
@@ -160,6 +169,10 @@ func (c *HTTPClient) ParseResponse(resp *http.Response, target interface{}) erro
 		}
 	}
 
+	if resp.StatusCode >= http.StatusBadRequest {
+		return parseErrorResponse(resp.StatusCode, body)
+	}
+
 	if err := json.Unmarshal(body, target); err != nil {
 		return &Error{
 			Code:          ErrCodeInternal,
@@ -172,6 +185,34 @@ func (c *HTTPClient) ParseResponse(resp *http.Response, target interface{}) erro
 	return nil
 }
 
+// parseErrorResponse converts the API error envelope into an Error
+func parseErrorResponse(statusCode int, body []byte) error {
+	var envelope struct {
+		Error   string `json:"error"`
+		Code    string `json:"code"`
+		Details string `json:"details"`
+		RunID   string `json:"run_id"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Code == "" {
+		envelope.Code = ErrCodeInternal
+		envelope.Error = fmt.Sprintf("Request failed with status %d", statusCode)
+	}
+
+	details := map[string]interface{}{"status_code": statusCode}
+	if envelope.Details != "" {
+		details["details"] = envelope.Details
+	}
+	if envelope.RunID != "" {
+		details["run_id"] = envelope.RunID
+	}
+	return &Error{
+		Code:      envelope.Code,
+		Message:   envelope.Error,
+		Details:   details,
+		Retryable: statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests,
+	}
+}
+
 // Ping performs a health check
 func (c *HTTPClient) Ping(ctx context.Context) error {
 	resp, err := c.DoRequest(ctx, "GET", "/health", nil)
@@ -0,0 +1,1158 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: workflow/v1/execution.proto
+
+package workflowpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExecuteWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	InputData     *structpb.Struct       `protobuf:"bytes,2,opt,name=input_data,json=inputData,proto3" json:"input_data,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteWorkflowRequest) Reset() {
+	*x = ExecuteWorkflowRequest{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteWorkflowRequest) ProtoMessage() {}
+
+func (x *ExecuteWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExecuteWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ExecuteWorkflowRequest) GetInputData() *structpb.Struct {
+	if x != nil {
+		return x.InputData
+	}
+	return nil
+}
+
+func (x *ExecuteWorkflowRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type ExecuteWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	WorkflowId    string                 `protobuf:"bytes,2,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Completed     bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	Result        *ExecutionResult       `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteWorkflowResponse) Reset() {
+	*x = ExecuteWorkflowResponse{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteWorkflowResponse) ProtoMessage() {}
+
+func (x *ExecuteWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExecuteWorkflowResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ExecuteWorkflowResponse) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ExecuteWorkflowResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecuteWorkflowResponse) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *ExecuteWorkflowResponse) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type SubmitWorkflowRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId        string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	InputData         *structpb.Struct       `protobuf:"bytes,2,opt,name=input_data,json=inputData,proto3" json:"input_data,omitempty"`
+	CallbackUrl       string                 `protobuf:"bytes,3,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	WaitForCompletion bool                   `protobuf:"varint,4,opt,name=wait_for_completion,json=waitForCompletion,proto3" json:"wait_for_completion,omitempty"`
+	TimeoutMs         int64                  `protobuf:"varint,5,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SubmitWorkflowRequest) Reset() {
+	*x = SubmitWorkflowRequest{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitWorkflowRequest) ProtoMessage() {}
+
+func (x *SubmitWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*SubmitWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *SubmitWorkflowRequest) GetInputData() *structpb.Struct {
+	if x != nil {
+		return x.InputData
+	}
+	return nil
+}
+
+func (x *SubmitWorkflowRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *SubmitWorkflowRequest) GetWaitForCompletion() bool {
+	if x != nil {
+		return x.WaitForCompletion
+	}
+	return false
+}
+
+func (x *SubmitWorkflowRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type SubmitWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Result        *ExecutionResult       `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitWorkflowResponse) Reset() {
+	*x = SubmitWorkflowResponse{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitWorkflowResponse) ProtoMessage() {}
+
+func (x *SubmitWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*SubmitWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitWorkflowResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *SubmitWorkflowResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SubmitWorkflowResponse) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type GetExecutionStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionStatusRequest) Reset() {
+	*x = GetExecutionStatusRequest{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionStatusRequest) ProtoMessage() {}
+
+func (x *GetExecutionStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetExecutionStatusRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetExecutionStatusRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type GetExecutionResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	WaitMs        int64                  `protobuf:"varint,2,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionResultRequest) Reset() {
+	*x = GetExecutionResultRequest{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionResultRequest) ProtoMessage() {}
+
+func (x *GetExecutionResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionResultRequest.ProtoReflect.Descriptor instead.
+func (*GetExecutionResultRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetExecutionResultRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *GetExecutionResultRequest) GetWaitMs() int64 {
+	if x != nil {
+		return x.WaitMs
+	}
+	return 0
+}
+
+type GetExecutionResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ready         bool                   `protobuf:"varint,1,opt,name=ready,proto3" json:"ready,omitempty"`
+	Status        *ExecutionStatus       `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Result        *ExecutionResult       `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionResultResponse) Reset() {
+	*x = GetExecutionResultResponse{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionResultResponse) ProtoMessage() {}
+
+func (x *GetExecutionResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionResultResponse.ProtoReflect.Descriptor instead.
+func (*GetExecutionResultResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetExecutionResultResponse) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *GetExecutionResultResponse) GetStatus() *ExecutionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetExecutionResultResponse) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ListExecutionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExecutionsRequest) Reset() {
+	*x = ListExecutionsRequest{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionsRequest) ProtoMessage() {}
+
+func (x *ListExecutionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionsRequest.ProtoReflect.Descriptor instead.
+func (*ListExecutionsRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListExecutionsRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ListExecutionsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListExecutionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListExecutionsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListExecutionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Executions    []*ExecutionStatus     `protobuf:"bytes,1,rep,name=executions,proto3" json:"executions,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExecutionsResponse) Reset() {
+	*x = ListExecutionsResponse{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionsResponse) ProtoMessage() {}
+
+func (x *ListExecutionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionsResponse.ProtoReflect.Descriptor instead.
+func (*ListExecutionsResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListExecutionsResponse) GetExecutions() []*ExecutionStatus {
+	if x != nil {
+		return x.Executions
+	}
+	return nil
+}
+
+func (x *ListExecutionsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListExecutionsResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ExecutionControlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecutionControlRequest) Reset() {
+	*x = ExecutionControlRequest{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionControlRequest) ProtoMessage() {}
+
+func (x *ExecutionControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionControlRequest.ProtoReflect.Descriptor instead.
+func (*ExecutionControlRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExecutionControlRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type ExecutionControlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecutionControlResponse) Reset() {
+	*x = ExecutionControlResponse{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionControlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionControlResponse) ProtoMessage() {}
+
+func (x *ExecutionControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionControlResponse.ProtoReflect.Descriptor instead.
+func (*ExecutionControlResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExecutionControlResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ExecutionControlResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type WatchExecutionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchExecutionRequest) Reset() {
+	*x = WatchExecutionRequest{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchExecutionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchExecutionRequest) ProtoMessage() {}
+
+func (x *WatchExecutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchExecutionRequest.ProtoReflect.Descriptor instead.
+func (*WatchExecutionRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchExecutionRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type ExecutionStatus struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	RunId                 string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	WorkflowId            string                 `protobuf:"bytes,2,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Status                string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	CurrentStep           string                 `protobuf:"bytes,4,opt,name=current_step,json=currentStep,proto3" json:"current_step,omitempty"`
+	CurrentStepIndex      int32                  `protobuf:"varint,5,opt,name=current_step_index,json=currentStepIndex,proto3" json:"current_step_index,omitempty"`
+	CurrentChildStepIndex int32                  `protobuf:"varint,6,opt,name=current_child_step_index,json=currentChildStepIndex,proto3" json:"current_child_step_index,omitempty"`
+	Progress              float64                `protobuf:"fixed64,7,opt,name=progress,proto3" json:"progress,omitempty"`
+	StartTime             *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime               *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	ErrorMessage          string                 `protobuf:"bytes,10,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	LastAttemptedStep     string                 `protobuf:"bytes,11,opt,name=last_attempted_step,json=lastAttemptedStep,proto3" json:"last_attempted_step,omitempty"`
+	IsTerminal            bool                   `protobuf:"varint,12,opt,name=is_terminal,json=isTerminal,proto3" json:"is_terminal,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ExecutionStatus) Reset() {
+	*x = ExecutionStatus{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionStatus) ProtoMessage() {}
+
+func (x *ExecutionStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionStatus.ProtoReflect.Descriptor instead.
+func (*ExecutionStatus) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExecutionStatus) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ExecutionStatus) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ExecutionStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecutionStatus) GetCurrentStep() string {
+	if x != nil {
+		return x.CurrentStep
+	}
+	return ""
+}
+
+func (x *ExecutionStatus) GetCurrentStepIndex() int32 {
+	if x != nil {
+		return x.CurrentStepIndex
+	}
+	return 0
+}
+
+func (x *ExecutionStatus) GetCurrentChildStepIndex() int32 {
+	if x != nil {
+		return x.CurrentChildStepIndex
+	}
+	return 0
+}
+
+func (x *ExecutionStatus) GetProgress() float64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *ExecutionStatus) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ExecutionStatus) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ExecutionStatus) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ExecutionStatus) GetLastAttemptedStep() string {
+	if x != nil {
+		return x.LastAttemptedStep
+	}
+	return ""
+}
+
+func (x *ExecutionStatus) GetIsTerminal() bool {
+	if x != nil {
+		return x.IsTerminal
+	}
+	return false
+}
+
+type ExecutionResult struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	RunId               string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	WorkflowId          string                 `protobuf:"bytes,2,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Status              string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	OutputData          *structpb.Struct       `protobuf:"bytes,4,opt,name=output_data,json=outputData,proto3" json:"output_data,omitempty"`
+	ErrorMessage        string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	StartTime           *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime             *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	ExecutionTimeMillis int64                  `protobuf:"varint,8,opt,name=execution_time_millis,json=executionTimeMillis,proto3" json:"execution_time_millis,omitempty"`
+	StepCount           int32                  `protobuf:"varint,9,opt,name=step_count,json=stepCount,proto3" json:"step_count,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ExecutionResult) Reset() {
+	*x = ExecutionResult{}
+	mi := &file_workflow_v1_execution_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionResult) ProtoMessage() {}
+
+func (x *ExecutionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_execution_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionResult.ProtoReflect.Descriptor instead.
+func (*ExecutionResult) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_execution_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExecutionResult) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ExecutionResult) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ExecutionResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecutionResult) GetOutputData() *structpb.Struct {
+	if x != nil {
+		return x.OutputData
+	}
+	return nil
+}
+
+func (x *ExecutionResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ExecutionResult) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ExecutionResult) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ExecutionResult) GetExecutionTimeMillis() int64 {
+	if x != nil {
+		return x.ExecutionTimeMillis
+	}
+	return 0
+}
+
+func (x *ExecutionResult) GetStepCount() int32 {
+	if x != nil {
+		return x.StepCount
+	}
+	return 0
+}
+
+var File_workflow_v1_execution_proto protoreflect.FileDescriptor
+
+const file_workflow_v1_execution_proto_rawDesc = "" +
+	"\n" +
+	"\x1bworkflow/v1/execution.proto\x12\vworkflow.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x90\x01\n" +
+	"\x16ExecuteWorkflowRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x126\n" +
+	"\n" +
+	"input_data\x18\x02 \x01(\v2\x17.google.protobuf.StructR\tinputData\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x03 \x01(\x03R\ttimeoutMs\"\xbd\x01\n" +
+	"\x17ExecuteWorkflowResponse\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1f\n" +
+	"\vworkflow_id\x18\x02 \x01(\tR\n" +
+	"workflowId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\bR\tcompleted\x124\n" +
+	"\x06result\x18\x05 \x01(\v2\x1c.workflow.v1.ExecutionResultR\x06result\"\xe2\x01\n" +
+	"\x15SubmitWorkflowRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x126\n" +
+	"\n" +
+	"input_data\x18\x02 \x01(\v2\x17.google.protobuf.StructR\tinputData\x12!\n" +
+	"\fcallback_url\x18\x03 \x01(\tR\vcallbackUrl\x12.\n" +
+	"\x13wait_for_completion\x18\x04 \x01(\bR\x11waitForCompletion\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x05 \x01(\x03R\ttimeoutMs\"}\n" +
+	"\x16SubmitWorkflowResponse\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x124\n" +
+	"\x06result\x18\x03 \x01(\v2\x1c.workflow.v1.ExecutionResultR\x06result\"2\n" +
+	"\x19GetExecutionStatusRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"K\n" +
+	"\x19GetExecutionResultRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x17\n" +
+	"\await_ms\x18\x02 \x01(\x03R\x06waitMs\"\x9e\x01\n" +
+	"\x1aGetExecutionResultResponse\x12\x14\n" +
+	"\x05ready\x18\x01 \x01(\bR\x05ready\x124\n" +
+	"\x06status\x18\x02 \x01(\v2\x1c.workflow.v1.ExecutionStatusR\x06status\x124\n" +
+	"\x06result\x18\x03 \x01(\v2\x1c.workflow.v1.ExecutionResultR\x06result\"~\n" +
+	"\x15ListExecutionsRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\x84\x01\n" +
+	"\x16ListExecutionsResponse\x12<\n" +
+	"\n" +
+	"executions\x18\x01 \x03(\v2\x1c.workflow.v1.ExecutionStatusR\n" +
+	"executions\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"0\n" +
+	"\x17ExecutionControlRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"K\n" +
+	"\x18ExecutionControlResponse\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\".\n" +
+	"\x15WatchExecutionRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"\xef\x03\n" +
+	"\x0fExecutionStatus\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1f\n" +
+	"\vworkflow_id\x18\x02 \x01(\tR\n" +
+	"workflowId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12!\n" +
+	"\fcurrent_step\x18\x04 \x01(\tR\vcurrentStep\x12,\n" +
+	"\x12current_step_index\x18\x05 \x01(\x05R\x10currentStepIndex\x127\n" +
+	"\x18current_child_step_index\x18\x06 \x01(\x05R\x15currentChildStepIndex\x12\x1a\n" +
+	"\bprogress\x18\a \x01(\x01R\bprogress\x129\n" +
+	"\n" +
+	"start_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12#\n" +
+	"\rerror_message\x18\n" +
+	" \x01(\tR\ferrorMessage\x12.\n" +
+	"\x13last_attempted_step\x18\v \x01(\tR\x11lastAttemptedStep\x12\x1f\n" +
+	"\vis_terminal\x18\f \x01(\bR\n" +
+	"isTerminal\"\x85\x03\n" +
+	"\x0fExecutionResult\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1f\n" +
+	"\vworkflow_id\x18\x02 \x01(\tR\n" +
+	"workflowId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x128\n" +
+	"\voutput_data\x18\x04 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"outputData\x12#\n" +
+	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x122\n" +
+	"\x15execution_time_millis\x18\b \x01(\x03R\x13executionTimeMillis\x12\x1d\n" +
+	"\n" +
+	"step_count\x18\t \x01(\x05R\tstepCount2\xbd\a\n" +
+	"\x10ExecutionService\x12\\\n" +
+	"\x0fExecuteWorkflow\x12#.workflow.v1.ExecuteWorkflowRequest\x1a$.workflow.v1.ExecuteWorkflowResponse\x12Y\n" +
+	"\x0eSubmitWorkflow\x12\".workflow.v1.SubmitWorkflowRequest\x1a#.workflow.v1.SubmitWorkflowResponse\x12Z\n" +
+	"\x12GetExecutionStatus\x12&.workflow.v1.GetExecutionStatusRequest\x1a\x1c.workflow.v1.ExecutionStatus\x12e\n" +
+	"\x12GetExecutionResult\x12&.workflow.v1.GetExecutionResultRequest\x1a'.workflow.v1.GetExecutionResultResponse\x12Y\n" +
+	"\x0eListExecutions\x12\".workflow.v1.ListExecutionsRequest\x1a#.workflow.v1.ListExecutionsResponse\x12^\n" +
+	"\x0fCancelExecution\x12$.workflow.v1.ExecutionControlRequest\x1a%.workflow.v1.ExecutionControlResponse\x12]\n" +
+	"\x0ePauseExecution\x12$.workflow.v1.ExecutionControlRequest\x1a%.workflow.v1.ExecutionControlResponse\x12^\n" +
+	"\x0fResumeExecution\x12$.workflow.v1.ExecutionControlRequest\x1a%.workflow.v1.ExecutionControlResponse\x12]\n" +
+	"\x0eRetryExecution\x12$.workflow.v1.ExecutionControlRequest\x1a%.workflow.v1.ExecutionControlResponse\x12T\n" +
+	"\x0eWatchExecution\x12\".workflow.v1.WatchExecutionRequest\x1a\x1c.workflow.v1.ExecutionStatus0\x01B,Z*unified-workflow/pkg/workflowpb;workflowpbb\x06proto3"
+
+var (
+	file_workflow_v1_execution_proto_rawDescOnce sync.Once
+	file_workflow_v1_execution_proto_rawDescData []byte
+)
+
+func file_workflow_v1_execution_proto_rawDescGZIP() []byte {
+	file_workflow_v1_execution_proto_rawDescOnce.Do(func() {
+		file_workflow_v1_execution_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_workflow_v1_execution_proto_rawDesc), len(file_workflow_v1_execution_proto_rawDesc)))
+	})
+	return file_workflow_v1_execution_proto_rawDescData
+}
+
+var file_workflow_v1_execution_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_workflow_v1_execution_proto_goTypes = []any{
+	(*ExecuteWorkflowRequest)(nil),     // 0: workflow.v1.ExecuteWorkflowRequest
+	(*ExecuteWorkflowResponse)(nil),    // 1: workflow.v1.ExecuteWorkflowResponse
+	(*SubmitWorkflowRequest)(nil),      // 2: workflow.v1.SubmitWorkflowRequest
+	(*SubmitWorkflowResponse)(nil),     // 3: workflow.v1.SubmitWorkflowResponse
+	(*GetExecutionStatusRequest)(nil),  // 4: workflow.v1.GetExecutionStatusRequest
+	(*GetExecutionResultRequest)(nil),  // 5: workflow.v1.GetExecutionResultRequest
+	(*GetExecutionResultResponse)(nil), // 6: workflow.v1.GetExecutionResultResponse
+	(*ListExecutionsRequest)(nil),      // 7: workflow.v1.ListExecutionsRequest
+	(*ListExecutionsResponse)(nil),     // 8: workflow.v1.ListExecutionsResponse
+	(*ExecutionControlRequest)(nil),    // 9: workflow.v1.ExecutionControlRequest
+	(*ExecutionControlResponse)(nil),   // 10: workflow.v1.ExecutionControlResponse
+	(*WatchExecutionRequest)(nil),      // 11: workflow.v1.WatchExecutionRequest
+	(*ExecutionStatus)(nil),            // 12: workflow.v1.ExecutionStatus
+	(*ExecutionResult)(nil),            // 13: workflow.v1.ExecutionResult
+	(*structpb.Struct)(nil),            // 14: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),      // 15: google.protobuf.Timestamp
+}
+var file_workflow_v1_execution_proto_depIdxs = []int32{
+	14, // 0: workflow.v1.ExecuteWorkflowRequest.input_data:type_name -> google.protobuf.Struct
+	13, // 1: workflow.v1.ExecuteWorkflowResponse.result:type_name -> workflow.v1.ExecutionResult
+	14, // 2: workflow.v1.SubmitWorkflowRequest.input_data:type_name -> google.protobuf.Struct
+	13, // 3: workflow.v1.SubmitWorkflowResponse.result:type_name -> workflow.v1.ExecutionResult
+	12, // 4: workflow.v1.GetExecutionResultResponse.status:type_name -> workflow.v1.ExecutionStatus
+	13, // 5: workflow.v1.GetExecutionResultResponse.result:type_name -> workflow.v1.ExecutionResult
+	12, // 6: workflow.v1.ListExecutionsResponse.executions:type_name -> workflow.v1.ExecutionStatus
+	15, // 7: workflow.v1.ExecutionStatus.start_time:type_name -> google.protobuf.Timestamp
+	15, // 8: workflow.v1.ExecutionStatus.end_time:type_name -> google.protobuf.Timestamp
+	14, // 9: workflow.v1.ExecutionResult.output_data:type_name -> google.protobuf.Struct
+	15, // 10: workflow.v1.ExecutionResult.start_time:type_name -> google.protobuf.Timestamp
+	15, // 11: workflow.v1.ExecutionResult.end_time:type_name -> google.protobuf.Timestamp
+	0,  // 12: workflow.v1.ExecutionService.ExecuteWorkflow:input_type -> workflow.v1.ExecuteWorkflowRequest
+	2,  // 13: workflow.v1.ExecutionService.SubmitWorkflow:input_type -> workflow.v1.SubmitWorkflowRequest
+	4,  // 14: workflow.v1.ExecutionService.GetExecutionStatus:input_type -> workflow.v1.GetExecutionStatusRequest
+	5,  // 15: workflow.v1.ExecutionService.GetExecutionResult:input_type -> workflow.v1.GetExecutionResultRequest
+	7,  // 16: workflow.v1.ExecutionService.ListExecutions:input_type -> workflow.v1.ListExecutionsRequest
+	9,  // 17: workflow.v1.ExecutionService.CancelExecution:input_type -> workflow.v1.ExecutionControlRequest
+	9,  // 18: workflow.v1.ExecutionService.PauseExecution:input_type -> workflow.v1.ExecutionControlRequest
+	9,  // 19: workflow.v1.ExecutionService.ResumeExecution:input_type -> workflow.v1.ExecutionControlRequest
+	9,  // 20: workflow.v1.ExecutionService.RetryExecution:input_type -> workflow.v1.ExecutionControlRequest
+	11, // 21: workflow.v1.ExecutionService.WatchExecution:input_type -> workflow.v1.WatchExecutionRequest
+	1,  // 22: workflow.v1.ExecutionService.ExecuteWorkflow:output_type -> workflow.v1.ExecuteWorkflowResponse
+	3,  // 23: workflow.v1.ExecutionService.SubmitWorkflow:output_type -> workflow.v1.SubmitWorkflowResponse
+	12, // 24: workflow.v1.ExecutionService.GetExecutionStatus:output_type -> workflow.v1.ExecutionStatus
+	6,  // 25: workflow.v1.ExecutionService.GetExecutionResult:output_type -> workflow.v1.GetExecutionResultResponse
+	8,  // 26: workflow.v1.ExecutionService.ListExecutions:output_type -> workflow.v1.ListExecutionsResponse
+	10, // 27: workflow.v1.ExecutionService.CancelExecution:output_type -> workflow.v1.ExecutionControlResponse
+	10, // 28: workflow.v1.ExecutionService.PauseExecution:output_type -> workflow.v1.ExecutionControlResponse
+	10, // 29: workflow.v1.ExecutionService.ResumeExecution:output_type -> workflow.v1.ExecutionControlResponse
+	10, // 30: workflow.v1.ExecutionService.RetryExecution:output_type -> workflow.v1.ExecutionControlResponse
+	12, // 31: workflow.v1.ExecutionService.WatchExecution:output_type -> workflow.v1.ExecutionStatus
+	22, // [22:32] is the sub-list for method output_type
+	12, // [12:22] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_workflow_v1_execution_proto_init() }
+func file_workflow_v1_execution_proto_init() {
+	if File_workflow_v1_execution_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_workflow_v1_execution_proto_rawDesc), len(file_workflow_v1_execution_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_workflow_v1_execution_proto_goTypes,
+		DependencyIndexes: file_workflow_v1_execution_proto_depIdxs,
+		MessageInfos:      file_workflow_v1_execution_proto_msgTypes,
+	}.Build()
+	File_workflow_v1_execution_proto = out.File
+	file_workflow_v1_execution_proto_goTypes = nil
+	file_workflow_v1_execution_proto_depIdxs = nil
+}
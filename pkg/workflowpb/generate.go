@@ -0,0 +1,4 @@
+// Package workflowpb holds the generated gRPC bindings of the execution API
+package workflowpb
+
+//go:generate protoc -I ../../api/proto --go_out=. --go_opt=module=unified-workflow/pkg/workflowpb --go-grpc_out=. --go-grpc_opt=module=unified-workflow/pkg/workflowpb workflow/v1/execution.proto
@@ -2,6 +2,7 @@ package workflows
 
 import (
 	"fmt"
+	"log/slog"
 
 	"unified-workflow/internal/common/model"
 	"unified-workflow/workflows/steps"
@@ -15,35 +16,33 @@ func CreateAntifraudTransactionWorkflow(endpoint string) model.Workflow {
 		fmt.Sprintf("Complete transaction validation using antifraud SDK at %s", endpoint),
 	)
 
-	fmt.Printf("Creating antifraud transaction workflow for endpoint: %s\n", endpoint)
-
 	// Step 1: Store Transaction
 	storeStep := steps.NewStoreTransactionStep(endpoint)
 	workflow.AddStep(storeStep)
-	fmt.Println("✓ Added StoreTransactionStep")
 
-	// Step 2: AML Validation
+	// Steps 2-4: AML, FC (Fraud Check) and ML (Machine Learning) validation are independent
+	// and run concurrently once the transaction is stored
 	amlStep := steps.NewAMLValidationStep(endpoint)
+	amlStep.SetDependsOn(storeStep.GetName())
 	workflow.AddStep(amlStep)
-	fmt.Println("✓ Added AMLValidationStep")
 
-	// Step 3: FC Validation (Fraud Check)
 	fcStep := steps.NewFCValidationStep(endpoint)
+	fcStep.SetDependsOn(storeStep.GetName())
 	workflow.AddStep(fcStep)
-	fmt.Println("✓ Added FCValidationStep")
 
-	// Step 4: ML Validation (Machine Learning)
 	mlStep := steps.NewMLValidationStep(endpoint)
+	mlStep.SetDependsOn(storeStep.GetName())
 	workflow.AddStep(mlStep)
-	fmt.Println("✓ Added MLValidationStep")
 
-	// Step 5: Finalize Transaction
+	// Step 5: Finalize Transaction, once all validations have finished
 	finalizeStep := steps.NewFinalizeTransactionStep(endpoint)
+	finalizeStep.SetDependsOn(amlStep.GetName(), fcStep.GetName(), mlStep.GetName())
 	workflow.AddStep(finalizeStep)
-	fmt.Println("✓ Added FinalizeTransactionStep")
 
-	fmt.Printf("✓ Antifraud workflow created with %d steps\n", workflow.GetStepCount())
-	fmt.Printf("✓ Total child steps: %d\n", workflow.GetTotalChildStepCount())
+	slog.Debug("Antifraud workflow created",
+		"endpoint", endpoint,
+		"step_count", workflow.GetStepCount(),
+		"child_step_count", workflow.GetTotalChildStepCount())
 
 	return workflow
 }
@@ -66,22 +65,21 @@ func GetAntifraudWorkflows() []model.Workflow {
 
 // GetAntifraudExampleWorkflows returns all example workflows including antifraud workflows
 func GetAntifraudExampleWorkflows() []model.Workflow {
+	// Add antifraud workflows; payment processing runs the primary one as its fraud check
+	antifraudWorkflows := GetAntifraudWorkflows()
+
 	// Get existing example workflows
 	existingWorkflows := []model.Workflow{
 		createEchoWorkflow(),
 		createSequentialWorkflow(),
-		createPaymentProcessingWorkflow(),
+		createPaymentProcessingWorkflow(antifraudWorkflows[0].GetID()),
 		createMultiStepWorkflow(),
 	}
 
-	// Add antifraud workflows
-	antifraudWorkflows := GetAntifraudWorkflows()
-
 	// Combine all workflows
 	allWorkflows := append(existingWorkflows, antifraudWorkflows...)
 
-	fmt.Printf("Total workflows available: %d (including %d antifraud workflows)\n",
-		len(allWorkflows), len(antifraudWorkflows))
+	slog.Debug("Workflows available", "total", len(allWorkflows), "antifraud", len(antifraudWorkflows))
 
 	return allWorkflows
 }
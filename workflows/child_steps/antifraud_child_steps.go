@@ -1,83 +1,132 @@
 package child_steps
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/common/typed"
+	"unified-workflow/internal/logging"
 	"unified-workflow/internal/primitive"
+	primitiveModel "unified-workflow/internal/primitive/model"
 	"unified-workflow/internal/primitive/services/antifraud/models"
 
 	"github.com/google/uuid"
 )
 
+// Names of the antifraud child steps; later child steps read earlier results with typed.Result
+const (
+	PrepareTransactionRequestChildStep = "antifraud_prepare_transaction_request_child_step"
+	CallStoreTransactionAsyncChildStep = "antifraud_call_store_transaction_async_child_step"
+	ProcessStoreResponseChildStep      = "antifraud_process_store_response_child_step"
+	StoreTransactionResultChildStep    = "antifraud_store_transaction_result_child_step"
+	PrepareAMLRequestChildStep         = "antifraud_prepare_aml_request_child_step"
+	CallAMLValidationChildStep         = "antifraud_call_aml_validation_child_step"
+	ProcessAMLResponseChildStep        = "antifraud_process_aml_response_child_step"
+	ValidateAMLResponseChildStep       = "antifraud_validate_aml_response_child_step"
+	ValidateAMLResultChildStep         = "antifraud_validate_aml_result_child_step"
+	StoreAMLResolutionChildStep        = "antifraud_store_aml_resolution_child_step"
+	AddAMLToTransactionChildStep       = "antifraud_add_aml_to_transaction_child_step"
+	PrepareFCRequestChildStep          = "antifraud_prepare_fc_request_child_step"
+	CallFCValidationChildStep          = "antifraud_call_fc_validation_child_step"
+	ProcessFCResponseChildStep         = "antifraud_process_fc_response_child_step"
+	ValidateFCResponseChildStep        = "antifraud_validate_fc_response_child_step"
+	ValidateFCResultChildStep          = "antifraud_validate_fc_result_child_step"
+	StoreFCResolutionChildStep         = "antifraud_store_fc_resolution_child_step"
+	AddFCToTransactionChildStep        = "antifraud_add_fc_to_transaction_child_step"
+	PrepareMLRequestChildStep          = "antifraud_prepare_ml_request_child_step"
+	CallMLValidationChildStep          = "antifraud_call_ml_validation_child_step"
+	ProcessMLResponseChildStep         = "antifraud_process_ml_response_child_step"
+	ValidateMLResponseChildStep        = "antifraud_validate_ml_response_child_step"
+	ValidateMLResultChildStep          = "antifraud_validate_ml_result_child_step"
+	StoreMLResolutionChildStep         = "antifraud_store_ml_resolution_child_step"
+	AddMLToTransactionChildStep        = "antifraud_add_ml_to_transaction_child_step"
+	FinalizeTransactionChildStep       = "antifraud_finalize_transaction_child_step"
+	ValidateFinalResolutionChildStep   = "antifraud_validate_final_resolution_child_step"
+	StoreFinalResolutionChildStep      = "antifraud_store_final_resolution_child_step"
+)
+
+// errAntifraudServiceUnavailable is returned when the antifraud primitive is not initialized
+var errAntifraudServiceUnavailable = errors.New("antifraud service is not initialized")
+
+// AsyncCall acknowledges a call to an antifraud service
+type AsyncCall struct {
+	OperationID   string    `json:"operation_id"`
+	Service       string    `json:"service"`
+	TransactionID string    `json:"transaction_id"`
+	Status        string    `json:"status"`
+	StartedAt     time.Time `json:"started_at"`
+	SDKCallMade   bool      `json:"sdk_call_made"`
+}
+
+// StoreResponse is the processed response of a store transaction call
+type StoreResponse struct {
+	TransactionID string    `json:"transaction_id"`
+	Status        string    `json:"status"`
+	Message       string    `json:"message"`
+	StoredAt      time.Time `json:"stored_at"`
+	Call          AsyncCall `json:"call"`
+}
+
+// ValidationResponse is the processed response of an AML, FC or ML validation: a PASS, REVIEW or FAIL
+// resolution with a risk score from 0 to 100
+type ValidationResponse struct {
+	models.ServiceResolution
+	CheckedAt   time.Time `json:"checked_at"`
+	RiskFactors []string  `json:"risk_factors"`
+}
+
+// OperationResult reports an operation recorded in the antifraud system
+type OperationResult struct {
+	Operation     string    `json:"operation"`
+	Success       bool      `json:"success"`
+	ID            string    `json:"id,omitempty"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	CompletedAt   time.Time `json:"completed_at"`
+}
+
 // GetAntifraudChildSteps returns all reusable antifraud child steps
 func GetAntifraudChildSteps() []*model.ChildStep {
-	return []*model.ChildStep{
+	childSteps := []*model.ChildStep{
 		CreateAntifraudPrepareTransactionRequestChildStep(),
 		CreateAntifraudCallStoreTransactionAsyncChildStep(),
 		CreateAntifraudProcessStoreResponseChildStep(),
 		CreateAntifraudStoreTransactionResultChildStep(),
 		CreateAntifraudPrepareAMLRequestChildStep(),
-		CreateAntifraudCallAMLValidationAsyncChildStep(),
+		CreateAntifraudCallAMLValidationChildStep(),
 		CreateAntifraudProcessAMLResponseChildStep(),
 		CreateAntifraudValidateAMLResponseChildStep(),
 		CreateAntifraudValidateAMLResultChildStep(),
 		CreateAntifraudStoreAMLResolutionChildStep(),
 		CreateAntifraudAddAMLToTransactionChildStep(),
 	}
+	childSteps = append(childSteps, CreateAntifraudFCValidationChildSteps()...)
+	childSteps = append(childSteps, CreateAntifraudMLValidationChildSteps()...)
+	return append(childSteps,
+		CreateAntifraudFinalizeTransactionChildStep(),
+		CreateAntifraudValidateFinalResolutionChildStep(),
+		CreateAntifraudStoreFinalResolutionChildStep(),
+	)
 }
 
 // CreateAntifraudPrepareTransactionRequestChildStep creates a child step for preparing transaction request
 func CreateAntifraudPrepareTransactionRequestChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_prepare_transaction_request_child_step",
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Preparing transaction request...")
-
-			// Extract transaction data from workflow data
-			transactionData := extractTransactionData(data)
-
-			// Get transaction ID from context or generate one
-			transactionID := getTransactionIDFromContext(context)
-			if transactionID == "" {
-				transactionID = uuid.NewString()
-			}
-
-			// Create antifraud transaction from real data
-			afTransaction := models.AF_Transaction{
-				AF_Id:      transactionID,
-				AF_AddDate: time.Now().Format(time.RFC3339Nano),
-				Transaction: models.Transaction{
-					Id:                 getString(transactionData, "id", ""),
-					Type:               getString(transactionData, "type", ""),
-					Date:               getString(transactionData, "date", time.Now().Format(time.RFC3339)),
-					Amount:             getString(transactionData, "amount", ""),
-					Currency:           getString(transactionData, "currency", ""),
-					ClientId:           getString(transactionData, "client_id", ""),
-					ClientName:         getString(transactionData, "client_name", ""),
-					ClientPAN:          getString(transactionData, "client_pan", ""),
-					ClientCVV:          getString(transactionData, "client_cvv", ""),
-					ClientCardHolder:   getString(transactionData, "client_card_holder", ""),
-					ClientPhone:        getString(transactionData, "client_phone", ""),
-					MerchantTerminalId: getString(transactionData, "merchant_terminal_id", ""),
-					Channel:            getString(transactionData, "channel", ""),
-					LocationIp:         getString(transactionData, "location_ip", ""),
-				},
-			}
-
-			fmt.Printf("Prepared transaction with ID: %s\n", afTransaction.AF_Id)
-			return afTransaction
+	return typed.NewChildStep[models.AF_Transaction, models.AF_Transaction](
+		PrepareTransactionRequestChildStep,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.AF_Transaction, error) {
+			slog.Debug("Preparing transaction request")
+			afTransaction := buildAFTransaction(workflowContext, data)
+			slog.Debug("Prepared transaction", "af_id", afTransaction.AF_Id)
+			return afTransaction, nil
 		},
-		nil, // No response hook for request preparation
-		func(request interface{}) error {
-			fmt.Println("Validating transaction request...")
-
-			// Validate the antifraud transaction
-			afTransaction, ok := request.(models.AF_Transaction)
-			if !ok {
-				return fmt.Errorf("invalid transaction type: %T", request)
-			}
+		nil, // The prepared transaction is the result
+		func(afTransaction models.AF_Transaction) error {
+			slog.Debug("Validating transaction request")
 
 			// Validate required fields for deployment
 			if afTransaction.AF_Id == "" {
@@ -96,538 +145,575 @@ func CreateAntifraudPrepareTransactionRequestChildStep() *model.ChildStep {
 				return fmt.Errorf("missing client ID")
 			}
 
-			fmt.Println("Transaction request validation passed")
+			slog.Debug("Transaction request validation passed")
 			return nil
 		},
-	)
+	).Untyped()
 }
 
 // CreateAntifraudCallStoreTransactionAsyncChildStep creates a child step for calling store transaction async
 func CreateAntifraudCallStoreTransactionAsyncChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_call_store_transaction_async_child_step",
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Calling store transaction async...")
-
-			// Extract transaction from data
-			transaction, err := extractAFTransactionFromData(data)
-			if err != nil {
-				return fmt.Errorf("failed to extract transaction: %w", err)
-			}
-
-			// Get antifraud service from context
-			antifraudService, err := getAntifraudServiceFromContext(context)
-			if err != nil {
-				return fmt.Errorf("failed to get antifraud service: %w", err)
-			}
+	return typed.NewChildStep[models.AF_Transaction, AsyncCall](
+		CallStoreTransactionAsyncChildStep,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.AF_Transaction, error) {
+			return requireResult[models.AF_Transaction](data, PrepareTransactionRequestChildStep)
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, transaction models.AF_Transaction) (AsyncCall, error) {
+			slog.Debug("Calling store transaction async")
 
-			// Call the actual antifraud SDK
-			err = antifraudService.StoreTransaction(transaction)
+			antifraudService, err := antifraudService()
 			if err != nil {
-				return fmt.Errorf("store transaction failed: %w", err)
+				return AsyncCall{}, err
 			}
 
-			// Return actual SDK response
-			return map[string]interface{}{
-				"async_operation_id": uuid.NewString(),
-				"status":             "completed",
-				"started_at":         time.Now().Format(time.RFC3339),
-				"completed_at":       time.Now().Format(time.RFC3339),
-				"transaction_id":     transaction.AF_Id,
-				"sdk_call_made":      true,
-				"error":              nil,
+			startedAt := time.Now()
+			if err := antifraudService.StoreTransaction(ctx, transaction); err != nil {
+				return AsyncCall{}, fmt.Errorf("store transaction failed: %w", err)
 			}
+			return AsyncCall{
+				OperationID:   uuid.NewString(),
+				Service:       "STORE",
+				TransactionID: transaction.AF_Id,
+				Status:        "completed",
+				StartedAt:     startedAt,
+				SDKCallMade:   true,
+			}, nil
 		},
-		nil, // Response hook would process async response
 		nil, // No validation for async call initiation
-	)
+	).Untyped()
 }
 
 // CreateAntifraudProcessStoreResponseChildStep creates a child step for processing store response
 func CreateAntifraudProcessStoreResponseChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_process_store_response_child_step",
-		nil, // No request hook for response processing
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Processing store response...")
-
-			// Extract the SDK response from the previous async call
-			// In production, this would be the actual SDK response
-			sdkResponse, err := extractSDKResponseFromData(data)
-			if err != nil {
-				return fmt.Errorf("failed to extract SDK response: %w", err)
-			}
-
-			// Process the actual SDK response
-			return map[string]interface{}{
-				"transaction_id": sdkResponse["transaction_id"],
-				"stored_at":      time.Now().Format(time.RFC3339),
-				"status":         "stored",
-				"message":        "Transaction stored successfully",
-				"sdk_response":   sdkResponse,
-			}
+	return typed.NewChildStep[AsyncCall, StoreResponse](
+		ProcessStoreResponseChildStep,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (AsyncCall, error) {
+			return requireResult[AsyncCall](data, CallStoreTransactionAsyncChildStep)
 		},
-		func(response interface{}) error {
-			fmt.Println("Validating store response...")
-
-			resp, ok := response.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("invalid store response type: %T", response)
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, call AsyncCall) (StoreResponse, error) {
+			slog.Debug("Processing store response")
+			return StoreResponse{
+				TransactionID: call.TransactionID,
+				Status:        "stored",
+				Message:       "Transaction stored successfully",
+				StoredAt:      time.Now(),
+				Call:          call,
+			}, nil
+		},
+		func(response StoreResponse) error {
+			slog.Debug("Validating store response")
+			if response.TransactionID == "" {
+				return fmt.Errorf("missing store response field: transaction_id")
 			}
-
-			// Validate required fields
-			requiredFields := []string{"transaction_id", "stored_at", "status"}
-			for _, field := range requiredFields {
-				if _, exists := resp[field]; !exists {
-					return fmt.Errorf("missing store response field: %s", field)
-				}
+			if !response.Call.SDKCallMade {
+				return fmt.Errorf("missing SDK call confirmation")
 			}
-
-			// Validate that SDK response is present
-			if sdkResponse, exists := resp["sdk_response"]; exists {
-				if sdkResp, ok := sdkResponse.(map[string]interface{}); ok {
-					if _, hasSDKCall := sdkResp["sdk_call_made"]; !hasSDKCall {
-						return fmt.Errorf("missing SDK call confirmation")
-					}
-				}
-			}
-
-			fmt.Println("Store response validation passed")
+			slog.Debug("Store response validation passed")
 			return nil
 		},
-	)
+	).Untyped()
 }
 
 // CreateAntifraudStoreTransactionResultChildStep creates a child step for storing transaction result
 func CreateAntifraudStoreTransactionResultChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_store_transaction_result_child_step",
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Storing transaction result in workflow data...")
-
-			// Extract processed response
-			processedResponse, err := extractProcessedResponseFromData(data)
-			if err != nil {
-				return fmt.Errorf("failed to extract processed response: %w", err)
-			}
-
-			// Store the actual transaction result in workflow data
-			// This uses the real processed response from SDK
-			return map[string]interface{}{
-				"operation":             "store_transaction",
-				"success":               true,
-				"timestamp":             time.Now().Format(time.RFC3339),
-				"message":               "Transaction stored and result saved to workflow data",
-				"transaction_id":        processedResponse["transaction_id"],
-				"original_sdk_response": processedResponse["sdk_response"],
-			}
+	return typed.NewChildStep[StoreResponse, OperationResult](
+		StoreTransactionResultChildStep,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (StoreResponse, error) {
+			return requireResult[StoreResponse](data, ProcessStoreResponseChildStep)
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, response StoreResponse) (OperationResult, error) {
+			slog.Debug("Storing transaction result in workflow data")
+			return OperationResult{
+				Operation:     "store_transaction",
+				Success:       true,
+				TransactionID: response.TransactionID,
+				Message:       "Transaction stored and result saved to workflow data",
+				CompletedAt:   time.Now(),
+			}, nil
 		},
-		nil, // No response hook
 		nil, // No validation
-	)
+	).Untyped()
+}
+
+// validation describes the child steps validating a transaction with one antifraud service
+// Services report a safety score; the child steps turn it into a risk score and check it against the thresholds
+type validation struct {
+	service          string
+	prepare          string
+	call             string
+	process          string
+	validateResponse string
+	validateResult   string
+	storeResolution  string
+	addToTransaction string
+	validate         func(primitive.AntifraudService, context.Context, interface{}) (interface{}, error)
+	rejection        string // why a FAIL resolution rejects the transaction
+	reviewScore      int    // risk score above which a passing transaction is worth a manual review
+	rejectScore      int    // risk score above which a passing transaction is rejected
+}
+
+var (
+	amlValidation = validation{
+		service:          "AML",
+		prepare:          PrepareAMLRequestChildStep,
+		call:             CallAMLValidationChildStep,
+		process:          ProcessAMLResponseChildStep,
+		validateResponse: ValidateAMLResponseChildStep,
+		validateResult:   ValidateAMLResultChildStep,
+		storeResolution:  StoreAMLResolutionChildStep,
+		addToTransaction: AddAMLToTransactionChildStep,
+		validate:         primitive.AntifraudService.ValidateTransactionByAML,
+		rejection:        "transaction rejected",
+		reviewScore:      70,
+		rejectScore:      90,
+	}
+	fcValidation = validation{
+		service:          "FC",
+		prepare:          PrepareFCRequestChildStep,
+		call:             CallFCValidationChildStep,
+		process:          ProcessFCResponseChildStep,
+		validateResponse: ValidateFCResponseChildStep,
+		validateResult:   ValidateFCResultChildStep,
+		storeResolution:  StoreFCResolutionChildStep,
+		addToTransaction: AddFCToTransactionChildStep,
+		validate:         primitive.AntifraudService.ValidateTransactionByFC,
+		rejection:        "potential fraud detected",
+		reviewScore:      80,
+		rejectScore:      95,
+	}
+	mlValidation = validation{
+		service:          "ML",
+		prepare:          PrepareMLRequestChildStep,
+		call:             CallMLValidationChildStep,
+		process:          ProcessMLResponseChildStep,
+		validateResponse: ValidateMLResponseChildStep,
+		validateResult:   ValidateMLResultChildStep,
+		storeResolution:  StoreMLResolutionChildStep,
+		addToTransaction: AddMLToTransactionChildStep,
+		validate:         primitive.AntifraudService.ValidateTransactionByML,
+		rejection:        "high fraud probability detected",
+		reviewScore:      85,
+		rejectScore:      90,
+	}
+)
+
+// childSteps returns the child steps of a validation, in order
+func (v validation) childSteps() []*model.ChildStep {
+	return []*model.ChildStep{
+		v.prepareRequestChildStep(),
+		v.callChildStep(),
+		v.processResponseChildStep(),
+		v.validateResponseChildStep(),
+		v.validateResultChildStep(),
+		v.storeResolutionChildStep(),
+		v.addToTransactionChildStep(),
+	}
+}
+
+// CreateAntifraudFCValidationChildSteps creates the child steps validating a transaction with the FC service
+func CreateAntifraudFCValidationChildSteps() []*model.ChildStep {
+	return fcValidation.childSteps()
+}
+
+// CreateAntifraudMLValidationChildSteps creates the child steps validating a transaction with the ML service
+func CreateAntifraudMLValidationChildSteps() []*model.ChildStep {
+	return mlValidation.childSteps()
 }
 
 // CreateAntifraudPrepareAMLRequestChildStep creates a child step for preparing AML request
 func CreateAntifraudPrepareAMLRequestChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_prepare_aml_request_child_step",
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Preparing AML validation request...")
-
-			// Extract transaction data from workflow data
-			transactionData := extractTransactionData(data)
-
-			// Get transaction ID from context or generate one
-			transactionID := getTransactionIDFromContext(context)
-			if transactionID == "" {
-				transactionID = uuid.NewString()
-			}
-
-			// Create AML request from real data
-			amlRequest := map[string]interface{}{
-				"af_id":       transactionID,
-				"af_add_date": time.Now().Format(time.RFC3339Nano),
-				"transaction": map[string]interface{}{
-					"id":          getString(transactionData, "id", ""),
-					"type":        getString(transactionData, "type", ""),
-					"amount":      getString(transactionData, "amount", ""),
-					"currency":    getString(transactionData, "currency", ""),
-					"client_id":   getString(transactionData, "client_id", ""),
-					"client_name": getString(transactionData, "client_name", ""),
-				},
-			}
-
-			fmt.Printf("Prepared AML request for transaction: %s\n", amlRequest["af_id"])
-			return amlRequest
-		},
-		nil, // No response hook
-		func(request interface{}) error {
-			fmt.Println("Validating AML request...")
+	return amlValidation.prepareRequestChildStep()
+}
 
-			req, ok := request.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("invalid AML request type: %T", request)
-			}
+// CreateAntifraudCallAMLValidationChildStep creates a child step calling AML validation and awaiting its resolution
+func CreateAntifraudCallAMLValidationChildStep() *model.ChildStep {
+	return amlValidation.callChildStep()
+}
+
+// CreateAntifraudProcessAMLResponseChildStep creates a child step for processing AML response
+func CreateAntifraudProcessAMLResponseChildStep() *model.ChildStep {
+	return amlValidation.processResponseChildStep()
+}
 
+// CreateAntifraudValidateAMLResponseChildStep creates a child step for validating AML response structure
+func CreateAntifraudValidateAMLResponseChildStep() *model.ChildStep {
+	return amlValidation.validateResponseChildStep()
+}
+
+// CreateAntifraudValidateAMLResultChildStep creates a child step for validating AML result against business rules
+func CreateAntifraudValidateAMLResultChildStep() *model.ChildStep {
+	return amlValidation.validateResultChildStep()
+}
+
+// CreateAntifraudStoreAMLResolutionChildStep creates a child step for storing AML resolution
+func CreateAntifraudStoreAMLResolutionChildStep() *model.ChildStep {
+	return amlValidation.storeResolutionChildStep()
+}
+
+// CreateAntifraudAddAMLToTransactionChildStep creates a child step for adding AML to transaction
+func CreateAntifraudAddAMLToTransactionChildStep() *model.ChildStep {
+	return amlValidation.addToTransactionChildStep()
+}
+
+// prepareRequestChildStep creates a child step for preparing the validation request
+func (v validation) prepareRequestChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.AF_Transaction, models.AF_Transaction](
+		v.prepare,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.AF_Transaction, error) {
+			logging.Debug(ctx, "Preparing validation request", "service", v.service)
+			return preparedTransaction(workflowContext, data), nil
+		},
+		nil, // The prepared transaction is the request
+		func(afTransaction models.AF_Transaction) error {
 			// Validate required fields for deployment
-			requiredFields := []string{"af_id", "transaction"}
-			for _, field := range requiredFields {
-				if _, exists := req[field]; !exists {
-					return fmt.Errorf("missing AML request field: %s", field)
-				}
+			if afTransaction.AF_Id == "" {
+				return fmt.Errorf("missing %s request field: af_id", v.service)
 			}
-
-			// Validate transaction structure
-			transaction, ok := req["transaction"].(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("invalid transaction structure")
+			if afTransaction.Transaction.Id == "" {
+				return fmt.Errorf("missing transaction field: id")
 			}
-
-			// Validate transaction fields
-			txRequiredFields := []string{"id", "amount", "currency", "client_id"}
-			for _, field := range txRequiredFields {
-				if _, exists := transaction[field]; !exists {
-					return fmt.Errorf("missing transaction field: %s", field)
-				}
+			if afTransaction.Transaction.Amount == "" {
+				return fmt.Errorf("missing transaction field: amount")
+			}
+			if afTransaction.Transaction.Currency == "" {
+				return fmt.Errorf("missing transaction field: currency")
+			}
+			if afTransaction.Transaction.ClientId == "" {
+				return fmt.Errorf("missing transaction field: client_id")
 			}
-
-			fmt.Println("AML request validation passed")
 			return nil
 		},
-	)
+	).Untyped()
 }
 
-// CreateAntifraudCallAMLValidationAsyncChildStep creates a child step for calling AML validation async
-func CreateAntifraudCallAMLValidationAsyncChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_call_aml_validation_async_child_step",
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Calling AML validation async...")
-
-			// Simulate async AML validation call
-			amlValidationID := uuid.NewString()
+// callChildStep creates a child step calling the validation service and awaiting its resolution
+// The call is cancelled with the step, so the step and run timeouts bound it
+func (v validation) callChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.AF_Transaction, models.ServiceResolution](
+		v.call,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.AF_Transaction, error) {
+			return requireResult[models.AF_Transaction](data, v.prepare)
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, request models.AF_Transaction) (models.ServiceResolution, error) {
+			antifraudService, err := antifraudService()
+			if err != nil {
+				return models.ServiceResolution{}, err
+			}
 
-			return map[string]interface{}{
-				"aml_validation_id": amlValidationID,
-				"status":            "processing",
-				"started_at":        time.Now().Format(time.RFC3339),
-				"service":           "AML",
+			startedAt := time.Now()
+			result, err := v.validate(antifraudService, ctx, request)
+			if err != nil {
+				return models.ServiceResolution{}, fmt.Errorf("%s validation failed: %w", v.service, err)
+			}
+			resolution, ok := result.(models.ServiceResolution)
+			if !ok {
+				return models.ServiceResolution{}, fmt.Errorf("unexpected %s resolution type: %T", v.service, result)
 			}
+			if resolution.ServiceName == "" {
+				resolution.ServiceName = v.service
+			}
+			if resolution.TransactionId == "" {
+				resolution.TransactionId = request.AF_Id
+			}
+			logging.Debug(ctx, "Validation resolved", "service", v.service, "resolution", resolution.Resolution, "duration", time.Since(startedAt))
+			return resolution, nil
 		},
-		nil, // Response hook would process async response
-		nil, // No validation for async call
-	)
+		nil, // The response is validated once processed
+	).Untyped()
 }
 
-// CreateAntifraudProcessAMLResponseChildStep creates a child step for processing AML response
-func CreateAntifraudProcessAMLResponseChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_process_aml_response_child_step",
-		nil, // No request hook
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Processing AML response...")
-
-			// In production, this would process the actual SDK response
-			// For high TPS, we process immediately without delays
-			return map[string]interface{}{
-				"service_name": "AML",
-				"resolution":   "PASS", // PASS, FAIL, REVIEW
-				"score":        85,     // Risk score 0-100
-				"details":      "Transaction passed AML screening",
-				"checked_at":   time.Now().Format(time.RFC3339),
-				"risk_factors": []string{
-					"Low risk country",
-					"Amount within limits",
-					"No PEP involvement",
-				},
-			}
+// processResponseChildStep creates a child step turning the resolution of the service into a validation response
+func (v validation) processResponseChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.ServiceResolution, ValidationResponse](
+		v.process,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.ServiceResolution, error) {
+			return requireResult[models.ServiceResolution](data, v.call)
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, resolution models.ServiceResolution) (ValidationResponse, error) {
+			return newValidationResponse(resolution), nil
 		},
 		nil, // Validation done in separate step
-	)
+	).Untyped()
 }
 
-// CreateAntifraudValidateAMLResponseChildStep creates a child step for validating AML response structure
-func CreateAntifraudValidateAMLResponseChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_validate_aml_response_child_step",
-		nil, // No request hook
-		nil, // No response hook
-		func(response interface{}) error {
-			fmt.Println("Validating AML response...")
-
-			resp, ok := response.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("invalid AML response type: %T", response)
-			}
-
-			requiredFields := []string{"service_name", "resolution", "score", "details"}
-			for _, field := range requiredFields {
-				if _, exists := resp[field]; !exists {
-					return fmt.Errorf("missing AML response field: %s", field)
-				}
+// validateResponseChildStep creates a child step for validating the response structure
+func (v validation) validateResponseChildStep() *model.ChildStep {
+	return typed.NewChildStep[ValidationResponse, ValidationResponse](
+		v.validateResponse,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (ValidationResponse, error) {
+			return requireResult[ValidationResponse](data, v.process)
+		},
+		nil, // The response is validated as is
+		func(response ValidationResponse) error {
+			if response.ServiceName == "" {
+				return fmt.Errorf("missing %s response field: service_name", v.service)
 			}
 
 			// Validate resolution value
-			resolution, ok := resp["resolution"].(string)
-			if !ok {
-				return fmt.Errorf("invalid resolution type")
-			}
-
 			validResolutions := map[string]bool{"PASS": true, "FAIL": true, "REVIEW": true}
-			if !validResolutions[resolution] {
-				return fmt.Errorf("invalid resolution value: %s", resolution)
+			if !validResolutions[response.Resolution] {
+				return fmt.Errorf("invalid resolution value: %s", response.Resolution)
 			}
 
 			// Validate score range
-			score, ok := resp["score"].(int)
-			if !ok || score < 0 || score > 100 {
-				return fmt.Errorf("invalid score value: %v", score)
+			if response.Score < 0 || response.Score > 100 {
+				return fmt.Errorf("invalid score value: %v", response.Score)
 			}
-
-			fmt.Println("AML response validation passed")
 			return nil
 		},
-	)
+	).Untyped()
 }
 
-// CreateAntifraudValidateAMLResultChildStep creates a child step for validating AML result against business rules
-func CreateAntifraudValidateAMLResultChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_validate_aml_result_child_step",
-		nil, // No request hook
-		nil, // No response hook
-		func(result interface{}) error {
-			fmt.Println("Validating AML result against business rules...")
-
-			res, ok := result.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("invalid AML result type: %T", result)
-			}
-
-			resolution, _ := res["resolution"].(string)
-			score, _ := res["score"].(int)
-
+// validateResultChildStep creates a child step for validating the result against business rules
+func (v validation) validateResultChildStep() *model.ChildStep {
+	return typed.NewChildStep[ValidationResponse, ValidationResponse](
+		v.validateResult,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (ValidationResponse, error) {
+			return requireResult[ValidationResponse](data, v.validateResponse)
+		},
+		nil, // The response is validated as is
+		func(result ValidationResponse) error {
 			// Business rule: If resolution is FAIL, reject
-			if resolution == "FAIL" {
-				return fmt.Errorf("AML validation failed: transaction rejected")
+			if result.Resolution == "FAIL" {
+				return fmt.Errorf("%s validation failed: %s", v.service, v.rejection)
 			}
 
-			// Business rule: If score > 70, flag for review
-			if score > 70 && resolution == "PASS" {
-				fmt.Println("AML Warning: High risk score, consider manual review")
-				// Continue processing but log warning
+			// Business rule: If the risk score is high, flag for review
+			if result.Score > v.reviewScore && result.Resolution == "PASS" {
+				slog.Warn("High risk score, consider manual review", "service", v.service, "score", result.Score)
 			}
 
-			// Business rule: If score > 90, fail even if resolution is PASS
-			if score > 90 {
-				return fmt.Errorf("AML validation failed: risk score too high (%d)", score)
+			// Business rule: If the risk score is too high, fail even if resolution is PASS
+			if result.Score > v.rejectScore {
+				return fmt.Errorf("%s validation failed: risk score too high (%d)", v.service, result.Score)
 			}
-
-			fmt.Println("AML result validation passed")
 			return nil
 		},
-	)
+	).Untyped()
 }
 
-// CreateAntifraudStoreAMLResolutionChildStep creates a child step for storing AML resolution
-func CreateAntifraudStoreAMLResolutionChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_store_aml_resolution_child_step",
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Storing AML resolution...")
-
-			// In production, this would call the SDK immediately
-			// For high TPS, no artificial delays
-			return map[string]interface{}{
-				"operation":     "store_aml_resolution",
-				"success":       true,
-				"stored_at":     time.Now().Format(time.RFC3339),
-				"resolution_id": uuid.NewString(),
+// storeResolutionChildStep creates a child step storing the resolution of the service
+func (v validation) storeResolutionChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.ServiceResolution, OperationResult](
+		v.storeResolution,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.ServiceResolution, error) {
+			return requireResult[models.ServiceResolution](data, v.call)
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, resolution models.ServiceResolution) (OperationResult, error) {
+			antifraudService, err := antifraudService()
+			if err != nil {
+				return OperationResult{}, err
+			}
+			if err := antifraudService.StoreServiceResolution(ctx, resolution); err != nil {
+				return OperationResult{}, fmt.Errorf("store %s resolution failed: %w", v.service, err)
 			}
+			return OperationResult{
+				Operation:   "store_" + strings.ToLower(v.service) + "_resolution",
+				Success:     true,
+				CompletedAt: time.Now(),
+			}, nil
 		},
-		nil, // No response hook
 		nil, // No validation
-	)
+	).Untyped()
 }
 
-// CreateAntifraudAddAMLToTransactionChildStep creates a child step for adding AML to transaction
-func CreateAntifraudAddAMLToTransactionChildStep() *model.ChildStep {
-	return model.NewChildStep(
-		"antifraud_add_aml_to_transaction_child_step",
-		func(context interface{}, data interface{}) interface{} {
-			fmt.Println("Adding AML check to transaction...")
-
-			// In production, this would call the SDK immediately
-			// For high TPS, no artificial delays
-			return map[string]interface{}{
-				"operation": "add_aml_to_transaction",
-				"success":   true,
-				"added_at":  time.Now().Format(time.RFC3339),
-				"message":   "AML check added to transaction aggregation",
+// addToTransactionChildStep creates a child step adding the service check to the aggregation of the transaction
+func (v validation) addToTransactionChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.ServiceResolution, OperationResult](
+		v.addToTransaction,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.ServiceResolution, error) {
+			return requireResult[models.ServiceResolution](data, v.call)
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, resolution models.ServiceResolution) (OperationResult, error) {
+			antifraudService, err := antifraudService()
+			if err != nil {
+				return OperationResult{}, err
+			}
+			if err := antifraudService.AddTransactionServiceCheck(ctx, resolution); err != nil {
+				return OperationResult{}, fmt.Errorf("add %s check to transaction failed: %w", v.service, err)
 			}
+			return OperationResult{
+				Operation:   "add_" + strings.ToLower(v.service) + "_to_transaction",
+				Success:     true,
+				Message:     v.service + " check added to transaction aggregation",
+				CompletedAt: time.Now(),
+			}, nil
 		},
-		nil, // No response hook
 		nil, // No validation
-	)
+	).Untyped()
 }
 
-// Helper function to extract transaction data from workflow data
-func extractTransactionData(data interface{}) map[string]interface{} {
-	if data == nil {
-		return make(map[string]interface{})
-	}
-
-	// Try to extract transaction from data
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		if transaction, exists := dataMap["transaction"]; exists {
-			if transactionMap, ok := transaction.(map[string]interface{}); ok {
-				return transactionMap
+// CreateAntifraudFinalizeTransactionChildStep creates a child step finalizing the transaction once its checks were added
+func CreateAntifraudFinalizeTransactionChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.AF_Transaction, models.FinalResolution](
+		FinalizeTransactionChildStep,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.AF_Transaction, error) {
+			return preparedTransaction(workflowContext, data), nil
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, transaction models.AF_Transaction) (models.FinalResolution, error) {
+			antifraudService, err := antifraudService()
+			if err != nil {
+				return models.FinalResolution{}, err
 			}
-		}
-	}
-
-	return make(map[string]interface{})
-}
-
-// Helper function to get transaction ID from context
-func getTransactionIDFromContext(context interface{}) string {
-	if context == nil {
-		return ""
-	}
-
-	// Try to extract from context map
-	if contextMap, ok := context.(map[string]interface{}); ok {
-		if transactionID, exists := contextMap["transaction_id"]; exists {
-			if id, ok := transactionID.(string); ok {
-				return id
+			result, err := antifraudService.FinalizeTransaction(ctx, transaction)
+			if err != nil {
+				return models.FinalResolution{}, fmt.Errorf("finalize transaction failed: %w", err)
 			}
-		}
-		// Also check for run_id which might contain transaction ID
-		if runID, exists := contextMap["run_id"]; exists {
-			if id, ok := runID.(string); ok {
-				return id
+			resolution, ok := result.(models.FinalResolution)
+			if !ok {
+				return models.FinalResolution{}, fmt.Errorf("unexpected final resolution type: %T", result)
 			}
-		}
-	}
-
-	return ""
+			if resolution.TransactionId == "" {
+				resolution.TransactionId = transaction.AF_Id
+			}
+			return resolution, nil
+		},
+		nil, // Validation done in separate step
+	).Untyped()
 }
 
-// Helper function to get antifraud service from context
-func getAntifraudServiceFromContext(context interface{}) (primitive.AntifraudService, error) {
-	if context == nil {
-		return nil, fmt.Errorf("context is nil")
-	}
+// CreateAntifraudValidateFinalResolutionChildStep creates a child step validating the final resolution against business rules
+func CreateAntifraudValidateFinalResolutionChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.FinalResolution, models.FinalResolution](
+		ValidateFinalResolutionChildStep,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.FinalResolution, error) {
+			return requireResult[models.FinalResolution](data, FinalizeTransactionChildStep)
+		},
+		nil, // The final resolution is validated as is
+		func(resolution models.FinalResolution) error {
+			if resolution.RiskScore < 0 || resolution.RiskScore > 100 {
+				return fmt.Errorf("invalid risk score value: %v", resolution.RiskScore)
+			}
 
-	// Try to extract antifraud service from context
-	if contextMap, ok := context.(map[string]interface{}); ok {
-		if antifraudService, exists := contextMap["antifraud_service"]; exists {
-			// Type assert to primitive.AntifraudService
-			if service, ok := antifraudService.(primitive.AntifraudService); ok {
-				return service, nil
+			switch resolution.FinalDecision {
+			case "REJECTED", "FRAUD", "BLOCKED":
+				// Business rule: If decision is a rejection, fail
+				return fmt.Errorf("transaction rejected by antifraud system: %s", resolution.FinalDecision)
+			case "REVIEW", "ALERT":
+				slog.Warn("Transaction requires manual review", "decision", resolution.FinalDecision)
 			}
-		}
-	}
 
-	return nil, fmt.Errorf("antifraud service not found in context")
+			// Business rule: If risk score > 95, fail even if approved
+			if resolution.RiskScore > 95 {
+				return fmt.Errorf("transaction risk score too high (%d) for approval", resolution.RiskScore)
+			}
+			return nil
+		},
+	).Untyped()
 }
 
-// Helper function to extract AF_Transaction from data
-func extractAFTransactionFromData(data interface{}) (models.AF_Transaction, error) {
-	if data == nil {
-		return models.AF_Transaction{}, fmt.Errorf("data is nil")
-	}
+// CreateAntifraudStoreFinalResolutionChildStep creates a child step storing the final resolution of the transaction
+func CreateAntifraudStoreFinalResolutionChildStep() *model.ChildStep {
+	return typed.NewChildStep[models.FinalResolution, OperationResult](
+		StoreFinalResolutionChildStep,
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) (models.FinalResolution, error) {
+			return requireResult[models.FinalResolution](data, ValidateFinalResolutionChildStep)
+		},
+		func(ctx context.Context, workflowContext primitiveModel.WorkflowContext, resolution models.FinalResolution) (OperationResult, error) {
+			antifraudService, err := antifraudService()
+			if err != nil {
+				return OperationResult{}, err
+			}
+			if err := antifraudService.StoreFinalResolution(ctx, resolution); err != nil {
+				return OperationResult{}, fmt.Errorf("store final resolution failed: %w", err)
+			}
+			return OperationResult{
+				Operation:     "store_final_resolution",
+				Success:       true,
+				TransactionID: resolution.TransactionId,
+				Message:       resolution.FinalDecision,
+				CompletedAt:   time.Now(),
+			}, nil
+		},
+		nil, // No validation
+	).Untyped()
+}
 
-	// Try to extract AF_Transaction from data
-	if afTransaction, ok := data.(models.AF_Transaction); ok {
-		return afTransaction, nil
+// newValidationResponse turns the resolution of a validation service into the PASS, REVIEW or FAIL of the workflow
+// The score of the service rates how safe the transaction is, so its risk score is the complement
+func newValidationResponse(resolution models.ServiceResolution) ValidationResponse {
+	response := ValidationResponse{
+		ServiceResolution: resolution,
+		CheckedAt:         time.Now(),
 	}
-
-	// Try to extract from map
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		// Check if it's an AF_Transaction in a map
-		if afTransaction, exists := dataMap["af_transaction"]; exists {
-			if transaction, ok := afTransaction.(models.AF_Transaction); ok {
-				return transaction, nil
-			}
-		}
+	switch resolution.Resolution {
+	case "APPROVED":
+		response.Resolution = "PASS"
+	case "ALERT":
+		response.Resolution = "REVIEW"
+	default:
+		response.Resolution = "FAIL"
 	}
-
-	return models.AF_Transaction{}, fmt.Errorf("AF_Transaction not found in data")
+	response.Score = min(max(100-resolution.Score, 0), 100)
+	if resolution.Details != "" {
+		response.RiskFactors = strings.Split(resolution.Details, "; ")
+	}
+	return response
 }
 
-// Helper function to extract SDK response from data
-func extractSDKResponseFromData(data interface{}) (map[string]interface{}, error) {
-	if data == nil {
-		return nil, fmt.Errorf("data is nil")
+// preparedTransaction returns the transaction prepared by the store step, or builds it when that step did not run
+func preparedTransaction(workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) models.AF_Transaction {
+	if afTransaction, ok := typed.Result[models.AF_Transaction](data, PrepareTransactionRequestChildStep); ok {
+		return afTransaction
 	}
+	return buildAFTransaction(workflowContext, data)
+}
 
-	// Try to extract SDK response from data
-	if sdkResponse, ok := data.(map[string]interface{}); ok {
-		// Check if it has SDK call confirmation
-		if sdkCallMade, exists := sdkResponse["sdk_call_made"]; exists {
-			if callMade, ok := sdkCallMade.(bool); ok && callMade {
-				return sdkResponse, nil
-			}
-		}
-		// Return anyway if it looks like an SDK response
-		return sdkResponse, nil
-	}
+// buildAFTransaction creates an antifraud transaction from the transaction in the workflow data
+// The AF id is the run id, or a new id outside of a run
+func buildAFTransaction(workflowContext primitiveModel.WorkflowContext, data primitiveModel.WorkflowData) models.AF_Transaction {
+	transactionData := extractTransactionData(data)
 
-	// Try to extract from map
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		// Check if SDK response is in a map
-		if sdkResponse, exists := dataMap["sdk_response"]; exists {
-			if response, ok := sdkResponse.(map[string]interface{}); ok {
-				return response, nil
-			}
-		}
-		// Check if async operation result is in a map
-		if asyncResult, exists := dataMap["async_operation_result"]; exists {
-			if result, ok := asyncResult.(map[string]interface{}); ok {
-				return result, nil
-			}
-		}
+	transactionID := workflowContext.GetRunID()
+	if transactionID == "" {
+		transactionID = uuid.NewString()
 	}
 
-	return nil, fmt.Errorf("SDK response not found in data")
+	return models.AF_Transaction{
+		AF_Id:      transactionID,
+		AF_AddDate: time.Now().Format(time.RFC3339Nano),
+		Transaction: models.Transaction{
+			Id:                 getString(transactionData, "id", ""),
+			Type:               getString(transactionData, "type", ""),
+			Date:               getString(transactionData, "date", time.Now().Format(time.RFC3339)),
+			Amount:             getString(transactionData, "amount", ""),
+			Currency:           getString(transactionData, "currency", ""),
+			ClientId:           getString(transactionData, "client_id", ""),
+			ClientName:         getString(transactionData, "client_name", ""),
+			ClientPAN:          getString(transactionData, "client_pan", ""),
+			ClientCVV:          getString(transactionData, "client_cvv", ""),
+			ClientCardHolder:   getString(transactionData, "client_card_holder", ""),
+			ClientPhone:        getString(transactionData, "client_phone", ""),
+			MerchantTerminalId: getString(transactionData, "merchant_terminal_id", ""),
+			Channel:            getString(transactionData, "channel", ""),
+			LocationIp:         getString(transactionData, "location_ip", ""),
+		},
+	}
 }
 
-// Helper function to extract processed response from data
-func extractProcessedResponseFromData(data interface{}) (map[string]interface{}, error) {
-	if data == nil {
-		return nil, fmt.Errorf("data is nil")
+// requireResult returns the result of an earlier child step, failing when it did not run
+func requireResult[T any](data primitiveModel.WorkflowData, childStepName string) (T, error) {
+	result, ok := typed.Result[T](data, childStepName)
+	if !ok {
+		return result, fmt.Errorf("result of child step %s not found in workflow data", childStepName)
 	}
+	return result, nil
+}
 
-	// Try to extract processed response from data
-	if processedResponse, ok := data.(map[string]interface{}); ok {
-		// Check if it has processed response fields
-		if _, hasTransactionID := processedResponse["transaction_id"]; hasTransactionID {
-			if _, hasSDKResponse := processedResponse["sdk_response"]; hasSDKResponse {
-				return processedResponse, nil
-			}
-		}
-		// Return anyway if it looks like a processed response
-		return processedResponse, nil
+// antifraudService returns the antifraud primitive
+func antifraudService() (primitive.AntifraudService, error) {
+	if primitive.Default == nil || primitive.Default.Antifraud == nil {
+		return nil, errAntifraudServiceUnavailable
 	}
+	return primitive.Default.Antifraud, nil
+}
 
-	// Try to extract from map
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		// Check if processed response is in a map
-		if processedResponse, exists := dataMap["processed_response"]; exists {
-			if response, ok := processedResponse.(map[string]interface{}); ok {
-				return response, nil
-			}
-		}
-		// Check if store response is in a map
-		if storeResponse, exists := dataMap["store_response"]; exists {
-			if response, ok := storeResponse.(map[string]interface{}); ok {
-				return response, nil
-			}
-		}
+// Helper function to extract transaction data from workflow data
+func extractTransactionData(data primitiveModel.WorkflowData) map[string]interface{} {
+	if transactionMap, ok := data.Get("transaction").(map[string]interface{}); ok {
+		return transactionMap
 	}
-
-	return nil, fmt.Errorf("processed response not found in data")
+	return make(map[string]interface{})
 }
 
 // Helper function to get string value from map with default
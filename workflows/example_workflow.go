@@ -1,7 +1,7 @@
 package workflows
 
 import (
-	"fmt"
+	"log/slog"
 
 	"unified-workflow/internal/common/model"
 	"unified-workflow/workflows/steps"
@@ -9,22 +9,21 @@ import (
 
 // GetExampleWorkflows returns a list of example workflows that should be available when the API starts
 func GetExampleWorkflows() []model.Workflow {
+	// Add antifraud workflows; payment processing runs the primary one as its fraud check
+	antifraudWorkflows := GetAntifraudWorkflows()
+
 	// Get existing example workflows
 	existingWorkflows := []model.Workflow{
 		createEchoWorkflow(),
 		createSequentialWorkflow(),
-		createPaymentProcessingWorkflow(),
+		createPaymentProcessingWorkflow(antifraudWorkflows[0].GetID()),
 		createMultiStepWorkflow(),
 	}
 
-	// Add antifraud workflows
-	antifraudWorkflows := GetAntifraudWorkflows()
-
 	// Combine all workflows
 	allWorkflows := append(existingWorkflows, antifraudWorkflows...)
 
-	fmt.Printf("Total workflows available: %d (including %d antifraud workflows)\n",
-		len(allWorkflows), len(antifraudWorkflows))
+	slog.Debug("Workflows available", "total", len(allWorkflows), "antifraud", len(antifraudWorkflows))
 
 	return allWorkflows
 }
@@ -61,7 +60,8 @@ func createSequentialWorkflow() model.Workflow {
 }
 
 // createPaymentProcessingWorkflow creates a payment processing workflow example
-func createPaymentProcessingWorkflow() model.Workflow {
+// Its fraud check runs the antifraud workflow registered under antifraudWorkflowID as a sub-workflow
+func createPaymentProcessingWorkflow(antifraudWorkflowID string) model.Workflow {
 	workflow := model.NewBaseWorkflow(
 		"Payment Processing Workflow",
 		"Processes payments with validation and fraud check",
@@ -69,7 +69,9 @@ func createPaymentProcessingWorkflow() model.Workflow {
 
 	// Add payment processing steps
 	workflow.AddStep(model.NewSequentialStep("validate-payment"))
-	workflow.AddStep(model.NewSequentialStep("check-fraud"))
+	checkFraud := model.NewSubWorkflowStep("check-fraud", antifraudWorkflowID)
+	checkFraud.Input = model.MustParseBindings(map[string]string{"transaction": "transaction"})
+	workflow.AddStep(checkFraud)
 	workflow.AddStep(model.NewSequentialStep("process-transaction"))
 	workflow.AddStep(model.NewSequentialStep("send-receipt"))
 
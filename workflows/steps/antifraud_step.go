@@ -3,15 +3,20 @@ package steps
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"time"
 
 	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
 	"unified-workflow/internal/primitive"
 
 	"github.com/baraic-io/antifraud-go"
 )
 
+// ValidationStepTimeout bounds an antifraud step, together with the calls its child steps make to the antifraud API
+const ValidationStepTimeout = 30 * time.Second
+
 // AntifraudStep is the base class for all antifraud steps
 type AntifraudStep struct {
 	*model.BaseStep
@@ -66,7 +71,7 @@ func (s *AntifraudStep) InitializeService() error {
 
 	s.antifraudService = primitive.Default.Antifraud
 	s.initialized = true
-	fmt.Printf("✓ Antifraud service initialized with endpoint: %s\n", s.endpoint)
+	slog.Info("Antifraud service initialized", "endpoint", s.endpoint)
 	return nil
 }
 
@@ -92,6 +97,7 @@ func (s *AntifraudStep) Run(ctx context.Context, context interface{}, data inter
 	// Execute with timing
 	startTime := time.Now()
 	s.StartTime = &startTime
+	logging.Debug(ctx, "Antifraud step started", "child_step_count", s.GetChildStepCount())
 
 	// Execute child steps if any
 	if s.HasChildSteps() {
@@ -125,35 +131,18 @@ func (s *AntifraudStep) ExecuteStepLogic(ctx context.Context, context interface{
 // ExecuteChildStepWithTiming executes a child step with timing and error handling
 func (s *AntifraudStep) ExecuteChildStepWithTiming(ctx context.Context, childStep *model.ChildStep, context interface{}, data interface{}) error {
 	startTime := time.Now()
+	ctx = logging.WithChildStep(ctx, childStep.GetName())
+	logging.Debug(ctx, "Executing child step")
 
-	// Execute request hook if present
-	var requestResult interface{}
-	if requestHook := childStep.GetRequestHook(); requestHook != nil {
-		requestResult = requestHook(context, data)
-	}
-
-	// Execute response hook if present (simulating async response processing)
-	var responseResult interface{}
-	if responseHook := childStep.GetResponseHook(); responseHook != nil {
-		responseResult = responseHook(context, data)
-	}
-
-	// Execute validate hook if present
-	if validateHook := childStep.GetValidateHook(); validateHook != nil {
-		// Use response result if available, otherwise use request result
-		validateTarget := responseResult
-		if validateTarget == nil {
-			validateTarget = requestResult
-		}
-		if validateTarget != nil {
-			err := validateHook(validateTarget)
-			if err != nil {
-				endTime := time.Now()
-				s.StoreChildStepMetrics(childStep, context, data, startTime, endTime, err.Error())
-				return fmt.Errorf("validation failed for child step %s: %w", childStep.GetName(), err)
-			}
-		}
+	workflowData := model.WorkflowDataOf(data)
+	result, err := childStep.Execute(ctx, model.WorkflowContextOf(context), workflowData)
+	if err != nil {
+		endTime := time.Now()
+		logging.Warn(ctx, "Child step failed", "error", err)
+		s.StoreChildStepMetrics(childStep, context, data, startTime, endTime, err.Error())
+		return err
 	}
+	model.StoreChildStepResult(workflowData, childStep.GetName(), result)
 
 	endTime := time.Now()
 	s.StoreChildStepMetrics(childStep, context, data, startTime, endTime, "")
@@ -168,11 +157,19 @@ func (s *AntifraudStep) StoreChildStepMetrics(childStep *model.ChildStep, contex
 
 	// In a real implementation, this would store metrics to a monitoring system
 	// For now, we'll just log the information
-	fmt.Printf("Child step %s executed in %v", childStep.GetName(), duration)
 	if errorMessage != "" {
-		fmt.Printf(" with error: %s", errorMessage)
+		slog.Debug("Child step executed", "child_step", childStep.GetName(), "duration", duration, "error", errorMessage)
+		return
+	}
+	slog.Debug("Child step executed", "child_step", childStep.GetName(), "duration", duration)
+}
+
+// stepContext returns the context passed to an ExecuteStepLogic implementation, or a background context
+func stepContext(ctx interface{}) context.Context {
+	if stepCtx, ok := ctx.(context.Context); ok {
+		return stepCtx
 	}
-	fmt.Println()
+	return context.Background()
 }
 
 // Helper function to extract transaction from workflow data
@@ -213,6 +210,6 @@ func getAntifraudAPIKey() string {
 
 	// For Qazpost TAF service, use g.rakhmanov credential by default
 	// This is for demo purposes - in production, use environment variables
-	fmt.Println("Warning: Using default Qazpost credential for antifraud API key")
+	slog.Warn("ANTIFRAUD_API_KEY not set, using the default Qazpost credential")
 	return "M8#Qe!2$ZrA9xKp" // g.rakhmanov credential
 }
@@ -3,9 +3,11 @@ package steps
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
 	"unified-workflow/workflows/child_steps"
 )
 
@@ -42,45 +44,31 @@ func (s *EchoStep) GetChildSteps() []*model.ChildStep {
 
 // Run executes the echo step and processes all child steps
 func (s *EchoStep) Run(ctx context.Context, context interface{}, data interface{}) error {
-	fmt.Printf("=== EchoStep [%s] Starting ===\n", s.GetName())
-	fmt.Printf("Message: %s\n", s.Message)
-	fmt.Printf("Child Steps Count: %d\n", len(s.ChildSteps))
+	logging.Info(ctx, "Echo step started", "message", s.Message, "child_step_count", len(s.ChildSteps))
 
 	// Process each child step through full lifecycle
 	for i, childStep := range s.ChildSteps {
-		fmt.Printf("\n--- Processing Child Step %d: %s ---\n", i+1, childStep.GetName())
-
-		// 1. Request hook
-		fmt.Println("1. Executing Request Hook...")
-		requestData := childStep.GetRequestHook()(context, data)
-		fmt.Printf("   Request Data: %v\n", requestData)
-
-		// 2. Response hook
-		fmt.Println("2. Executing Response Hook...")
-		responseData := childStep.GetResponseHook()(context, data)
-		fmt.Printf("   Response Data: %v\n", responseData)
-
-		// 3. Validate hook
-		fmt.Println("3. Executing Validate Hook...")
-		err := childStep.GetValidateHook()(responseData)
-		if err != nil {
-			fmt.Printf("   Validation Failed: %v\n", err)
-			return fmt.Errorf("child step %s validation failed: %w", childStep.GetName(), err)
+		childCtx := logging.WithChildStep(ctx, childStep.GetName())
+		logging.Debug(childCtx, "Processing child step", "index", i+1)
+
+		// Request, response and validate hooks
+		startTime := time.Now()
+		if err := s.ExecuteChildStep(ctx, childStep, context, data); err != nil {
+			logging.Warn(childCtx, "Child step failed", "error", err)
+			return fmt.Errorf("child step %s failed: %w", childStep.GetName(), err)
 		}
-		fmt.Println("   Validation Passed ✓")
 
 		// Store child step metrics
-		startTime := time.Now()
 		endTime := time.Now()
 		s.StoreChildStepMetrics(childStep, context, data, startTime, endTime, "")
 
-		fmt.Printf("--- Child Step %s Completed Successfully ---\n", childStep.GetName())
+		logging.Debug(childCtx, "Child step completed")
 	}
 
 	// Simulate some work
 	time.Sleep(100 * time.Millisecond)
 
-	fmt.Printf("\n=== EchoStep [%s] Completed Successfully ===\n", s.GetName())
+	logging.Info(ctx, "Echo step completed")
 	return nil
 }
 
@@ -99,18 +87,17 @@ func (s *EchoStep) ExecuteWithTiming(ctx context.Context, context interface{}, d
 
 // ExecuteChildStep executes a specific child step
 func (s *EchoStep) ExecuteChildStep(ctx context.Context, childStep *model.ChildStep, context interface{}, data interface{}) error {
-	fmt.Printf("Executing child step: %s\n", childStep.GetName())
-
-	// Execute request hook
-	requestData := childStep.GetRequestHook()(context, data)
-	fmt.Printf("Request data: %v\n", requestData)
+	ctx = logging.WithChildStep(ctx, childStep.GetName())
+	logging.Debug(ctx, "Executing child step")
 
-	// Execute response hook
-	responseData := childStep.GetResponseHook()(context, data)
-	fmt.Printf("Response data: %v\n", responseData)
-
-	// Execute validate hook
-	return childStep.GetValidateHook()(responseData)
+	workflowData := model.WorkflowDataOf(data)
+	result, err := childStep.Execute(ctx, model.WorkflowContextOf(context), workflowData)
+	if err != nil {
+		return err
+	}
+	logging.Debug(ctx, "Child step executed", "result", result)
+	model.StoreChildStepResult(workflowData, childStep.GetName(), result)
+	return nil
 }
 
 // ExecuteChildStepWithTiming executes a child step with timing
@@ -128,8 +115,7 @@ func (s *EchoStep) ExecuteChildStepWithTiming(ctx context.Context, childStep *mo
 // StoreChildStepMetrics stores metrics for a child step execution
 func (s *EchoStep) StoreChildStepMetrics(childStep *model.ChildStep, context interface{}, data interface{}, startTime, endTime time.Time, errorMessage string) {
 	duration := endTime.Sub(startTime)
-	fmt.Printf("Child Step Metrics - %s: Duration=%v, Error=%s\n",
-		childStep.GetName(), duration, errorMessage)
+	slog.Debug("Child step metrics", "child_step", childStep.GetName(), "duration", duration, "error", errorMessage)
 }
 
 // GetEchoSteps returns example echo steps with child steps
@@ -2,9 +2,11 @@ package steps
 
 import (
 	"fmt"
+	"log/slog"
 	"time"
 
 	"unified-workflow/internal/common/model"
+	"unified-workflow/internal/logging"
 	"unified-workflow/workflows/child_steps"
 
 	"github.com/google/uuid"
@@ -38,7 +40,7 @@ func NewStoreTransactionStep(endpoint string) *StoreTransactionStep {
 
 // prepareTransactionRequest prepares the transaction request for antifraud system
 func (s *StoreTransactionStep) prepareTransactionRequest(context interface{}, data interface{}) interface{} {
-	fmt.Println("Preparing transaction request...")
+	slog.Debug("Preparing transaction request")
 
 	// Extract transaction data from workflow data
 	// In a real implementation, you would extract from the data parameter
@@ -68,13 +70,13 @@ func (s *StoreTransactionStep) prepareTransactionRequest(context interface{}, da
 		"transaction": transactionData["transaction"],
 	}
 
-	fmt.Printf("Prepared transaction with ID: %s\n", afTransaction["af_id"])
+	slog.Debug("Prepared transaction", "af_id", afTransaction["af_id"])
 	return afTransaction
 }
 
 // validateTransactionRequest validates the prepared transaction request
 func (s *StoreTransactionStep) validateTransactionRequest(request interface{}) error {
-	fmt.Println("Validating transaction request...")
+	slog.Debug("Validating transaction request")
 
 	// Check if request is a map
 	req, ok := request.(map[string]interface{})
@@ -104,13 +106,13 @@ func (s *StoreTransactionStep) validateTransactionRequest(request interface{}) e
 		}
 	}
 
-	fmt.Println("Transaction request validation passed")
+	slog.Debug("Transaction request validation passed")
 	return nil
 }
 
 // callStoreTransactionAsync calls the antifraud service to store transaction
 func (s *StoreTransactionStep) callStoreTransactionAsync(context interface{}, data interface{}) interface{} {
-	fmt.Println("Calling store transaction async...")
+	slog.Debug("Calling store transaction async")
 
 	// Get antifraud service
 	_, err := s.GetAntifraudService()
@@ -122,7 +124,7 @@ func (s *StoreTransactionStep) callStoreTransactionAsync(context interface{}, da
 	// For high TPS, we don't simulate delays
 	go func() {
 		// Real async call would happen here
-		fmt.Println("Async store transaction completed")
+		slog.Debug("Async store transaction completed")
 	}()
 
 	// Return a mock async result
@@ -135,7 +137,7 @@ func (s *StoreTransactionStep) callStoreTransactionAsync(context interface{}, da
 
 // processStoreResponse processes the async store transaction response
 func (s *StoreTransactionStep) processStoreResponse(context interface{}, data interface{}) interface{} {
-	fmt.Println("Processing store response...")
+	slog.Debug("Processing store response")
 
 	// In production, this would process the actual SDK response
 	// For high TPS, we process immediately without delays
@@ -149,7 +151,7 @@ func (s *StoreTransactionStep) processStoreResponse(context interface{}, data in
 
 // validateStoreResponse validates the store transaction response
 func (s *StoreTransactionStep) validateStoreResponse(response interface{}) error {
-	fmt.Println("Validating store response...")
+	slog.Debug("Validating store response")
 
 	// Check if response is a map
 	resp, ok := response.(map[string]interface{})
@@ -171,13 +173,13 @@ func (s *StoreTransactionStep) validateStoreResponse(response interface{}) error
 		return fmt.Errorf("invalid transaction status: %v", status)
 	}
 
-	fmt.Println("Store response validation passed")
+	slog.Debug("Store response validation passed")
 	return nil
 }
 
 // storeTransactionResult stores the transaction result in workflow data
 func (s *StoreTransactionStep) storeTransactionResult(context interface{}, data interface{}) interface{} {
-	fmt.Println("Storing transaction result in workflow data...")
+	slog.Debug("Storing transaction result in workflow data")
 
 	// In a real implementation, you would store the result in workflow data
 	// For now, we'll return a success result
@@ -191,7 +193,8 @@ func (s *StoreTransactionStep) storeTransactionResult(context interface{}, data
 
 // ExecuteStepLogic executes the main step logic (alternative to child steps)
 func (s *StoreTransactionStep) ExecuteStepLogic(ctx interface{}, context interface{}, data interface{}) error {
-	fmt.Println("Executing StoreTransactionStep logic...")
+	logCtx := stepContext(ctx)
+	logging.Debug(logCtx, "Executing StoreTransactionStep logic")
 
 	// This method provides an alternative execution path without child steps
 	// It follows the same flow as child steps but in a single method
@@ -206,7 +209,7 @@ func (s *StoreTransactionStep) ExecuteStepLogic(ctx interface{}, context interfa
 
 	// 3. Call async operation
 	asyncResult := s.callStoreTransactionAsync(context, data)
-	fmt.Printf("Async operation started: %v\n", asyncResult)
+	logging.Debug(logCtx, "Async store started", "result", asyncResult)
 
 	// 4. Process response
 	response := s.processStoreResponse(context, data)
@@ -218,8 +221,8 @@ func (s *StoreTransactionStep) ExecuteStepLogic(ctx interface{}, context interfa
 
 	// 6. Store result
 	result := s.storeTransactionResult(context, data)
-	fmt.Printf("Transaction result: %v\n", result)
+	logging.Info(logCtx, "Transaction stored", "result", result)
 
-	fmt.Println("StoreTransactionStep completed successfully")
+	logging.Debug(logCtx, "StoreTransactionStep completed successfully")
 	return nil
 }